@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/propdiff"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// writeDiffReport compares the property files of the mod directory with those of the world directory.
+// Files that do not exist in either directory are not compared.
+func writeDiffReport(writer io.Writer, modPath, worldPath string) error {
+	modObjects, err := loadObjectProperties(modPath)
+	if err != nil {
+		return err
+	}
+	worldObjects, err := loadObjectProperties(worldPath)
+	if err != nil {
+		return err
+	}
+	modTextures, err := loadTextureProperties(modPath)
+	if err != nil {
+		return err
+	}
+	worldTextures, err := loadTextureProperties(worldPath)
+	if err != nil {
+		return err
+	}
+	return propdiff.WriteReport(writer,
+		propdiff.CompareObjects(modObjects, worldObjects),
+		propdiff.CompareTextures(modTextures, worldTextures))
+}
+
+func loadObjectProperties(dirname string) (object.PropertiesTable, error) {
+	data, err := readPropertiesFile(dirname, world.ObjectPropertiesFilename)
+	if (err != nil) || (data == nil) {
+		return nil, err
+	}
+	return world.DecodeObjectProperties(data)
+}
+
+func loadTextureProperties(dirname string) (texture.PropertiesList, error) {
+	data, err := readPropertiesFile(dirname, world.TexturePropertiesFilename)
+	if (err != nil) || (data == nil) {
+		return nil, err
+	}
+	return world.DecodeTextureProperties(data)
+}
+
+// readPropertiesFile returns the content of the named file, matched case-insensitive.
+// If the file does not exist, nil is returned.
+func readPropertiesFile(dirname, filename string) ([]byte, error) {
+	files, err := ioutil.ReadDir(dirname)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if !file.IsDir() && (strings.ToLower(file.Name()) == filename) {
+			return ioutil.ReadFile(filepath.Join(dirname, file.Name()))
+		}
+	}
+	return nil, nil
+}
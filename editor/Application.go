@@ -9,6 +9,7 @@ import (
 	"github.com/inkyblackness/hacked/editor/animations"
 	"github.com/inkyblackness/hacked/editor/archives"
 	"github.com/inkyblackness/hacked/editor/bitmaps"
+	"github.com/inkyblackness/hacked/editor/differences"
 	"github.com/inkyblackness/hacked/editor/event"
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/levels"
@@ -17,19 +18,27 @@ import (
 	"github.com/inkyblackness/hacked/editor/objects"
 	"github.com/inkyblackness/hacked/editor/project"
 	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/editor/search"
+	"github.com/inkyblackness/hacked/editor/shortcuts"
 	"github.com/inkyblackness/hacked/editor/sounds"
 	"github.com/inkyblackness/hacked/editor/texts"
 	"github.com/inkyblackness/hacked/editor/textures"
+	"github.com/inkyblackness/hacked/editor/translations"
+	"github.com/inkyblackness/hacked/editor/undo"
 	"github.com/inkyblackness/hacked/ss1/content/archive"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/font"
 	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ss1/content/sound"
 	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit"
 	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+	"github.com/inkyblackness/hacked/ss1/edit/texusage"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/history"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
@@ -53,10 +62,15 @@ type Application struct {
 	// FontSize specifies the font size to use.
 	FontSize float32
 	// GuiScale is applied when the window is initialized.
-	GuiScale   float32
+	GuiScale float32
+	// ProjectFile is the project to open when the window is initialized. It is optional.
+	ProjectFile string
+
 	guiContext *gui.Context
 
 	lastModifier input.Modifier
+	keymap       input.Keymap
+	appActions   []appAction
 	lastMouseX   float32
 	lastMouseY   float32
 
@@ -65,7 +79,7 @@ type Application struct {
 
 	cmdStack         *cmd.Stack
 	mod              *world.Mod
-	cp               text.Codepage
+	codepages        *text.LanguageCodepages
 	textLineCache    *text.Cache
 	textPageCache    *text.Cache
 	messagesCache    *text.ElectronicMessageCache
@@ -75,6 +89,7 @@ type Application struct {
 	animationCache   *bitmap.AnimationCache
 	movieCache       *movie.Cache
 	soundEffectCache *sound.SoundEffectCache
+	fontCache        *font.Cache
 
 	mapDisplay *levels.MapDisplay
 
@@ -85,17 +100,32 @@ type Application struct {
 	levelControlView *levels.ControlView
 	levelTilesView   *levels.TilesView
 	levelObjectsView *levels.ObjectsView
+	levelMacrosView  *levels.MacrosView
+	levelPaintView   *levels.PaintView
+	levelImageView   *levels.ImageImportView
+	levelRegionView  *levels.TransformView
+	levelLightView   *levels.LightingView
 	messagesView     *messages.View
+	chainsView       *messages.ChainsView
 	textsView        *texts.View
+	translationsView *translations.View
+	searchView       *search.View
 	bitmapsView      *bitmaps.View
 	texturesView     *textures.View
+	textureTableView *textures.TableView
 	animationsView   *animations.View
 	moviesView       *movies.View
 	soundEffectsView *sounds.View
 	objectsView      *objects.View
+	differencesView  *differences.View
+	historyView      *undo.View
+	shortcutsView    *shortcuts.View
 	aboutView        *about.View
 	licensesView     *about.LicensesView
 
+	projectFilename string
+	recentProjects  projectfile.RecentList
+
 	modalState gui.ModalStateWrapper
 
 	failureMessage string
@@ -118,6 +148,12 @@ func (app *Application) InitializeWindow(window opengl.Window) (err error) {
 
 	app.initModel()
 	app.initView()
+	app.initShortcuts()
+	app.loadRecentProjects()
+	if len(app.ProjectFile) > 0 {
+		app.openProject(app.ProjectFile)
+	}
+	app.projectView.OfferRecovery()
 
 	app.onWindowResize(app.window.Size())
 
@@ -125,6 +161,10 @@ func (app *Application) InitializeWindow(window opengl.Window) (err error) {
 }
 
 func (app *Application) onWindowClosed() {
+	if app.projectView != nil {
+		app.projectView.FinishRecovery()
+		app.storeHistoryOnExit()
+	}
 	if app.guiContext != nil {
 		app.guiContext.Destroy()
 		app.guiContext = nil
@@ -139,7 +179,6 @@ func (app *Application) initWindowCallbacks() {
 	app.window.OnResize(app.onWindowResize)
 
 	app.window.OnKey(app.onKey)
-	app.window.OnCharCallback(app.onChar)
 	app.window.OnModifier(app.onModifier)
 
 	app.window.OnMouseMove(app.onMouseMove)
@@ -164,14 +203,26 @@ func (app *Application) render() {
 	app.levelControlView.Render(activeLevel)
 	app.levelTilesView.Render(activeLevel)
 	app.levelObjectsView.Render(activeLevel)
+	app.levelMacrosView.Render(activeLevel)
+	app.levelPaintView.Render(activeLevel)
+	app.levelImageView.Render(activeLevel)
+	app.levelRegionView.Render(activeLevel)
+	app.levelLightView.Render(activeLevel)
 	app.messagesView.Render()
+	app.chainsView.Render()
 	app.textsView.Render()
+	app.translationsView.Render()
+	app.searchView.Render()
 	app.bitmapsView.Render()
 	app.texturesView.Render()
+	app.textureTableView.Render()
 	app.animationsView.Render()
 	app.moviesView.Render()
 	app.soundEffectsView.Render()
 	app.objectsView.Render()
+	app.differencesView.Render()
+	app.historyView.Render()
+	app.shortcutsView.Render()
 
 	paletteTexture, _ := app.paletteCache.Palette(0)
 	app.mapDisplay.Render(app.mod.ObjectProperties(), activeLevel,
@@ -258,39 +309,14 @@ func (app *Application) onFilesDropped(names []string) {
 
 func (app *Application) onKey(key input.Key, modifier input.Modifier) {
 	app.lastModifier = modifier
+	binding := input.Binding{Key: key, Modifier: modifier}
 	switch {
+	case app.shortcutsView.Capturing():
+		app.shortcutsView.Capture(binding)
 	case key == input.KeyEscape:
 		app.modalState.SetState(nil)
-	case key == input.KeyUndo:
-		app.tryUndo()
-	case key == input.KeyRedo:
-		app.tryRedo()
-	case key == input.KeySave:
-		app.projectView.StartSavingMod()
-	case key == input.KeyF1:
-		*app.projectView.WindowOpen() = !*app.projectView.WindowOpen()
-	case key == input.KeyF2:
-		*app.levelControlView.WindowOpen() = !*app.levelControlView.WindowOpen()
-	case key == input.KeyF3:
-		*app.levelTilesView.WindowOpen() = !*app.levelTilesView.WindowOpen()
-	case key == input.KeyF4:
-		*app.levelObjectsView.WindowOpen() = !*app.levelObjectsView.WindowOpen()
-	case key == input.KeyF5:
-		*app.messagesView.WindowOpen() = !*app.messagesView.WindowOpen()
-	}
-}
-
-func (app *Application) onChar(char rune) {
-	if !app.guiContext.IsUsingKeyboard() {
-		activeLevel := app.levels[app.levelControlView.SelectedLevel()]
-		switch char {
-		case 'v':
-			app.levelObjectsView.PlaceSelectedObjectsOnFloor(activeLevel)
-		case 'f':
-			app.levelObjectsView.PlaceSelectedObjectsOnEyeLevel(activeLevel)
-		case 'r':
-			app.levelObjectsView.PlaceSelectedObjectsOnCeiling(activeLevel)
-		}
+	default:
+		app.handleShortcut(binding)
 	}
 }
 
@@ -347,7 +373,7 @@ func (app *Application) onMouseScroll(dx, dy float32) {
 
 func (app *Application) onMouseButtonDown(buttonMask uint32, modifier input.Modifier) {
 	if !app.guiContext.IsUsingMouse() {
-		app.mapDisplay.MouseButtonDown(app.lastMouseX, app.lastMouseY, buttonMask)
+		app.mapDisplay.MouseButtonDown(app.lastMouseX, app.lastMouseY, buttonMask, modifier)
 	}
 	app.reportButtonChange(buttonMask, true)
 }
@@ -435,12 +461,13 @@ func (app *Application) initSignalling() {
 func (app *Application) initModel() {
 	app.mod = world.NewMod(app.resourcesChanged, app.modReset)
 
-	app.cp = text.DefaultCodepage()
-	app.textLineCache = text.NewLineCache(app.cp, app.mod)
-	app.textPageCache = text.NewPageCache(app.cp, app.mod)
-	app.messagesCache = text.NewElectronicMessageCache(app.cp, app.mod)
-	app.movieCache = movie.NewCache(app.cp, app.mod)
+	app.codepages = text.NewLanguageCodepages(text.DefaultCodepage())
+	app.textLineCache = text.NewLineCache(app.codepages, app.mod)
+	app.textPageCache = text.NewPageCache(app.codepages, app.mod)
+	app.messagesCache = text.NewElectronicMessageCache(app.codepages, app.mod)
+	app.movieCache = movie.NewCache(app.codepages, app.mod)
 	app.soundEffectCache = sound.NewSoundCache(app.mod)
+	app.fontCache = font.NewCache(app.mod)
 
 	for i := 0; i < archive.MaxLevels; i++ {
 		app.levels[i] = level.NewLevel(ids.LevelResourcesStart, i, app.mod)
@@ -458,6 +485,7 @@ func (app *Application) resourcesChanged(modifiedIDs []resource.ID, failedIDs []
 	app.messagesCache.InvalidateResources(modifiedIDs)
 	app.movieCache.InvalidateResources(modifiedIDs)
 	app.soundEffectCache.InvalidateResources(modifiedIDs)
+	app.fontCache.InvalidateResources(modifiedIDs)
 	for _, lvl := range app.levels {
 		lvl.InvalidateResources(modifiedIDs)
 	}
@@ -466,6 +494,13 @@ func (app *Application) resourcesChanged(modifiedIDs []resource.ID, failedIDs []
 	app.animationCache.InvalidateResources(modifiedIDs)
 }
 
+func (app *Application) codepagesChanged() {
+	app.textLineCache.InvalidateAll()
+	app.textPageCache.InvalidateAll()
+	app.messagesCache.InvalidateAll()
+	app.movieCache.InvalidateAll()
+}
+
 func (app *Application) modReset() {
 	app.cmdStack = new(cmd.Stack)
 }
@@ -473,41 +508,68 @@ func (app *Application) modReset() {
 // nolint: lll
 func (app *Application) initView() {
 	textViewer := media.NewTextViewerService(app.textLineCache, app.textPageCache, app.mod)
-	textSetter := media.NewTextSetterService(app.cp)
+	textSetter := media.NewTextSetterService(app.codepages)
 	audioViewer := media.NewAudioViewerService(app.movieCache, app.mod)
 	audioSetter := media.NewAudioSetterService()
 	movieViewer := media.NewMovieViewerService(app.movieCache, app.mod)
-	movieSetter := media.NewMovieSetterService(app.cp)
+	movieSetter := media.NewMovieSetterService(app.codepages)
 	soundEffectViewer := media.NewSoundViewerService(app.soundEffectCache, app.mod)
 	soundEffectSetter := media.NewSoundSetterService()
 	soundEffectService := undoable.NewSoundEffectService(edit.NewSoundEffectService(soundEffectViewer, soundEffectSetter), app)
 	augmentedTextService := undoable.NewAugmentedTextService(edit.NewAugmentedTextService(textViewer, textSetter, audioViewer, audioSetter), app)
-	movieService := undoable.NewMovieService(edit.NewMovieService(app.cp, movieViewer, movieSetter), app)
+	editTranslationService := edit.NewTranslationService(app.codepages, textViewer, textSetter, app.messagesCache, app.mod)
+	translationService := undoable.NewTranslationService(editTranslationService, app)
+	editMovieService := edit.NewMovieService(app.codepages, movieViewer, movieSetter)
+	movieService := undoable.NewMovieService(editMovieService, app)
+	searchService := undoable.NewSearchService(edit.NewSearchService(editTranslationService, editMovieService), app)
 
-	app.projectView = project.NewView(app.mod, &app.modalState, app.GuiScale, app)
+	app.projectView = project.NewView(app.mod, app.codepages, app.codepagesChanged, &app.modalState, app.GuiScale, app, newRecoveryStore())
 	app.archiveView = archives.NewArchiveView(app.mod, app.GuiScale, app)
-	app.levelControlView = levels.NewControlView(app.mod, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
-	app.levelTilesView = levels.NewTilesView(app.mod, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
-	app.levelObjectsView = levels.NewObjectsView(app.mod, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
-	app.messagesView = messages.NewMessagesView(app.mod, app.messagesCache, app.cp, app.movieCache, app.textureCache, &app.modalState, app.clipboard, app.GuiScale, app)
-	app.textsView = texts.NewTextsView(augmentedTextService, &app.modalState, app.clipboard, app.GuiScale)
+	app.levelControlView = levels.NewControlView(app.mod, &app.modalState, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
+	app.levelTilesView = levels.NewTilesView(app.mod, &app.modalState, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
+	app.levelObjectsView = levels.NewObjectsView(app.mod, &app.modalState, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
+	app.levelMacrosView = levels.NewMacrosView(app.mod, &app.modalState, app.GuiScale, app.levelObjectsView, app, &app.eventQueue, app.eventDispatcher)
+	app.levelPaintView = levels.NewPaintView(app.levelTilesView, app.GuiScale, &app.eventQueue, app.eventDispatcher)
+	app.levelImageView = levels.NewImageImportView(app.mod, &app.modalState, app.GuiScale, app, &app.eventQueue)
+	app.levelRegionView = levels.NewTransformView(app.mod, &app.modalState, app.GuiScale, app, &app.eventQueue, app.eventDispatcher)
+	app.levelLightView = levels.NewLightingView(app.mod, app.levelTilesView, app.GuiScale, &app.eventQueue, app.eventDispatcher)
+	app.messagesView = messages.NewMessagesView(app.mod, app.messagesCache, app.codepages, app.movieCache, app.textureCache, app.fontCache, app.frameCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.chainsView = messages.NewChainsView(app.mod, app.messagesCache, app.movieCache, app.messagesView, app.GuiScale)
+	app.textsView = texts.NewTextsView(augmentedTextService, app.fontCache, app.codepages, app.frameCache, &app.modalState, app.clipboard, app.GuiScale)
+	app.translationsView = translations.NewTranslationsView(translationService, &app.modalState, app.GuiScale)
+	app.searchView = search.NewSearchView(app.mod, searchService, app.codepages, searchNavigator{app: app}, &app.modalState, app.GuiScale)
 	app.bitmapsView = bitmaps.NewBitmapsView(app.mod, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
-	app.texturesView = textures.NewTexturesView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.texturesView = textures.NewTexturesView(app.mod, app.textLineCache, app.codepages, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.textureTableView = textures.NewTableView(app.mod, app.textLineCache, app.usageLevels(), app.texturesView, app.GuiScale)
 	app.animationsView = animations.NewAnimationsView(app.mod, app.textureCache, app.paletteCache, app.animationCache, &app.modalState, app.GuiScale, app)
-	app.moviesView = movies.NewMoviesView(app.mod, app.frameCache, movieService, &app.modalState, app.GuiScale, app)
-	app.soundEffectsView = sounds.NewSoundEffectsView(soundEffectService, &app.modalState, app.GuiScale)
-	app.objectsView = objects.NewView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.moviesView = movies.NewMoviesView(app.mod, app.codepages, app.frameCache, movieService, &app.modalState, app.GuiScale, app)
+	app.soundEffectsView = sounds.NewSoundEffectsView(soundEffectService, app.frameCache, &app.modalState, app.GuiScale)
+	app.objectsView = objects.NewView(app.mod, app.textLineCache, app.codepages, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.differencesView = differences.NewDifferencesView(app.mod, propertiesSetter{app: app}, &app.modalState, app.GuiScale)
+	app.historyView = undo.NewHistoryView(undoStack{app: app}, app.GuiScale)
 	app.aboutView = about.NewView(app.clipboard, app.GuiScale, app.Version)
 	app.licensesView = about.NewLicensesView(app.GuiScale)
 
 	app.eventDispatcher.RegisterHandler(app.onLevelObjectRequestCreateEvent)
+	app.eventDispatcher.RegisterHandler(app.onLevelTilesPaintRequestEvent)
+}
+
+func (app *Application) usageLevels() []texusage.Level {
+	levels := make([]texusage.Level, len(app.levels))
+	for index, lvl := range app.levels {
+		levels[index] = lvl
+	}
+	return levels
 }
 
 // Queue requests to perform the given command.
+// The changes of the command are recorded, so that they can be stored with the project.
 func (app *Application) Queue(command cmd.Command) {
+	recorded := history.Record(command, app.mod)
 	err := app.modifyModByCommand(func(modder world.Modder) error {
-		return app.cmdStack.Perform(command, modder)
+		return app.cmdStack.Perform(recorded, modder)
 	})
+	recorded.Complete()
 	if err != nil {
 		app.onFailure("command", "", err)
 	}
@@ -529,16 +591,26 @@ func (app *Application) onLevelObjectRequestCreateEvent(evt levels.ObjectRequest
 	app.levelObjectsView.RequestCreateObject(lvl, evt.Pos)
 }
 
+func (app *Application) onLevelTilesPaintRequestEvent(evt levels.TilesPaintRequestEvent) {
+	lvl := app.levels[app.levelControlView.SelectedLevel()]
+	app.levelPaintView.Paint(lvl, evt.Tiles)
+}
+
 func (app *Application) renderMainMenu() {
-	windowEntry := func(name string, shortcut string, isOpen *bool) {
-		if imgui.MenuItemV(name, shortcut, *isOpen, true) {
+	windowEntry := func(name string, isOpen *bool) {
+		if imgui.MenuItemV(name, app.shortcutText(windowActionName(name)), *isOpen, true) {
 			*isOpen = !*isOpen
 		}
 	}
+	actionEntry := func(label string, action string, enabled bool) bool {
+		return imgui.MenuItemV(label, app.shortcutText(action), false, enabled)
+	}
 
 	if imgui.BeginMainMenuBar() {
 		if imgui.BeginMenu("File") {
-			windowEntry("Project", "F1", app.projectView.WindowOpen())
+			windowEntry("Project", app.projectView.WindowOpen())
+			imgui.Separator()
+			app.renderProjectMenuItems()
 			imgui.Separator()
 			if imgui.MenuItem("Exit") {
 				app.window.SetCloseRequest(true)
@@ -546,27 +618,39 @@ func (app *Application) renderMainMenu() {
 			imgui.EndMenu()
 		}
 		if imgui.BeginMenu("Edit") {
-			if imgui.MenuItemV("Undo", "Ctrl+Z", false, app.cmdStack.CanUndo()) {
+			if actionEntry("Undo", "Undo", app.cmdStack.CanUndo()) {
 				app.tryUndo()
 			}
-			if imgui.MenuItemV("Redo", "Ctrl+Y / Ctrl+Shift+Z", false, app.cmdStack.CanRedo()) {
+			if actionEntry("Redo", "Redo", app.cmdStack.CanRedo()) {
 				app.tryRedo()
 			}
+			imgui.Separator()
+			activeLevel := app.levels[app.levelControlView.SelectedLevel()]
+			if actionEntry("Copy Tile", "Tiles: Copy", true) {
+				app.levelTilesView.CopySelectedTile(activeLevel)
+			}
+			if actionEntry("Paste Tile", "Tiles: Paste", true) {
+				app.levelTilesView.PasteToSelectedTiles(activeLevel)
+			}
+			if actionEntry("Place Objects on Floor", "Objects: Place on Floor", true) {
+				app.levelObjectsView.PlaceSelectedObjectsOnFloor(activeLevel)
+			}
+			if actionEntry("Place Objects on Eye Level", "Objects: Place on Eye Level", true) {
+				app.levelObjectsView.PlaceSelectedObjectsOnEyeLevel(activeLevel)
+			}
+			if actionEntry("Place Objects on Ceiling", "Objects: Place on Ceiling", true) {
+				app.levelObjectsView.PlaceSelectedObjectsOnCeiling(activeLevel)
+			}
+			imgui.Separator()
+			if imgui.MenuItemV("Keyboard Shortcuts...", "", false, true) {
+				*app.shortcutsView.WindowOpen() = true
+			}
 			imgui.EndMenu()
 		}
 		if imgui.BeginMenu("Window") {
-			windowEntry("Archive", "", app.archiveView.WindowOpen())
-			windowEntry("Level Control", "F2", app.levelControlView.WindowOpen())
-			windowEntry("Level Tiles", "F3", app.levelTilesView.WindowOpen())
-			windowEntry("Level Objects", "F4", app.levelObjectsView.WindowOpen())
-			windowEntry("Messages", "F5", app.messagesView.WindowOpen())
-			windowEntry("Texts", "", app.textsView.WindowOpen())
-			windowEntry("Bitmaps", "", app.bitmapsView.WindowOpen())
-			windowEntry("Textures", "", app.texturesView.WindowOpen())
-			windowEntry("Animations", "", app.animationsView.WindowOpen())
-			windowEntry("Movies", "", app.moviesView.WindowOpen())
-			windowEntry("Sound Effects", "", app.soundEffectsView.WindowOpen())
-			windowEntry("Game Objects", "", app.objectsView.WindowOpen())
+			for _, window := range app.windows() {
+				windowEntry(window.title, window.open)
+			}
 			imgui.EndMenu()
 		}
 		if imgui.BeginMenu("Help") {
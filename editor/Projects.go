@@ -0,0 +1,294 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+	"github.com/inkyblackness/hacked/ss1/edit/recovery"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/history"
+	"github.com/inkyblackness/hacked/ui/input"
+)
+
+var projectTypes = []external.TypeInfo{{
+	Title:      "Project files (*" + projectfile.Extension + ")",
+	Extensions: []string{"json"},
+}}
+
+type appWindow struct {
+	title    string
+	shortcut input.Key
+	open     *bool
+}
+
+func (app *Application) windows() []appWindow {
+	return []appWindow{
+		{title: "Archive", open: app.archiveView.WindowOpen()},
+		{title: "Level Control", shortcut: input.KeyF2, open: app.levelControlView.WindowOpen()},
+		{title: "Level Tiles", shortcut: input.KeyF3, open: app.levelTilesView.WindowOpen()},
+		{title: "Level Objects", shortcut: input.KeyF4, open: app.levelObjectsView.WindowOpen()},
+		{title: "Level Macros", open: app.levelMacrosView.WindowOpen()},
+		{title: "Tile Painting", open: app.levelPaintView.WindowOpen()},
+		{title: "Map Image Import", open: app.levelImageView.WindowOpen()},
+		{title: "Region Transform", open: app.levelRegionView.WindowOpen()},
+		{title: "Tile Lighting", open: app.levelLightView.WindowOpen()},
+		{title: "Messages", shortcut: input.KeyF5, open: app.messagesView.WindowOpen()},
+		{title: "Message Chains", open: app.chainsView.WindowOpen()},
+		{title: "Texts", open: app.textsView.WindowOpen()},
+		{title: "Translations", open: app.translationsView.WindowOpen()},
+		{title: "Search", open: app.searchView.WindowOpen()},
+		{title: "Bitmaps", open: app.bitmapsView.WindowOpen()},
+		{title: "Textures", open: app.texturesView.WindowOpen()},
+		{title: "Texture Table", open: app.textureTableView.WindowOpen()},
+		{title: "Animations", open: app.animationsView.WindowOpen()},
+		{title: "Movies", open: app.moviesView.WindowOpen()},
+		{title: "Sound Effects", open: app.soundEffectsView.WindowOpen()},
+		{title: "Game Objects", open: app.objectsView.WindowOpen()},
+		{title: "Mod Differences", open: app.differencesView.WindowOpen()},
+		{title: "Undo History", open: app.historyView.WindowOpen()},
+	}
+}
+
+func (app *Application) layoutWindows() []appWindow {
+	return append([]appWindow{{title: "Project", shortcut: input.KeyF1, open: app.projectView.WindowOpen()}}, app.windows()...)
+}
+
+func (app *Application) renderProjectMenuItems() {
+	unsaved := len(app.mod.ModifiedFilenames()) > 0
+	if imgui.MenuItemV("New Project", "", false, !unsaved) {
+		app.newProject()
+	}
+	if imgui.MenuItemV("Open Project...", "", false, !unsaved) {
+		app.startOpeningProject()
+	}
+	if imgui.BeginMenuV("Recent Projects", !unsaved && (len(app.recentProjects.Files) > 0)) {
+		for _, filename := range app.recentProjects.Files {
+			if imgui.MenuItem(filename) {
+				app.openProject(filename)
+			}
+		}
+		imgui.EndMenu()
+	}
+	if imgui.MenuItem("Save Project") {
+		app.saveProject()
+	}
+	if imgui.MenuItem("Save Project As...") {
+		app.startSavingProjectAs()
+	}
+}
+
+func (app *Application) newProject() {
+	app.projectFilename = ""
+	app.projectView.RestoreProject(projectfile.File{})
+	app.levelMacrosView.SetMacros(nil)
+}
+
+func (app *Application) startOpeningProject() {
+	info := "File must be a project file (*" + projectfile.Extension + ").\n" +
+		"Opening a project replaces the static world data and the mod."
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		if _, err := projectfile.Load(filename); err != nil {
+			external.Import(&app.modalState, "File could not be read: "+err.Error()+"\n"+info, projectTypes, fileHandler, true)
+			return
+		}
+		app.openProject(filename)
+	}
+
+	external.Import(&app.modalState, info, projectTypes, fileHandler, false)
+}
+
+func (app *Application) openProject(filename string) {
+	file, err := projectfile.Load(filename)
+	if err != nil {
+		app.recentProjects.Remove(filename)
+		app.storeRecentProjects()
+		external.Notice(&app.modalState, "Project", fmt.Sprintf("Project <%s> could not be opened:\n%v", filename, err))
+		return
+	}
+	problems := app.projectView.RestoreProject(file)
+	app.levelMacrosView.SetMacros(file.Macros)
+	problems = append(problems, app.loadHistory(projectfile.HistoryFilename(filename))...)
+	if len(file.Layout.OpenWindows) > 0 {
+		open := make(map[string]bool)
+		for _, title := range file.Layout.OpenWindows {
+			open[title] = true
+		}
+		for _, window := range app.layoutWindows() {
+			*window.open = open[window.title]
+		}
+	}
+	app.projectFilename = filename
+	app.addRecentProject(filename)
+	if len(problems) > 0 {
+		external.Notice(&app.modalState, "Project", "The project was opened with problems:\n"+strings.Join(problems, "\n"))
+	}
+}
+
+func (app *Application) currentProject() projectfile.File {
+	var file projectfile.File
+	app.projectView.StoreProject(&file)
+	file.Macros = app.levelMacrosView.Macros()
+	for _, window := range app.layoutWindows() {
+		if *window.open {
+			file.Layout.OpenWindows = append(file.Layout.OpenWindows, window.title)
+		}
+	}
+	return file
+}
+
+func (app *Application) saveProject() {
+	if len(app.projectFilename) == 0 {
+		app.startSavingProjectAs()
+		return
+	}
+	err := app.writeProject(app.projectFilename)
+	if err != nil {
+		external.Notice(&app.modalState, "Project", fmt.Sprintf("Project could not be saved:\n%v", err))
+		return
+	}
+	app.addRecentProject(app.projectFilename)
+}
+
+func (app *Application) startSavingProjectAs() {
+	name := "project"
+	if modPath := app.mod.Path(); len(modPath) > 0 {
+		name = filepath.Base(modPath)
+	}
+	filename := name + projectfile.Extension
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		fullName := filepath.Join(dirname, filename)
+		err := app.writeProject(fullName)
+		if err != nil {
+			external.Export(&app.modalState, "Could not write file.\n"+info, exportTo, true)
+			return
+		}
+		app.projectFilename = fullName
+		app.addRecentProject(fullName)
+	}
+
+	external.Export(&app.modalState, info, exportTo, false)
+}
+
+func (app *Application) writeProject(filename string) error {
+	err := projectfile.Save(filename, app.currentProject())
+	if err != nil {
+		return err
+	}
+	return app.saveHistory(projectfile.HistoryFilename(filename))
+}
+
+// saveHistory stores the undo history of the current mod.
+// The history is only valid for the mod in its current state, which is verified when it is loaded.
+func (app *Application) saveHistory(filename string) error {
+	writer, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = history.Write(writer, history.FromStack(app.cmdStack.History()))
+	closeErr := writer.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// storeHistoryOnExit keeps the undo history of a saved mod that belongs to a project.
+func (app *Application) storeHistoryOnExit() {
+	if (len(app.projectFilename) == 0) || (len(app.mod.ModifiedFilenames()) > 0) {
+		return
+	}
+	_ = app.saveHistory(projectfile.HistoryFilename(app.projectFilename))
+}
+
+func (app *Application) loadHistory(filename string) []string {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = reader.Close() }()
+	file, err := history.Read(reader)
+	if err != nil {
+		return []string{fmt.Sprintf("Undo history could not be read: %v", err)}
+	}
+	if !file.Consistent(app.mod) {
+		return []string{"Undo history does not match the mod and was discarded."}
+	}
+	app.cmdStack = cmd.NewStackFrom(file.StackEntries(), file.Done)
+	return nil
+}
+
+func (app *Application) addRecentProject(filename string) {
+	if absName, err := filepath.Abs(filename); err == nil {
+		filename = absName
+	}
+	app.recentProjects.Add(filename)
+	app.storeRecentProjects()
+}
+
+// userDataDir returns the directory for the data the editor keeps between sessions.
+func userDataDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "InkyBlackness", "HackEd"), nil
+}
+
+func recentProjectsFilename() (string, error) {
+	dir, err := userDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "recent-projects.json"), nil
+}
+
+func newRecoveryStore() *recovery.Store {
+	dir, err := userDataDir()
+	if err != nil {
+		return nil
+	}
+	return recovery.NewStore(filepath.Join(dir, "recovery"))
+}
+
+func (app *Application) loadRecentProjects() {
+	filename, err := recentProjectsFilename()
+	if err != nil {
+		return
+	}
+	reader, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer func() { _ = reader.Close() }()
+	list, err := projectfile.ReadRecentList(reader)
+	if err == nil {
+		app.recentProjects = list
+	}
+}
+
+func (app *Application) storeRecentProjects() {
+	filename, err := recentProjectsFilename()
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(filename), 0755) != nil {
+		return
+	}
+	writer, err := os.Create(filename)
+	if err != nil {
+		return
+	}
+	defer func() { _ = writer.Close() }()
+	_ = projectfile.WriteRecentList(writer, app.recentProjects)
+}
@@ -0,0 +1,18 @@
+package editor
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+)
+
+type propertiesSetter struct {
+	app *Application
+}
+
+func (setter propertiesSetter) RequestSetObjectProperties(triple object.Triple, properties object.Properties) {
+	setter.app.objectsView.RequestSetProperties(triple, properties)
+}
+
+func (setter propertiesSetter) RequestSetTextureProperties(index int, properties texture.Properties) {
+	setter.app.texturesView.RequestSetProperties(index, properties)
+}
@@ -0,0 +1,30 @@
+package editor
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+type searchNavigator struct {
+	app *Application
+}
+
+func (nav searchNavigator) ShowText(key resource.Key) {
+	nav.app.textsView.ShowText(key)
+}
+
+func (nav searchNavigator) ShowMessage(key resource.Key) {
+	nav.app.messagesView.ShowMessage(key)
+}
+
+func (nav searchNavigator) ShowObject(triple object.Triple, lang resource.Language) {
+	nav.app.objectsView.ShowObject(triple, lang)
+}
+
+func (nav searchNavigator) ShowTexture(index int, lang resource.Language) {
+	nav.app.texturesView.ShowTexture(index, lang)
+}
+
+func (nav searchNavigator) ShowSubtitles(movieKey resource.Key, lang resource.Language) {
+	nav.app.moviesView.ShowSubtitles(movieKey, lang)
+}
@@ -0,0 +1,170 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/hacked/editor/levels"
+	"github.com/inkyblackness/hacked/editor/shortcuts"
+	"github.com/inkyblackness/hacked/ui/input"
+)
+
+type appAction struct {
+	shortcuts.Action
+	handler func()
+}
+
+func bindings(texts ...string) []input.Binding {
+	result := make([]input.Binding, 0, len(texts))
+	for _, text := range texts {
+		binding, err := input.ParseBinding(text)
+		if err != nil {
+			panic(err)
+		}
+		result = append(result, binding)
+	}
+	return result
+}
+
+func windowActionName(title string) string {
+	return "Window: " + title
+}
+
+// actions returns all operations of the application that can be bound to keys.
+func (app *Application) actions() []appAction {
+	activeLevel := func() int { return app.levelControlView.SelectedLevel() }
+	list := []appAction{
+		{Action: shortcuts.Action{Name: "Undo", Defaults: bindings("Ctrl+Z")}, handler: app.tryUndo},
+		{Action: shortcuts.Action{Name: "Redo", Defaults: bindings("Ctrl+Y", "Ctrl+Shift+Z")}, handler: app.tryRedo},
+		{Action: shortcuts.Action{Name: "Save Mod", Defaults: bindings("Ctrl+S")}, handler: app.projectView.StartSavingMod},
+		{
+			Action:  shortcuts.Action{Name: "Level: Previous", Defaults: bindings("Ctrl+PageUp")},
+			handler: func() { app.levelControlView.SelectLevel(activeLevel() - 1) },
+		},
+		{
+			Action:  shortcuts.Action{Name: "Level: Next", Defaults: bindings("Ctrl+PageDown")},
+			handler: func() { app.levelControlView.SelectLevel(activeLevel() + 1) },
+		},
+		{Action: shortcuts.Action{Name: "Map: Zoom In", Defaults: bindings("PageUp")}, handler: func() { app.mapDisplay.Zoom(0.5) }},
+		{Action: shortcuts.Action{Name: "Map: Zoom Out", Defaults: bindings("PageDown")}, handler: func() { app.mapDisplay.Zoom(-0.5) }},
+		{Action: shortcuts.Action{Name: "Map: Clear Selection"}, handler: app.mapDisplay.ClearSelection},
+		{
+			Action:  shortcuts.Action{Name: "Tiles: Copy", Defaults: bindings("Ctrl+Shift+C")},
+			handler: func() { app.levelTilesView.CopySelectedTile(app.levels[activeLevel()]) },
+		},
+		{
+			Action:  shortcuts.Action{Name: "Tiles: Paste", Defaults: bindings("Ctrl+Shift+V")},
+			handler: func() { app.levelTilesView.PasteToSelectedTiles(app.levels[activeLevel()]) },
+		},
+		{
+			Action:  shortcuts.Action{Name: "Objects: Place on Floor", Defaults: bindings("V")},
+			handler: func() { app.levelObjectsView.PlaceSelectedObjectsOnFloor(app.levels[activeLevel()]) },
+		},
+		{
+			Action:  shortcuts.Action{Name: "Objects: Place on Eye Level", Defaults: bindings("F")},
+			handler: func() { app.levelObjectsView.PlaceSelectedObjectsOnEyeLevel(app.levels[activeLevel()]) },
+		},
+		{
+			Action:  shortcuts.Action{Name: "Objects: Place on Ceiling", Defaults: bindings("R")},
+			handler: func() { app.levelObjectsView.PlaceSelectedObjectsOnCeiling(app.levels[activeLevel()]) },
+		},
+	}
+	for index, tool := range levels.PaintTools() {
+		selected := tool
+		list = append(list, appAction{
+			Action:  shortcuts.Action{Name: "Map Tool: " + tool.String(), Defaults: bindings(fmt.Sprintf("%d", index+1))},
+			handler: func() { app.levelPaintView.SetTool(selected) },
+		})
+	}
+	for _, window := range app.layoutWindows() {
+		open := window.open
+		action := appAction{
+			Action:  shortcuts.Action{Name: windowActionName(window.title)},
+			handler: func() { *open = !*open },
+		}
+		if window.shortcut != 0 {
+			action.Defaults = []input.Binding{{Key: window.shortcut}}
+		}
+		list = append(list, action)
+	}
+	return list
+}
+
+func (app *Application) initShortcuts() {
+	app.appActions = app.actions()
+	actions := make([]shortcuts.Action, len(app.appActions))
+	for index, action := range app.appActions {
+		actions[index] = action.Action
+	}
+	app.keymap = app.loadKeymap().WithDefaults(shortcuts.DefaultKeymap(actions))
+	app.shortcutsView = shortcuts.NewView(actions, &app.keymap, app.storeKeymap, app.GuiScale)
+}
+
+// handleShortcut performs the action bound to the given key combination.
+// Bindings that are assigned to more than one action are ignored, as they are in conflict.
+func (app *Application) handleShortcut(binding input.Binding) {
+	if binding.Typing() && app.guiContext.IsUsingKeyboard() {
+		return
+	}
+	names := app.keymap.Actions(binding)
+	if len(names) != 1 {
+		return
+	}
+	for _, action := range app.appActions {
+		if action.Name == names[0] {
+			action.handler()
+		}
+	}
+}
+
+// shortcutText returns the bindings of the given action, to be shown in menus.
+func (app *Application) shortcutText(action string) string {
+	var texts []string
+	for _, binding := range app.keymap.Bindings[action] {
+		texts = append(texts, binding.String())
+	}
+	return strings.Join(texts, " / ")
+}
+
+func keymapFilename() (string, error) {
+	dir, err := userDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "keymap.json"), nil
+}
+
+func (app *Application) loadKeymap() input.Keymap {
+	filename, err := keymapFilename()
+	if err != nil {
+		return input.NewKeymap()
+	}
+	reader, err := os.Open(filename)
+	if err != nil {
+		return input.NewKeymap()
+	}
+	defer func() { _ = reader.Close() }()
+	keymap, err := input.ReadKeymap(reader)
+	if err != nil {
+		return input.NewKeymap()
+	}
+	return keymap
+}
+
+func (app *Application) storeKeymap() {
+	filename, err := keymapFilename()
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(filename), 0755) != nil {
+		return
+	}
+	writer, err := os.Create(filename)
+	if err != nil {
+		return
+	}
+	defer func() { _ = writer.Close() }()
+	_ = input.WriteKeymap(writer, app.keymap)
+}
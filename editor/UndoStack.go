@@ -0,0 +1,30 @@
+package editor
+
+import (
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+)
+
+type undoStack struct {
+	app *Application
+}
+
+func (stack undoStack) History() ([]cmd.Entry, int) {
+	return stack.app.cmdStack.History()
+}
+
+func (stack undoStack) JumpTo(done int) {
+	for {
+		_, current := stack.app.cmdStack.History()
+		switch {
+		case current > done:
+			stack.app.tryUndo()
+		case current < done:
+			stack.app.tryRedo()
+		default:
+			return
+		}
+		if _, now := stack.app.cmdStack.History(); now == current {
+			return
+		}
+	}
+}
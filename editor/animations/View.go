@@ -334,5 +334,8 @@ func (view *View) requestSetAnimation(newAnim bitmap.Animation, newFrames [][]by
 		command.oldAnimation = encodeAnim(oldAnim)
 	}
 	command.oldFrames = view.mod.ModifiedBlocks(view.model.currentKey.Lang, newAnim.ResourceID)
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{
+		Command: command,
+		Title:   fmt.Sprintf("Set animation %v[%d] (%v)", command.animationKey.ID, command.animationKey.Index, command.animationKey.Lang),
+	})
 }
@@ -140,7 +140,7 @@ func (view *View) requestClearLevel(id int) {
 			}
 		}
 
-		view.commander.Queue(command)
+		view.commander.Queue(cmd.Named{Command: command, Title: fmt.Sprintf("Clear level %d", id)})
 	}
 }
 
@@ -162,6 +162,6 @@ func (view *View) requestRemoveLevel(id int) {
 			}
 		}
 
-		view.commander.Queue(command)
+		view.commander.Queue(cmd.Named{Command: command, Title: fmt.Sprintf("Remove level %d", id)})
 	}
 }
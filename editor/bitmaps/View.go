@@ -265,5 +265,8 @@ func (view *View) requestSetBitmapData(newData []byte) {
 		oldData:     view.mod.ModifiedBlock(resourceKey.Lang, resourceKey.ID, resourceKey.Index),
 		newData:     newData,
 	}
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{
+		Command: command,
+		Title:   fmt.Sprintf("Set bitmap %v[%d] (%v)", command.displayKey.ID, command.displayKey.Index, command.displayKey.Lang),
+	})
 }
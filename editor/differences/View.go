@@ -0,0 +1,186 @@
+package differences
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/propdiff"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// PropertiesSetter applies reverted properties as undoable changes.
+type PropertiesSetter interface {
+	RequestSetObjectProperties(triple object.Triple, properties object.Properties)
+	RequestSetTextureProperties(index int, properties texture.Properties)
+}
+
+// View shows the object and texture properties of the mod that differ from the world.
+type View struct {
+	mod    *world.Mod
+	setter PropertiesSetter
+
+	modalStateMachine gui.ModalStateMachine
+	guiScale          float32
+
+	model viewModel
+}
+
+// NewDifferencesView returns a new instance.
+func NewDifferencesView(mod *world.Mod, setter PropertiesSetter,
+	modalStateMachine gui.ModalStateMachine, guiScale float32) *View {
+	view := &View{
+		mod:    mod,
+		setter: setter,
+
+		modalStateMachine: modalStateMachine,
+		guiScale:          guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 600 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionOnce)
+		if imgui.BeginV("Mod Differences", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *View) renderContent() {
+	if !view.model.compared || !view.model.comparedAt.Equal(view.mod.LastChangeTime()) {
+		view.compare()
+	}
+	if imgui.Button("Refresh") {
+		view.compare()
+	}
+	imgui.Separator()
+
+	if imgui.BeginChildV("Differences", imgui.Vec2{X: -1, Y: -1}, false, imgui.WindowFlagsHorizontalScrollbar) {
+		objectTitle := fmt.Sprintf("Object Properties (%d)###objects", len(view.model.objects))
+		if imgui.TreeNodeV(objectTitle, imgui.TreeNodeFlagsDefaultOpen|imgui.TreeNodeFlagsFramed) {
+			if view.mod.HasModifyableObjectProperties() {
+				view.renderObjectDifferences()
+			} else {
+				imgui.Text("The mod uses the object properties of the world.")
+			}
+			imgui.TreePop()
+		}
+		textureTitle := fmt.Sprintf("Texture Properties (%d)###textures", len(view.model.textures))
+		if imgui.TreeNodeV(textureTitle, imgui.TreeNodeFlagsDefaultOpen|imgui.TreeNodeFlagsFramed) {
+			if view.mod.HasModifyableTextureProperties() {
+				view.renderTextureDifferences()
+			} else {
+				imgui.Text("The mod uses the texture properties of the world.")
+			}
+			imgui.TreePop()
+		}
+	}
+	imgui.EndChild()
+}
+
+func (view *View) compare() {
+	view.model.compared = true
+	view.model.comparedAt = view.mod.LastChangeTime()
+	view.model.objects = nil
+	view.model.textures = nil
+	if view.mod.HasModifyableObjectProperties() {
+		view.model.objects = propdiff.CompareObjects(view.mod.ObjectProperties(), view.mod.World().ObjectProperties())
+	}
+	if view.mod.HasModifyableTextureProperties() {
+		view.model.textures = propdiff.CompareTextures(view.mod.TextureProperties(), view.mod.World().TextureProperties())
+	}
+}
+
+func (view *View) renderObjectDifferences() {
+	imgui.Columns(5, "objects")
+	view.renderHeader("Object", "Property")
+	for index, diff := range view.model.objects {
+		imgui.Text(diff.Triple.String())
+		imgui.NextColumn()
+		view.renderDifference(fmt.Sprintf("object%d", index), diff.Column, diff.ModValue, diff.WorldValue,
+			func() { view.requestRevertObject(diff) })
+	}
+	imgui.Columns(1, "")
+}
+
+func (view *View) renderTextureDifferences() {
+	imgui.Columns(5, "textures")
+	view.renderHeader("Texture", "Property")
+	for index, diff := range view.model.textures {
+		imgui.Text(fmt.Sprintf("%3d", diff.Index))
+		imgui.NextColumn()
+		view.renderDifference(fmt.Sprintf("texture%d", index), diff.Field, diff.ModValue, diff.WorldValue,
+			func() { view.requestRevertTexture(diff) })
+	}
+	imgui.Columns(1, "")
+}
+
+func (view *View) renderHeader(subject, field string) {
+	for _, title := range []string{subject, field, "Mod", "World", ""} {
+		imgui.Text(title)
+		imgui.NextColumn()
+	}
+	imgui.Separator()
+}
+
+func (view *View) renderDifference(id string, field, modValue, worldValue string, revert func()) {
+	imgui.Text(field)
+	imgui.NextColumn()
+	imgui.Text(displayedValue(modValue))
+	imgui.NextColumn()
+	imgui.Text(displayedValue(worldValue))
+	imgui.NextColumn()
+	if len(worldValue) > 0 {
+		imgui.PushID(id)
+		if imgui.Button("Revert") {
+			revert()
+		}
+		imgui.PopID()
+	}
+	imgui.NextColumn()
+}
+
+func displayedValue(value string) string {
+	if len(value) == 0 {
+		return "(n/a)"
+	}
+	return value
+}
+
+func (view *View) requestRevertObject(diff propdiff.ObjectDifference) {
+	properties, err := propdiff.RevertedObject(view.mod.ObjectProperties(), diff)
+	if err != nil {
+		external.Notice(view.modalStateMachine, "Revert", fmt.Sprintf("Could not revert property: %v", err))
+		return
+	}
+	view.setter.RequestSetObjectProperties(diff.Triple, properties)
+}
+
+func (view *View) requestRevertTexture(diff propdiff.TextureDifference) {
+	properties, err := propdiff.RevertedTexture(view.mod.TextureProperties(), view.mod.World().TextureProperties(), diff)
+	if err != nil {
+		external.Notice(view.modalStateMachine, "Revert", fmt.Sprintf("Could not revert property: %v", err))
+		return
+	}
+	view.setter.RequestSetTextureProperties(diff.Index, properties)
+}
@@ -0,0 +1,21 @@
+package differences
+
+import (
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/edit/propdiff"
+)
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	comparedAt time.Time
+	compared   bool
+	objects    []propdiff.ObjectDifference
+	textures   []propdiff.TextureDifference
+}
+
+func freshViewModel() viewModel {
+	return viewModel{}
+}
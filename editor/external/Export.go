@@ -5,8 +5,10 @@ import (
 	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/voc"
 	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ui/gui"
@@ -23,6 +25,8 @@ func Export(machine gui.ModalStateMachine, info string, callback func(string), l
 }
 
 // ExportAudio is a helper wrapper for exporting audio.
+// The format is determined by the extension of the filename: ".voc" files are written
+// as Creative Voice File, all others as WAV.
 func ExportAudio(machine gui.ModalStateMachine, filename string, sound audio.L8) {
 	info := "File to be written: " + filename
 	var dirHandler func(string)
@@ -34,7 +38,11 @@ func ExportAudio(machine gui.ModalStateMachine, filename string, sound audio.L8)
 			return
 		}
 		defer func() { _ = writer.Close() }()
-		err = wav.Save(writer, sound.SampleRate, sound.Samples)
+		if strings.EqualFold(filepath.Ext(filename), ".voc") {
+			err = voc.Save(writer, sound.SampleRate, sound.Samples)
+		} else {
+			err = wav.Save(writer, sound.SampleRate, sound.Samples)
+		}
 		if err != nil {
 			Export(machine, info, dirHandler, true)
 		}
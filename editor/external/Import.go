@@ -1,6 +1,7 @@
 package external
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"math"
@@ -23,30 +24,60 @@ func Import(machine gui.ModalStateMachine, info string, types []TypeInfo, callba
 	})
 }
 
+// AudioSampleRate is the sample rate all audio of the game is stored with.
+const AudioSampleRate = 22050
+
+// LoadAudio loads the given WAV file and converts it to the format of the game.
+// Other sample rates are resampled, and multiple channels are mixed down.
+// The returned clipping information describes how much of the sound exceeded the 8-bit range.
+func LoadAudio(filename string) (audio.L8, audio.Clipping, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return audio.L8{}, audio.Clipping{}, err
+	}
+	defer func() { _ = reader.Close() }()
+	sound, err := wav.LoadFloat(reader)
+	if err != nil {
+		return audio.L8{}, audio.Clipping{}, err
+	}
+	l8, clip := sound.Resampled(AudioSampleRate).ToL8()
+	return l8, clip, nil
+}
+
 // ImportAudio is a helper to handle audio file import. The callback is called with the loaded audio.
+// Should the converted audio have been clipped, a notice is shown after the callback.
 func ImportAudio(machine gui.ModalStateMachine, callback func(l8 audio.L8)) {
-	info := "File must be a WAV file, 22050 Hz, 8-bit or 16-bit, uncompressed."
+	info := "File must be an uncompressed WAV file.\n" +
+		"Samples can be 8, 16, 24, or 32 bit integer, or floating point.\n" +
+		"The audio is converted to mono, 8-bit, 22050 Hz."
 	types := []TypeInfo{{Title: "Audio files (*.wav)", Extensions: []string{"wav"}}}
 	var fileHandler func(string)
 
 	fileHandler = func(filename string) {
-		reader, err := os.Open(filename)
-		if err != nil {
-			Import(machine, info, types, fileHandler, true)
-			return
-		}
-		defer func() { _ = reader.Close() }()
-		sound, err := wav.Load(reader)
+		sound, clip, err := LoadAudio(filename)
 		if err != nil {
 			Import(machine, info, types, fileHandler, true)
 			return
 		}
 		callback(sound)
+		ClippingNotice(machine, clip, len(sound.Samples))
 	}
 
 	Import(machine, info, types, fileHandler, false)
 }
 
+// ClippingNotice shows a notice about clipped audio, should clipping have occurred.
+func ClippingNotice(machine gui.ModalStateMachine, clip audio.Clipping, sampleCount int) {
+	if !clip.Occurred() {
+		return
+	}
+	Notice(machine, "Audio Clipped",
+		fmt.Sprintf("%d of %d samples exceeded the range and were limited.\n"+
+			"The peak was at %.1f dB of full scale.\n"+
+			"Consider reducing the volume and importing again.",
+			clip.Samples, sampleCount, 20*math.Log10(float64(clip.Peak))))
+}
+
 // ImportImage is a helper to handle image file import. The callback is called with the loaded image.
 func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.Palette, error), callback func(bitmap.Bitmap)) {
 	info := "File should be either a PNG or a GIF file.\nPaletted images matching game palette are taken 1:1,\nothers are mapped closest fitting."
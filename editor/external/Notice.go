@@ -0,0 +1,12 @@
+package external
+
+import "github.com/inkyblackness/hacked/ui/gui"
+
+// Notice shows a modal dialog with given text, which the user has to acknowledge.
+func Notice(machine gui.ModalStateMachine, title string, text string) {
+	machine.SetState(&noticeStartState{
+		machine: machine,
+		title:   title,
+		text:    text,
+	})
+}
@@ -0,0 +1,25 @@
+package external
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type noticeStartState struct {
+	machine gui.ModalStateMachine
+	title   string
+	text    string
+}
+
+func (state noticeStartState) Render() {
+	imgui.OpenPopup(state.title)
+	state.machine.SetState(&noticeWaitingState{
+		machine: state.machine,
+		title:   state.title,
+		text:    state.text,
+	})
+}
+
+func (state noticeStartState) HandleFiles(names []string) {
+}
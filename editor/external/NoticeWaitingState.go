@@ -0,0 +1,31 @@
+package external
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type noticeWaitingState struct {
+	machine gui.ModalStateMachine
+	title   string
+	text    string
+}
+
+func (state *noticeWaitingState) Render() {
+	if imgui.BeginPopupModalV(state.title, nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		imgui.Text(state.text)
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.machine.SetState(nil)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *noticeWaitingState) HandleFiles(names []string) {
+}
@@ -0,0 +1,21 @@
+package external
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// UnencodableNotice informs that a text was rejected because the codepage of the language
+// can not represent the given characters.
+func UnencodableNotice(machine gui.ModalStateMachine, lang resource.Language, chars []rune) {
+	quoted := make([]string, len(chars))
+	for index, r := range chars {
+		quoted[index] = fmt.Sprintf("%q", r)
+	}
+	Notice(machine, "Unsupported Characters",
+		fmt.Sprintf("The codepage of language %v can not represent these characters:\n%s\n\nThe text was not changed.",
+			lang, strings.Join(quoted, ", ")))
+}
@@ -12,6 +12,8 @@ const (
 	ColorDisplayNone    ColorDisplay = 0
 	ColorDisplayFloor   ColorDisplay = 1
 	ColorDisplayCeiling ColorDisplay = 2
+	// ColorDisplayLighting combines the floor light of tiles with the light of emitting objects.
+	ColorDisplayLighting ColorDisplay = 3
 )
 
 // String returns a textual representation.
@@ -23,6 +25,8 @@ func (display ColorDisplay) String() string {
 		return "Floor"
 	case ColorDisplayCeiling:
 		return "Ceiling"
+	case ColorDisplayLighting:
+		return "Lighting"
 	default:
 		return fmt.Sprintf("Unknown%d", int(display))
 	}
@@ -32,3 +36,8 @@ func (display ColorDisplay) String() string {
 func ColorDisplays() []ColorDisplay {
 	return []ColorDisplay{ColorDisplayNone, ColorDisplayFloor, ColorDisplayCeiling}
 }
+
+// ShadowDisplays returns the ColorDisplay constants that apply to the shadows of real world tiles.
+func ShadowDisplays() []ColorDisplay {
+	return []ColorDisplay{ColorDisplayNone, ColorDisplayFloor, ColorDisplayCeiling, ColorDisplayLighting}
+}
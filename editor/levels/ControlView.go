@@ -10,8 +10,9 @@ import (
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/content/archive"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
-	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
 	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/heightscale"
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
@@ -30,11 +31,14 @@ type ControlView struct {
 	textCache    *text.Cache
 	textureCache *graphics.TextureCache
 
+	modalStateMachine gui.ModalStateMachine
+
 	model controlViewModel
 }
 
 // NewControlView returns a new instance.
-func NewControlView(mod *world.Mod, guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache,
+func NewControlView(mod *world.Mod, modalStateMachine gui.ModalStateMachine, guiScale float32,
+	textCache *text.Cache, textureCache *graphics.TextureCache,
 	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *ControlView {
 	view := &ControlView{
 		mod:           mod,
@@ -44,6 +48,8 @@ func NewControlView(mod *world.Mod, guiScale float32, textCache *text.Cache, tex
 		textCache:     textCache,
 		textureCache:  textureCache,
 		model:         freshControlViewModel(),
+
+		modalStateMachine: modalStateMachine,
 	}
 	eventRegistry.RegisterHandler(view.onLevelSelectionSetEvent)
 	view.setSelectedLevel(view.model.selectedLevel)
@@ -60,6 +66,15 @@ func (view *ControlView) SelectedLevel() int {
 	return view.model.selectedLevel
 }
 
+// SelectLevel changes the active level. Identifiers outside the valid range are ignored.
+func (view *ControlView) SelectLevel(id int) {
+	if (id < 0) || (id >= archive.MaxLevels) {
+		return
+	}
+	view.eventListener.Event(ObjectSelectionSetEvent{})
+	view.setSelectedLevel(id)
+}
+
 // Render renders the view.
 func (view *ControlView) Render(lvl *level.Level) {
 	if view.model.restoreFocus {
@@ -70,7 +85,7 @@ func (view *ControlView) Render(lvl *level.Level) {
 	if view.model.windowOpen {
 		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
 		title := "Level Control"
-		readOnly := !view.editingAllowed(lvl.ID())
+		readOnly := !levelEditingAllowed(view.mod, lvl.ID())
 		if readOnly {
 			title += hintReadOnly
 		}
@@ -96,8 +111,7 @@ func (view *ControlView) renderContent(lvl *level.Level, readOnly bool) {
 	imgui.PushItemWidth(-200 * view.guiScale)
 	selectedLevel := view.model.selectedLevel
 	if gui.StepSliderInt("Active Level", &selectedLevel, 0, archive.MaxLevels-1) {
-		view.eventListener.Event(ObjectSelectionSetEvent{})
-		view.setSelectedLevel(selectedLevel)
+		view.SelectLevel(selectedLevel)
 	}
 	imgui.Separator()
 	levelType := "Real World"
@@ -129,6 +143,63 @@ func (view *ControlView) renderLevelHeight(lvl *level.Level, readOnly bool) {
 		}
 		imgui.EndCombo()
 	}
+	if !readOnly {
+		view.renderRescale(lvl, int(currentShift))
+	}
+}
+
+func (view *ControlView) renderRescale(lvl *level.Level, currentShift int) {
+	if (view.model.rescaleLevel != lvl.ID()) || (view.model.rescaleFrom != currentShift) {
+		view.model.rescaleLevel = lvl.ID()
+		view.model.rescaleFrom = currentShift
+		view.model.rescaleShift = currentShift
+		view.model.rescaleIssues = nil
+		view.model.rescaleAdjusted = nil
+	}
+	if imgui.BeginCombo("Rescale Height", levelHeights[view.model.rescaleShift]) {
+		for shift, height := range levelHeights {
+			if imgui.SelectableV(height, shift == view.model.rescaleShift, 0, imgui.Vec2{}) {
+				view.model.rescaleShift = shift
+				view.model.rescaleAdjusted = nil
+				view.previewRescale(lvl)
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Changes the level height while keeping tiles and objects at their position in the world.")
+	}
+	if len(view.model.rescaleAdjusted) > 0 {
+		view.renderRescaleIssues("Rescale adjusted %d value(s):", view.model.rescaleAdjusted)
+	}
+	if view.model.rescaleShift == currentShift {
+		return
+	}
+	if view.model.rescaleChangeCount != view.mod.ChangeCount() {
+		view.previewRescale(lvl)
+	}
+	if len(view.model.rescaleIssues) > 0 {
+		view.renderRescaleIssues("%d value(s) can not be represented and will be adjusted:", view.model.rescaleIssues)
+	}
+	if imgui.Button("Rescale") {
+		view.requestRescaleHeight(lvl, level.HeightShift(view.model.rescaleShift))
+	}
+}
+
+// previewRescale determines the issues of the selected rescale for the current state of the level.
+func (view *ControlView) previewRescale(lvl *level.Level) {
+	view.model.rescaleIssues, _ = heightscale.Preview(lvl, level.HeightShift(view.model.rescaleShift))
+	view.model.rescaleChangeCount = view.mod.ChangeCount()
+}
+
+func (view *ControlView) renderRescaleIssues(format string, issues []heightscale.Issue) {
+	imgui.Text(fmt.Sprintf(format, len(issues)))
+	if imgui.BeginChildV("Rescale Issues", imgui.Vec2{X: -1, Y: 100 * view.guiScale}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		for _, issue := range issues {
+			imgui.Text(issue.String())
+		}
+	}
+	imgui.EndChild()
 }
 
 func (view *ControlView) textureName(index int) string {
@@ -221,9 +292,9 @@ func (view *ControlView) renderHazards(lvl *level.Level, readOnly bool) {
 	if readOnly {
 		imgui.LabelText("Ceiling Hazard", currentCeiling.title)
 	} else if imgui.BeginCombo("Ceiling Hazard", currentCeiling.title) {
-		for _, info := range ceilingHazards {
+		for index, info := range ceilingHazards {
 			if imgui.SelectableV(info.title, info.title == currentCeiling.title, 0, imgui.Vec2{}) {
-				view.requestSetCeilingHazard(lvl, info)
+				view.requestSetCeilingHazard(lvl, index)
 			}
 		}
 		imgui.EndCombo()
@@ -238,9 +309,9 @@ func (view *ControlView) renderHazards(lvl *level.Level, readOnly bool) {
 	if readOnly {
 		imgui.LabelText("Floor Hazard", currentFloor.title)
 	} else if imgui.BeginCombo("Floor Hazard", currentFloor.title) {
-		for _, info := range floorHazards {
+		for index, info := range floorHazards {
 			if imgui.SelectableV(info.title, info.title == currentFloor.title, 0, imgui.Vec2{}) {
-				view.requestSetFloorHazard(lvl, info)
+				view.requestSetFloorHazard(lvl, index)
 			}
 		}
 		imgui.EndCombo()
@@ -297,14 +368,6 @@ func (view *ControlView) renderTextureAnimations(lvl *level.Level, readOnly bool
 	}
 }
 
-func (view *ControlView) editingAllowed(id int) bool {
-	gameStateData := view.mod.ModifiedBlocks(resource.LangAny, ids.GameState)
-	isSavegame := (len(gameStateData) == 1) && (len(gameStateData[0]) == archive.GameStateSize) && (gameStateData[0][0x009C] > 0)
-	moddedLevel := len(view.mod.ModifiedBlocks(resource.LangAny, ids.LevelResourcesStart.Plus(lvlids.PerLevel*id+lvlids.FirstUsed))) > 0
-
-	return moddedLevel && !isSavegame
-}
-
 func (view *ControlView) renderSliderInt(readOnly bool, label string, selectedValue int,
 	formatter func(int) string, min, max int, changeHandler func(int)) {
 	selectedString := formatter(selectedValue)
@@ -317,107 +380,96 @@ func (view *ControlView) renderSliderInt(readOnly bool, label string, selectedVa
 }
 
 func (view *ControlView) requestSetZShift(lvl *level.Level, newValue int) {
-	lvl.SetHeightShift(level.HeightShift(newValue))
-	view.patchLevelResources(lvl, func() {})
+	view.changeLevelProperty(lvl, "Set height shift", macro.Step{Property: "height shift", Value: int64(newValue)}, func() {})
+}
+
+func (view *ControlView) requestRescaleHeight(lvl *level.Level, newShift level.HeightShift) {
+	adjusted, err := heightscale.Apply(lvl, newShift)
+	if err != nil {
+		return
+	}
+	view.model.rescaleFrom = int(newShift)
+	view.model.rescaleIssues = nil
+	view.model.rescaleAdjusted = adjusted
+	view.patchLevelResources(lvl, "Rescale height", func() {})
 }
 
 func (view *ControlView) requestSetLevelTexture(lvl *level.Level, atlasIndex, worldTextureIndex int) {
-	lvl.SetTextureAtlasEntry(atlasIndex, level.TextureIndex(worldTextureIndex))
-	view.patchLevelResources(lvl, func() {
-		view.model.selectedAtlasIndex = atlasIndex
-	})
+	view.changeLevelProperty(lvl, fmt.Sprintf("Set level texture %d", atlasIndex),
+		macro.Step{Property: "level texture", Index: atlasIndex, Value: int64(worldTextureIndex)}, func() {
+			view.model.selectedAtlasIndex = atlasIndex
+		})
 }
 
 func (view *ControlView) requestSetSurveillanceSource(lvl *level.Level, objectIndex int, objectID level.ObjectID) {
 	lvl.SetSurveillanceSource(objectIndex, objectID)
-	view.patchLevelResources(lvl, func() {
+	view.patchLevelResources(lvl, fmt.Sprintf("Set surveillance source %d", objectIndex), func() {
 		view.model.selectedSurveillanceObjectIndex = objectIndex
 	})
 }
 
 func (view *ControlView) requestSetSurveillanceSurrogate(lvl *level.Level, objectIndex int, objectID level.ObjectID) {
 	lvl.SetSurveillanceSurrogate(objectIndex, objectID)
-	view.patchLevelResources(lvl, func() {
+	view.patchLevelResources(lvl, fmt.Sprintf("Set surveillance surrogate %d", objectIndex), func() {
 		view.model.selectedSurveillanceObjectIndex = objectIndex
 	})
 }
 
-func (view *ControlView) requestSetCeilingHazard(lvl *level.Level, info ceilingHazardInfo) {
-	parameters := lvl.Parameters()
-	parameters.RadiationRegister = 0
-	if info.radiationRegister {
-		parameters.RadiationRegister = 2
-	}
-	view.patchLevelResources(lvl, func() {})
+func (view *ControlView) requestSetCeilingHazard(lvl *level.Level, hazardIndex int) {
+	view.changeLevelProperty(lvl, "Set ceiling hazard", macro.Step{Property: "ceiling hazard", Value: int64(hazardIndex)}, func() {})
 }
 
 func (view *ControlView) requestSetCeilingHazardLevel(lvl *level.Level, value byte) {
-	lvl.Parameters().CeilingHazardLevel = value
-	view.patchLevelResources(lvl, func() {})
+	view.changeLevelProperty(lvl, "Set ceiling hazard level", macro.Step{Property: "ceiling hazard level", Value: int64(value)}, func() {})
 }
 
-func (view *ControlView) requestSetFloorHazard(lvl *level.Level, info floorHazardInfo) {
-	parameters := lvl.Parameters()
-	parameters.BiohazardRegister = 0
-	parameters.FloorHazardIsGravity = 0
-	if info.isGravity {
-		parameters.FloorHazardIsGravity = 1
-	} else if info.biohazardRegister {
-		parameters.BiohazardRegister = 2
-	}
-	view.patchLevelResources(lvl, func() {})
+func (view *ControlView) requestSetFloorHazard(lvl *level.Level, hazardIndex int) {
+	view.changeLevelProperty(lvl, "Set floor hazard", macro.Step{Property: "floor hazard", Value: int64(hazardIndex)}, func() {})
 }
 
 func (view *ControlView) requestSetFloorHazardLevel(lvl *level.Level, value byte) {
-	lvl.Parameters().FloorHazardLevel = value
-	view.patchLevelResources(lvl, func() {})
+	view.changeLevelProperty(lvl, "Set floor hazard level", macro.Step{Property: "floor hazard level", Value: int64(value)}, func() {})
 }
 
 func (view *ControlView) requestSetTextureAnimationTime(lvl *level.Level, index int, value uint16) {
-	lvl.TextureAnimations()[index].FrameTime = value
-	view.patchLevelResources(lvl, func() {
-		view.model.selectedTextureAnimationIndex = index
-	})
+	view.changeLevelProperty(lvl, fmt.Sprintf("Set frame time of texture animation %d", index),
+		macro.Step{Property: "texture animation frame time", Index: index, Value: int64(value)}, func() {
+			view.model.selectedTextureAnimationIndex = index
+		})
 }
 
 func (view *ControlView) requestSetTextureAnimationFrameCount(lvl *level.Level, index int, value byte) {
-	lvl.TextureAnimations()[index].FrameCount = value
-	view.patchLevelResources(lvl, func() {
-		view.model.selectedTextureAnimationIndex = index
-	})
+	view.changeLevelProperty(lvl, fmt.Sprintf("Set frame count of texture animation %d", index),
+		macro.Step{Property: "texture animation frame count", Index: index, Value: int64(value)}, func() {
+			view.model.selectedTextureAnimationIndex = index
+		})
 }
 
 func (view *ControlView) requestSetTextureAnimationType(lvl *level.Level, index int, value level.TextureAnimationLoopType) {
-	lvl.TextureAnimations()[index].LoopType = value
-	view.patchLevelResources(lvl, func() {
-		view.model.selectedTextureAnimationIndex = index
-	})
+	view.changeLevelProperty(lvl, fmt.Sprintf("Set loop type of texture animation %d", index),
+		macro.Step{Property: "texture animation loop type", Index: index, Value: int64(value)}, func() {
+			view.model.selectedTextureAnimationIndex = index
+		})
 }
 
-func (view *ControlView) patchLevelResources(lvl *level.Level, extraRestoreState func()) {
-	command := patchLevelDataCommand{
-		restoreState: func(bool) {
-			view.model.restoreFocus = true
-			view.setSelectedLevel(lvl.ID())
-			extraRestoreState()
-		},
-	}
-
-	newDataSet := lvl.EncodeState()
-	for id, newData := range &newDataSet {
-		if len(newData) > 0 {
-			resourceID := ids.LevelResourcesStart.Plus(lvlids.PerLevel*lvl.ID() + id)
-			patch, changed, err := view.mod.CreateBlockPatch(resource.LangAny, resourceID, 0, newData)
-			if err != nil {
-				fmt.Printf("err: %v\n", err)
-				// TODO how to handle this? We're not expecting this, so crash and burn?
-			} else if changed {
-				command.patches = append(command.patches, patch)
-			}
-		}
-	}
+func (view *ControlView) changeLevelProperty(lvl *level.Level, description string, step macro.Step, extraRestoreState func()) {
+	step.Target = macro.TargetLevel
+	view.eventListener.Event(MacroStepRecordedEvent{step: step})
+	levelProperties[step.Property](lvl, step.Index, step.Value)
+	view.patchLevelResources(lvl, description, extraRestoreState)
+}
 
-	view.commander.Queue(command)
+func (view *ControlView) patchLevelResources(lvl *level.Level, description string, extraRestoreState func()) {
+	restoreState := func(bool) {
+		view.model.restoreFocus = true
+		view.setSelectedLevel(lvl.ID())
+		extraRestoreState()
+	}
+	title := fmt.Sprintf("%s on L%d", description, lvl.ID())
+	err := queueLevelPatch(view.mod, view.commander, lvl, title, "level/"+title, restoreState)
+	if err != nil {
+		notifyLevelPatchFailure(view.modalStateMachine, err)
+	}
 }
 
 func (view *ControlView) setSelectedLevel(id int) {
@@ -1,6 +1,9 @@
 package levels
 
-import "github.com/inkyblackness/hacked/ss1/world"
+import (
+	"github.com/inkyblackness/hacked/ss1/edit/heightscale"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
 
 type controlViewModel struct {
 	selectedLevel                   int
@@ -8,6 +11,13 @@ type controlViewModel struct {
 	selectedSurveillanceObjectIndex int
 	selectedTextureAnimationIndex   int
 
+	rescaleLevel       int
+	rescaleFrom        int
+	rescaleShift       int
+	rescaleIssues      []heightscale.Issue
+	rescaleChangeCount uint64
+	rescaleAdjusted    []heightscale.Issue
+
 	restoreFocus bool
 	windowOpen   bool
 }
@@ -16,5 +26,6 @@ func freshControlViewModel() controlViewModel {
 	return controlViewModel{
 		selectedLevel:                 world.StartingLevel,
 		selectedTextureAnimationIndex: 1,
+		rescaleLevel:                  -1,
 	}
 }
@@ -0,0 +1,267 @@
+package levels
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/mapimage"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// ImageImportView is for importing heights and the layout of a level map from images.
+type ImageImportView struct {
+	mod *world.Mod
+
+	modalStateMachine gui.ModalStateMachine
+	guiScale          float32
+	commander         cmd.Commander
+	eventListener     event.Listener
+
+	model imageImportViewModel
+}
+
+// NewImageImportView returns a new instance.
+func NewImageImportView(mod *world.Mod, modalStateMachine gui.ModalStateMachine, guiScale float32,
+	commander cmd.Commander, eventListener event.Listener) *ImageImportView {
+	view := &ImageImportView{
+		mod: mod,
+
+		modalStateMachine: modalStateMachine,
+		guiScale:          guiScale,
+		commander:         commander,
+		eventListener:     eventListener,
+		model:             freshImageImportViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *ImageImportView) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *ImageImportView) Render(lvl *level.Level) {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 500 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionOnce)
+		title := "Map Image Import"
+		readOnly := !levelEditingAllowed(view.mod, lvl.ID())
+		if readOnly {
+			title += hintReadOnly
+		}
+		if imgui.BeginV(title+"###Map Image Import", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent(lvl, readOnly)
+		}
+		imgui.End()
+	}
+}
+
+func (view *ImageImportView) renderContent(lvl *level.Level, readOnly bool) {
+	columns, rows, _ := lvl.Size()
+	imgui.Text(fmt.Sprintf("Images are scaled to the map size of %dx%d tiles. The top of an image is north.", columns, rows))
+	imgui.Separator()
+
+	view.renderImageEntry("Floor Heights", &view.model.floorImage,
+		"Brightness determines the floor height, black being the lowest.", nil)
+	view.renderImageEntry("Ceiling Heights", &view.model.ceilingImage,
+		"Brightness determines the ceiling height, black being the lowest.", nil)
+	view.renderImageEntry("Layout", &view.model.layoutImage,
+		"Image must have indexed colors.\nEach color is mapped to a tile type and textures.",
+		func(img image.Image) error {
+			layout, err := mapimage.LayoutFrom(img, columns, rows)
+			if err != nil {
+				return err
+			}
+			view.model.layoutPalette = layout.Palette
+			view.model.slotIndices = layout.UsedIndices()
+			view.model.slots = layout.DefaultSlots()
+			return nil
+		})
+	if view.model.layoutImage != nil {
+		view.renderSlots(lvl)
+	}
+
+	imgui.Separator()
+	if readOnly {
+		imgui.Text("The level can not be modified.")
+	} else if view.hasImages() {
+		view.renderClamped(lvl)
+		if imgui.Button("Import") {
+			view.requestImport(lvl)
+		}
+	}
+}
+
+func (view *ImageImportView) renderClamped(lvl *level.Level) {
+	if (view.model.floorImage == nil) && (view.model.ceilingImage == nil) {
+		return
+	}
+	const maxListed = 8
+	columns, rows, _ := lvl.Size()
+	clamped := view.sampledImport(columns, rows).Clamped(lvl, columns, rows)
+	if len(clamped) == 0 {
+		return
+	}
+	var listed []string
+	for index, pos := range clamped {
+		if index >= maxListed {
+			listed = append(listed, "...")
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%d/%d", pos.X, pos.Y))
+	}
+	imgui.Text(fmt.Sprintf("%d tile(s) would have the floor above the ceiling.\n"+
+		"Their imported height is clamped:", len(clamped)))
+	imgui.Text(strings.Join(listed, ", "))
+}
+
+func (view *ImageImportView) renderImageEntry(label string, img *image.Image, info string, loaded func(image.Image) error) {
+	imgui.PushID(label)
+	state := "none"
+	if *img != nil {
+		bounds := (*img).Bounds()
+		state = fmt.Sprintf("%dx%d", bounds.Dx(), bounds.Dy())
+	}
+	imgui.Text(fmt.Sprintf("%s: %s", label, state))
+	imgui.SameLine()
+	if imgui.Button("Load...") {
+		view.requestLoadImage(info, img, loaded)
+	}
+	if *img != nil {
+		imgui.SameLine()
+		if imgui.Button("Clear") {
+			*img = nil
+		}
+	}
+	imgui.PopID()
+}
+
+func (view *ImageImportView) renderSlots(lvl *level.Level) {
+	atlasSize := len(lvl.TextureAtlas())
+	floorCeilingMax := atlasSize - 1
+	if floorCeilingMax >= level.FloorCeilingTextureLimit {
+		floorCeilingMax = level.FloorCeilingTextureLimit - 1
+	}
+	wallMax := atlasSize - 1
+	if wallMax >= 64 {
+		wallMax = 63
+	}
+	textureSlider := func(label string, value *int, max int) {
+		format := "%d"
+		if *value < 0 {
+			format = "Keep"
+		}
+		gui.StepSliderIntV(label, value, -1, max, format)
+	}
+
+	imgui.PushItemWidth(-150 * view.guiScale)
+	for _, index := range view.model.slotIndices {
+		slot := view.model.slots[index]
+		label := fmt.Sprintf("Color %d", index)
+		if int(index) < len(view.model.layoutPalette) {
+			r, g, b, _ := view.model.layoutPalette[index].RGBA()
+			label += fmt.Sprintf(" (#%02X%02X%02X)", r>>8, g>>8, b>>8)
+		}
+		if imgui.TreeNodeV(label+fmt.Sprintf("###%d", index), imgui.TreeNodeFlagsFramed) {
+			if imgui.BeginCombo("Tile Type", slot.Type.String()) {
+				for _, tileType := range level.TileTypes() {
+					if imgui.SelectableV(tileType.String(), tileType == slot.Type, 0, imgui.Vec2{}) {
+						slot.Type = tileType
+					}
+				}
+				imgui.EndCombo()
+			}
+			textureSlider("Floor Texture", &slot.FloorTexture, floorCeilingMax)
+			textureSlider("Ceiling Texture", &slot.CeilingTexture, floorCeilingMax)
+			textureSlider("Wall Texture", &slot.WallTexture, wallMax)
+			imgui.TreePop()
+		}
+		view.model.slots[index] = slot
+	}
+	imgui.PopItemWidth()
+}
+
+func (view *ImageImportView) requestLoadImage(info string, target *image.Image, loaded func(image.Image) error) {
+	info = "File should be either a PNG or a GIF file.\n" + info
+	types := []external.TypeInfo{{Title: "Image files (*.gif, *.png)", Extensions: []string{"png", "gif"}}}
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		reader, err := os.Open(filename)
+		if err != nil {
+			external.Import(view.modalStateMachine, "Could not open file.\n"+info, types, fileHandler, true)
+			return
+		}
+		defer func() { _ = reader.Close() }()
+		img, _, err := image.Decode(reader)
+		if err != nil {
+			external.Import(view.modalStateMachine, "File not recognized as image.\n"+info, types, fileHandler, true)
+			return
+		}
+		if loaded != nil {
+			err = loaded(img)
+			if err != nil {
+				external.Import(view.modalStateMachine, fmt.Sprintf("Image not supported: %v.\n", err)+info, types, fileHandler, true)
+				return
+			}
+		}
+		*target = img
+	}
+
+	external.Import(view.modalStateMachine, info, types, fileHandler, false)
+}
+
+func (view *ImageImportView) hasImages() bool {
+	return (view.model.floorImage != nil) || (view.model.ceilingImage != nil) || (view.model.layoutImage != nil)
+}
+
+func (view *ImageImportView) sampledImport(columns, rows int) mapimage.Import {
+	var imp mapimage.Import
+	if view.model.floorImage != nil {
+		imp.Floor = mapimage.HeightsFrom(view.model.floorImage, columns, rows)
+	}
+	if view.model.ceilingImage != nil {
+		imp.Ceiling = mapimage.HeightsFrom(view.model.ceilingImage, columns, rows)
+	}
+	if view.model.layoutImage != nil {
+		layout, err := mapimage.LayoutFrom(view.model.layoutImage, columns, rows)
+		if err == nil {
+			imp.Layout = layout
+			imp.Slots = view.model.slots
+		}
+	}
+	return imp
+}
+
+func (view *ImageImportView) requestImport(lvl *level.Level) {
+	columns, rows, _ := lvl.Size()
+	imp := view.sampledImport(columns, rows)
+	if imp.Empty() {
+		return
+	}
+	imp.Apply(lvl, columns, rows)
+	lvl.RecalculateWallHeights()
+
+	err := queueLevelPatch(view.mod, view.commander, lvl, fmt.Sprintf("Import map images on L%d", lvl.ID()), "",
+		func(bool) {
+			view.model.restoreFocus = true
+			view.eventListener.Event(LevelSelectionSetEvent{id: lvl.ID()})
+		})
+	if err != nil {
+		notifyLevelPatchFailure(view.modalStateMachine, err)
+	}
+}
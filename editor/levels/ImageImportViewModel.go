@@ -0,0 +1,25 @@
+package levels
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/inkyblackness/hacked/ss1/edit/mapimage"
+)
+
+type imageImportViewModel struct {
+	floorImage   image.Image
+	ceilingImage image.Image
+	layoutImage  image.Image
+
+	layoutPalette color.Palette
+	slotIndices   []uint8
+	slots         map[uint8]mapimage.Slot
+
+	restoreFocus bool
+	windowOpen   bool
+}
+
+func freshImageImportViewModel() imageImportViewModel {
+	return imageImportViewModel{}
+}
@@ -0,0 +1,64 @@
+package levels
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// levelEditingAllowed returns true if the level with given ID is part of the mod, and the mod is not a savegame.
+func levelEditingAllowed(mod *world.Mod, id int) bool {
+	gameStateData := mod.ModifiedBlocks(resource.LangAny, ids.GameState)
+	isSavegame := (len(gameStateData) == 1) && (len(gameStateData[0]) == archive.GameStateSize) && (gameStateData[0][0x009C] > 0)
+	moddedLevel := len(mod.ModifiedBlocks(resource.LangAny, ids.LevelResourcesStart.Plus(lvlids.PerLevel*id+lvlids.FirstUsed))) > 0
+
+	return moddedLevel && !isSavegame
+}
+
+// queueLevelPatch queues a command that patches the resources of the level to its current state.
+// The command is named with given title, and merged with previous commands of the same non-empty group.
+// If the patch can not be created, nothing is queued, and the level is reloaded to discard its changes.
+func queueLevelPatch(mod *world.Mod, commander cmd.Commander, lvl *level.Level, title string, group string,
+	restoreState stateRestorer) error {
+	command := patchLevelDataCommand{restoreState: restoreState}
+
+	newDataSet := lvl.EncodeState()
+	for id, newData := range &newDataSet {
+		if len(newData) == 0 {
+			continue
+		}
+		resourceID := ids.LevelResourcesStart.Plus(lvlids.PerLevel*lvl.ID() + id)
+		patch, changed, err := mod.CreateBlockPatch(resource.LangAny, resourceID, 0, newData)
+		if err != nil {
+			lvl.InvalidateResources(allLevelResources(lvl.ID()))
+			return err
+		}
+		if changed {
+			command.patches = append(command.patches, patch)
+		}
+	}
+
+	commander.Queue(cmd.Named{Command: command, Title: title, Group: group})
+	return nil
+}
+
+func allLevelResources(levelID int) []resource.ID {
+	resources := make([]resource.ID, 0, lvlids.PerLevel)
+	for id := 0; id < lvlids.PerLevel; id++ {
+		resources = append(resources, ids.LevelResourcesStart.Plus(lvlids.PerLevel*levelID+id))
+	}
+	return resources
+}
+
+// notifyLevelPatchFailure tells the user that a change of a level could not be applied.
+func notifyLevelPatchFailure(machine gui.ModalStateMachine, err error) {
+	external.Notice(machine, "Level", fmt.Sprintf("The level could not be changed:\n%v", err))
+}
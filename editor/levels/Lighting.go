@@ -0,0 +1,114 @@
+package levels
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+	"github.com/inkyblackness/hacked/ss1/edit/lightbake"
+)
+
+const emitLightFlag = 0x01
+
+// LightingSettingsSetEvent notifies about the current settings for light-emitting objects.
+type LightingSettingsSetEvent struct {
+	radius    int
+	intensity int
+}
+
+func defaultLightingSettings() LightingSettingsSetEvent {
+	return LightingSettingsSetEvent{radius: 6, intensity: 10}
+}
+
+// lightSources returns the sources of all light-emitting objects in the level.
+func lightSources(properties object.PropertiesTable, lvl *level.Level, settings LightingSettingsSetEvent) []lightbake.Source {
+	var sources []lightbake.Source
+	lvl.ForEachObject(func(id level.ObjectID, entry level.ObjectMasterEntry) {
+		if !emitsLight(properties, entry.Triple()) {
+			return
+		}
+		sources = append(sources, lightbake.Source{
+			X:         float64(entry.X.Tile()) + float64(entry.X.Fine())/fineCoordinatesPerTileSide,
+			Y:         float64(entry.Y.Tile()) + float64(entry.Y.Fine())/fineCoordinatesPerTileSide,
+			Intensity: float64(settings.intensity) / lightbake.MaxLevel,
+			Radius:    float64(settings.radius),
+		})
+	})
+	return sources
+}
+
+func emitsLight(properties object.PropertiesTable, triple object.Triple) bool {
+	if (triple.Class != object.ClassPhysics) && (triple.Class != object.ClassAnimating) {
+		return false
+	}
+	prop, err := properties.ForObject(triple)
+	if err != nil {
+		return false
+	}
+	generic := objprop.GenericProperties(triple.Class, prop.Generic)
+	return (generic.Get("Flags") & emitLightFlag) != 0
+}
+
+// lightBlocking returns a function reporting the tiles of the level that block light.
+func lightBlocking(lvl *level.Level) func(x, y int) bool {
+	return func(x, y int) bool {
+		tile := lvl.Tile(x, y)
+		return (tile == nil) || (tile.Type == level.TileTypeSolid)
+	}
+}
+
+// lightingCache keeps the brightness that the light-emitting objects cast on a level.
+// Determining the sources and the blocking tiles is cheap compared to illuminating the map,
+// so the brightness is only computed again if either of them differs from the previous request.
+type lightingCache struct {
+	lvl        *level.Level
+	sources    []lightbake.Source
+	blocking   []bool
+	brightness lightbake.Brightness
+}
+
+// illuminate returns the brightness that the light-emitting objects cast on the level.
+func (cache *lightingCache) illuminate(properties object.PropertiesTable, lvl *level.Level,
+	settings LightingSettingsSetEvent) lightbake.Brightness {
+	columns, rows, _ := lvl.Size()
+	isBlocking := lightBlocking(lvl)
+	blocking := make([]bool, 0, columns*rows)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			blocking = append(blocking, isBlocking(x, y))
+		}
+	}
+	sources := lightSources(properties, lvl, settings)
+	if (cache.brightness != nil) && (cache.lvl == lvl) &&
+		sameSources(cache.sources, sources) && sameBlocking(cache.blocking, blocking) {
+		return cache.brightness
+	}
+	cache.lvl = lvl
+	cache.sources = sources
+	cache.blocking = blocking
+	cache.brightness = lightbake.Illuminate(columns, rows, isBlocking, sources)
+	return cache.brightness
+}
+
+func sameSources(a, b []lightbake.Source) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if a[index] != b[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameBlocking(a, b []bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if a[index] != b[index] {
+			return false
+		}
+	}
+	return true
+}
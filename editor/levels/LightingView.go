@@ -0,0 +1,167 @@
+package levels
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/lightbake"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// LightingView is for previewing the light of emitting objects and baking it into the tiles.
+type LightingView struct {
+	mod       *world.Mod
+	tilesView *TilesView
+
+	guiScale      float32
+	eventListener event.Listener
+
+	model lightingViewModel
+}
+
+// NewLightingView returns a new instance.
+func NewLightingView(mod *world.Mod, tilesView *TilesView, guiScale float32,
+	eventListener event.Listener, eventRegistry event.Registry) *LightingView {
+	view := &LightingView{
+		mod:       mod,
+		tilesView: tilesView,
+
+		guiScale:      guiScale,
+		eventListener: eventListener,
+		model:         freshLightingViewModel(),
+	}
+	view.model.selectedTiles.registerAt(eventRegistry)
+	view.notifySettings()
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *LightingView) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *LightingView) Render(lvl *level.Level) {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		title := "Tile Lighting"
+		readOnly := !levelEditingAllowed(view.mod, lvl.ID())
+		if readOnly {
+			title += hintReadOnly
+		}
+		if imgui.BeginV(title+"###Tile Lighting", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent(lvl, readOnly)
+		}
+		imgui.End()
+	}
+}
+
+func (view *LightingView) renderContent(lvl *level.Level, readOnly bool) {
+	if lvl.IsCyberspace() {
+		imgui.Text("Cyberspace has no lighting.")
+		return
+	}
+	sources := lightSources(view.mod.ObjectProperties(), lvl, view.settings())
+	imgui.Text(fmt.Sprintf("Light-emitting objects: %d", len(sources)))
+	imgui.Text("Set the Shadow View of the tiles to \"Lighting\" for a preview.")
+
+	imgui.PushItemWidth(-150 * view.guiScale)
+	if gui.StepSliderInt("Light Radius", &view.model.radius, 1, 16) {
+		view.notifySettings()
+	}
+	if gui.StepSliderInt("Light Intensity", &view.model.intensity, 1, lightbake.MaxLevel) {
+		view.notifySettings()
+	}
+
+	imgui.Separator()
+	gui.StepSliderInt("Ambient Light", &view.model.ambient, 0, lightbake.MaxLevel)
+	imgui.Checkbox("Floor", &view.model.floor)
+	imgui.SameLine()
+	imgui.Checkbox("Ceiling", &view.model.ceiling)
+	imgui.Checkbox("Selected Tiles Only", &view.model.selectedOnly)
+	imgui.PopItemWidth()
+
+	if readOnly {
+		imgui.Text("The level can not be modified.")
+		return
+	}
+	positions := view.bakePositions(lvl)
+	if (len(positions) == 0) || (!view.model.floor && !view.model.ceiling) {
+		return
+	}
+	if imgui.Button("Bake") {
+		view.bake(lvl, positions, sources)
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip(fmt.Sprintf("Sets the light of %d tile(s), starting from the ambient light.", len(positions)))
+	}
+}
+
+func (view *LightingView) settings() LightingSettingsSetEvent {
+	return LightingSettingsSetEvent{
+		radius:    view.model.radius,
+		intensity: view.model.intensity,
+	}
+}
+
+func (view *LightingView) notifySettings() {
+	view.eventListener.Event(view.settings())
+}
+
+// bakePositions returns the open tiles that receive light.
+func (view *LightingView) bakePositions(lvl *level.Level) []MapPosition {
+	var positions []MapPosition
+	blocking := lightBlocking(lvl)
+	if view.model.selectedOnly {
+		for _, pos := range view.model.selectedTiles.list {
+			if !blocking(int(pos.X.Tile()), int(pos.Y.Tile())) {
+				positions = append(positions, pos)
+			}
+		}
+		return positions
+	}
+	columns, rows, _ := lvl.Size()
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			if !blocking(x, y) {
+				positions = append(positions, MapPosition{
+					X: level.CoordinateAt(byte(x), fineCoordinatesPerTileSide/2),
+					Y: level.CoordinateAt(byte(y), fineCoordinatesPerTileSide/2),
+				})
+			}
+		}
+	}
+	return positions
+}
+
+func (view *LightingView) bake(lvl *level.Level, positions []MapPosition, sources []lightbake.Source) {
+	columns, rows, _ := lvl.Size()
+	brightness := lightbake.Illuminate(columns, rows, lightBlocking(lvl), sources)
+	shadows := make(map[*level.TileMapEntry]int)
+	for _, pos := range positions {
+		x, y := int(pos.X.Tile()), int(pos.Y.Tile())
+		shadows[lvl.Tile(x, y)] = lightbake.MaxLevel - brightness.LevelAt(x, y, view.model.ambient)
+	}
+	floor := view.model.floor
+	ceiling := view.model.ceiling
+	view.tilesView.changeTiles(lvl, positions, "baked light", func(tile *level.TileMapEntry) {
+		shadow := shadows[tile]
+		flags := tile.Flags.ForRealWorld()
+		if floor {
+			flags = flags.WithFloorShadow(shadow)
+		}
+		if ceiling {
+			flags = flags.WithCeilingShadow(shadow)
+		}
+		tile.Flags = flags.AsTileFlag()
+	})
+}
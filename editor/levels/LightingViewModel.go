@@ -0,0 +1,27 @@
+package levels
+
+type lightingViewModel struct {
+	selectedTiles tileCoordinates
+
+	radius    int
+	intensity int
+	ambient   int
+
+	floor        bool
+	ceiling      bool
+	selectedOnly bool
+
+	restoreFocus bool
+	windowOpen   bool
+}
+
+func freshLightingViewModel() lightingViewModel {
+	settings := defaultLightingSettings()
+	return lightingViewModel{
+		radius:    settings.radius,
+		intensity: settings.intensity,
+		ambient:   2,
+		floor:     true,
+		ceiling:   true,
+	}
+}
@@ -0,0 +1,196 @@
+package levels
+
+import (
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
+)
+
+// MacroStepRecordedEvent notifies about an operation that can be recorded for a macro.
+type MacroStepRecordedEvent struct {
+	step macro.Step
+}
+
+func boolValue(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// tileProperties are the properties of tiles that can be set by macros.
+var tileProperties = map[string]func(tile *level.TileMapEntry, value int64){
+	"tile type": func(tile *level.TileMapEntry, value int64) { tile.Type = level.TileType(value) },
+	"floor height": func(tile *level.TileMapEntry, value int64) {
+		tile.Floor = tile.Floor.WithAbsoluteHeight(level.TileHeightUnit(value))
+	},
+	"ceiling height": func(tile *level.TileMapEntry, value int64) {
+		tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(level.TileHeightUnit(value))
+	},
+	"slope height": func(tile *level.TileMapEntry, value int64) { tile.SlopeHeight = level.TileHeightUnit(value) },
+	"slope control": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.WithSlopeControl(level.TileSlopeControl(value))
+	},
+	"music index": func(tile *level.TileMapEntry, value int64) { tile.Flags = tile.Flags.WithMusicIndex(int(value)) },
+	"floor texture": func(tile *level.TileMapEntry, value int64) {
+		tile.TextureInfo = tile.TextureInfo.WithFloorTextureIndex(int(value))
+	},
+	"floor texture rotations": func(tile *level.TileMapEntry, value int64) { tile.Floor = tile.Floor.WithTextureRotations(int(value)) },
+	"ceiling texture": func(tile *level.TileMapEntry, value int64) {
+		tile.TextureInfo = tile.TextureInfo.WithCeilingTextureIndex(int(value))
+	},
+	"ceiling texture rotations": func(tile *level.TileMapEntry, value int64) {
+		tile.Ceiling = tile.Ceiling.WithTextureRotations(int(value))
+	},
+	"wall texture": func(tile *level.TileMapEntry, value int64) {
+		tile.TextureInfo = tile.TextureInfo.WithWallTextureIndex(int(value))
+	},
+	"wall texture offset": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForRealWorld().WithWallTextureOffset(level.TileHeightUnit(value)).AsTileFlag()
+	},
+	"adjacent wall texture usage": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForRealWorld().WithUseAdjacentWallTexture(value != 0).AsTileFlag()
+	},
+	"wall texture pattern": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForRealWorld().WithWallTexturePattern(level.WallTexturePattern(value)).AsTileFlag()
+	},
+	"floor light": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForRealWorld().WithFloorShadow(15 - int(value)).AsTileFlag()
+	},
+	"ceiling light": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForRealWorld().WithCeilingShadow(15 - int(value)).AsTileFlag()
+	},
+	"deconstructed flag": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForRealWorld().WithDeconstructed(value != 0).AsTileFlag()
+	},
+	"floor hazard":   func(tile *level.TileMapEntry, value int64) { tile.Floor = tile.Floor.WithHazard(value != 0) },
+	"ceiling hazard": func(tile *level.TileMapEntry, value int64) { tile.Ceiling = tile.Ceiling.WithHazard(value != 0) },
+	"floor palette index": func(tile *level.TileMapEntry, value int64) {
+		tile.TextureInfo = tile.TextureInfo.WithFloorPaletteIndex(byte(value))
+	},
+	"ceiling palette index": func(tile *level.TileMapEntry, value int64) {
+		tile.TextureInfo = tile.TextureInfo.WithCeilingPaletteIndex(byte(value))
+	},
+	"flight pull type": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForCyberspace().WithFlightPull(level.CyberspaceFlightPull(value)).AsTileFlag()
+	},
+	"game of life state": func(tile *level.TileMapEntry, value int64) {
+		tile.Flags = tile.Flags.ForCyberspace().WithGameOfLifeState(int(value)).AsTileFlag()
+	},
+}
+
+// Prefixes of the object properties that are described by interpreters.
+const (
+	objectExtraPrefix = "extra."
+	objectClassPrefix = "class."
+)
+
+// objectPlacementProperty sets the height of objects relative to their tile.
+// The value is one of the placement constants.
+const objectPlacementProperty = "placement"
+
+// Placements of objects.
+const (
+	placementFloor int64 = iota
+	placementEyeLevel
+	placementCeiling
+)
+
+// objectBaseProperties are the base properties of objects that can be set by macros.
+// The position on the map is not part of it, as macros are relative to the selection.
+var objectBaseProperties = map[string]func(entry *level.ObjectMasterEntry, value int64){
+	"Z": func(entry *level.ObjectMasterEntry, value int64) { entry.Z = level.HeightUnit(value) },
+	"Fine X": func(entry *level.ObjectMasterEntry, value int64) {
+		entry.X = level.CoordinateAt(entry.X.Tile(), byte(value))
+	},
+	"Fine Y": func(entry *level.ObjectMasterEntry, value int64) {
+		entry.Y = level.CoordinateAt(entry.Y.Tile(), byte(value))
+	},
+	"Rotation X": func(entry *level.ObjectMasterEntry, value int64) { entry.XRotation = level.RotationUnit(value) },
+	"Rotation Y": func(entry *level.ObjectMasterEntry, value int64) { entry.YRotation = level.RotationUnit(value) },
+	"Rotation Z": func(entry *level.ObjectMasterEntry, value int64) { entry.ZRotation = level.RotationUnit(value) },
+	"Hitpoints":  func(entry *level.ObjectMasterEntry, value int64) { entry.Hitpoints = int16(value) },
+}
+
+// setInterpretedObjectProperty modifies the property of the given key, which is a path through the refinements.
+// Objects that do not have the property are not changed.
+func setInterpretedObjectProperty(lvl *level.Level, id level.ObjectID, key string, modifier func(uint32) uint32) {
+	obj := lvl.Object(id)
+	if obj == nil {
+		return
+	}
+	var data []byte
+	interpreterFactory := lvlobj.RealWorldExtra
+	if strings.HasPrefix(key, objectExtraPrefix) {
+		data = obj.Extra[:]
+		if lvl.IsCyberspace() {
+			interpreterFactory = lvlobj.CyberspaceExtra
+		}
+	} else if strings.HasPrefix(key, objectClassPrefix) {
+		data = lvl.ObjectClassData(id)
+		interpreterFactory = lvlobj.ForRealWorld
+		if lvl.IsCyberspace() {
+			interpreterFactory = lvlobj.ForCyberspace
+		}
+	} else {
+		return
+	}
+	subKeys := strings.Split(key, ".")[1:]
+	interpreter := interpreterFactory(obj.Triple(), data)
+	for _, subKey := range subKeys[:len(subKeys)-1] {
+		interpreter = interpreter.Refined(subKey)
+	}
+	valueKey := subKeys[len(subKeys)-1]
+	interpreter.Set(valueKey, modifier(interpreter.Get(valueKey)))
+}
+
+// levelProperties are the properties of a level that can be set by macros.
+var levelProperties = map[string]func(lvl *level.Level, index int, value int64){
+	"height shift": func(lvl *level.Level, index int, value int64) { lvl.SetHeightShift(level.HeightShift(value)) },
+	"level texture": func(lvl *level.Level, index int, value int64) {
+		lvl.SetTextureAtlasEntry(index, level.TextureIndex(value))
+	},
+	"ceiling hazard": func(lvl *level.Level, index int, value int64) {
+		if (value < 0) || (value >= int64(len(ceilingHazards))) {
+			return
+		}
+		parameters := lvl.Parameters()
+		parameters.RadiationRegister = 0
+		if ceilingHazards[value].radiationRegister {
+			parameters.RadiationRegister = 2
+		}
+	},
+	"ceiling hazard level": func(lvl *level.Level, index int, value int64) { lvl.Parameters().CeilingHazardLevel = byte(value) },
+	"floor hazard": func(lvl *level.Level, index int, value int64) {
+		if (value < 0) || (value >= int64(len(floorHazards))) {
+			return
+		}
+		info := floorHazards[value]
+		parameters := lvl.Parameters()
+		parameters.BiohazardRegister = 0
+		parameters.FloorHazardIsGravity = 0
+		if info.isGravity {
+			parameters.FloorHazardIsGravity = 1
+		} else if info.biohazardRegister {
+			parameters.BiohazardRegister = 2
+		}
+	},
+	"floor hazard level": func(lvl *level.Level, index int, value int64) { lvl.Parameters().FloorHazardLevel = byte(value) },
+	"texture animation frame time": func(lvl *level.Level, index int, value int64) {
+		if animations := lvl.TextureAnimations(); (index >= 0) && (index < len(animations)) {
+			animations[index].FrameTime = uint16(value)
+		}
+	},
+	"texture animation frame count": func(lvl *level.Level, index int, value int64) {
+		if animations := lvl.TextureAnimations(); (index >= 0) && (index < len(animations)) {
+			animations[index].FrameCount = byte(value)
+		}
+	},
+	"texture animation loop type": func(lvl *level.Level, index int, value int64) {
+		if animations := lvl.TextureAnimations(); (index >= 0) && (index < len(animations)) {
+			animations[index].LoopType = level.TextureAnimationLoopType(value)
+		}
+	},
+}
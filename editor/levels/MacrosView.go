@@ -0,0 +1,279 @@
+package levels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/editor/values"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// MacrosView is for recording and replaying sequences of level editing operations.
+type MacrosView struct {
+	mod         *world.Mod
+	objectsView *ObjectsView
+
+	guiScale      float32
+	commander     cmd.Commander
+	eventListener event.Listener
+
+	modalStateMachine gui.ModalStateMachine
+
+	model macrosViewModel
+}
+
+// NewMacrosView returns a new instance.
+func NewMacrosView(mod *world.Mod, modalStateMachine gui.ModalStateMachine, guiScale float32, objectsView *ObjectsView,
+	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *MacrosView {
+	view := &MacrosView{
+		mod:         mod,
+		objectsView: objectsView,
+
+		guiScale:      guiScale,
+		commander:     commander,
+		eventListener: eventListener,
+		model:         freshMacrosViewModel(),
+
+		modalStateMachine: modalStateMachine,
+	}
+	view.model.selectedTiles.registerAt(eventRegistry)
+	view.model.selectedObjects.registerAt(eventRegistry)
+	eventRegistry.RegisterHandler(view.onMacroStepRecordedEvent)
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *MacrosView) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Macros returns the currently known macros.
+func (view *MacrosView) Macros() []macro.Macro {
+	return view.model.macros
+}
+
+// SetMacros replaces the known macros.
+func (view *MacrosView) SetMacros(macros []macro.Macro) {
+	view.model.macros = macros
+	view.selectMacro(-1)
+}
+
+// Render renders the view.
+func (view *MacrosView) Render(lvl *level.Level) {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionOnce)
+		title := "Level Macros"
+		readOnly := !levelEditingAllowed(view.mod, lvl.ID())
+		if readOnly {
+			title += hintReadOnly
+		}
+		if imgui.BeginV(title+"###Level Macros", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent(lvl, readOnly)
+		}
+		imgui.End()
+	}
+}
+
+func (view *MacrosView) renderContent(lvl *level.Level, readOnly bool) {
+	view.renderRecording()
+	imgui.Separator()
+
+	if imgui.BeginChildV("Macros", imgui.Vec2{X: -1, Y: 150 * view.guiScale}, true, 0) {
+		for index, entry := range view.model.macros {
+			label := fmt.Sprintf("%s (%d step(s))###%d", entry.Name, len(entry.Steps), index)
+			if imgui.SelectableV(label, index == view.model.selectedMacroIndex, 0, imgui.Vec2{}) {
+				view.selectMacro(index)
+			}
+		}
+	}
+	imgui.EndChild()
+
+	if (view.model.selectedMacroIndex < 0) || (view.model.selectedMacroIndex >= len(view.model.macros)) {
+		return
+	}
+	selected := view.model.macros[view.model.selectedMacroIndex]
+	targets := selected.Targets()
+	imgui.Text(fmt.Sprintf("Selected: %d tile(s), %d object(s)",
+		len(view.model.selectedTiles.list), len(view.model.selectedObjects.list)))
+	view.renderArguments(selected)
+	args, argsErr := view.arguments()
+	if argsErr != nil {
+		imgui.Text(fmt.Sprintf("Invalid argument: %v", argsErr))
+	} else if !readOnly && !view.model.recorder.Active() {
+		if imgui.Button("Replay") {
+			view.requestReplay(lvl, selected, args)
+		}
+		imgui.SameLine()
+	}
+	if imgui.Button("Delete") {
+		view.deleteMacro(view.model.selectedMacroIndex)
+		return
+	}
+	if targets[macro.TargetTiles] && (len(view.model.selectedTiles.list) == 0) {
+		imgui.Text("Note: The macro changes tiles, yet none are selected.")
+	}
+	if targets[macro.TargetObjects] && (len(view.model.selectedObjects.list) == 0) {
+		imgui.Text("Note: The macro changes objects, yet none are selected.")
+	}
+	if imgui.BeginChildV("Steps", imgui.Vec2{X: -1, Y: -1}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		steps := view.model.macros[view.model.selectedMacroIndex].Steps
+		for index := range steps {
+			view.renderStep(&steps[index], index)
+		}
+	}
+	imgui.EndChild()
+}
+
+func (view *MacrosView) renderArguments(selected macro.Macro) {
+	for _, name := range selected.Parameters() {
+		text := view.model.argumentTexts[name]
+		if imgui.InputText("$"+name, &text) {
+			view.model.argumentTexts[name] = text
+		}
+	}
+}
+
+// arguments returns the entered arguments of the selected macro.
+// Parameters without an entered value keep their recorded value.
+func (view *MacrosView) arguments() (macro.Arguments, error) {
+	args := make(macro.Arguments)
+	for name, text := range view.model.argumentTexts {
+		trimmed := strings.TrimSpace(text)
+		if len(trimmed) == 0 {
+			continue
+		}
+		value, err := strconv.ParseInt(trimmed, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("$%s: %v", name, err)
+		}
+		args[name] = value
+	}
+	return args, nil
+}
+
+func (view *MacrosView) renderStep(step *macro.Step, index int) {
+	if !imgui.TreeNode(step.String() + fmt.Sprintf("###%d", index)) {
+		return
+	}
+	intIdentity := func(u values.Unifier) int { return u.Unified().(int) }
+	intFormat := func(value int) string { return "%+d" }
+	if step.Target == macro.TargetTiles {
+		offsetXUnifier := values.NewUnifier()
+		offsetXUnifier.Add(step.OffsetX)
+		values.RenderUnifiedSliderInt(false, false, "Offset X", offsetXUnifier, intIdentity, intFormat, -63, 63,
+			func(newValue int) { step.OffsetX = newValue })
+		offsetYUnifier := values.NewUnifier()
+		offsetYUnifier.Add(step.OffsetY)
+		values.RenderUnifiedSliderInt(false, false, "Offset Y", offsetYUnifier, intIdentity, intFormat, -63, 63,
+			func(newValue int) { step.OffsetY = newValue })
+	}
+	imgui.InputText("Parameter", &step.Parameter)
+	step.Parameter = strings.TrimSpace(step.Parameter)
+	imgui.TreePop()
+}
+
+func (view *MacrosView) renderRecording() {
+	if view.model.recorder.Active() {
+		imgui.Text(fmt.Sprintf("Recording... %d step(s)", view.model.recorder.StepCount()))
+		imgui.InputText("Name", &view.model.newMacroName)
+		name := strings.TrimSpace(view.model.newMacroName)
+		if (len(name) > 0) && (view.model.recorder.StepCount() > 0) && imgui.Button("Stop") {
+			view.model.macros = append(view.model.macros, view.model.recorder.Stop(name))
+			view.selectMacro(len(view.model.macros) - 1)
+			view.model.newMacroName = ""
+		}
+		imgui.SameLine()
+		if imgui.Button("Cancel") {
+			view.model.recorder.Stop("")
+		}
+	} else if imgui.Button("Record") {
+		view.model.recorder.Start()
+	}
+}
+
+func (view *MacrosView) selectMacro(index int) {
+	if index != view.model.selectedMacroIndex {
+		view.model.argumentTexts = make(map[string]string)
+	}
+	view.model.selectedMacroIndex = index
+}
+
+func (view *MacrosView) deleteMacro(index int) {
+	view.model.macros = append(view.model.macros[:index:index], view.model.macros[index+1:]...)
+	view.selectMacro(-1)
+}
+
+func (view *MacrosView) requestReplay(lvl *level.Level, entry macro.Macro, args macro.Arguments) {
+	positions := view.model.selectedTiles.list
+	objectIDs := view.model.selectedObjects.list
+	for _, step := range entry.Resolved(args) {
+		view.applyStep(lvl, positions, objectIDs, step)
+	}
+
+	err := queueLevelPatch(view.mod, view.commander, lvl, fmt.Sprintf("Replay macro %s on L%d", entry.Name, lvl.ID()), "",
+		func(bool) {
+			view.eventListener.Event(LevelSelectionSetEvent{id: lvl.ID()})
+			view.eventListener.Event(TileSelectionSetEvent{tiles: positions})
+			view.eventListener.Event(ObjectSelectionSetEvent{objects: objectIDs})
+		})
+	if err != nil {
+		notifyLevelPatchFailure(view.modalStateMachine, err)
+	}
+}
+
+func (view *MacrosView) applyStep(lvl *level.Level, positions []MapPosition, objectIDs []level.ObjectID, step macro.Step) {
+	switch step.Target {
+	case macro.TargetTiles:
+		modifier, known := tileProperties[step.Property]
+		if !known {
+			return
+		}
+		for _, pos := range positions {
+			x := int(pos.X.Tile()) + step.OffsetX
+			y := int(pos.Y.Tile()) + step.OffsetY
+			if tile := lvl.Tile(x, y); tile != nil {
+				modifier(tile, step.Value)
+			}
+		}
+	case macro.TargetObjects:
+		var modifier func(*level.ObjectMasterEntry)
+		if baseModifier, known := objectBaseProperties[step.Property]; known {
+			modifier = func(entry *level.ObjectMasterEntry) { baseModifier(entry, step.Value) }
+		} else if step.Property == objectPlacementProperty {
+			modifier = view.objectsView.placementModifier(lvl, step.Value)
+		}
+		for _, id := range objectIDs {
+			obj := lvl.Object(id)
+			if obj == nil {
+				continue
+			}
+			if modifier != nil {
+				modifier(obj)
+			} else {
+				setInterpretedObjectProperty(lvl, id, step.Property,
+					func(oldValue uint32) uint32 { return uint32(step.Applied(int64(oldValue))) })
+			}
+		}
+	case macro.TargetLevel:
+		if modifier, known := levelProperties[step.Property]; known {
+			modifier(lvl, step.Index, step.Value)
+		}
+	}
+}
+
+func (view *MacrosView) onMacroStepRecordedEvent(evt MacroStepRecordedEvent) {
+	view.model.recorder.Record(evt.step)
+}
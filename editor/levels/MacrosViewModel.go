@@ -0,0 +1,24 @@
+package levels
+
+import "github.com/inkyblackness/hacked/ss1/edit/macro"
+
+type macrosViewModel struct {
+	selectedTiles   tileCoordinates
+	selectedObjects objectIDs
+
+	recorder           macro.Recorder
+	newMacroName       string
+	macros             []macro.Macro
+	selectedMacroIndex int
+	argumentTexts      map[string]string
+
+	restoreFocus bool
+	windowOpen   bool
+}
+
+func freshMacrosViewModel() macrosViewModel {
+	return macrosViewModel{
+		selectedMacroIndex: -1,
+		argumentTexts:      make(map[string]string),
+	}
+}
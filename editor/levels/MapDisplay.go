@@ -12,6 +12,8 @@ import (
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/lightbake"
+	"github.com/inkyblackness/hacked/ss1/edit/tilepaint"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/input"
@@ -77,6 +79,17 @@ type MapDisplay struct {
 	availableHoverItems []hoverItem
 	activeHoverIndex    int
 	activeHoverItem     hoverItem
+
+	paintSettings PaintSettingsSetEvent
+	painting      bool
+	strokeStart   tilepaint.Point
+	strokeLast    tilepaint.Point
+	stroke        tilepaint.Area
+	previewPoint  tilepaint.Point
+	preview       []MapPosition
+
+	lightingSettings LightingSettingsSetEvent
+	lighting         lightingCache
 }
 
 // NewMapDisplay returns a new instance.
@@ -101,6 +114,8 @@ func NewMapDisplay(gl opengl.OpenGL, guiScale float32,
 		guiScale:      guiScale,
 		eventListener: eventListener,
 		moveCapture:   func(float32, float32) {},
+
+		lightingSettings: defaultLightingSettings(),
 	}
 	display.context.ViewMatrix = display.camera.ViewMatrix()
 	display.background = NewBackgroundGrid(&display.context)
@@ -117,6 +132,8 @@ func NewMapDisplay(gl opengl.OpenGL, guiScale float32,
 	display.selectedTiles.registerAt(eventRegistry)
 	display.selectedObjects.registerAt(eventRegistry)
 	eventRegistry.RegisterHandler(display.onLevelSelectionSetEvent)
+	eventRegistry.RegisterHandler(display.onPaintSettingsSetEvent)
+	eventRegistry.RegisterHandler(display.onLightingSettingsSetEvent)
 
 	return display
 }
@@ -176,6 +193,8 @@ func (display *MapDisplay) Render(properties object.PropertiesTable, lvl *level.
 			colorQuery = display.colorQueryFor(lvl, func(tile *level.TileMapEntry) [4]float32 {
 				return [4]float32{0.0, 0.0, 0.0, float32(tile.Flags.ForRealWorld().CeilingShadow()) / 15.0}
 			})
+		} else if colorDisplay == ColorDisplayLighting {
+			colorQuery = display.lightingQuery(properties, lvl)
 		}
 		if colorQuery != nil {
 			display.colors.Render(columns, rows, colorQuery)
@@ -256,7 +275,12 @@ func (display *MapDisplay) Render(properties object.PropertiesTable, lvl *level.
 		}
 		display.highlighter.Render(selectedObjectHighlights, fineCoordinatesPerTileSide/4, [4]float32{0.0, 0.8, 0.2, 0.5})
 	}
-	if display.activeHoverItem != nil {
+	if display.paintSettings.tool != PaintToolSelect {
+		if !display.painting {
+			display.updatePaintPreview()
+		}
+		display.highlighter.Render(display.preview, fineCoordinatesPerTileSide, [4]float32{1.0, 0.6, 0.0, 0.5})
+	} else if display.activeHoverItem != nil {
 		display.highlighter.Render([]MapPosition{display.activeHoverItem.Pos()}, display.activeHoverItem.Size(), [4]float32{0.0, 0.2, 0.8, 0.3})
 	}
 
@@ -298,6 +322,26 @@ func (display *MapDisplay) colorQueryFor(lvl *level.Level, tileToColor func(*lev
 	}
 }
 
+// lightingQuery returns the shading of tiles by their floor light, with added light of emitting objects.
+// Light from objects is tinted, to tell it apart from the light of the tiles.
+// The LightDelta of tiles is not considered, as it is not known how the game applies it.
+func (display *MapDisplay) lightingQuery(properties object.PropertiesTable, lvl *level.Level) ColorQuery {
+	brightness := display.lighting.illuminate(properties, lvl, display.lightingSettings)
+	return func(x, y int) [4]float32 {
+		tile := lvl.Tile(x, y)
+		if (tile == nil) || (tile.Type == level.TileTypeSolid) {
+			return [4]float32{}
+		}
+		tileLight := float32(lightbake.MaxLevel-tile.Flags.ForRealWorld().FloorShadow()) / lightbake.MaxLevel
+		objectLight := float32(brightness[y][x])
+		total := tileLight + objectLight
+		if total > 1.0 {
+			total = 1.0
+		}
+		return [4]float32{0.6 * objectLight, 0.45 * objectLight, 0.0, 0.85*(1.0-total) + 0.25*objectLight}
+	}
+}
+
 func (display *MapDisplay) renderPositionOverlay(lvl *level.Level) {
 	imgui.SetNextWindowPosV(display.positionPopupPos, imgui.ConditionAlways, imgui.Vec2{X: 1.0, Y: 1.0})
 	imgui.SetNextWindowSize(imgui.Vec2{X: 140 * display.guiScale, Y: 0})
@@ -392,10 +436,26 @@ func (display *MapDisplay) unprojectPixel(pixelX, pixelY float32) (x, y float32)
 	return result[0], result[1]
 }
 
+// Zoom changes the zoom level by given delta, centered around the middle of the view.
+func (display *MapDisplay) Zoom(levelDelta float32) {
+	worldX, worldY := display.unprojectPixel(display.camera.viewportWidth/2, display.camera.viewportHeight/2)
+	display.camera.ZoomAt(levelDelta, worldX, worldY)
+}
+
+// ClearSelection removes all tiles and objects from the selection.
+func (display *MapDisplay) ClearSelection() {
+	display.eventListener.Event(TileSelectionSetEvent{})
+	display.eventListener.Event(ObjectSelectionSetEvent{})
+}
+
 // MouseButtonDown must be called when a button was pressed.
-func (display *MapDisplay) MouseButtonDown(mouseX, mouseY float32, button uint32) {
+// With a paint tool, the primary button starts a stroke. Holding shift moves the map instead.
+func (display *MapDisplay) MouseButtonDown(mouseX, mouseY float32, button uint32, modifier input.Modifier) {
 	display.updateMouseWorldPosition(mouseX, mouseY)
-	if button == input.MousePrimary {
+	if (button == input.MousePrimary) && (display.paintSettings.tool != PaintToolSelect) &&
+		!modifier.Has(input.ModShift) && display.positionValid && (display.activeLevel != nil) {
+		display.startStroke()
+	} else if button == input.MousePrimary {
 		lastPixelX, lastPixelY := mouseX, mouseY
 
 		display.mouseMoved = false
@@ -413,6 +473,10 @@ func (display *MapDisplay) MouseButtonDown(mouseX, mouseY float32, button uint32
 // MouseButtonUp must be called when a button was released.
 func (display *MapDisplay) MouseButtonUp(mouseX, mouseY float32, button uint32, modifier input.Modifier) {
 	display.updateMouseWorldPosition(mouseX, mouseY)
+	if display.painting {
+		display.finishStroke(button == input.MousePrimary)
+		return
+	}
 	if button == input.MousePrimary {
 		display.moveCapture = func(float32, float32) {}
 		if !display.mouseMoved && display.positionValid {
@@ -582,4 +646,95 @@ func (display *MapDisplay) resetHoverItems() {
 
 func (display *MapDisplay) onLevelSelectionSetEvent(evt LevelSelectionSetEvent) {
 	display.resetHoverItems()
+	display.painting = false
+	display.preview = nil
+}
+
+func (display *MapDisplay) onPaintSettingsSetEvent(evt PaintSettingsSetEvent) {
+	display.paintSettings = evt
+	display.painting = false
+	display.preview = nil
+}
+
+func (display *MapDisplay) onLightingSettingsSetEvent(evt LightingSettingsSetEvent) {
+	display.lightingSettings = evt
+}
+
+func (display *MapDisplay) startStroke() {
+	display.painting = true
+	display.strokeStart = tilePoint(display.position)
+	display.strokeLast = display.strokeStart
+	display.stroke = make(tilepaint.Area)
+	display.extendStroke()
+	display.moveCapture = func(float32, float32) {
+		if display.positionValid {
+			display.extendStroke()
+		}
+	}
+}
+
+// extendStroke updates the stroke with the current mouse position.
+func (display *MapDisplay) extendStroke() {
+	lvl := display.activeLevel
+	columns, rows, _ := lvl.Size()
+	current := tilePoint(display.position)
+	brushSize := display.paintSettings.brushSize
+	brushed := func(area tilepaint.Area, points []tilepaint.Point) {
+		for _, point := range points {
+			area.Add(tilepaint.Brush(point, brushSize)...)
+		}
+	}
+	switch display.paintSettings.tool {
+	case PaintToolBrush:
+		brushed(display.stroke, tilepaint.Line(display.strokeLast, current))
+	case PaintToolRectangle:
+		display.stroke = make(tilepaint.Area)
+		display.stroke.Add(tilepaint.Rectangle(display.strokeStart, current)...)
+	case PaintToolLine:
+		display.stroke = make(tilepaint.Area)
+		brushed(display.stroke, tilepaint.Line(display.strokeStart, current))
+	case PaintToolFill:
+		if reference := lvl.Tile(display.strokeStart.X, display.strokeStart.Y); reference != nil {
+			matcher := display.paintSettings.boundary.Matcher(lvl, *reference)
+			display.stroke = tilepaint.FloodFill(display.strokeStart, columns, rows, matcher)
+		}
+	}
+	display.strokeLast = current
+	display.stroke = display.stroke.Within(columns, rows)
+	display.preview = tilePositions(display.stroke)
+}
+
+// finishStroke ends the current stroke. The painting is requested only if the stroke is completed.
+func (display *MapDisplay) finishStroke(completed bool) {
+	display.painting = false
+	display.moveCapture = func(float32, float32) {}
+	if completed && (len(display.stroke) > 0) {
+		display.eventListener.Event(TilesPaintRequestEvent{Tiles: tilePositions(display.stroke)})
+	}
+	display.stroke = nil
+	display.preview = nil
+}
+
+// updatePaintPreview shows what the tool would paint at the current mouse position.
+func (display *MapDisplay) updatePaintPreview() {
+	lvl := display.activeLevel
+	if !display.positionValid || (lvl == nil) {
+		display.preview = nil
+		return
+	}
+	current := tilePoint(display.position)
+	if (display.preview != nil) && (current == display.previewPoint) {
+		return
+	}
+	display.previewPoint = current
+	columns, rows, _ := lvl.Size()
+	area := make(tilepaint.Area)
+	if display.paintSettings.tool == PaintToolFill {
+		if reference := lvl.Tile(current.X, current.Y); reference != nil {
+			area = tilepaint.FloodFill(current, columns, rows, display.paintSettings.boundary.Matcher(lvl, *reference))
+		}
+	} else {
+		area.Add(tilepaint.Brush(current, display.paintSettings.brushSize)...)
+	}
+	display.preview = tilePositions(area.Within(columns, rows))
 }
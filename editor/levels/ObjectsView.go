@@ -10,17 +10,17 @@ import (
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/editor/values"
-	"github.com/inkyblackness/hacked/ss1/content/archive"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
-	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
 	"github.com/inkyblackness/hacked/ss1/content/interpreters"
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
 )
 
 // ObjectsView is for object properties.
@@ -33,11 +33,14 @@ type ObjectsView struct {
 	commander     cmd.Commander
 	eventListener event.Listener
 
+	modalStateMachine gui.ModalStateMachine
+
 	model objectsViewModel
 }
 
 // NewObjectsView returns a new instance.
-func NewObjectsView(mod *world.Mod, guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache,
+func NewObjectsView(mod *world.Mod, modalStateMachine gui.ModalStateMachine, guiScale float32,
+	textCache *text.Cache, textureCache *graphics.TextureCache,
 	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *ObjectsView {
 	view := &ObjectsView{
 		mod:          mod,
@@ -49,6 +52,8 @@ func NewObjectsView(mod *world.Mod, guiScale float32, textCache *text.Cache, tex
 		eventListener: eventListener,
 
 		model: freshObjectsViewModel(),
+
+		modalStateMachine: modalStateMachine,
 	}
 	view.model.selectedObjects.registerAt(eventRegistry)
 	return view
@@ -71,7 +76,7 @@ func (view *ObjectsView) Render(lvl *level.Level) {
 
 		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 500 * view.guiScale}, imgui.ConditionOnce)
 		title := fmt.Sprintf("Level Objects, %d selected", len(view.model.selectedObjects.list))
-		readOnly := !view.editingAllowed(lvl.ID())
+		readOnly := !levelEditingAllowed(view.mod, lvl.ID())
 		if readOnly {
 			title += hintReadOnly
 		}
@@ -189,7 +194,7 @@ func (view *ObjectsView) renderContent(lvl *level.Level, readOnly bool) {
 			objectHeightFormatter,
 			0, 0xFF,
 			func(newValue int) {
-				view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { entry.Z = level.HeightUnit(newValue) })
+				view.requestBaseProperty(lvl, "Z", int64(newValue))
 			})
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Tile X", tileXUnifier,
 			func(u values.Unifier) int { return int(u.Unified().(byte)) },
@@ -203,7 +208,7 @@ func (view *ObjectsView) renderContent(lvl *level.Level, readOnly bool) {
 			func(value int) string { return "%d" },
 			0, 0xFF,
 			func(newValue int) {
-				view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { entry.X = level.CoordinateAt(entry.X.Tile(), byte(newValue)) })
+				view.requestBaseProperty(lvl, "Fine X", int64(newValue))
 			})
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Tile Y", tileYUnifier,
 			func(u values.Unifier) int { return int(u.Unified().(byte)) },
@@ -217,28 +222,28 @@ func (view *ObjectsView) renderContent(lvl *level.Level, readOnly bool) {
 			func(value int) string { return "%d" },
 			0, 0xFF,
 			func(newValue int) {
-				view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { entry.Y = level.CoordinateAt(entry.Y.Tile(), byte(newValue)) })
+				view.requestBaseProperty(lvl, "Fine Y", int64(newValue))
 			})
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Rotation X", rotationXUnifier,
 			func(u values.Unifier) int { return int(u.Unified().(level.RotationUnit)) },
 			rotationFormatter,
 			0, 0xFF,
 			func(newValue int) {
-				view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { entry.XRotation = level.RotationUnit(newValue) })
+				view.requestBaseProperty(lvl, "Rotation X", int64(newValue))
 			})
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Rotation Y", rotationYUnifier,
 			func(u values.Unifier) int { return int(u.Unified().(level.RotationUnit)) },
 			rotationFormatter,
 			0, 0xFF,
 			func(newValue int) {
-				view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { entry.YRotation = level.RotationUnit(newValue) })
+				view.requestBaseProperty(lvl, "Rotation Y", int64(newValue))
 			})
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Rotation Z", rotationZUnifier,
 			func(u values.Unifier) int { return int(u.Unified().(level.RotationUnit)) },
 			rotationFormatter,
 			0, 0xFF,
 			func(newValue int) {
-				view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { entry.ZRotation = level.RotationUnit(newValue) })
+				view.requestBaseProperty(lvl, "Rotation Z", int64(newValue))
 			})
 
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Hitpoints", hitpointsUnifier,
@@ -246,19 +251,19 @@ func (view *ObjectsView) renderContent(lvl *level.Level, readOnly bool) {
 			func(value int) string { return "%d" },
 			0, 10000,
 			func(newValue int) {
-				view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { entry.Hitpoints = int16(newValue) })
+				view.requestBaseProperty(lvl, "Hitpoints", int64(newValue))
 			})
 
 		imgui.TreePop()
 	}
 	if imgui.TreeNodeV("Extra Properties", imgui.TreeNodeFlagsFramed) {
-		view.renderProperties(lvl, readOnly,
+		view.renderProperties(lvl, readOnly, objectExtraPrefix,
 			func(id level.ObjectID, entry *level.ObjectMasterEntry) []byte { return entry.Extra[:] },
 			view.extraInterpreterFactory(lvl))
 		imgui.TreePop()
 	}
 	if imgui.TreeNodeV("Class Properties", imgui.TreeNodeFlagsFramed) {
-		view.renderProperties(lvl, readOnly,
+		view.renderProperties(lvl, readOnly, objectClassPrefix,
 			func(id level.ObjectID, entry *level.ObjectMasterEntry) []byte { return lvl.ObjectClassData(id) },
 			view.classInterpreterFactory(lvl))
 		view.renderBlockPuzzleControl(lvl, readOnly)
@@ -268,7 +273,7 @@ func (view *ObjectsView) renderContent(lvl *level.Level, readOnly bool) {
 	imgui.PopItemWidth()
 }
 
-func (view *ObjectsView) renderProperties(lvl *level.Level, readOnly bool,
+func (view *ObjectsView) renderProperties(lvl *level.Level, readOnly bool, prefix string,
 	dataRetriever func(level.ObjectID, *level.ObjectMasterEntry) []byte,
 	interpreterFactory lvlobj.InterpreterFactory) {
 	propertyUnifier := make(map[string]*values.Unifier)
@@ -337,7 +342,7 @@ func (view *ObjectsView) renderProperties(lvl *level.Level, readOnly bool,
 			}
 			view.renderPropertyControl(lvl, readOnly, multiple, key, *unifier, propertyDescribers[key],
 				func(modifier func(uint32) uint32) {
-					view.requestPropertiesChange(lvl, dataRetriever, interpreterFactory, key, modifier) // nolint: scopelint
+					view.requestPropertiesChange(lvl, prefix, dataRetriever, interpreterFactory, key, modifier) // nolint: scopelint
 				})
 		}
 	}
@@ -652,7 +657,8 @@ func (view *ObjectsView) renderBlockPuzzleControl(lvl *level.Level, readOnly boo
 							if (clickRow >= 0) && (clickRow < blockHeight) && (clickCol >= 0) && (clickCol < blockWidth) {
 								oldValue := state.CellValue(clickRow, clickCol)
 								state.SetCellValue(clickRow, clickCol, (8+oldValue+1)%8)
-								view.patchLevel(lvl, view.model.selectedObjects.list, view.model.selectedObjects.list)
+								view.patchLevel(lvl, fmt.Sprintf("Change puzzle of object on L%d", lvl.ID()), "",
+									view.model.selectedObjects.list, view.model.selectedObjects.list)
 							}
 						}
 					} else {
@@ -669,14 +675,6 @@ func (view *ObjectsView) renderBlockPuzzleControl(lvl *level.Level, readOnly boo
 	}
 }
 
-func (view *ObjectsView) editingAllowed(id int) bool {
-	gameStateData := view.mod.ModifiedBlocks(resource.LangAny, ids.GameState)
-	isSavegame := (len(gameStateData) == 1) && (len(gameStateData[0]) == archive.GameStateSize) && (gameStateData[0][0x009C] > 0)
-	moddedLevel := len(view.mod.ModifiedBlocks(resource.LangAny, ids.LevelResourcesStart.Plus(lvlids.PerLevel*id+lvlids.FirstUsed))) > 0
-
-	return moddedLevel && !isSavegame
-}
-
 func (view *ObjectsView) requestBaseChange(lvl *level.Level, modifier func(*level.ObjectMasterEntry)) {
 	objectIDs := view.model.selectedObjects.list
 	for _, id := range objectIDs {
@@ -690,7 +688,34 @@ func (view *ObjectsView) requestBaseChange(lvl *level.Level, modifier func(*leve
 		}
 	}
 
-	view.patchLevel(lvl, objectIDs, objectIDs)
+	view.patchLevel(lvl, fmt.Sprintf("Change %d object(s) on L%d", len(objectIDs), lvl.ID()),
+		fmt.Sprintf("objects/%d/%v", lvl.ID(), objectIDs), objectIDs, objectIDs)
+}
+
+func (view *ObjectsView) requestBaseProperty(lvl *level.Level, property string, value int64) {
+	view.recordStep(property, value)
+	view.requestBaseChange(lvl, func(entry *level.ObjectMasterEntry) { objectBaseProperties[property](entry, value) })
+}
+
+func (view *ObjectsView) recordStep(property string, value int64) {
+	view.eventListener.Event(MacroStepRecordedEvent{step: macro.Step{Target: macro.TargetObjects, Property: property, Value: value}})
+}
+
+// recordModifierStep records the change of an interpreted property.
+// The modifiers of the controls either replace the value, or set some of its bits and keep the others.
+// Which bits are set is determined by applying the modifier to a value without and with all bits set.
+func (view *ObjectsView) recordModifierStep(property string, modifier func(uint32) uint32) {
+	setBits := modifier(0)
+	mask := ^(modifier(^uint32(0)) &^ setBits)
+	if mask == ^uint32(0) {
+		mask = 0
+	}
+	view.eventListener.Event(MacroStepRecordedEvent{step: macro.Step{
+		Target:   macro.TargetObjects,
+		Property: property,
+		Value:    int64(setBits),
+		Mask:     mask,
+	}})
 }
 
 func (view *ObjectsView) extraInterpreterFactory(lvl *level.Level) lvlobj.InterpreterFactory {
@@ -709,13 +734,16 @@ func (view *ObjectsView) classInterpreterFactory(lvl *level.Level) lvlobj.Interp
 	return interpreterFactory
 }
 
-func (view *ObjectsView) requestPropertiesChange(lvl *level.Level,
+func (view *ObjectsView) requestPropertiesChange(lvl *level.Level, prefix string,
 	dataRetriever func(level.ObjectID, *level.ObjectMasterEntry) []byte,
 	interpreterFactory lvlobj.InterpreterFactory,
 	key string, modifier func(uint32) uint32) {
 	objectIDs := view.model.selectedObjects.list
 	subKeys := strings.Split(key, ".")
 	valueIndex := len(subKeys) - 1
+	if len(objectIDs) > 0 {
+		view.recordModifierStep(prefix+key, modifier)
+	}
 
 	for _, id := range objectIDs {
 		obj := lvl.Object(id)
@@ -726,16 +754,18 @@ func (view *ObjectsView) requestPropertiesChange(lvl *level.Level,
 				interpreter = interpreter.Refined(subKeys[subIndex])
 			}
 			subKey := subKeys[valueIndex]
-			interpreter.Set(subKey, modifier(interpreter.Get(subKey)))
+			newValue := modifier(interpreter.Get(subKey))
+			interpreter.Set(subKey, newValue)
 		}
 	}
 
-	view.patchLevel(lvl, objectIDs, objectIDs)
+	view.patchLevel(lvl, fmt.Sprintf("Set %s of %d object(s) on L%d", key, len(objectIDs), lvl.ID()),
+		fmt.Sprintf("objects/%d/%s/%v", lvl.ID(), key, objectIDs), objectIDs, objectIDs)
 }
 
 // RequestCreateObject requests to create a new object of the currently selected type.
 func (view *ObjectsView) RequestCreateObject(lvl *level.Level, pos MapPosition) {
-	if view.editingAllowed(lvl.ID()) {
+	if levelEditingAllowed(view.mod, lvl.ID()) {
 		view.requestCreateObject(lvl, view.model.newObjectTriple, pos)
 	}
 }
@@ -763,7 +793,8 @@ func (view *ObjectsView) requestCreateObject(lvl *level.Level, triple object.Tri
 	obj.Subclass = triple.Subclass
 	obj.Type = triple.Type
 	lvl.UpdateObjectLocation(id)
-	view.patchLevel(lvl, []level.ObjectID{id}, view.model.selectedObjects.list)
+	view.patchLevel(lvl, fmt.Sprintf("Create object %v on L%d", triple, lvl.ID()), "",
+		[]level.ObjectID{id}, view.model.selectedObjects.list)
 }
 
 func (view *ObjectsView) floorHeightAtFine(tile *level.TileMapEntry, pos MapPosition, height level.HeightShift) float32 {
@@ -848,38 +879,25 @@ func (view *ObjectsView) requestDeleteObjects(lvl *level.Level, objectIDs []leve
 		for _, id := range objectIDs {
 			lvl.DelObject(id)
 		}
-		view.patchLevel(lvl, nil, objectIDs)
+		view.patchLevel(lvl, fmt.Sprintf("Delete %d object(s) on L%d", len(objectIDs), lvl.ID()), "", nil, objectIDs)
 	}
 }
 
-func (view *ObjectsView) patchLevel(lvl *level.Level, forwardObjectIDs []level.ObjectID, reverseObjectIDs []level.ObjectID) {
-	command := patchLevelDataCommand{
-		restoreState: func(forward bool) {
-			view.model.restoreFocus = true
-			view.setSelectedLevel(lvl.ID())
-			if forward {
-				view.setSelectedObjects(forwardObjectIDs)
-			} else {
-				view.setSelectedObjects(reverseObjectIDs)
-			}
-		},
-	}
-
-	newDataSet := lvl.EncodeState()
-	for id, newData := range &newDataSet {
-		if len(newData) > 0 {
-			resourceID := ids.LevelResourcesStart.Plus(lvlids.PerLevel*lvl.ID() + id)
-			patch, changed, err := view.mod.CreateBlockPatch(resource.LangAny, resourceID, 0, newData)
-			if err != nil {
-				fmt.Printf("err: %v\n", err)
-				// TODO how to handle this? We're not expecting this, so crash and burn?
-			} else if changed {
-				command.patches = append(command.patches, patch)
-			}
+func (view *ObjectsView) patchLevel(lvl *level.Level, title string, group string,
+	forwardObjectIDs []level.ObjectID, reverseObjectIDs []level.ObjectID) {
+	restoreState := func(forward bool) {
+		view.model.restoreFocus = true
+		view.setSelectedLevel(lvl.ID())
+		if forward {
+			view.setSelectedObjects(forwardObjectIDs)
+		} else {
+			view.setSelectedObjects(reverseObjectIDs)
 		}
 	}
-
-	view.commander.Queue(command)
+	err := queueLevelPatch(view.mod, view.commander, lvl, title, group, restoreState)
+	if err != nil {
+		notifyLevelPatchFailure(view.modalStateMachine, err)
+	}
 }
 
 func (view *ObjectsView) setSelectedLevel(id int) {
@@ -915,34 +933,44 @@ func (view *ObjectsView) textureName(index int) string {
 
 // PlaceSelectedObjectsOnFloor puts all selected objects to sit on the floor.
 func (view *ObjectsView) PlaceSelectedObjectsOnFloor(lvl *level.Level) {
-	_, _, height := lvl.Size()
-	view.placeSelectedObjects(lvl, func(tile *level.TileMapEntry, pos MapPosition, objPivot float32) level.HeightUnit {
-		floorHeight := view.floorHeightAtFine(tile, pos, height)
-		return height.ValueToObjectHeight(floorHeight + objPivot)
-	})
+	view.placeSelectedObjects(lvl, placementFloor)
 }
 
 // PlaceSelectedObjectsOnEyeLevel puts all selected objects to be at eye level (approximately).
 func (view *ObjectsView) PlaceSelectedObjectsOnEyeLevel(lvl *level.Level) {
-	_, _, height := lvl.Size()
-	view.placeSelectedObjects(lvl, func(tile *level.TileMapEntry, pos MapPosition, objPivot float32) level.HeightUnit {
-		floorHeight := view.floorHeightAtFine(tile, pos, height)
-		return height.ValueToObjectHeight(floorHeight + 0.75 - objPivot)
-	})
+	view.placeSelectedObjects(lvl, placementEyeLevel)
 }
 
 // PlaceSelectedObjectsOnCeiling puts all selected objects to hang from the ceiling.
 func (view *ObjectsView) PlaceSelectedObjectsOnCeiling(lvl *level.Level) {
-	_, _, height := lvl.Size()
-	view.placeSelectedObjects(lvl, func(tile *level.TileMapEntry, pos MapPosition, objPivot float32) level.HeightUnit {
-		ceilingHeight := view.ceilingHeightAtFine(tile, pos, height)
-		return height.ValueToObjectHeight(ceilingHeight - objPivot)
-	})
+	view.placeSelectedObjects(lvl, placementCeiling)
+}
+
+func (view *ObjectsView) placeSelectedObjects(lvl *level.Level, placement int64) {
+	view.recordStep(objectPlacementProperty, placement)
+	view.requestBaseChange(lvl, view.placementModifier(lvl, placement))
 }
 
-func (view *ObjectsView) placeSelectedObjects(lvl *level.Level,
-	atHeight func(tile *level.TileMapEntry, pos MapPosition, objPivot float32) level.HeightUnit) {
-	view.requestBaseChange(lvl, func(obj *level.ObjectMasterEntry) {
+// placementModifier returns a function that sets the height of an object according to the placement.
+func (view *ObjectsView) placementModifier(lvl *level.Level, placement int64) func(*level.ObjectMasterEntry) {
+	_, _, height := lvl.Size()
+	atHeight := func(tile *level.TileMapEntry, pos MapPosition, objPivot float32) level.HeightUnit {
+		floorHeight := view.floorHeightAtFine(tile, pos, height)
+		return height.ValueToObjectHeight(floorHeight + objPivot)
+	}
+	switch placement {
+	case placementEyeLevel:
+		atHeight = func(tile *level.TileMapEntry, pos MapPosition, objPivot float32) level.HeightUnit {
+			floorHeight := view.floorHeightAtFine(tile, pos, height)
+			return height.ValueToObjectHeight(floorHeight + 0.75 - objPivot)
+		}
+	case placementCeiling:
+		atHeight = func(tile *level.TileMapEntry, pos MapPosition, objPivot float32) level.HeightUnit {
+			ceilingHeight := view.ceilingHeightAtFine(tile, pos, height)
+			return height.ValueToObjectHeight(ceilingHeight - objPivot)
+		}
+	}
+	return func(obj *level.ObjectMasterEntry) {
 		var objPivot float32
 		prop, err := view.mod.ObjectProperties().ForObject(obj.Triple())
 		if err == nil {
@@ -952,5 +980,5 @@ func (view *ObjectsView) placeSelectedObjects(lvl *level.Level,
 		if tile != nil {
 			obj.Z = atHeight(tile, MapPosition{X: obj.X, Y: obj.Y}, objPivot)
 		}
-	})
+	}
 }
@@ -0,0 +1,109 @@
+package levels
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/tilepaint"
+)
+
+// PaintTool is an enumeration how the map is painted with a tile template.
+type PaintTool int
+
+// PaintTool constants
+const (
+	PaintToolSelect    PaintTool = 0
+	PaintToolBrush     PaintTool = 1
+	PaintToolRectangle PaintTool = 2
+	PaintToolLine      PaintTool = 3
+	PaintToolFill      PaintTool = 4
+)
+
+// String returns a textual representation.
+func (tool PaintTool) String() string {
+	switch tool {
+	case PaintToolSelect:
+		return "Select"
+	case PaintToolBrush:
+		return "Brush"
+	case PaintToolRectangle:
+		return "Rectangle"
+	case PaintToolLine:
+		return "Line"
+	case PaintToolFill:
+		return "Flood Fill"
+	default:
+		return fmt.Sprintf("Unknown%d", int(tool))
+	}
+}
+
+// PaintTools returns all PaintTool constants.
+func PaintTools() []PaintTool {
+	return []PaintTool{PaintToolSelect, PaintToolBrush, PaintToolRectangle, PaintToolLine, PaintToolFill}
+}
+
+// FillBoundary is an enumeration which tiles a flood fill covers.
+type FillBoundary int
+
+// FillBoundary constants
+const (
+	FillBoundaryType   FillBoundary = 0
+	FillBoundaryHeight FillBoundary = 1
+)
+
+// String returns a textual representation.
+func (boundary FillBoundary) String() string {
+	switch boundary {
+	case FillBoundaryType:
+		return "Same tile type"
+	case FillBoundaryHeight:
+		return "Same floor and ceiling height"
+	default:
+		return fmt.Sprintf("Unknown%d", int(boundary))
+	}
+}
+
+// FillBoundaries returns all FillBoundary constants.
+func FillBoundaries() []FillBoundary {
+	return []FillBoundary{FillBoundaryType, FillBoundaryHeight}
+}
+
+// Matcher returns a function that tells whether a tile belongs to the same area as the reference tile.
+func (boundary FillBoundary) Matcher(lvl *level.Level, reference level.TileMapEntry) func(tilepaint.Point) bool {
+	return func(point tilepaint.Point) bool {
+		tile := lvl.Tile(point.X, point.Y)
+		if tile == nil {
+			return false
+		}
+		if boundary == FillBoundaryHeight {
+			return (tile.Floor.AbsoluteHeight() == reference.Floor.AbsoluteHeight()) &&
+				(tile.Ceiling.AbsoluteHeight() == reference.Ceiling.AbsoluteHeight())
+		}
+		return tile.Type == reference.Type
+	}
+}
+
+// PaintSettingsSetEvent notifies about the current settings for painting the map.
+type PaintSettingsSetEvent struct {
+	tool      PaintTool
+	brushSize int
+	boundary  FillBoundary
+}
+
+// TilesPaintRequestEvent requests to paint the given tiles with the current template.
+type TilesPaintRequestEvent struct {
+	Tiles []MapPosition
+}
+
+func tilePoint(pos MapPosition) tilepaint.Point {
+	return tilepaint.Point{X: int(pos.X.Tile()), Y: int(pos.Y.Tile())}
+}
+
+func tilePositions(area tilepaint.Area) []MapPosition {
+	points := area.Points()
+	positions := make([]MapPosition, len(points))
+	for index, point := range points {
+		positions[index] = MapPosition{X: level.CoordinateAt(byte(point.X), 128), Y: level.CoordinateAt(byte(point.Y), 128)}
+	}
+	return positions
+}
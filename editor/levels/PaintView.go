@@ -0,0 +1,148 @@
+package levels
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// PaintView is for the tools that paint tiles on the map with a template.
+type PaintView struct {
+	tilesView *TilesView
+
+	guiScale      float32
+	eventListener event.Listener
+
+	model paintViewModel
+}
+
+// NewPaintView returns a new instance.
+func NewPaintView(tilesView *TilesView, guiScale float32, eventListener event.Listener, eventRegistry event.Registry) *PaintView {
+	view := &PaintView{
+		tilesView: tilesView,
+
+		guiScale:      guiScale,
+		eventListener: eventListener,
+		model:         freshPaintViewModel(),
+	}
+	view.model.selectedTiles.registerAt(eventRegistry)
+	view.notifySettings()
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *PaintView) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// SetTool changes the active paint tool.
+func (view *PaintView) SetTool(tool PaintTool) {
+	view.model.tool = tool
+	view.notifySettings()
+}
+
+// Render renders the view.
+func (view *PaintView) Render(lvl *level.Level) {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		title := "Tile Painting"
+		readOnly := !levelEditingAllowed(view.tilesView.mod, lvl.ID())
+		if readOnly {
+			title += hintReadOnly
+		}
+		if imgui.BeginV(title+"###Tile Painting", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent(lvl, readOnly)
+		}
+		imgui.End()
+	}
+}
+
+func (view *PaintView) renderContent(lvl *level.Level, readOnly bool) {
+	imgui.PushItemWidth(-150 * view.guiScale)
+	if imgui.BeginCombo("Tool", view.model.tool.String()) {
+		for _, tool := range PaintTools() {
+			if imgui.SelectableV(tool.String(), tool == view.model.tool, 0, imgui.Vec2{}) {
+				view.SetTool(tool)
+			}
+		}
+		imgui.EndCombo()
+	}
+	brushSize := view.model.brushSize
+	if gui.StepSliderInt("Brush Size", &brushSize, 1, 9) {
+		view.model.brushSize = brushSize
+		view.notifySettings()
+	}
+	if imgui.BeginCombo("Fill Boundary", view.model.boundary.String()) {
+		for _, boundary := range FillBoundaries() {
+			if imgui.SelectableV(boundary.String(), boundary == view.model.boundary, 0, imgui.Vec2{}) {
+				view.model.boundary = boundary
+				view.notifySettings()
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.Text("Hold Shift to move the map while a paint tool is active.")
+
+	imgui.Separator()
+	imgui.Text("Template")
+	if imgui.Button("Take From Selected Tile") {
+		view.takeTemplate(lvl)
+	}
+	if view.model.templateSet {
+		source := view.model.template.Source
+		imgui.Text(fmt.Sprintf("Type: %v, floor: %d, ceiling: %d, slope: %d",
+			source.Type, source.Floor.AbsoluteHeight(), source.Ceiling.AbsoluteHeight(), source.SlopeHeight))
+		imgui.Text(fmt.Sprintf("Textures: floor %d, ceiling %d, wall %d",
+			source.TextureInfo.FloorTextureIndex(), source.TextureInfo.CeilingTextureIndex(), source.TextureInfo.WallTextureIndex()))
+	} else {
+		imgui.Text("No template - select a tile on the map and take it.")
+	}
+	imgui.Checkbox("Tile Type", &view.model.template.Type)
+	imgui.Checkbox("Heights", &view.model.template.Heights)
+	imgui.Checkbox("Textures", &view.model.template.Textures)
+	imgui.Checkbox("Flags", &view.model.template.Flags)
+	if readOnly {
+		imgui.Text("The level can not be painted.")
+	}
+	imgui.PopItemWidth()
+}
+
+func (view *PaintView) takeTemplate(lvl *level.Level) {
+	if len(view.model.selectedTiles.list) == 0 {
+		return
+	}
+	pos := view.model.selectedTiles.list[0]
+	if tile := lvl.Tile(int(pos.X.Tile()), int(pos.Y.Tile())); tile != nil {
+		view.model.template.Source = *tile
+		view.model.templateSet = true
+	}
+}
+
+// Paint applies the template to the given tiles of the level, as one change.
+func (view *PaintView) Paint(lvl *level.Level, positions []MapPosition) {
+	if !view.model.templateSet || (len(positions) == 0) || !levelEditingAllowed(view.tilesView.mod, lvl.ID()) {
+		return
+	}
+	template := view.model.template
+	cyberspace := lvl.IsCyberspace()
+	view.tilesView.changeTiles(lvl, positions, "painted properties", func(tile *level.TileMapEntry) {
+		template.Apply(tile, cyberspace)
+	})
+}
+
+func (view *PaintView) notifySettings() {
+	view.eventListener.Event(PaintSettingsSetEvent{
+		tool:      view.model.tool,
+		brushSize: view.model.brushSize,
+		boundary:  view.model.boundary,
+	})
+}
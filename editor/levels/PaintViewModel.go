@@ -0,0 +1,30 @@
+package levels
+
+import "github.com/inkyblackness/hacked/ss1/edit/tilepaint"
+
+type paintViewModel struct {
+	selectedTiles tileCoordinates
+
+	tool      PaintTool
+	brushSize int
+	boundary  FillBoundary
+
+	template    tilepaint.Template
+	templateSet bool
+
+	restoreFocus bool
+	windowOpen   bool
+}
+
+func freshPaintViewModel() paintViewModel {
+	return paintViewModel{
+		tool:      PaintToolSelect,
+		brushSize: 1,
+		boundary:  FillBoundaryType,
+		template: tilepaint.Template{
+			Type:     true,
+			Heights:  true,
+			Textures: true,
+		},
+	}
+}
@@ -9,14 +9,14 @@ import (
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/editor/values"
-	"github.com/inkyblackness/hacked/ss1/content/archive"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
-	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
 	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
 )
 
 // TilesView is for tile properties.
@@ -29,11 +29,14 @@ type TilesView struct {
 	commander     cmd.Commander
 	eventListener event.Listener
 
+	modalStateMachine gui.ModalStateMachine
+
 	model tilesViewModel
 }
 
 // NewTilesView returns a new instance.
-func NewTilesView(mod *world.Mod, guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache,
+func NewTilesView(mod *world.Mod, modalStateMachine gui.ModalStateMachine, guiScale float32,
+	textCache *text.Cache, textureCache *graphics.TextureCache,
 	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *TilesView {
 	view := &TilesView{
 		mod:          mod,
@@ -44,6 +47,8 @@ func NewTilesView(mod *world.Mod, guiScale float32, textCache *text.Cache, textu
 		commander:     commander,
 		eventListener: eventListener,
 		model:         freshTilesViewModel(),
+
+		modalStateMachine: modalStateMachine,
 	}
 	view.model.selectedTiles.registerAt(eventRegistry)
 	return view
@@ -77,7 +82,7 @@ func (view *TilesView) Render(lvl *level.Level) {
 	if view.model.windowOpen {
 		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 500 * view.guiScale}, imgui.ConditionOnce)
 		title := fmt.Sprintf("Level Tiles, %d selected", len(view.model.selectedTiles.list))
-		readOnly := !view.editingAllowed(lvl.ID())
+		readOnly := !levelEditingAllowed(view.mod, lvl.ID())
 		if readOnly {
 			title += hintReadOnly
 		}
@@ -333,7 +338,7 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 		imgui.Separator()
 
 		if imgui.BeginCombo("Shadow View", view.model.shadowDisplay.String()) {
-			displays := ColorDisplays()
+			displays := ShadowDisplays()
 			for _, display := range displays {
 				displayString := display.String()
 
@@ -412,181 +417,151 @@ func (view *TilesView) textureName(index int) string {
 	return fmt.Sprintf("%3d", index) + suffix
 }
 
-func (view *TilesView) editingAllowed(id int) bool {
-	gameStateData := view.mod.ModifiedBlocks(resource.LangAny, ids.GameState)
-	isSavegame := (len(gameStateData) == 1) && (len(gameStateData[0]) == archive.GameStateSize) && (gameStateData[0][0x009C] > 0)
-	moddedLevel := len(view.mod.ModifiedBlocks(resource.LangAny, ids.LevelResourcesStart.Plus(lvlids.PerLevel*id+lvlids.FirstUsed))) > 0
-
-	return moddedLevel && !isSavegame
-}
-
 func (view *TilesView) requestSetTileType(lvl *level.Level, positions []MapPosition, tileType level.TileType) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Type = tileType
-	})
+	view.changeTileProperty(lvl, positions, "tile type", int64(tileType))
 }
 
 func (view *TilesView) requestSetFloorHeight(lvl *level.Level, positions []MapPosition, height level.TileHeightUnit) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Floor = tile.Floor.WithAbsoluteHeight(height)
-	})
+	view.changeTileProperty(lvl, positions, "floor height", int64(height))
 }
 
 func (view *TilesView) requestSetCeilingHeight(lvl *level.Level, positions []MapPosition, height level.TileHeightUnit) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(height)
-	})
+	view.changeTileProperty(lvl, positions, "ceiling height", int64(height))
 }
 
 func (view *TilesView) requestSetSlopeHeight(lvl *level.Level, positions []MapPosition, height level.TileHeightUnit) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.SlopeHeight = height
-	})
+	view.changeTileProperty(lvl, positions, "slope height", int64(height))
 }
 
 func (view *TilesView) requestSetSlopeControl(lvl *level.Level, positions []MapPosition, value level.TileSlopeControl) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.WithSlopeControl(value)
-	})
+	view.changeTileProperty(lvl, positions, "slope control", int64(value))
 }
 
 func (view *TilesView) requestMusicIndex(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.WithMusicIndex(value)
-	})
+	view.changeTileProperty(lvl, positions, "music index", int64(value))
 }
 
 func (view *TilesView) requestFloorTextureIndex(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.TextureInfo = tile.TextureInfo.WithFloorTextureIndex(value)
-	})
+	view.changeTileProperty(lvl, positions, "floor texture", int64(value))
 }
 
 func (view *TilesView) requestFloorTextureRotations(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Floor = tile.Floor.WithTextureRotations(value)
-	})
+	view.changeTileProperty(lvl, positions, "floor texture rotations", int64(value))
 }
 
 func (view *TilesView) requestCeilingTextureIndex(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.TextureInfo = tile.TextureInfo.WithCeilingTextureIndex(value)
-	})
+	view.changeTileProperty(lvl, positions, "ceiling texture", int64(value))
 }
 
 func (view *TilesView) requestCeilingTextureRotations(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Ceiling = tile.Ceiling.WithTextureRotations(value)
-	})
+	view.changeTileProperty(lvl, positions, "ceiling texture rotations", int64(value))
 }
 
 func (view *TilesView) requestWallTextureIndex(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.TextureInfo = tile.TextureInfo.WithWallTextureIndex(value)
-	})
+	view.changeTileProperty(lvl, positions, "wall texture", int64(value))
 }
 
 func (view *TilesView) requestWallTextureOffset(lvl *level.Level, positions []MapPosition, value level.TileHeightUnit) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForRealWorld().WithWallTextureOffset(value).AsTileFlag()
-	})
+	view.changeTileProperty(lvl, positions, "wall texture offset", int64(value))
 }
 
 func (view *TilesView) requestUseAdjacentWallTexture(lvl *level.Level, positions []MapPosition, value bool) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForRealWorld().WithUseAdjacentWallTexture(value).AsTileFlag()
-	})
+	view.changeTileProperty(lvl, positions, "adjacent wall texture usage", boolValue(value))
 }
 
 func (view *TilesView) requestWallTexturePattern(lvl *level.Level, positions []MapPosition, value level.WallTexturePattern) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForRealWorld().WithWallTexturePattern(value).AsTileFlag()
-	})
+	view.changeTileProperty(lvl, positions, "wall texture pattern", int64(value))
 }
 
 func (view *TilesView) requestFloorLight(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForRealWorld().WithFloorShadow(15 - value).AsTileFlag()
-	})
+	view.changeTileProperty(lvl, positions, "floor light", int64(value))
 }
 
 func (view *TilesView) requestCeilingLight(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForRealWorld().WithCeilingShadow(15 - value).AsTileFlag()
-	})
+	view.changeTileProperty(lvl, positions, "ceiling light", int64(value))
 }
 
 func (view *TilesView) requestDeconstructed(lvl *level.Level, positions []MapPosition, value bool) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForRealWorld().WithDeconstructed(value).AsTileFlag()
-	})
+	view.changeTileProperty(lvl, positions, "deconstructed flag", boolValue(value))
 }
 
 func (view *TilesView) requestFloorHazard(lvl *level.Level, positions []MapPosition, value bool) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Floor = tile.Floor.WithHazard(value)
-	})
+	view.changeTileProperty(lvl, positions, "floor hazard", boolValue(value))
 }
 
 func (view *TilesView) requestCeilingHazard(lvl *level.Level, positions []MapPosition, value bool) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Ceiling = tile.Ceiling.WithHazard(value)
-	})
+	view.changeTileProperty(lvl, positions, "ceiling hazard", boolValue(value))
 }
 
 func (view *TilesView) requestFloorPaletteIndex(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.TextureInfo = tile.TextureInfo.WithFloorPaletteIndex(byte(value))
-	})
+	view.changeTileProperty(lvl, positions, "floor palette index", int64(value))
 }
 
 func (view *TilesView) requestCeilingPaletteIndex(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.TextureInfo = tile.TextureInfo.WithCeilingPaletteIndex(byte(value))
-	})
+	view.changeTileProperty(lvl, positions, "ceiling palette index", int64(value))
 }
 
 func (view *TilesView) requestFlightPullType(lvl *level.Level, positions []MapPosition, value level.CyberspaceFlightPull) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForCyberspace().WithFlightPull(value).AsTileFlag()
-	})
+	view.changeTileProperty(lvl, positions, "flight pull type", int64(value))
 }
 
 func (view *TilesView) requestGameOfLightState(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForCyberspace().WithGameOfLifeState(value).AsTileFlag()
+	view.changeTileProperty(lvl, positions, "game of life state", int64(value))
+}
+
+func (view *TilesView) changeTileProperty(lvl *level.Level, positions []MapPosition, property string, value int64) {
+	view.eventListener.Event(MacroStepRecordedEvent{step: macro.Step{Target: macro.TargetTiles, Property: property, Value: value}})
+	view.changeTiles(lvl, positions, property, func(tile *level.TileMapEntry) {
+		tileProperties[property](tile, value)
 	})
 }
 
-func (view *TilesView) changeTiles(lvl *level.Level, positions []MapPosition, modifier func(*level.TileMapEntry)) {
+func (view *TilesView) changeTiles(lvl *level.Level, positions []MapPosition, property string,
+	modifier func(*level.TileMapEntry)) {
 	for _, pos := range positions {
 		tile := lvl.Tile(int(pos.X.Tile()), int(pos.Y.Tile()))
 		modifier(tile)
 	}
 
-	command := patchLevelDataCommand{
-		restoreState: func(bool) {
-			view.model.restoreFocus = true
-			view.setSelectedLevel(lvl.ID())
-			view.setSelectedTiles(positions)
-		},
+	restoreState := func(bool) {
+		view.model.restoreFocus = true
+		view.setSelectedLevel(lvl.ID())
+		view.setSelectedTiles(positions)
+	}
+	err := queueLevelPatch(view.mod, view.commander, lvl,
+		fmt.Sprintf("Set %s of %d tile(s) on L%d", property, len(positions), lvl.ID()),
+		fmt.Sprintf("tiles/%d/%s/%v", lvl.ID(), property, positions),
+		restoreState)
+	if err != nil {
+		notifyLevelPatchFailure(view.modalStateMachine, err)
 	}
+}
 
-	newDataSet := lvl.EncodeState()
-	for id, newData := range &newDataSet {
-		if len(newData) > 0 {
-			resourceID := ids.LevelResourcesStart.Plus(lvlids.PerLevel*lvl.ID() + id)
-			patch, changed, err := view.mod.CreateBlockPatch(resource.LangAny, resourceID, 0, newData)
-			if err != nil {
-				fmt.Printf("err: %v\n", err)
-				// TODO how to handle this? We're not expecting this, so crash and burn?
-			} else if changed {
-				command.patches = append(command.patches, patch)
-			}
-		}
+// CopySelectedTile keeps the properties of the first selected tile for later pasting.
+func (view *TilesView) CopySelectedTile(lvl *level.Level) {
+	if len(view.model.selectedTiles.list) == 0 {
+		return
 	}
+	pos := view.model.selectedTiles.list[0]
+	if tile := lvl.Tile(int(pos.X.Tile()), int(pos.Y.Tile())); tile != nil {
+		copied := *tile
+		view.model.copiedTile = &copied
+	}
+}
 
-	view.commander.Queue(command)
+// PasteToSelectedTiles sets the properties of the copied tile to all selected tiles.
+// The objects in the tiles are kept.
+func (view *TilesView) PasteToSelectedTiles(lvl *level.Level) {
+	positions := view.model.selectedTiles.list
+	if (view.model.copiedTile == nil) || (len(positions) == 0) || !levelEditingAllowed(view.mod, lvl.ID()) {
+		return
+	}
+	copied := *view.model.copiedTile
+	view.changeTiles(lvl, positions, "all properties", func(tile *level.TileMapEntry) {
+		firstObjectIndex := tile.FirstObjectIndex
+		*tile = copied
+		tile.FirstObjectIndex = firstObjectIndex
+	})
 }
 
 func (view *TilesView) setSelectedLevel(id int) {
@@ -1,10 +1,13 @@
 package levels
 
+import "github.com/inkyblackness/hacked/ss1/content/archive/level"
+
 type tilesViewModel struct {
 	selectedTiles     tileCoordinates
 	textureDisplay    TextureDisplay
 	shadowDisplay     ColorDisplay
 	cyberColorDisplay ColorDisplay
+	copiedTile        *level.TileMapEntry
 
 	restoreFocus bool
 	windowOpen   bool
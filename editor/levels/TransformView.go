@@ -0,0 +1,149 @@
+package levels
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/maptransform"
+	"github.com/inkyblackness/hacked/ss1/edit/tilepaint"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// TransformView is for rotating, mirroring, and moving the selected region of a map.
+type TransformView struct {
+	mod *world.Mod
+
+	guiScale      float32
+	commander     cmd.Commander
+	eventListener event.Listener
+
+	modalStateMachine gui.ModalStateMachine
+
+	model transformViewModel
+}
+
+// NewTransformView returns a new instance.
+func NewTransformView(mod *world.Mod, modalStateMachine gui.ModalStateMachine, guiScale float32,
+	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *TransformView {
+	view := &TransformView{
+		mod: mod,
+
+		guiScale:      guiScale,
+		commander:     commander,
+		eventListener: eventListener,
+		model:         freshTransformViewModel(),
+
+		modalStateMachine: modalStateMachine,
+	}
+	view.model.selectedTiles.registerAt(eventRegistry)
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *TransformView) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *TransformView) Render(lvl *level.Level) {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 250 * view.guiScale}, imgui.ConditionOnce)
+		title := "Region Transform"
+		readOnly := !levelEditingAllowed(view.mod, lvl.ID())
+		if readOnly {
+			title += hintReadOnly
+		}
+		if imgui.BeginV(title+"###Region Transform", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent(lvl, readOnly)
+		}
+		imgui.End()
+	}
+}
+
+func (view *TransformView) renderContent(lvl *level.Level, readOnly bool) {
+	imgui.Text(fmt.Sprintf("Selected: %d tile(s)", len(view.model.selectedTiles.list)))
+	if readOnly {
+		imgui.Text("The level can not be modified.")
+		return
+	}
+	if len(view.model.selectedTiles.list) == 0 {
+		imgui.Text("Select tiles on the map to transform them.")
+		return
+	}
+	imgui.Text("Objects within the selected tiles move with them.")
+	imgui.Separator()
+
+	imgui.Text("Rotation is clockwise.")
+
+	for turns := 1; turns < 4; turns++ {
+		if turns > 1 {
+			imgui.SameLine()
+		}
+		label := fmt.Sprintf("Rotate %d", turns*90)
+		if imgui.Button(label) {
+			view.requestTransform(lvl, maptransform.Rotate(turns), label)
+		}
+	}
+	if imgui.Button("Mirror East-West") {
+		view.requestTransform(lvl, maptransform.MirrorEastWest(), "Mirror east-west")
+	}
+	imgui.SameLine()
+	if imgui.Button("Mirror North-South") {
+		view.requestTransform(lvl, maptransform.MirrorNorthSouth(), "Mirror north-south")
+	}
+
+	imgui.Separator()
+	columns, rows, _ := lvl.Size()
+	imgui.PushItemWidth(-150 * view.guiScale)
+	gui.StepSliderInt("Offset X (East)", &view.model.offsetX, -columns+1, columns-1)
+	gui.StepSliderInt("Offset Y (North)", &view.model.offsetY, -rows+1, rows-1)
+	imgui.PopItemWidth()
+	if ((view.model.offsetX != 0) || (view.model.offsetY != 0)) && imgui.Button("Move") {
+		view.requestTransform(lvl, maptransform.Translate(view.model.offsetX, view.model.offsetY),
+			fmt.Sprintf("Move by %d/%d", view.model.offsetX, view.model.offsetY))
+	}
+
+	if len(view.model.lastError) > 0 {
+		imgui.Text(view.model.lastError)
+	}
+}
+
+func (view *TransformView) requestTransform(lvl *level.Level, op maptransform.Operation, description string) {
+	oldPositions := view.model.selectedTiles.list
+	region := tilepaint.Area{}
+	for _, pos := range oldPositions {
+		region.Add(tilePoint(pos))
+	}
+	moved, err := op.Apply(lvl, region)
+	if err != nil {
+		view.model.lastError = fmt.Sprintf("Can not transform: %v.", err)
+		return
+	}
+	view.model.lastError = ""
+	newPositions := tilePositions(moved)
+
+	title := fmt.Sprintf("%s %d tile(s) on L%d", description, len(oldPositions), lvl.ID())
+	err = queueLevelPatch(view.mod, view.commander, lvl, title, "",
+		func(forward bool) {
+			view.model.restoreFocus = true
+			view.eventListener.Event(LevelSelectionSetEvent{id: lvl.ID()})
+			if forward {
+				view.eventListener.Event(TileSelectionSetEvent{tiles: newPositions})
+			} else {
+				view.eventListener.Event(TileSelectionSetEvent{tiles: oldPositions})
+			}
+		})
+	if err != nil {
+		notifyLevelPatchFailure(view.modalStateMachine, err)
+	}
+}
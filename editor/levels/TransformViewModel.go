@@ -0,0 +1,17 @@
+package levels
+
+type transformViewModel struct {
+	selectedTiles tileCoordinates
+
+	offsetX int
+	offsetY int
+
+	lastError string
+
+	restoreFocus bool
+	windowOpen   bool
+}
+
+func freshTransformViewModel() transformViewModel {
+	return transformViewModel{}
+}
@@ -0,0 +1,157 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/messagechain"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ChainsView shows how messages are linked, and lists issues of these links.
+type ChainsView struct {
+	mod          *world.Mod
+	messageCache *text.ElectronicMessageCache
+	movieCache   *movie.Cache
+	messagesView *View
+
+	guiScale float32
+
+	model chainsViewModel
+}
+
+// NewChainsView returns a new instance. Selected messages are shown in the given messages view.
+func NewChainsView(mod *world.Mod, messageCache *text.ElectronicMessageCache, movieCache *movie.Cache,
+	messagesView *View, guiScale float32) *ChainsView {
+	return &ChainsView{
+		mod:          mod,
+		messageCache: messageCache,
+		movieCache:   movieCache,
+		messagesView: messagesView,
+
+		guiScale: guiScale,
+
+		model: freshChainsViewModel(),
+	}
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *ChainsView) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *ChainsView) Render() {
+	if !view.model.windowOpen {
+		view.model.graph = nil
+		return
+	}
+	imgui.SetNextWindowSizeV(imgui.Vec2{X: 500 * view.guiScale, Y: 480 * view.guiScale}, imgui.ConditionOnce)
+	if imgui.BeginV("Message Chains", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+		view.renderContent()
+	}
+	imgui.End()
+}
+
+func (view *ChainsView) renderContent() {
+	imgui.PushItemWidth(-150 * view.guiScale)
+	if imgui.BeginCombo("Language", view.model.language.String()) {
+		for _, lang := range resource.Languages() {
+			if imgui.SelectableV(lang.String(), lang == view.model.language, 0, imgui.Vec2{}) {
+				view.model.language = lang
+				view.model.graph = nil
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.PopItemWidth()
+	if imgui.Button("Refresh") || (view.model.graph == nil) {
+		graph := messagechain.Analyze(messageSource{view: view}, view.model.language)
+		view.model.graph = &graph
+	}
+	imgui.SameLine()
+	imgui.Checkbox("Only chains with issues", &view.model.issuesOnly)
+	imgui.Separator()
+
+	graph := view.model.graph
+	if imgui.BeginChildV("Chains", imgui.Vec2{X: -1, Y: -150 * view.guiScale}, true, 0) {
+		for _, chain := range graph.Chains() {
+			view.renderChain(graph, chain)
+		}
+	}
+	imgui.EndChild()
+	imgui.Text(fmt.Sprintf("%d issues", len(graph.Issues)))
+	if imgui.BeginChildV("Issues", imgui.Vec2{X: -1, Y: -1}, true, 0) {
+		for index, issue := range graph.Issues {
+			label := fmt.Sprintf("%s: %v - %s###issue%d", messagechain.NameOf(issue.Key), issue.Kind, issue.Description, index)
+			if imgui.Selectable(label) {
+				view.messagesView.ShowMessage(issue.Key)
+			}
+		}
+	}
+	imgui.EndChild()
+}
+
+func (view *ChainsView) renderChain(graph *messagechain.Graph, chain []resource.Key) {
+	var issueCount int
+	for _, key := range chain {
+		issueCount += len(graph.IssuesOf(key))
+	}
+	if view.model.issuesOnly && (issueCount == 0) {
+		return
+	}
+	names := make([]string, len(chain))
+	for index, key := range chain {
+		names[index] = messagechain.NameOf(key)
+	}
+	label := strings.Join(names, " -> ")
+	if issueCount > 0 {
+		label += fmt.Sprintf(" (%d issues)", issueCount)
+	}
+	if imgui.TreeNode(label + "###" + names[0]) {
+		for _, key := range chain {
+			node, _ := graph.Node(key)
+			entry := fmt.Sprintf("%s: %s", messagechain.NameOf(key), node.Title)
+			if node.IsInterrupt {
+				entry += " (interrupt)"
+			}
+			if imgui.Selectable(entry) {
+				view.messagesView.ShowMessage(key)
+			}
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.6, Z: 0.2, W: 1.0})
+			for _, issue := range graph.IssuesOf(key) {
+				imgui.Text(fmt.Sprintf("  %v: %s", issue.Kind, issue.Description))
+			}
+			imgui.PopStyleColor()
+		}
+		imgui.TreePop()
+	}
+}
+
+// messageSource provides the messages and their resources for the analysis.
+type messageSource struct {
+	view *ChainsView
+}
+
+func (source messageSource) Message(key resource.Key) (text.ElectronicMessage, error) {
+	return source.view.messageCache.Message(key)
+}
+
+func (source messageSource) HasAudio(key resource.Key) bool {
+	sound, err := source.view.movieCache.Audio(resource.KeyOf(key.ID.Plus(300), key.Lang, key.Index))
+	return (err == nil) && !sound.Empty()
+}
+
+func (source messageSource) DisplayCount(lang resource.Language) int {
+	res, err := source.view.mod.LocalizedResources(lang).Select(ids.MfdDataBitmaps)
+	if err != nil {
+		return 0
+	}
+	return res.BlockCount()
+}
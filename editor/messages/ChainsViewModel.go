@@ -0,0 +1,20 @@
+package messages
+
+import (
+	"github.com/inkyblackness/hacked/ss1/edit/messagechain"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+type chainsViewModel struct {
+	windowOpen bool
+
+	language   resource.Language
+	issuesOnly bool
+	graph      *messagechain.Graph
+}
+
+func freshChainsViewModel() chainsViewModel {
+	return chainsViewModel{
+		language: resource.LangDefault,
+	}
+}
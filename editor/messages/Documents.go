@@ -0,0 +1,281 @@
+package messages
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/messagedoc"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const jsonDocumentFilename = "messages.json"
+
+var documentTypes = []external.TypeInfo{{
+	Title:      "Message documents (*.json, *.md)",
+	Extensions: []string{"json", "md"},
+}}
+
+var messageDocumentTypes = map[resource.ID]string{
+	ids.MailsStart:     messagedoc.TypeMail,
+	ids.LogsStart:      messagedoc.TypeLog,
+	ids.FragmentsStart: messagedoc.TypeFragment,
+}
+
+func messageTypeID(docType string) (resource.ID, bool) {
+	for id, name := range messageDocumentTypes {
+		if name == docType {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+type documentEntry struct {
+	doc   messagedoc.Message
+	sound audio.L8
+}
+
+// documentEntries collects all messages that exist in any language.
+// Mails and logs carry their audio, if there is any.
+func (view *View) documentEntries() []documentEntry {
+	var entries []documentEntry
+	for _, id := range knownMessageTypesOrder {
+		info, _ := ids.Info(id)
+		withAudio := (id == ids.MailsStart) || (id == ids.LogsStart)
+		for index := 0; index < info.MaxCount; index++ {
+			for _, lang := range resource.Languages() {
+				msg, err := view.messageCache.Message(resource.KeyOf(id, lang, index))
+				if err != nil {
+					continue
+				}
+				entry := documentEntry{doc: messagedoc.FromElectronicMessage(messageDocumentTypes[id], index, lang.String(), msg)}
+				if withAudio {
+					sound, _ := view.movieCache.Audio(resource.KeyOf(id.Plus(300), lang, index))
+					if !sound.Empty() {
+						entry.doc.Audio = entry.doc.BaseName() + ".wav"
+						entry.sound = sound
+					}
+				}
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+func (view *View) requestExportDocuments(asMarkdown bool) {
+	info := "All mails, logs, and fragments of all languages are written to the folder,\n" +
+		"together with their audio as WAV files.\n"
+	if asMarkdown {
+		info += "Each message is written as a separate Markdown file."
+	} else {
+		info += "File to be written: " + jsonDocumentFilename
+	}
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		entries := view.documentEntries()
+		err := writeAudioFiles(dirname, entries)
+		if err == nil && asMarkdown {
+			err = writeMarkdownFiles(dirname, entries)
+		} else if err == nil {
+			err = writeJSONFile(dirname, entries)
+		}
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not write files.\n"+info, exportTo, true)
+			return
+		}
+		external.Notice(view.modalStateMachine, "Message Export", fmt.Sprintf("%d messages exported.", len(entries)))
+	}
+
+	external.Export(view.modalStateMachine, info, exportTo, false)
+}
+
+func writeAudioFiles(dirname string, entries []documentEntry) error {
+	for _, entry := range entries {
+		if entry.sound.Empty() {
+			continue
+		}
+		buf := bytes.NewBuffer(nil)
+		err := wav.Save(buf, entry.sound.SampleRate, entry.sound.Samples)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(dirname, entry.doc.Audio), buf.Bytes(), 0640)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMarkdownFiles(dirname string, entries []documentEntry) error {
+	for _, entry := range entries {
+		buf := bytes.NewBuffer(nil)
+		err := messagedoc.WriteMarkdown(buf, entry.doc)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(dirname, entry.doc.BaseName()+".md"), buf.Bytes(), 0640)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSONFile(dirname string, entries []documentEntry) error {
+	docs := make([]messagedoc.Message, len(entries))
+	for index, entry := range entries {
+		docs[index] = entry.doc
+	}
+	buf := bytes.NewBuffer(nil)
+	err := messagedoc.WriteJSON(buf, docs)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dirname, jsonDocumentFilename), buf.Bytes(), 0640)
+}
+
+func (view *View) requestImportDocuments() {
+	info := "File must be a JSON message document, or a Markdown message file.\n" +
+		"For Markdown, all .md files of the folder are imported."
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		var docs []messagedoc.Message
+		var err error
+		if strings.EqualFold(filepath.Ext(filename), ".md") {
+			docs, err = readMarkdownFiles(filepath.Dir(filename))
+		} else {
+			docs, err = readJSONFile(filename)
+		}
+		if err != nil {
+			external.Import(view.modalStateMachine, fmt.Sprintf("File could not be read: %v\n%s", err, info),
+				documentTypes, fileHandler, true)
+			return
+		}
+		view.importDocuments(filepath.Dir(filename), docs)
+	}
+
+	external.Import(view.modalStateMachine, info, documentTypes, fileHandler, false)
+}
+
+func readJSONFile(filename string) ([]messagedoc.Message, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return messagedoc.ReadJSON(reader)
+}
+
+func readMarkdownFiles(dirname string) ([]messagedoc.Message, error) {
+	filenames, err := filepath.Glob(filepath.Join(dirname, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]messagedoc.Message, 0, len(filenames))
+	for _, filename := range filenames {
+		reader, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := messagedoc.ReadMarkdown(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filepath.Base(filename), err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// importDocuments sets all the given messages, together with their audio, as one undoable step.
+// Messages that can not be applied are reported.
+func (view *View) importDocuments(dirname string, docs []messagedoc.Message) {
+	var commands cmd.List
+	var problems []string
+	for _, doc := range docs {
+		command, err := view.documentCommand(dirname, doc)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", doc.BaseName(), err))
+			continue
+		}
+		commands = append(commands, command)
+	}
+	if len(commands) > 0 {
+		view.commander.Queue(cmd.Named{Command: commands, Title: fmt.Sprintf("Import %d message(s)", len(commands))})
+	}
+
+	const maxProblems = 10
+	lines := []string{fmt.Sprintf("%d messages imported.", len(commands))}
+	if len(problems) > 0 {
+		lines = append(lines, fmt.Sprintf("%d messages rejected:", len(problems)))
+	}
+	for index, problem := range problems {
+		if index >= maxProblems {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(problems)-maxProblems))
+			break
+		}
+		lines = append(lines, "  "+problem)
+	}
+	external.Notice(view.modalStateMachine, "Message Import", strings.Join(lines, "\n"))
+}
+
+func (view *View) documentCommand(dirname string, doc messagedoc.Message) (setMessageDataCommand, error) {
+	id, _ := messageTypeID(doc.Type)
+	info, _ := ids.Info(id)
+	if doc.Index >= info.MaxCount {
+		return setMessageDataCommand{}, fmt.Errorf("index out of range")
+	}
+	lang, known := resource.LanguageNamed(doc.Language)
+	if !known {
+		return setMessageDataCommand{}, fmt.Errorf("unknown language <%s>", doc.Language)
+	}
+	msg := doc.ElectronicMessage()
+	cp := view.cps.ForLanguage(lang)
+	unencodable := text.Unencodable(cp, msg.Title+msg.Sender+msg.Subject+msg.VerboseText+msg.TerseText)
+	if len(unencodable) > 0 {
+		return setMessageDataCommand{}, fmt.Errorf("characters not in codepage: %s", string(unencodable))
+	}
+
+	key := resource.KeyOf(id, lang, doc.Index)
+	textID := id.Plus(doc.Index)
+	command := setMessageDataCommand{
+		model:           &view.model,
+		key:             key,
+		showVerboseText: view.model.showVerboseText,
+		textEntries: map[resource.Language]messageDataEntry{
+			lang: {oldData: view.mod.ModifiedBlocks(lang, textID), newData: msg.Encode(cp)},
+		},
+	}
+	if len(doc.Audio) > 0 {
+		if (id != ids.MailsStart) && (id != ids.LogsStart) {
+			return setMessageDataCommand{}, fmt.Errorf("fragments can not have audio")
+		}
+		audioFilename := doc.Audio
+		if !filepath.IsAbs(audioFilename) {
+			audioFilename = filepath.Join(dirname, audioFilename)
+		}
+		sound, _, err := external.LoadAudio(audioFilename)
+		if err != nil {
+			return setMessageDataCommand{}, fmt.Errorf("audio <%s> could not be loaded", doc.Audio)
+		}
+		command.audioEntries = map[resource.Language]messageDataEntry{
+			lang: {oldData: view.mod.ModifiedBlocks(lang, textID.Plus(300)), newData: [][]byte{movie.ContainSoundData(sound)}},
+		}
+	}
+	return command, nil
+}
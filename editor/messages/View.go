@@ -8,8 +8,11 @@ import (
 	"github.com/inkyblackness/hacked/editor/external"
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/editor/textpreview"
 	"github.com/inkyblackness/hacked/editor/values"
+	"github.com/inkyblackness/hacked/editor/waveform"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/font"
 	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
@@ -46,9 +49,12 @@ const videoMailDisplayBase = 256
 type View struct {
 	mod          *world.Mod
 	messageCache *text.ElectronicMessageCache
-	cp           text.Codepage
+	cps          text.Codepages
 	movieCache   *movie.Cache
 	imageCache   *graphics.TextureCache
+	preview      *textpreview.Preview
+
+	waveformEditor *waveform.Editor
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
@@ -59,16 +65,19 @@ type View struct {
 }
 
 // NewMessagesView returns a new instance.
-func NewMessagesView(mod *world.Mod, messageCache *text.ElectronicMessageCache, cp text.Codepage,
-	movieCache *movie.Cache, imageCache *graphics.TextureCache,
+func NewMessagesView(mod *world.Mod, messageCache *text.ElectronicMessageCache, cps text.Codepages,
+	movieCache *movie.Cache, imageCache *graphics.TextureCache, fontCache *font.Cache, frameCache *graphics.FrameCache,
 	modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
 	guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		messageCache: messageCache,
-		cp:           cp,
+		cps:          cps,
 		movieCache:   movieCache,
 		imageCache:   imageCache,
+		preview:      textpreview.NewPreview(fontCache, frameCache, cps, guiScale),
+
+		waveformEditor: waveform.NewEditor(frameCache, modalStateMachine, guiScale),
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
@@ -85,6 +94,12 @@ func (view *View) WindowOpen() *bool {
 	return &view.model.windowOpen
 }
 
+// ShowMessage opens the view with the message of given key selected.
+func (view *View) ShowMessage(key resource.Key) {
+	view.model.currentKey = key
+	view.model.restoreFocus = true
+}
+
 // Render renders the view.
 func (view *View) Render() {
 	if view.model.restoreFocus {
@@ -128,6 +143,19 @@ func (view *View) renderContent() {
 			imgui.EndCombo()
 		}
 
+		if imgui.Button("Export JSON...") {
+			view.requestExportDocuments(false)
+		}
+		imgui.SameLine()
+		if imgui.Button("Export Markdown...") {
+			view.requestExportDocuments(true)
+		}
+		imgui.SameLine()
+		if imgui.Button("Import...") {
+			view.requestImportDocuments()
+		}
+		imgui.Separator()
+
 		message, msgReadOnly := view.currentMessage()
 		availableDisplays := view.availableDisplays()
 
@@ -168,6 +196,9 @@ func (view *View) renderContent() {
 			if imgui.Button("Clear") {
 				view.requestClearAudio()
 			}
+			if !sound.Empty() {
+				view.waveformEditor.Render(sound, soundReadOnly, view.requestSetSound)
+			}
 			imgui.PopID()
 		}
 		imgui.Separator()
@@ -292,6 +323,17 @@ func (view *View) renderMFDs() {
 	})
 	imgui.SameLineV(0, 0)
 	view.renderSideImage("RightMFD", message.RightDisplay)
+
+	if imgui.TreeNodeV("Preview", imgui.TreeNodeFlagsFramed) {
+		previewKind := textpreview.KindMailVerbose
+		if !view.model.showVerboseText {
+			previewKind = textpreview.KindMailTerse
+		}
+		imgui.PushItemWidth(-150 * view.guiScale)
+		view.preview.Render(textToDisplay, view.model.currentKey.Lang, previewKind)
+		imgui.PopItemWidth()
+		imgui.TreePop()
+	}
 }
 
 func (view View) currentMessage() (msg text.ElectronicMessage, readOnly bool) {
@@ -402,6 +444,10 @@ func (view *View) requestImportAudio() {
 	})
 }
 
+func (view *View) requestSetSound(sound audio.L8) {
+	view.requestAudioChange(movie.ContainSoundData(sound))
+}
+
 func (view *View) requestClearAudio() {
 	view.requestAudioChange(view.silence())
 }
@@ -412,7 +458,7 @@ func (view View) silence() []byte {
 }
 
 func (view *View) requestClearAll() {
-	view.requestWipe(text.EmptyElectronicMessage().Encode(view.cp), view.silence())
+	view.requestWipe(text.EmptyElectronicMessage().Encode(view.cps.ForLanguage(view.model.currentKey.Lang)), view.silence())
 }
 
 func (view *View) requestRemoveAll() {
@@ -423,7 +469,7 @@ func (view *View) requestWipe(newTextData [][]byte, newAudioData []byte) {
 	textEntries := make(map[resource.Language]messageDataEntry)
 	audioEntries := make(map[resource.Language]messageDataEntry)
 	textID := view.model.currentKey.ID.Plus(view.model.currentKey.Index)
-	for lang := resource.Language(0); lang < resource.LanguageCount; lang++ {
+	for _, lang := range resource.Languages() {
 		textEntries[lang] = messageDataEntry{
 			oldData: view.mod.ModifiedBlocks(lang, textID),
 			newData: newTextData,
@@ -442,17 +488,23 @@ func (view *View) requestTextChange(modifier func(*text.ElectronicMessage)) {
 	msg := view.messageOf(view.model.currentKey)
 	entries := make(map[resource.Language]messageDataEntry)
 	modifier(&msg)
+	cp := view.cps.ForLanguage(view.model.currentKey.Lang)
+	unencodable := text.Unencodable(cp, msg.Title+msg.Sender+msg.Subject+msg.VerboseText+msg.TerseText)
+	if len(unencodable) > 0 {
+		external.UnencodableNotice(view.modalStateMachine, view.model.currentKey.Lang, unencodable)
+		return
+	}
 
 	entries[view.model.currentKey.Lang] = messageDataEntry{
 		oldData: view.mod.ModifiedBlocks(view.model.currentKey.Lang, view.model.currentKey.ID.Plus(view.model.currentKey.Index)),
-		newData: msg.Encode(view.cp),
+		newData: msg.Encode(cp),
 	}
 	view.requestSetMessageData(entries, nil)
 }
 
 func (view *View) requestPropertyChange(modifier func(*text.ElectronicMessage)) {
 	entries := make(map[resource.Language]messageDataEntry)
-	for lang := resource.Language(0); lang < resource.LanguageCount; lang++ {
+	for _, lang := range resource.Languages() {
 		key := view.model.currentKey
 		key.Lang = lang
 		msg := view.messageOf(key)
@@ -460,7 +512,7 @@ func (view *View) requestPropertyChange(modifier func(*text.ElectronicMessage))
 
 		entries[lang] = messageDataEntry{
 			oldData: view.mod.ModifiedBlocks(lang, key.ID.Plus(key.Index)),
-			newData: msg.Encode(view.cp),
+			newData: msg.Encode(view.cps.ForLanguage(lang)),
 		}
 	}
 	view.requestSetMessageData(entries, nil)
@@ -484,5 +536,8 @@ func (view *View) requestSetMessageData(textEntries map[resource.Language]messag
 		textEntries:     textEntries,
 		audioEntries:    audioEntries,
 	}
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{
+		Command: command,
+		Title:   fmt.Sprintf("Change message %v[%d] (%v)", command.key.ID, command.key.Index, command.key.Lang),
+	})
 }
@@ -3,7 +3,6 @@ package movies
 import (
 	"github.com/inkyblackness/imgui-go"
 
-	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
@@ -15,17 +14,16 @@ type compressingStartState struct {
 
 	width    int
 	height   int
-	input    movie.Scene
+	source   sceneSourceFunc
 	listener compressionListenerFunc
 }
 
 func (state compressingStartState) Render() {
 	imgui.OpenPopup("Compressing...")
-	task := newCompressionTask(state.input, state.width, state.height)
+	task := newCompressionTask(state.source, state.width, state.height)
 	state.machine.SetState(&compressingWaitingState{
 		machine:  state.machine,
 		view:     state.view,
-		input:    state.input,
 		listener: state.listener,
 		task:     task,
 	})
@@ -3,7 +3,6 @@ package movies
 import (
 	"github.com/inkyblackness/imgui-go"
 
-	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
@@ -11,7 +10,6 @@ type compressingWaitingState struct {
 	machine gui.ModalStateMachine
 	view    *View
 
-	input    movie.Scene
 	listener compressionListenerFunc
 	task     *compressionTask
 }
@@ -6,10 +6,13 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/movie"
 )
 
+// sceneSourceFunc provides the uncompressed scene. It is called from within the compression task.
+type sceneSourceFunc func(ctx context.Context) (movie.Scene, error)
+
 type compressionTask struct {
 	width      int
 	height     int
-	input      movie.Scene
+	source     sceneSourceFunc
 	ctx        context.Context
 	ctxCancel  context.CancelFunc
 	resultChan chan compressionResult
@@ -23,11 +26,11 @@ type compressionFailed struct{ err error }
 
 type compressionFinished struct{ scene movie.HighResScene }
 
-func newCompressionTask(scene movie.Scene, width, height int) *compressionTask {
+func newCompressionTask(source sceneSourceFunc, width, height int) *compressionTask {
 	task := &compressionTask{
 		width:      width,
 		height:     height,
-		input:      scene,
+		source:     source,
 		resultChan: make(chan compressionResult),
 	}
 	task.ctx, task.ctxCancel = context.WithCancel(context.Background())
@@ -37,7 +40,11 @@ func newCompressionTask(scene movie.Scene, width, height int) *compressionTask {
 
 func (task *compressionTask) run() {
 	defer close(task.resultChan)
-	highResScene, err := movie.HighResSceneFrom(task.ctx, task.input, task.width, task.height)
+	var highResScene movie.HighResScene
+	input, err := task.source(task.ctx)
+	if err == nil {
+		highResScene, err = movie.HighResSceneFrom(task.ctx, input, task.width, task.height)
+	}
 	switch {
 	case task.ctx.Err() != nil:
 		task.resultChan <- compressionAborted{}
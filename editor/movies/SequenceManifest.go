@@ -0,0 +1,143 @@
+package movies
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+const defaultSequenceFrameTime = 100 * time.Millisecond
+
+// sequenceManifest describes a scene made of individual image files.
+// The manifest is stored as JSON. All file references are relative to the manifest file.
+//
+// If no frames are listed, all PNG files of the manifest directory are taken in alphabetical order.
+type sequenceManifest struct {
+	// FrameTime is the default display time of a frame, in Go duration format, such as "66ms".
+	FrameTime string `json:"frameTime"`
+	// Frames lists the image files in order.
+	Frames []sequenceManifestFrame `json:"frames"`
+	// Audio optionally refers to a WAV file.
+	Audio string `json:"audio"`
+	// Subtitles optionally map language names to subtitle files.
+	Subtitles map[string]string `json:"subtitles"`
+}
+
+type sequenceManifestFrame struct {
+	// File is the name of the PNG file.
+	File string `json:"file"`
+	// Time optionally specifies the display time of this frame.
+	Time string `json:"time"`
+}
+
+type sequenceFrameFile struct {
+	filename    string
+	displayTime time.Duration
+}
+
+// sequence is a resolved manifest, with absolute file names and parsed values.
+type sequence struct {
+	frames    []sequenceFrameFile
+	audio     string
+	subtitles map[resource.Language]string
+}
+
+func readSequenceManifest(reader io.Reader) (sequenceManifest, error) {
+	var manifest sequenceManifest
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&manifest)
+	return manifest, err
+}
+
+func (manifest sequenceManifest) resolve(baseDir string) (sequence, error) {
+	var seq sequence
+	localPath := func(name string) string {
+		if filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(baseDir, name)
+	}
+	frameTime := defaultSequenceFrameTime
+	if len(manifest.FrameTime) > 0 {
+		parsed, err := parseFrameTime(manifest.FrameTime)
+		if err != nil {
+			return seq, err
+		}
+		frameTime = parsed
+	}
+
+	frames := manifest.Frames
+	if len(frames) == 0 {
+		listed, err := pngFilesIn(baseDir)
+		if err != nil {
+			return seq, err
+		}
+		for _, name := range listed {
+			frames = append(frames, sequenceManifestFrame{File: name})
+		}
+	}
+	if len(frames) == 0 {
+		return seq, fmt.Errorf("no frames found")
+	}
+	for index, frame := range frames {
+		if len(frame.File) == 0 {
+			return seq, fmt.Errorf("frame %d has no file", index)
+		}
+		displayTime := frameTime
+		if len(frame.Time) > 0 {
+			parsed, err := parseFrameTime(frame.Time)
+			if err != nil {
+				return seq, fmt.Errorf("frame %d: %v", index, err)
+			}
+			displayTime = parsed
+		}
+		seq.frames = append(seq.frames, sequenceFrameFile{filename: localPath(frame.File), displayTime: displayTime})
+	}
+
+	if len(manifest.Audio) > 0 {
+		seq.audio = localPath(manifest.Audio)
+	}
+	seq.subtitles = make(map[resource.Language]string)
+	for langName, file := range manifest.Subtitles {
+		lang, known := resource.LanguageNamed(langName)
+		if !known {
+			return seq, fmt.Errorf("unknown subtitle language <%s>", langName)
+		}
+		seq.subtitles[lang] = localPath(file)
+	}
+	return seq, nil
+}
+
+func parseFrameTime(value string) (time.Duration, error) {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("frame time %v must be positive", parsed)
+	}
+	return parsed, nil
+}
+
+func pngFilesIn(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, info := range infos {
+		if !info.IsDir() && strings.EqualFold(filepath.Ext(info.Name()), ".png") {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,54 @@
+package movies
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestSequenceManifestResolvesRelativeFiles(t *testing.T) {
+	manifest, err := readSequenceManifest(strings.NewReader(`{
+		"frameTime": "50ms",
+		"frames": [{"file": "a.png"}, {"file": "b.png", "time": "200ms"}],
+		"audio": "sound.wav",
+		"subtitles": {"german": "de.srt"}
+	}`))
+	require.Nil(t, err)
+
+	seq, err := manifest.resolve("base")
+
+	require.Nil(t, err)
+	assert.Equal(t, []sequenceFrameFile{
+		{filename: filepath.Join("base", "a.png"), displayTime: 50 * time.Millisecond},
+		{filename: filepath.Join("base", "b.png"), displayTime: 200 * time.Millisecond},
+	}, seq.frames)
+	assert.Equal(t, filepath.Join("base", "sound.wav"), seq.audio)
+	assert.Equal(t, map[resource.Language]string{resource.LangGerman: filepath.Join("base", "de.srt")}, seq.subtitles)
+}
+
+func TestSequenceManifestRejectsUnknownLanguage(t *testing.T) {
+	manifest := sequenceManifest{
+		Frames:    []sequenceManifestFrame{{File: "a.png"}},
+		Subtitles: map[string]string{"Klingon": "tlh.srt"},
+	}
+
+	_, err := manifest.resolve("base")
+
+	assert.NotNil(t, err)
+}
+
+func TestSequenceManifestRejectsInvalidFrameTime(t *testing.T) {
+	manifest := sequenceManifest{
+		Frames: []sequenceManifestFrame{{File: "a.png", Time: "-5ms"}},
+	}
+
+	_, err := manifest.resolve("base")
+
+	assert.NotNil(t, err)
+}
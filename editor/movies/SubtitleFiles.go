@@ -0,0 +1,494 @@
+package movies
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asticode/go-astisub"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+type subtitleFormat int
+
+const (
+	subtitleFormatSRT subtitleFormat = iota
+	subtitleFormatWebVTT
+	subtitleFormatASS
+)
+
+var subtitleFormats = []subtitleFormat{subtitleFormatSRT, subtitleFormatWebVTT, subtitleFormatASS}
+
+var subtitleTypes = []external.TypeInfo{{
+	Title:      "Subtitle files (*.srt, *.vtt, *.ass, *.ssa)",
+	Extensions: []string{"srt", "vtt", "ass", "ssa"},
+}}
+
+func (format subtitleFormat) String() string {
+	switch format {
+	case subtitleFormatSRT:
+		return "SRT"
+	case subtitleFormatWebVTT:
+		return "WebVTT"
+	case subtitleFormatASS:
+		return "ASS"
+	default:
+		return "Unknown"
+	}
+}
+
+func (format subtitleFormat) extension() string {
+	switch format {
+	case subtitleFormatWebVTT:
+		return "vtt"
+	case subtitleFormatASS:
+		return "ass"
+	default:
+		return "srt"
+	}
+}
+
+// resolution returns the finest time step the format can store.
+func (format subtitleFormat) resolution() time.Duration {
+	if format == subtitleFormatASS {
+		return 10 * time.Millisecond
+	}
+	return time.Millisecond
+}
+
+func subtitleFormatOf(filename string) (subtitleFormat, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".srt":
+		return subtitleFormatSRT, true
+	case ".vtt":
+		return subtitleFormatWebVTT, true
+	case ".ass", ".ssa":
+		return subtitleFormatASS, true
+	default:
+		return subtitleFormatSRT, false
+	}
+}
+
+// subtitleTrack is a list of subtitles, optionally associated with a style name.
+type subtitleTrack struct {
+	style string
+	list  movie.SubtitleList
+	// markup holds, if any entry has inline styling, the text of each entry including its style tags.
+	// The tags are in the syntax of the format the track is read from or written to.
+	markup []string
+}
+
+// markupOf returns the text of the entry with given index including its style tags.
+// The result is empty if the track has no markup for the entry.
+func (track subtitleTrack) markupOf(index int) string {
+	if index >= len(track.markup) {
+		return ""
+	}
+	return track.markup[index]
+}
+
+// styledText is the text of one subtitle line, as it was read from a subtitle file.
+type styledText struct {
+	style  string
+	plain  string
+	markup string
+}
+
+// subtitleStyles keeps the styled lines that make up the entries of a subtitle list, keyed by the
+// timestamp of the entry. The game only shows plain text, so the styling is kept aside to be written
+// again when the list is exported.
+type subtitleStyles struct {
+	format subtitleFormat
+	texts  map[time.Duration][]styledText
+}
+
+// unnamed returns a copy of the styles without the style names.
+func (styles subtitleStyles) unnamed() subtitleStyles {
+	result := subtitleStyles{format: styles.format, texts: make(map[time.Duration][]styledText)}
+	for timestamp, texts := range styles.texts {
+		for _, text := range texts {
+			text.style = ""
+			result.texts[timestamp] = append(result.texts[timestamp], text)
+		}
+	}
+	return result
+}
+
+// readSubtitles decodes the subtitles from given reader.
+// The result is grouped by the style of the lines. Formats without styles return one track with an empty style.
+//
+// Any styling within the lines is removed from the text, and kept in the markup of the track.
+// If there is a gap between the end of one line and the start of the next, an empty entry is inserted
+// to clear the text.
+func readSubtitles(reader io.Reader, format subtitleFormat) ([]subtitleTrack, error) {
+	var subtitles *astisub.Subtitles
+	var err error
+	switch format {
+	case subtitleFormatWebVTT:
+		subtitles, err = astisub.ReadFromWebVTT(reader)
+	case subtitleFormatASS:
+		var data []byte
+		data, err = ioutil.ReadAll(reader)
+		if err == nil {
+			subtitles, err = astisub.ReadFromSSA(bytes.NewReader(data))
+		}
+		if err == nil {
+			restoreSSATexts(subtitles, data)
+		}
+	default:
+		subtitles, err = astisub.ReadFromSRT(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	itemsByStyle := make(map[string][]*astisub.Item)
+	var styles []string
+	for _, item := range subtitles.Items {
+		style := ""
+		if item.Style != nil {
+			style = item.Style.ID
+		}
+		if _, known := itemsByStyle[style]; !known {
+			styles = append(styles, style)
+		}
+		itemsByStyle[style] = append(itemsByStyle[style], item)
+	}
+	tracks := make([]subtitleTrack, 0, len(styles))
+	for _, style := range styles {
+		tracks = append(tracks, subtitleTrackFromItems(style, itemsByStyle[style], format))
+	}
+	return tracks, nil
+}
+
+// restoreSSATexts sets the lines of the items from the texts of the dialogue events, as they are in the data.
+// The items that the SSA reader provides lose any text in front of the first override tag of a line.
+func restoreSSATexts(subtitles *astisub.Subtitles, data []byte) {
+	texts := ssaDialogueTexts(data)
+	if len(texts) != len(subtitles.Items) {
+		return
+	}
+	for index, item := range subtitles.Items {
+		item.Lines = nil
+		for _, line := range strings.Split(texts[index], `\n`) {
+			item.Lines = append(item.Lines, astisub.Line{Items: []astisub.LineItem{{Text: strings.TrimSpace(line)}}})
+		}
+	}
+}
+
+// ssaDialogueTexts returns the texts of all dialogue events in the data, in order.
+func ssaDialogueTexts(data []byte) []string {
+	var texts []string
+	inEvents := false
+	fieldCount := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		separator := strings.Index(line, ":")
+		if !inEvents || (separator < 0) {
+			continue
+		}
+		header, content := line[:separator], strings.TrimSpace(line[separator+1:])
+		switch header {
+		case "Format":
+			fieldCount = len(strings.Split(content, ","))
+		case "Dialogue":
+			fields := strings.SplitN(content, ",", fieldCount)
+			if (fieldCount > 0) && (len(fields) == fieldCount) {
+				texts = append(texts, fields[fieldCount-1])
+			}
+		}
+	}
+	return texts
+}
+
+func subtitleTrackFromItems(style string, items []*astisub.Item, format subtitleFormat) subtitleTrack {
+	sorted := make([]*astisub.Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].StartAt < sorted[b].StartAt })
+
+	track := subtitleTrack{style: style}
+	var markup []string
+	styled := false
+	for index, item := range sorted {
+		var lines []string
+		var markupLines []string
+		for _, line := range item.Lines {
+			var lineText string
+			var lineMarkup string
+			for _, lineItem := range line.Items {
+				if lineItem.InlineStyle != nil {
+					lineMarkup += lineItem.InlineStyle.SSAEffect
+				}
+				lineText += lineItem.Text
+				lineMarkup += lineItem.Text
+			}
+			lines = append(lines, plainSubtitleText(lineText, format))
+			markupLines = append(markupLines, lineMarkup)
+		}
+		text := strings.Join(lines, "\n")
+		entryMarkup := strings.Join(markupLines, "\n")
+		styled = styled || (entryMarkup != text)
+		track.list.Entries = append(track.list.Entries, movie.Subtitle{Timestamp: item.StartAt, Text: text})
+		markup = append(markup, entryMarkup)
+		hasGap := (index+1 < len(sorted)) && (item.EndAt < sorted[index+1].StartAt) && (item.EndAt > item.StartAt)
+		if hasGap {
+			track.list.Entries = append(track.list.Entries, movie.Subtitle{Timestamp: item.EndAt})
+			markup = append(markup, "")
+		}
+	}
+	if styled {
+		track.markup = markup
+	}
+	return track
+}
+
+// mergedSubtitleTracks combines the tracks into one list.
+// Texts of several tracks that are shown at the same time are joined, in order of the tracks.
+// The styled lines that make up the entries are returned as well, with their markup in the given format.
+func mergedSubtitleTracks(tracks []subtitleTrack, format subtitleFormat) (movie.SubtitleList, subtitleStyles) {
+	var timestamps []time.Duration
+	for _, track := range tracks {
+		for _, entry := range track.list.Entries {
+			timestamps = append(timestamps, entry.Timestamp)
+		}
+	}
+	sort.Slice(timestamps, func(a, b int) bool { return timestamps[a] < timestamps[b] })
+
+	var list movie.SubtitleList
+	styles := subtitleStyles{format: format, texts: make(map[time.Duration][]styledText)}
+	current := make([]styledText, len(tracks))
+	next := make([]int, len(tracks))
+	for _, timestamp := range timestamps {
+		for index, track := range tracks {
+			entries := track.list.Entries
+			for (next[index] < len(entries)) && (entries[next[index]].Timestamp <= timestamp) {
+				current[index] = styledText{
+					style:  track.style,
+					plain:  entries[next[index]].Text,
+					markup: track.markupOf(next[index]),
+				}
+				next[index]++
+			}
+		}
+		var shown []styledText
+		var texts []string
+		for _, line := range current {
+			if len(line.plain) > 0 {
+				shown = append(shown, line)
+				texts = append(texts, line.plain)
+			}
+		}
+		text := strings.Join(texts, "\n")
+		lastIndex := len(list.Entries) - 1
+		if ((lastIndex < 0) && (len(text) == 0)) || ((lastIndex >= 0) && (list.Entries[lastIndex].Text == text)) {
+			continue
+		}
+		list.Entries = append(list.Entries, movie.Subtitle{Timestamp: timestamp, Text: text})
+		styles.texts[timestamp] = shown
+	}
+	return list, styles
+}
+
+// styledSubtitleTracks splits the list into tracks again, according to the styled lines that were kept
+// for its entries. Entries of which the text no longer matches the kept lines are put in the default style.
+// Style names are only kept for the ASS format, and markup only if it is in the given format.
+func styledSubtitleTracks(list movie.SubtitleList, styles subtitleStyles, defaultStyle string,
+	format subtitleFormat) []subtitleTrack {
+	keepNames := format == subtitleFormatASS
+	keepMarkup := styles.format == format
+	var tracks []subtitleTrack
+	trackIndices := make(map[string]int)
+	for _, entry := range list.Entries {
+		lines := styles.texts[entry.Timestamp]
+		var texts []string
+		for _, line := range lines {
+			texts = append(texts, line.plain)
+		}
+		if strings.Join(texts, "\n") != entry.Text {
+			lines = []styledText{{plain: entry.Text}}
+		}
+
+		shown := make(map[string]styledText)
+		for _, line := range lines {
+			name := defaultStyle
+			if keepNames && (len(line.style) > 0) {
+				name = line.style
+			}
+			if !keepMarkup {
+				line.markup = ""
+			}
+			if _, known := trackIndices[name]; !known {
+				trackIndices[name] = len(tracks)
+				tracks = append(tracks, subtitleTrack{style: name})
+			}
+			if previous, known := shown[name]; known {
+				line.plain = previous.plain + "\n" + line.plain
+				if (len(previous.markup) > 0) && (len(line.markup) > 0) {
+					line.markup = previous.markup + "\n" + line.markup
+				} else {
+					line.markup = ""
+				}
+			}
+			shown[name] = line
+		}
+
+		for index := range tracks {
+			track := &tracks[index]
+			line := shown[track.style]
+			lastIndex := len(track.list.Entries) - 1
+			if (lastIndex >= 0) && (track.list.Entries[lastIndex].Text == line.plain) &&
+				(track.markup[lastIndex] == line.markup) {
+				continue
+			}
+			if (lastIndex < 0) && (len(line.plain) == 0) {
+				continue
+			}
+			track.list.Entries = append(track.list.Entries, movie.Subtitle{Timestamp: entry.Timestamp, Text: line.plain})
+			track.markup = append(track.markup, line.markup)
+		}
+	}
+	return tracks
+}
+
+var webVTTEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&nbsp;", " ", "&lrm;", "", "&rlm;", "")
+
+func plainSubtitleText(value string, format subtitleFormat) string {
+	switch format {
+	case subtitleFormatWebVTT:
+		return webVTTEntities.Replace(withoutEnclosed(value, '<', '>'))
+	case subtitleFormatASS:
+		value = withoutEnclosed(value, '{', '}')
+		return strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(value)
+	default:
+		return srtStyleTags.ReplaceAllString(value, "")
+	}
+}
+
+var srtStyleTags = regexp.MustCompile(`(?i)</?(i|b|u|s|font)(\s[^>]*)?>|\{\\[^}]*}`)
+
+// withoutEnclosed removes all parts of the text that are enclosed by the given markers.
+// These are typically inline style tags. Unterminated markers are kept.
+func withoutEnclosed(value string, start, end rune) string {
+	var result strings.Builder
+	rest := value
+	for len(rest) > 0 {
+		startIndex := strings.IndexRune(rest, start)
+		if startIndex < 0 {
+			break
+		}
+		endIndex := strings.IndexRune(rest[startIndex:], end)
+		if endIndex < 0 {
+			break
+		}
+		result.WriteString(rest[:startIndex])
+		rest = rest[startIndex+endIndex+1:]
+	}
+	result.WriteString(rest)
+	return result.String()
+}
+
+// writeSubtitles encodes the given tracks into the writer.
+// Only the ASS format stores more than one track, using the style of the track. The other formats
+// store only the first track. Entries with markup are written with it, as it is.
+// Each line lasts until the next entry in the list, the last until given end, if that is later than its start.
+func writeSubtitles(writer io.Writer, format subtitleFormat, tracks []subtitleTrack, end time.Duration) error {
+	if (format != subtitleFormatASS) && (len(tracks) > 1) {
+		tracks = tracks[:1]
+	}
+	sub := astisub.NewSubtitles()
+	resolution := format.resolution()
+	for _, track := range tracks {
+		var style *astisub.Style
+		if (format == subtitleFormatASS) && (len(track.style) > 0) {
+			style = &astisub.Style{ID: track.style, InlineStyle: &astisub.StyleAttributes{}}
+			sub.Styles[track.style] = style
+		}
+		entries := track.list.Entries
+		for index, entry := range entries {
+			if len(entry.Text) == 0 {
+				continue
+			}
+			item := &astisub.Item{
+				StartAt: roundedDuration(entry.Timestamp, resolution),
+				Style:   style,
+			}
+			item.EndAt = item.StartAt
+			if index+1 < len(entries) {
+				item.EndAt = roundedDuration(entries[index+1].Timestamp, resolution)
+			} else if end > entry.Timestamp {
+				item.EndAt = roundedDuration(end, resolution)
+			}
+			if markup := track.markupOf(index); len(markup) > 0 {
+				for _, line := range strings.Split(markup, "\n") {
+					item.Lines = append(item.Lines, astisub.Line{Items: []astisub.LineItem{{Text: line}}})
+				}
+				sub.Items = append(sub.Items, item)
+				continue
+			}
+			lines := strings.Split(entry.Text, "\n")
+			if format == subtitleFormatASS {
+				lines = []string{strings.Join(lines, `\N`)}
+			}
+			for _, line := range lines {
+				if format == subtitleFormatWebVTT {
+					line = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(line)
+				}
+				item.Lines = append(item.Lines, astisub.Line{Items: []astisub.LineItem{{Text: line}}})
+			}
+			sub.Items = append(sub.Items, item)
+		}
+	}
+	sub.Order()
+
+	switch format {
+	case subtitleFormatWebVTT:
+		return sub.WriteToWebVTT(writer)
+	case subtitleFormatASS:
+		return sub.WriteToSSA(writer)
+	default:
+		return sub.WriteToSRT(writer)
+	}
+}
+
+func roundedDuration(value time.Duration, resolution time.Duration) time.Duration {
+	return ((value + resolution/2) / resolution) * resolution
+}
+
+// unencodableSubtitlesReport returns a description of all the subtitles that can not be represented
+// by the codepage of their language. The result is empty if all text can be encoded.
+func unencodableSubtitlesReport(cps text.Codepages, subtitles map[resource.Language]movie.SubtitleList) string {
+	const maxLines = 10
+	var lines []string
+	for _, lang := range resource.Languages() {
+		for _, entry := range subtitles[lang].Entries {
+			runes := text.Unencodable(cps.ForLanguage(lang), entry.Text)
+			if len(runes) == 0 {
+				continue
+			}
+			quoted := make([]string, len(runes))
+			for index, r := range runes {
+				quoted[index] = fmt.Sprintf("%q", r)
+			}
+			lines = append(lines, fmt.Sprintf("%v at %v: %s", lang, entry.Timestamp, strings.Join(quoted, ", ")))
+		}
+	}
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines], fmt.Sprintf("... and %d more", len(lines)-maxLines))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,197 @@
+package movies
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestSubtitlesRoundTripForAllFormats(t *testing.T) {
+	list := movie.SubtitleList{Entries: []movie.Subtitle{
+		{Timestamp: 1500 * time.Millisecond, Text: "First line\nsecond line"},
+		{Timestamp: 4 * time.Second, Text: "Fish & <chips>"},
+	}}
+	for _, format := range subtitleFormats {
+		t.Run(format.String(), func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			err := writeSubtitles(buf, format, []subtitleTrack{{style: "Default", list: list}}, 6*time.Second)
+			require.Nil(t, err)
+
+			tracks, err := readSubtitles(bytes.NewReader(buf.Bytes()), format)
+
+			require.Nil(t, err)
+			require.Equal(t, 1, len(tracks))
+			assert.Equal(t, list, tracks[0].list)
+		})
+	}
+}
+
+func TestSubtitlesExportRoundsToFormatResolution(t *testing.T) {
+	list := movie.SubtitleList{Entries: []movie.Subtitle{{Timestamp: 1999600 * time.Microsecond, Text: "a"}}}
+	buf := bytes.NewBuffer(nil)
+
+	err := writeSubtitles(buf, subtitleFormatSRT, []subtitleTrack{{list: list}}, 3*time.Second)
+
+	require.Nil(t, err)
+	assert.Contains(t, buf.String(), "00:00:02,000 --> 00:00:03,000")
+}
+
+func TestSubtitlesExportOfASSKeepsTracksAsStyles(t *testing.T) {
+	tracks := []subtitleTrack{
+		{style: "Default", list: movie.SubtitleList{Entries: []movie.Subtitle{{Timestamp: time.Second, Text: "Hello"}}}},
+		{style: "German", list: movie.SubtitleList{Entries: []movie.Subtitle{{Timestamp: time.Second, Text: "Hallo"}}}},
+	}
+	buf := bytes.NewBuffer(nil)
+	err := writeSubtitles(buf, subtitleFormatASS, tracks, 2*time.Second)
+	require.Nil(t, err)
+
+	result, err := readSubtitles(bytes.NewReader(buf.Bytes()), subtitleFormatASS)
+
+	require.Nil(t, err)
+	assert.ElementsMatch(t, tracks, result)
+}
+
+func TestSubtitlesImportRemovesStyling(t *testing.T) {
+	input := "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n<v Bob>Hello <i>there</i> &amp; you\n"
+
+	tracks, err := readSubtitles(strings.NewReader(input), subtitleFormatWebVTT)
+
+	require.Nil(t, err)
+	require.Equal(t, 1, len(tracks))
+	assert.Equal(t, "Hello there & you", tracks[0].list.Entries[0].Text)
+}
+
+func TestSubtitlesImportOfASSHandlesOverridesAndLineBreaks(t *testing.T) {
+	input := "[Script Info]\nScriptType: v4.00+\n\n[V4+ Styles]\nFormat: Name, Fontname\nStyle: French,Arial\n\n" +
+		"[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n" +
+		"Dialogue: 0,0:00:01.00,0:00:02.50,French,,0,0,0,,{\\i1}Bonjour{\\i0}\\Nmon ami\n"
+
+	tracks, err := readSubtitles(strings.NewReader(input), subtitleFormatASS)
+
+	require.Nil(t, err)
+	require.Equal(t, 1, len(tracks))
+	assert.Equal(t, "French", tracks[0].style)
+	assert.Equal(t, "Bonjour\nmon ami", tracks[0].list.Entries[0].Text)
+}
+
+func TestSubtitlesImportClearsTextInGaps(t *testing.T) {
+	input := "1\n00:00:01,000 --> 00:00:02,000\nOne\n\n2\n00:00:05,000 --> 00:00:06,000\nTwo\n"
+
+	tracks, err := readSubtitles(strings.NewReader(input), subtitleFormatSRT)
+
+	require.Nil(t, err)
+	assert.Equal(t, []movie.Subtitle{
+		{Timestamp: time.Second, Text: "One"},
+		{Timestamp: 2 * time.Second, Text: ""},
+		{Timestamp: 5 * time.Second, Text: "Two"},
+	}, tracks[0].list.Entries)
+}
+
+func TestUnencodableSubtitlesReportListsAffectedLines(t *testing.T) {
+	subtitles := map[resource.Language]movie.SubtitleList{
+		resource.LangDefault: {Entries: []movie.Subtitle{{Timestamp: time.Second, Text: "fine"}}},
+		resource.LangGerman:  {Entries: []movie.Subtitle{{Timestamp: 2 * time.Second, Text: "„nicht”"}}},
+	}
+
+	report := unencodableSubtitlesReport(text.NewLanguageCodepages(text.DefaultCodepage()), subtitles)
+
+	assert.Equal(t, "German at 2s: '„', '”'", report)
+}
+
+func TestSubtitlesImportOfSRTRemovesKnownTags(t *testing.T) {
+	input := "1\n00:00:01,000 --> 00:00:02,000\n{\\an8}<i>Hello</i> <font color=\"red\">you</font> <3\n"
+
+	tracks, err := readSubtitles(strings.NewReader(input), subtitleFormatSRT)
+
+	require.Nil(t, err)
+	assert.Equal(t, "Hello you <3", tracks[0].list.Entries[0].Text)
+}
+
+func TestMergedSubtitleTracksCombinesOverlappingText(t *testing.T) {
+	tracks := []subtitleTrack{
+		{style: "Top", list: movie.SubtitleList{Entries: []movie.Subtitle{
+			{Timestamp: 1 * time.Second, Text: "One"},
+			{Timestamp: 4 * time.Second, Text: ""},
+		}}},
+		{style: "Bottom", list: movie.SubtitleList{Entries: []movie.Subtitle{
+			{Timestamp: 2 * time.Second, Text: "Two"},
+			{Timestamp: 3 * time.Second, Text: ""},
+			{Timestamp: 5 * time.Second, Text: "Three"},
+		}}},
+	}
+
+	list, _ := mergedSubtitleTracks(tracks, subtitleFormatASS)
+
+	assert.Equal(t, []movie.Subtitle{
+		{Timestamp: 1 * time.Second, Text: "One"},
+		{Timestamp: 2 * time.Second, Text: "One\nTwo"},
+		{Timestamp: 3 * time.Second, Text: "One"},
+		{Timestamp: 4 * time.Second, Text: ""},
+		{Timestamp: 5 * time.Second, Text: "Three"},
+	}, list.Entries)
+}
+
+func TestSubtitlesKeepMixedStylingThroughImportAndExport(t *testing.T) {
+	input := "[Script Info]\nScriptType: v4.00+\n\n[V4+ Styles]\nFormat: Name, Fontname\n" +
+		"Style: Narrator,Arial\nStyle: Speaker,Arial\n\n" +
+		"[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n" +
+		"Dialogue: 0,0:00:01.00,0:00:06.00,Narrator,,0,0,0,,{\\i1}Long ago{\\i0}\n" +
+		"Dialogue: 0,0:00:02.00,0:00:03.00,Speaker,,0,0,0,,Hello {\\b1}there{\\b0}\\Nfriend\n" +
+		"Dialogue: 0,0:00:05.00,0:00:06.00,Speaker,,0,0,0,,Plain\n"
+	tracks, err := readSubtitles(strings.NewReader(input), subtitleFormatASS)
+	require.Nil(t, err)
+	list, styles := mergedSubtitleTracks(tracks, subtitleFormatASS)
+
+	buf := bytes.NewBuffer(nil)
+	err = writeSubtitles(buf, subtitleFormatASS, styledSubtitleTracks(list, styles, "Default", subtitleFormatASS), 6*time.Second)
+	require.Nil(t, err)
+	exported := buf.String()
+	assert.Contains(t, exported, "Narrator,{\\i1}Long ago{\\i0}")
+	assert.Contains(t, exported, "Speaker,Hello {\\b1}there{\\b0}\\Nfriend")
+	assert.NotContains(t, exported, "Default")
+
+	assert.Equal(t, "Long ago\nHello there\nfriend", list.Entries[1].Text)
+
+	reimported, err := readSubtitles(bytes.NewReader(buf.Bytes()), subtitleFormatASS)
+	require.Nil(t, err)
+	reimportedList, reimportedStyles := mergedSubtitleTracks(reimported, subtitleFormatASS)
+	assert.Equal(t, list, reimportedList)
+	assert.Equal(t, styles, reimportedStyles)
+}
+
+func TestSubtitlesKeepInlineStylingOfSRT(t *testing.T) {
+	input := "1\n00:00:01,000 --> 00:00:02,000\n<i>Hello</i>\n\n2\n00:00:02,000 --> 00:00:03,000\nplain\n"
+	tracks, err := readSubtitles(strings.NewReader(input), subtitleFormatSRT)
+	require.Nil(t, err)
+	list, styles := mergedSubtitleTracks(tracks, subtitleFormatSRT)
+
+	srt := bytes.NewBuffer(nil)
+	err = writeSubtitles(srt, subtitleFormatSRT, styledSubtitleTracks(list, styles, "", subtitleFormatSRT), 3*time.Second)
+	require.Nil(t, err)
+	assert.Equal(t, input, strings.TrimPrefix(srt.String(), "\ufeff"))
+
+	vtt := bytes.NewBuffer(nil)
+	err = writeSubtitles(vtt, subtitleFormatWebVTT, styledSubtitleTracks(list, styles, "", subtitleFormatWebVTT), 3*time.Second)
+	require.Nil(t, err)
+	assert.NotContains(t, vtt.String(), "<i>", "markup of other formats should not be written")
+	assert.Contains(t, vtt.String(), "Hello")
+}
+
+func TestStyledSubtitleTracksUseDefaultStyleForChangedText(t *testing.T) {
+	styles := subtitleStyles{format: subtitleFormatASS, texts: map[time.Duration][]styledText{
+		time.Second: {{style: "Speaker", plain: "Hello", markup: "{\\i1}Hello"}},
+	}}
+	list := movie.SubtitleList{Entries: []movie.Subtitle{{Timestamp: time.Second, Text: "Hi"}}}
+
+	tracks := styledSubtitleTracks(list, styles, "English", subtitleFormatASS)
+
+	assert.Equal(t, []subtitleTrack{{style: "English", list: list, markup: []string{""}}}, tracks)
+}
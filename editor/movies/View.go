@@ -1,15 +1,18 @@
 package movies
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"image/gif"
+	"image/png"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/asticode/go-astisub"
 	"github.com/inkyblackness/imgui-go"
 
 	"github.com/inkyblackness/hacked/editor/external"
@@ -18,6 +21,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/audio"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -42,6 +46,7 @@ var knownMoviesOrder = []resource.ID{ids.MovieIntro, ids.MovieDeath, ids.MovieEn
 // View provides edit controls for animations.
 type View struct {
 	mod *world.Mod
+	cps text.Codepages
 
 	frameCache    *graphics.FrameCache
 	frameCacheKey graphics.FrameCacheKey
@@ -56,11 +61,12 @@ type View struct {
 }
 
 // NewMoviesView returns a new instance.
-func NewMoviesView(mod *world.Mod, frameCache *graphics.FrameCache,
+func NewMoviesView(mod *world.Mod, cps text.Codepages, frameCache *graphics.FrameCache,
 	movieService undoable.MovieService,
 	modalStateMachine gui.ModalStateMachine, guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod: mod,
+		cps: cps,
 
 		frameCache:    frameCache,
 		frameCacheKey: frameCache.AllocateKey(),
@@ -81,6 +87,16 @@ func (view *View) WindowOpen() *bool {
 	return &view.model.windowOpen
 }
 
+// ShowSubtitles opens the view with the given movie selected, showing the subtitles of the given language.
+func (view *View) ShowSubtitles(key resource.Key, lang resource.Language) {
+	view.model.currentKey = key
+	view.model.currentSubtitleLang = lang
+	view.model.currentScene = 0
+	view.model.currentFrame = 0
+	view.model.frameTimeFraction = -1
+	view.model.restoreFocus = true
+}
+
 // Render renders the view.
 func (view *View) Render() {
 	if view.model.restoreFocus {
@@ -167,6 +183,10 @@ func (view *View) renderContent() {
 	if imgui.Button("Export") {
 		view.requestExportScene()
 	}
+	imgui.SameLine()
+	if imgui.Button("Import Sequence") {
+		view.requestImportSequence("")
+	}
 	imgui.EndGroup()
 	imgui.SameLine()
 	if imgui.BeginChildV("Frames", imgui.Vec2{X: -1, Y: 0}, false, 0) {
@@ -253,10 +273,18 @@ func (view *View) renderSubtitlesProperties() {
 		}
 		imgui.EndCombo()
 	}
+	if imgui.BeginCombo("Sub Format", view.model.subtitleFormat.String()) {
+		for _, format := range subtitleFormats {
+			if imgui.SelectableV(format.String(), format == view.model.subtitleFormat, 0, imgui.Vec2{}) {
+				view.model.subtitleFormat = format
+			}
+		}
+		imgui.EndCombo()
+	}
 	sub := view.currentSubtitles()
 	imgui.Text(fmt.Sprintf("%d lines", len(sub.Entries)))
 	if imgui.Button("Import") {
-		view.requestImportSubtitles()
+		view.requestImportSubtitles("")
 	}
 	if len(sub.Entries) > 0 {
 		imgui.SameLine()
@@ -268,6 +296,13 @@ func (view *View) renderSubtitlesProperties() {
 			view.requestClearSubtitles()
 		}
 	}
+	if imgui.Button("Import All") {
+		view.requestImportAllSubtitles("")
+	}
+	imgui.SameLine()
+	if imgui.Button("Export All") {
+		view.requestExportAllSubtitles()
+	}
 	imgui.PopID()
 }
 
@@ -327,85 +362,222 @@ func (view *View) currentSubtitles() movie.SubtitleList {
 	return view.movieService.Subtitles(view.model.currentKey, view.model.currentSubtitleLang)
 }
 
-func (view View) requestExportSubtitles() {
-	filename := fmt.Sprintf("%s_%s.srt", knownMovies[view.model.currentKey.ID].title, view.model.currentSubtitleLang.String())
-	info := "File to be written: " + filename
-	var exportTo func(string)
-	currentSubtitles := view.currentSubtitles()
+func (view *View) movieDuration() time.Duration {
+	var sum time.Duration
+	for _, scene := range view.movieService.Video(view.model.currentKey) {
+		for _, frame := range scene.Frames {
+			sum += frame.DisplayTime
+		}
+	}
+	return sum
+}
 
-	exportTo = func(dirname string) {
-		writer, err := os.Create(filepath.Join(dirname, filename))
-		if err != nil {
-			external.Export(view.modalStateMachine, "Could not create file.\n"+info, exportTo, true)
-			return
+func (view *View) requestExportSubtitles() {
+	format := view.model.subtitleFormat
+	filename := fmt.Sprintf("%s_%s.%s", knownMovies[view.model.currentKey.ID].title,
+		view.model.currentSubtitleLang.String(), format.extension())
+	lang := view.model.currentSubtitleLang
+	tracks := styledSubtitleTracks(view.currentSubtitles(), view.subtitleStyles(lang), lang.String(), format)
+	view.exportSubtitleFiles(format, map[string][]subtitleTrack{filename: tracks})
+}
+
+func (view *View) requestExportAllSubtitles() {
+	format := view.model.subtitleFormat
+	title := knownMovies[view.model.currentKey.ID].title
+	files := make(map[string][]subtitleTrack)
+	for _, lang := range resource.Languages() {
+		list := view.movieService.Subtitles(view.model.currentKey, lang)
+		if len(list.Entries) == 0 {
+			continue
 		}
-		defer func() { _ = writer.Close() }()
+		if format == subtitleFormatASS {
+			// The styles are named after the languages, so that the file can be imported for all languages.
+			tracks := styledSubtitleTracks(list, view.subtitleStyles(lang).unnamed(), lang.String(), format)
+			filename := fmt.Sprintf("%s.%s", title, format.extension())
+			files[filename] = append(files[filename], tracks...)
+		} else {
+			tracks := styledSubtitleTracks(list, view.subtitleStyles(lang), lang.String(), format)
+			files[fmt.Sprintf("%s_%s.%s", title, lang.String(), format.extension())] = tracks
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+	view.exportSubtitleFiles(format, files)
+}
+
+func (view *View) exportSubtitleFiles(format subtitleFormat, files map[string][]subtitleTrack) {
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	info := "File(s) to be written: " + strings.Join(filenames, ", ")
+	end := view.movieDuration()
+	var exportTo func(string)
 
-		sub := astisub.NewSubtitles()
-
-		var lastItem *astisub.Item
-		for _, entry := range currentSubtitles.Entries {
-			var item astisub.Item
-			var line astisub.Line
-			line.Items = append(line.Items, astisub.LineItem{Text: entry.Text})
-			item.Lines = []astisub.Line{line}
-			item.StartAt = entry.Timestamp
-			item.EndAt = item.StartAt
-			if lastItem != nil {
-				lastItem.EndAt = item.StartAt
+	exportTo = func(dirname string) {
+		for _, filename := range filenames {
+			writer, err := os.Create(filepath.Join(dirname, filename))
+			if err != nil {
+				external.Export(view.modalStateMachine, "Could not create file.\n"+info, exportTo, true)
+				return
+			}
+			err = writeSubtitles(writer, format, files[filename], end)
+			_ = writer.Close()
+			if err != nil {
+				external.Export(view.modalStateMachine, "Could not export subtitles.\n"+info, exportTo, true)
+				return
 			}
-			lastItem = &item
-			sub.Items = append(sub.Items, lastItem)
 		}
+	}
 
-		err = sub.WriteToSRT(writer)
+	external.Export(view.modalStateMachine, info, exportTo, false)
+}
+
+func (view *View) requestImportSubtitles(returningInfo string) {
+	info := "File must be an SRT, WebVTT, or ASS file.\n" +
+		"All lines are taken. The game shows them without styling,\n" +
+		"the styling is kept for exporting them again."
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		newSubtitles, styles, err := loadSubtitlesFile(filename)
 		if err != nil {
-			external.Export(view.modalStateMachine, "Could not export subtitles.\n"+info, exportTo, true)
+			external.Import(view.modalStateMachine, "Could not read subtitles.\n"+info, subtitleTypes, fileHandler, true)
+			return
+		}
+		lang := view.model.currentSubtitleLang
+		report := unencodableSubtitlesReport(view.cps, map[resource.Language]movie.SubtitleList{lang: newSubtitles})
+		if len(report) > 0 {
+			view.requestImportSubtitles("Text contains unsupported characters:\n" + report + "\n\n")
 			return
 		}
+		view.setSubtitleStyles(lang, styles)
+		view.movieService.RequestSetSubtitles(view.model.currentKey, lang, newSubtitles, view.restoreFunc())
 	}
 
-	external.Export(view.modalStateMachine, info, exportTo, false)
+	external.Import(view.modalStateMachine, returningInfo+info, subtitleTypes, fileHandler, false)
 }
 
-func (view *View) requestImportSubtitles() {
-	info := "File must be an .SRT file."
-	types := []external.TypeInfo{{Title: "Subtitle files (*.srt)", Extensions: []string{"srt"}}}
+func (view *View) requestImportAllSubtitles(returningInfo string) {
+	info := "Select either an ASS file with styles named after the languages,\n" +
+		"or one of several files named <name>_<language>.<ext>, such as Intro_French.srt.\n" +
+		"Languages are " + languageNames() + "."
 	var fileHandler func(string)
 
 	fileHandler = func(filename string) {
-		reader, err := os.Open(filename)
+		perLanguage, styles, err := loadSubtitlesPerLanguage(filename)
 		if err != nil {
-			external.Import(view.modalStateMachine, "Could not open file.\n"+info, types, fileHandler, true)
+			external.Import(view.modalStateMachine, err.Error()+"\n"+info, subtitleTypes, fileHandler, true)
 			return
 		}
-		defer func() { _ = reader.Close() }()
-
-		subtitles, err := astisub.ReadFromSRT(reader)
-		if err != nil {
-			external.Import(view.modalStateMachine, "File not recognized as SRT.\n"+info, types, fileHandler, true)
+		report := unencodableSubtitlesReport(view.cps, perLanguage)
+		if len(report) > 0 {
+			view.requestImportAllSubtitles("Text contains unsupported characters:\n" + report + "\n\n")
 			return
 		}
-		var newSubtitles movie.SubtitleList
-		for _, item := range subtitles.Items {
-			var newEntry movie.Subtitle
-			newEntry.Timestamp = item.StartAt
-			for _, line := range item.Lines {
-				for _, lineItem := range line.Items {
-					if len(newEntry.Text) > 0 {
-						newEntry.Text += "\n"
-					}
-					newEntry.Text += lineItem.Text
-				}
-			}
-			newSubtitles.Entries = append(newSubtitles.Entries, newEntry)
+		for lang, languageStyles := range styles {
+			view.setSubtitleStyles(lang, languageStyles)
+		}
+		view.movieService.RequestSetSubtitlesPerLanguage(view.model.currentKey, perLanguage, view.restoreFunc())
+	}
+
+	external.Import(view.modalStateMachine, returningInfo+info, subtitleTypes, fileHandler, false)
+}
+
+func languageNames() string {
+	var names []string
+	for _, lang := range resource.Languages() {
+		names = append(names, lang.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// loadSubtitlesFile reads all the subtitles of given file as one list, together with the styling of its lines.
+// Lines of several styles that are shown at the same time are combined.
+func loadSubtitlesFile(filename string) (movie.SubtitleList, subtitleStyles, error) {
+	tracks, format, err := loadSubtitleTracks(filename)
+	if err != nil {
+		return movie.SubtitleList{}, subtitleStyles{}, err
+	}
+	list, styles := mergedSubtitleTracks(tracks, format)
+	return list, styles, nil
+}
+
+func loadSubtitleTracks(filename string) ([]subtitleTrack, subtitleFormat, error) {
+	format, known := subtitleFormatOf(filename)
+	if !known {
+		return nil, format, fmt.Errorf("unknown subtitle format")
+	}
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, format, err
+	}
+	defer func() { _ = reader.Close() }()
+	tracks, err := readSubtitles(reader, format)
+	return tracks, format, err
+}
+
+// loadSubtitlesPerLanguage reads the subtitles for all languages, together with the styling of their lines.
+// The given file is either a file with tracks named after the languages, or one of several files
+// that have the language as suffix.
+func loadSubtitlesPerLanguage(filename string) (map[resource.Language]movie.SubtitleList,
+	map[resource.Language]subtitleStyles, error) {
+	perLanguage := make(map[resource.Language]movie.SubtitleList)
+	styles := make(map[resource.Language]subtitleStyles)
+	tracks, format, err := loadSubtitleTracks(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read subtitles")
+	}
+	for _, track := range tracks {
+		if lang, known := resource.LanguageNamed(track.style); known {
+			perLanguage[lang], styles[lang] = mergedSubtitleTracks([]subtitleTrack{track}, format)
 		}
+	}
+	if len(perLanguage) > 0 {
+		return perLanguage, styles, nil
+	}
 
-		view.movieService.RequestSetSubtitles(view.model.currentKey, view.model.currentSubtitleLang,
-			newSubtitles, view.restoreFunc())
+	ext := filepath.Ext(filename)
+	baseName := strings.TrimSuffix(filepath.Base(filename), ext)
+	separator := strings.LastIndex(baseName, "_")
+	if separator < 0 {
+		return nil, nil, fmt.Errorf("file name does not end with a language")
+	}
+	if _, known := resource.LanguageNamed(baseName[separator+1:]); !known {
+		return nil, nil, fmt.Errorf("file name does not end with a language")
+	}
+	prefix := filepath.Join(filepath.Dir(filename), baseName[:separator+1])
+	for _, lang := range resource.Languages() {
+		candidates := []string{prefix + lang.String() + ext}
+		for _, format := range subtitleFormats {
+			candidates = append(candidates, prefix+lang.String()+"."+format.extension())
+		}
+		for _, candidate := range candidates {
+			if _, err := os.Stat(candidate); err != nil {
+				continue
+			}
+			list, listStyles, err := loadSubtitlesFile(candidate)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not read %s", filepath.Base(candidate))
+			}
+			perLanguage[lang] = list
+			styles[lang] = listStyles
+			break
+		}
 	}
+	return perLanguage, styles, nil
+}
+
+// subtitleStyles returns the styling of the subtitle lines of the current movie that were last imported
+// for the given language.
+func (view *View) subtitleStyles(lang resource.Language) subtitleStyles {
+	return view.model.subtitleStyles[subtitleStylesKey{movie: view.model.currentKey, lang: lang}]
+}
 
-	external.Import(view.modalStateMachine, info, types, fileHandler, false)
+func (view *View) setSubtitleStyles(lang resource.Language, styles subtitleStyles) {
+	view.model.subtitleStyles[subtitleStylesKey{movie: view.model.currentKey, lang: lang}] = styles
 }
 
 func (view *View) requestClearSubtitles() {
@@ -503,20 +675,115 @@ func (view *View) requestImportScene(returningInfo string) {
 			scene.Frames[index].Pixels = framebufferSnapshot()
 		}
 
-		view.compressAndAddScene(scene, data.Config.Width, data.Config.Height)
+		view.compressAndAddScene(func(context.Context) (movie.Scene, error) { return scene, nil },
+			data.Config.Width, data.Config.Height, view.onCompressionResult)
 	}
 
 	external.Import(view.modalStateMachine, returningInfo+info, types, fileHandler, false)
 }
 
-func (view *View) compressAndAddScene(scene movie.Scene, width, height int) {
+func (view *View) requestImportSequence(returningInfo string) {
+	info := "File must be a JSON manifest, listing PNG frames and their display time.\n" +
+		"Optionally, it refers to a WAV file and subtitle files per language.\n" +
+		fmt.Sprintf("Frames are scaled to fit into %dx%d.", movie.HighResDefaultWidth, movie.HighResDefaultHeight)
+	types := []external.TypeInfo{{Title: "Manifest files (*.json)", Extensions: []string{"json"}}}
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		failed := func(reason string) {
+			external.Import(view.modalStateMachine, reason+"\n"+info, types, fileHandler, true)
+		}
+		reader, err := os.Open(filename)
+		if err != nil {
+			failed("Could not open file.")
+			return
+		}
+		defer func() { _ = reader.Close() }()
+		manifest, err := readSequenceManifest(reader)
+		if err != nil {
+			failed("File not recognized as manifest: " + err.Error())
+			return
+		}
+		seq, err := manifest.resolve(filepath.Dir(filename))
+		if err != nil {
+			failed("Manifest is not valid: " + err.Error())
+			return
+		}
+
+		var sound audio.L8
+		var clip audio.Clipping
+		if len(seq.audio) > 0 {
+			sound, clip, err = external.LoadAudio(seq.audio)
+			if err != nil {
+				failed("Could not load audio: " + err.Error())
+				return
+			}
+		}
+		subtitles := make(map[resource.Language]movie.SubtitleList)
+		for lang, subtitleFile := range seq.subtitles {
+			subtitles[lang], _, err = loadSubtitlesFile(subtitleFile)
+			if err != nil {
+				failed(fmt.Sprintf("Could not load %v subtitles: %v", lang, err))
+				return
+			}
+		}
+		if report := unencodableSubtitlesReport(view.cps, subtitles); len(report) > 0 {
+			failed("Subtitles contain unsupported characters:\n" + report)
+			return
+		}
+
+		source := func(ctx context.Context) (movie.Scene, error) {
+			frames := make([]movie.ImageFrame, len(seq.frames))
+			for index, frame := range seq.frames {
+				img, err := loadPNGFile(frame.filename)
+				if err != nil {
+					return movie.Scene{}, fmt.Errorf("frame %d (%s): %v", index, filepath.Base(frame.filename), err)
+				}
+				frames[index] = movie.ImageFrame{Image: img, DisplayTime: frame.displayTime}
+				if ctx.Err() != nil {
+					return movie.Scene{}, ctx.Err()
+				}
+			}
+			return movie.SceneFromImages(ctx, frames, movie.HighResDefaultWidth, movie.HighResDefaultHeight)
+		}
+		view.compressAndAddScene(source, movie.HighResDefaultWidth, movie.HighResDefaultHeight,
+			func(result compressionResult) {
+				switch typedResult := result.(type) {
+				case compressionAborted:
+				case compressionFinished:
+					resampleClip := view.movieService.RequestAddSceneWithMedia(view.model.currentKey, typedResult.scene,
+						sound, subtitles, view.restoreFunc())
+					if !clip.Occurred() {
+						clip = resampleClip
+					}
+					external.ClippingNotice(view.modalStateMachine, clip, len(sound.Samples))
+				case compressionFailed:
+					view.requestImportSequence("Could not import. Follow recommendations and retry.\n" +
+						"Technical details:\n" + typedResult.err.Error() + "\n\n")
+				}
+			})
+	}
+
+	external.Import(view.modalStateMachine, returningInfo+info, types, fileHandler, false)
+}
+
+func loadPNGFile(filename string) (image.Image, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return png.Decode(reader)
+}
+
+func (view *View) compressAndAddScene(source sceneSourceFunc, width, height int, listener compressionListenerFunc) {
 	view.modalStateMachine.SetState(&compressingStartState{
 		machine:  view.modalStateMachine,
 		view:     view,
 		width:    width,
 		height:   height,
-		input:    scene,
-		listener: view.onCompressionResult,
+		source:   source,
+		listener: listener,
 	})
 }
 
@@ -11,10 +11,18 @@ type viewModel struct {
 
 	currentKey          resource.Key
 	currentSubtitleLang resource.Language
+	subtitleFormat      subtitleFormat
 	currentScene        int
 	currentFrame        int
 
 	frameTimeFraction int
+
+	subtitleStyles map[subtitleStylesKey]subtitleStyles
+}
+
+type subtitleStylesKey struct {
+	movie resource.Key
+	lang  resource.Language
 }
 
 func freshViewModel() viewModel {
@@ -22,5 +30,6 @@ func freshViewModel() viewModel {
 		currentKey:          resource.KeyOf(ids.MovieIntro, resource.LangDefault, 0),
 		currentSubtitleLang: resource.LangDefault,
 		frameTimeFraction:   -1,
+		subtitleStyles:      make(map[subtitleStylesKey]subtitleStyles),
 	}
 }
@@ -0,0 +1,146 @@
+package objects
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/objtable"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+)
+
+var tableTypes = []external.TypeInfo{{
+	Title:      "Property tables (*.csv, *.json)",
+	Extensions: []string{"csv", "json"},
+}}
+
+func (view *View) renderTableControls(readOnly bool) {
+	imgui.Checkbox("All Classes", &view.model.tableAllClasses)
+	if imgui.Button("Export CSV...") {
+		view.requestExportTable("csv")
+	}
+	imgui.SameLine()
+	if imgui.Button("Export JSON...") {
+		view.requestExportTable("json")
+	}
+	if !readOnly {
+		imgui.SameLine()
+		if imgui.Button("Import...") {
+			view.requestImportTable()
+		}
+	}
+}
+
+func (view *View) tableTriples() ([]object.Triple, string) {
+	properties := view.mod.ObjectProperties()
+	if !view.model.tableAllClasses {
+		class := view.model.currentObject.Class
+		return properties.TriplesInClass(class), strings.ToLower(class.String())
+	}
+	var triples []object.Triple
+	for _, class := range object.Classes() {
+		triples = append(triples, properties.TriplesInClass(class)...)
+	}
+	return triples, "all"
+}
+
+func (view *View) requestExportTable(extension string) {
+	triples, scope := view.tableTriples()
+	filename := fmt.Sprintf("objects_%s.%s", scope, extension)
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		table := objtable.Export(view.mod.ObjectProperties(), triples, func(triple object.Triple) string {
+			return view.objectName(triple, view.model.currentLang, true)
+		})
+		buf := bytes.NewBuffer(nil)
+		var err error
+		if extension == "csv" {
+			err = objtable.WriteCSV(buf, table)
+		} else {
+			err = objtable.WriteJSON(buf, table)
+		}
+		if err == nil {
+			err = ioutil.WriteFile(filepath.Join(dirname, filename), buf.Bytes(), 0640)
+		}
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not write file.\n"+info, exportTo, true)
+			return
+		}
+		external.Notice(view.modalStateMachine, "Object Export", fmt.Sprintf("%d object types exported.", len(table.Rows)))
+	}
+
+	external.Export(view.modalStateMachine, info, exportTo, false)
+}
+
+func (view *View) requestImportTable() {
+	info := "File must be a CSV or JSON property table.\n" +
+		"Empty cells keep the current values."
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		table, err := readTableFile(filename)
+		if err != nil {
+			external.Import(view.modalStateMachine, fmt.Sprintf("File could not be read: %v\n%s", err, info),
+				tableTypes, fileHandler, true)
+			return
+		}
+		view.importTable(table)
+	}
+
+	external.Import(view.modalStateMachine, info, tableTypes, fileHandler, false)
+}
+
+func readTableFile(filename string) (objtable.Table, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return objtable.Table{}, err
+	}
+	defer func() { _ = reader.Close() }()
+	if strings.EqualFold(filepath.Ext(filename), ".csv") {
+		return objtable.ReadCSV(reader)
+	}
+	return objtable.ReadJSON(reader)
+}
+
+// importTable sets the properties of all changed object types as one undoable step.
+// Cells that could not be applied are reported.
+func (view *View) importTable(table objtable.Table) {
+	properties := view.mod.ObjectProperties()
+	changes, issues := objtable.Review(properties, table)
+	var commands cmd.List
+	for _, change := range changes {
+		current, _ := properties.ForObject(change.Triple)
+		commands = append(commands, setObjectPropertiesCommand{
+			model:         &view.model,
+			triple:        change.Triple,
+			oldProperties: current.Clone(),
+			newProperties: change.Properties,
+		})
+	}
+	if len(commands) > 0 {
+		view.commander.Queue(cmd.Named{Command: commands, Title: fmt.Sprintf("Import properties of %d object type(s)", len(changes))})
+	}
+
+	const maxIssues = 10
+	lines := []string{fmt.Sprintf("%d object types changed.", len(changes))}
+	if len(issues) > 0 {
+		lines = append(lines, fmt.Sprintf("%d values rejected:", len(issues)))
+	}
+	for index, issue := range issues {
+		if index >= maxIssues {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(issues)-maxIssues))
+			break
+		}
+		lines = append(lines, "  "+issue.String())
+	}
+	external.Notice(view.modalStateMachine, "Object Import", strings.Join(lines, "\n"))
+}
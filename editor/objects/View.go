@@ -26,7 +26,7 @@ import (
 type View struct {
 	mod          *world.Mod
 	textCache    *text.Cache
-	cp           text.Codepage
+	cps          text.Codepages
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
 
@@ -39,14 +39,14 @@ type View struct {
 }
 
 // NewView returns a new instance.
-func NewView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
+func NewView(mod *world.Mod, textCache *text.Cache, cps text.Codepages,
 	imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
 	modalStateMachine gui.ModalStateMachine,
 	clipboard external.Clipboard, guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		textCache:    textCache,
-		cp:           cp,
+		cps:          cps,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
 
@@ -65,6 +65,13 @@ func (view *View) WindowOpen() *bool {
 	return &view.model.windowOpen
 }
 
+// ShowObject opens the view with the given object selected, in the given language.
+func (view *View) ShowObject(triple object.Triple, lang resource.Language) {
+	view.model.currentObject = triple
+	view.model.currentLang = lang
+	view.model.restoreFocus = true
+}
+
 // Render renders the view.
 func (view *View) Render() {
 	if view.model.restoreFocus {
@@ -110,6 +117,11 @@ func (view *View) renderContent() {
 		readOnly := !view.mod.HasModifyableObjectProperties()
 		properties, propErr := view.mod.ObjectProperties().ForObject(view.model.currentObject)
 
+		if imgui.TreeNodeV("Property Tables", imgui.TreeNodeFlagsFramed) {
+			view.renderTableControls(readOnly)
+			imgui.TreePop()
+		}
+
 		imgui.Separator()
 		bitmapLimit := 0
 		if propErr == nil {
@@ -220,6 +232,11 @@ func (view *View) requestSetObjectName(triple object.Triple, longName bool, newV
 		}
 		key := resource.KeyOf(id, view.model.currentLang, linearIndex)
 		oldValue, _ := view.textCache.Text(key)
+		cp := view.cps.ForLanguage(key.Lang)
+		if unencodable := text.Unencodable(cp, newValue); len(unencodable) > 0 {
+			external.UnencodableNotice(view.modalStateMachine, key.Lang, unencodable)
+			return
+		}
 
 		if oldValue != newValue {
 			command := setObjectTextCommand{
@@ -227,15 +244,32 @@ func (view *View) requestSetObjectName(triple object.Triple, longName bool, newV
 				triple:  view.model.currentObject,
 				bitmap:  view.model.currentBitmap,
 				key:     key,
-				oldData: view.cp.Encode(oldValue),
-				newData: view.cp.Encode(text.Blocked(newValue)[0]),
+				oldData: cp.Encode(oldValue),
+				newData: cp.Encode(text.Blocked(newValue)[0]),
 			}
-			view.commander.Queue(command)
+			view.commander.Queue(cmd.Named{Command: command, Title: fmt.Sprintf("Set name of object %v", triple)})
 		}
 	}
 }
 
-func (view *View) requestSetObjectProperties(modifier func(*object.Properties)) {
+// RequestSetProperties queues the change of all properties of the given object type.
+func (view *View) RequestSetProperties(triple object.Triple, properties object.Properties) {
+	currentProp, err := view.mod.ObjectProperties().ForObject(triple)
+	if err != nil {
+		return
+	}
+	view.commander.Queue(cmd.Named{
+		Command: setObjectPropertiesCommand{
+			model:         &view.model,
+			triple:        triple,
+			oldProperties: currentProp.Clone(),
+			newProperties: properties.Clone(),
+		},
+		Title: fmt.Sprintf("Set properties of object %v", triple),
+	})
+}
+
+func (view *View) requestSetObjectProperties(field string, modifier func(*object.Properties)) {
 	command := setObjectPropertiesCommand{
 		model:  &view.model,
 		triple: view.model.currentObject,
@@ -248,7 +282,11 @@ func (view *View) requestSetObjectProperties(modifier func(*object.Properties))
 	command.oldProperties = currentProp.Clone()
 	command.newProperties = currentProp.Clone()
 	modifier(&command.newProperties)
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{
+		Command: command,
+		Title:   fmt.Sprintf("Change properties of object %v", command.triple),
+		Group:   fmt.Sprintf("objects/%v/%s", command.triple, field),
+	})
 }
 
 func (view *View) renderCommonProperties(readOnly bool, properties *object.Properties) {
@@ -260,7 +298,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 			flagUnifier := values.NewUnifier()
 			flagUnifier.Add(properties.Common.Flags.Has(flag))
 			values.RenderUnifiedCheckboxCombo(readOnly, false, flag.String(), flagUnifier, func(newValue bool) {
-				view.requestSetObjectProperties(func(prop *object.Properties) {
+				view.requestSetObjectProperties("Flags/"+flag.String(), func(prop *object.Properties) {
 					if newValue {
 						prop.Common.Flags = prop.Common.Flags.With(flag)
 					} else {
@@ -283,7 +321,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		func(value int) string {
 			return object.LightType(value).String()
 		}, len(lightTypes), func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("LightType", func(prop *object.Properties) {
 				prop.Common.Flags = prop.Common.Flags.WithLightType(object.LightType(newValue))
 			})
 		})
@@ -294,7 +332,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		func(value int) string {
 			return object.UseMode(value).String()
 		}, 4, func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("UseMode", func(prop *object.Properties) {
 				prop.Common.Flags = prop.Common.Flags.WithUseMode(object.UseMode(newValue))
 			})
 		})
@@ -303,7 +341,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	massUnifier.Add(int(properties.Common.Mass))
 	values.RenderUnifiedSliderInt(readOnly, false, "Mass", massUnifier, intIdentity, intFormat, -1, 5000,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Mass", func(prop *object.Properties) {
 				prop.Common.Mass = int32(newValue)
 			})
 		})
@@ -312,7 +350,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	hitpointsUnifier.Add(int(properties.Common.Hitpoints))
 	values.RenderUnifiedSliderInt(readOnly, false, "Hitpoints", hitpointsUnifier, intIdentity, intFormat, 0, 10000,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Hitpoints", func(prop *object.Properties) {
 				prop.Common.Hitpoints = int16(newValue)
 			})
 		})
@@ -321,7 +359,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	armorUnifier.Add(int(properties.Common.Armor))
 	values.RenderUnifiedSliderInt(readOnly, false, "Armor", armorUnifier, intIdentity, intFormat, 0, 255,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Armor", func(prop *object.Properties) {
 				prop.Common.Armor = byte(newValue)
 			})
 		})
@@ -331,7 +369,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	values.RenderUnifiedCombo(readOnly, false, "Render Type", renderTypeUnifier, intIdentity,
 		func(value int) string { return object.RenderType(value).String() },
 		len(object.RenderTypes()), func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Render Type", func(prop *object.Properties) {
 				prop.Common.RenderType = object.RenderType(newValue)
 			})
 		})
@@ -341,7 +379,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	values.RenderUnifiedCombo(readOnly, false, "Physics Model", physicsModelUnifier, intIdentity,
 		func(value int) string { return object.PhysicsModel(value).String() },
 		len(object.PhysicsModels()), func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Physics Model", func(prop *object.Properties) {
 				prop.Common.PhysicsModel = object.PhysicsModel(newValue)
 			})
 		})
@@ -350,7 +388,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	hardnessUnifier.Add(int(properties.Common.Hardness))
 	values.RenderUnifiedSliderInt(readOnly, false, "Hardness", hardnessUnifier, intIdentity, intFormat, 0, object.HardnessLimit,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Hardness", func(prop *object.Properties) {
 				prop.Common.Hardness = byte(newValue)
 			})
 		})
@@ -359,7 +397,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	physicsXRUnifier.Add(int(properties.Common.PhysicsXR))
 	values.RenderUnifiedSliderInt(readOnly, false, "Physics XR", physicsXRUnifier, intIdentity, intFormat, 0, object.PhysicsXRLimit,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Physics XR", func(prop *object.Properties) {
 				prop.Common.PhysicsXR = byte(newValue)
 			})
 		})
@@ -368,7 +406,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	physicsZUnifier.Add(int(properties.Common.PhysicsZ))
 	values.RenderUnifiedSliderInt(readOnly, false, "Physics Z", physicsZUnifier, intIdentity, intFormat, 0, 255,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Physics Z", func(prop *object.Properties) {
 				prop.Common.PhysicsZ = byte(newValue)
 			})
 		})
@@ -378,7 +416,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 			damageUnifier := values.NewUnifier()
 			damageUnifier.Add(properties.Common.Vulnerabilities.Has(damageType))
 			values.RenderUnifiedCheckboxCombo(readOnly, false, damageType.String(), damageUnifier, func(newValue bool) {
-				view.requestSetObjectProperties(func(prop *object.Properties) {
+				view.requestSetObjectProperties("Vulnerabilities/"+damageType.String(), func(prop *object.Properties) {
 					if newValue {
 						prop.Common.Vulnerabilities = prop.Common.Vulnerabilities.With(damageType)
 					} else {
@@ -395,7 +433,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		primaryUnifier.Add(properties.Common.SpecialVulnerabilities.PrimaryValue())
 		values.RenderUnifiedSliderInt(readOnly, false, "Primary (double dmg)", primaryUnifier, intIdentity, intFormat, 0, object.SpecialDamageTypeLimit,
 			func(newValue int) {
-				view.requestSetObjectProperties(func(prop *object.Properties) {
+				view.requestSetObjectProperties("Primary (double dmg)", func(prop *object.Properties) {
 					prop.Common.SpecialVulnerabilities = prop.Common.SpecialVulnerabilities.WithPrimaryValue(newValue)
 				})
 			})
@@ -404,7 +442,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		superUnifier.Add(properties.Common.SpecialVulnerabilities.SuperValue())
 		values.RenderUnifiedSliderInt(readOnly, false, "Super (quad dmg)", superUnifier, intIdentity, intFormat, 0, object.SpecialDamageTypeLimit,
 			func(newValue int) {
-				view.requestSetObjectProperties(func(prop *object.Properties) {
+				view.requestSetObjectProperties("Super (quad dmg)", func(prop *object.Properties) {
 					prop.Common.SpecialVulnerabilities = prop.Common.SpecialVulnerabilities.WithSuperValue(newValue)
 				})
 			})
@@ -422,7 +460,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 			return "%d"
 		}, 0, 255,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Defense", func(prop *object.Properties) {
 				prop.Common.Defense = byte(newValue)
 			})
 		})
@@ -438,7 +476,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		},
 		0, 7,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Toughness", func(prop *object.Properties) {
 				prop.Common.Toughness = byte(newValue)
 			})
 		})
@@ -447,7 +485,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	mfdOrMeshIDUnifier.Add(int(properties.Common.MfdOrMeshID))
 	values.RenderUnifiedSliderInt(readOnly, false, "MFD/Mesh ID", mfdOrMeshIDUnifier, intIdentity, intFormat, 0, 1000,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("MFD/Mesh ID", func(prop *object.Properties) {
 				prop.Common.MfdOrMeshID = uint16(newValue)
 			})
 		})
@@ -456,7 +494,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	bitmap3DBitmapNumUnifier.Add(int(properties.Common.Bitmap3D.BitmapNumber()))
 	values.RenderUnifiedSliderInt(readOnly, false, "Bitmap Number", bitmap3DBitmapNumUnifier, intIdentity, intFormat, 0, int(object.Bitmap3DBitmapNumberLimit),
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Bitmap Number", func(prop *object.Properties) {
 				prop.Common.Bitmap3D = prop.Common.Bitmap3D.WithBitmapNumber(uint16(newValue))
 			})
 		})
@@ -464,7 +502,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	bitmap3DFrameNumUnifier.Add(int(properties.Common.Bitmap3D.FrameNumber()))
 	values.RenderUnifiedSliderInt(readOnly, false, "Frame Number", bitmap3DFrameNumUnifier, intIdentity, intFormat, 0, int(object.Bitmap3DFrameNumberLimit),
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Frame Number", func(prop *object.Properties) {
 				prop.Common.Bitmap3D = prop.Common.Bitmap3D.WithFrameNumber(uint16(newValue))
 			})
 		})
@@ -472,7 +510,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	bitmap3DAnimUnifier.Add(properties.Common.Bitmap3D.Animation())
 	values.RenderUnifiedCheckboxCombo(readOnly, false, "Animation", bitmap3DAnimUnifier,
 		func(newValue bool) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Animation", func(prop *object.Properties) {
 				prop.Common.Bitmap3D = prop.Common.Bitmap3D.WithAnimation(newValue)
 			})
 		})
@@ -480,7 +518,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	bitmap3DRepeatUnifier.Add(properties.Common.Bitmap3D.Repeat())
 	values.RenderUnifiedCheckboxCombo(readOnly, false, "Repeat", bitmap3DRepeatUnifier,
 		func(newValue bool) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("Repeat", func(prop *object.Properties) {
 				prop.Common.Bitmap3D = prop.Common.Bitmap3D.WithRepeat(newValue)
 			})
 		})
@@ -490,7 +528,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	values.RenderUnifiedSliderInt(readOnly, false, "DestroyEffect Value", destroyEffectValueUnifier,
 		intIdentity, intFormat, 0, int(object.DestroyEffectValueLimit),
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("DestroyEffect Value", func(prop *object.Properties) {
 				prop.Common.DestroyEffect = prop.Common.DestroyEffect.WithValue(byte(newValue))
 			})
 		})
@@ -498,7 +536,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	destroyEffectPlaySoundUnifier.Add(properties.Common.DestroyEffect.PlaySound())
 	values.RenderUnifiedCheckboxCombo(readOnly, false, "DestroyEffect PlaySound", destroyEffectPlaySoundUnifier,
 		func(newValue bool) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("DestroyEffect PlaySound", func(prop *object.Properties) {
 				prop.Common.DestroyEffect = prop.Common.DestroyEffect.WithSound(newValue)
 			})
 		})
@@ -506,7 +544,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	destroyEffectShowExplosionUnifier.Add(properties.Common.DestroyEffect.ShowExplosion())
 	values.RenderUnifiedCheckboxCombo(readOnly, false, "DestroyEffect ShowExplosion", destroyEffectShowExplosionUnifier,
 		func(newValue bool) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectProperties("DestroyEffect ShowExplosion", func(prop *object.Properties) {
 				prop.Common.DestroyEffect = prop.Common.DestroyEffect.WithExplosion(newValue)
 			})
 		})
@@ -515,7 +553,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 func (view *View) renderGenericProperties(readOnly bool, properties *object.Properties) {
 	readInterpreter := objprop.GenericProperties(view.model.currentObject.Class, properties.Generic)
 	view.createPropertyControls(readOnly, readInterpreter, func(key string, modifier func(uint32) uint32) {
-		view.requestSetObjectProperties(func(prop *object.Properties) {
+		view.requestSetObjectProperties("Generic/"+key, func(prop *object.Properties) {
 			writeInterpreter := objprop.GenericProperties(view.model.currentObject.Class, prop.Generic)
 			view.setInterpreterValueKeyed(writeInterpreter, key, modifier)
 		})
@@ -525,7 +563,7 @@ func (view *View) renderGenericProperties(readOnly bool, properties *object.Prop
 func (view *View) renderSpecificProperties(readOnly bool, properties *object.Properties) {
 	readInterpreter := objprop.SpecificProperties(view.model.currentObject, properties.Specific)
 	view.createPropertyControls(readOnly, readInterpreter, func(key string, modifier func(uint32) uint32) {
-		view.requestSetObjectProperties(func(prop *object.Properties) {
+		view.requestSetObjectProperties("Specific/"+key, func(prop *object.Properties) {
 			writeInterpreter := objprop.SpecificProperties(view.model.currentObject, prop.Specific)
 			view.setInterpreterValueKeyed(writeInterpreter, key, modifier)
 		})
@@ -684,7 +722,7 @@ func (view *View) requestSetBitmapData(newData []byte) {
 		oldData:     view.mod.ModifiedBlock(resourceKey.Lang, resourceKey.ID, resourceKey.Index),
 		newData:     newData,
 	}
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{Command: command, Title: fmt.Sprintf("Set bitmap of object %v", command.triple)})
 }
 
 func (view *View) currentBitmapKey() resource.Key {
@@ -12,6 +12,8 @@ type viewModel struct {
 	currentObject object.Triple
 	currentBitmap int
 	currentLang   resource.Language
+
+	tableAllClasses bool
 }
 
 func freshViewModel() viewModel {
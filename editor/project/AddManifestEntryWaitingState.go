@@ -6,9 +6,6 @@ import (
 	"github.com/inkyblackness/imgui-go"
 	"github.com/sqweek/dialog"
 
-	"github.com/inkyblackness/hacked/ss1/resource"
-	"github.com/inkyblackness/hacked/ss1/world"
-	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
@@ -60,21 +57,7 @@ func (state *addManifestEntryWaitingState) HandleFiles(names []string) {
 	staging.stageAll(names)
 
 	if len(staging.resources) > 0 {
-		entry := &world.ManifestEntry{
-			ID: names[0],
-		}
-
-		for filename, viewer := range staging.resources {
-			localized := resource.LocalizedResources{
-				ID:       filename,
-				Language: ids.LocalizeFilename(filename),
-				Viewer:   viewer,
-			}
-			entry.Resources = append(entry.Resources, localized)
-		}
-		entry.ObjectProperties = staging.objectProperties
-		entry.TextureProperties = staging.textureProperties
-
+		entry := staging.manifestEntry(names)
 		state.view.requestAddManifestEntry(entry)
 		state.machine.SetState(nil)
 	} else {
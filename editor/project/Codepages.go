@@ -0,0 +1,125 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+const builtinCodepageName = "CP437 (built-in)"
+
+var codepageTypes = []external.TypeInfo{{Title: "Codepage mapping files (*.txt)", Extensions: []string{"txt"}}}
+
+// loadedCodepage is a codepage that was read from a mapping file.
+type loadedCodepage struct {
+	name     string
+	filename string
+	cp       text.Codepage
+}
+
+func (view *View) renderCodepages() {
+	imgui.PushItemWidth(-150 * view.guiScale)
+	for _, lang := range resource.Languages() {
+		assigned, hasAssignment := view.model.assignedCodepages[lang]
+		selectedName := builtinCodepageName
+		if hasAssignment {
+			selectedName = view.model.codepages[assigned].name
+		}
+		if imgui.BeginCombo(lang.String()+" Codepage", selectedName) {
+			if imgui.SelectableV(builtinCodepageName, !hasAssignment, 0, imgui.Vec2{}) {
+				view.assignCodepage(lang, -1)
+			}
+			for index, loaded := range view.model.codepages {
+				if imgui.SelectableV(loaded.name+"###"+loaded.filename, hasAssignment && (assigned == index), 0, imgui.Vec2{}) {
+					view.assignCodepage(lang, index)
+				}
+			}
+			imgui.EndCombo()
+		}
+	}
+	imgui.PopItemWidth()
+	if imgui.Button("Load Codepage...") {
+		view.startLoadingCodepage()
+	}
+}
+
+func (view *View) startLoadingCodepage() {
+	info := "File must list one mapping per line, such as \"0x80 0x0410\".\n" +
+		"The first value is the byte, the second the Unicode code point.\n" +
+		"Bytes that are not listed keep their CP437 meaning."
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		if _, err := os.Stat(filename); err != nil {
+			external.Import(view.modalStateMachine, "Could not open file.\n"+info, codepageTypes, fileHandler, true)
+			return
+		}
+		cp, err := loadCodepageFile(filename)
+		if err != nil {
+			external.Import(view.modalStateMachine, "File could not be read: "+err.Error()+"\n"+info,
+				codepageTypes, fileHandler, true)
+			return
+		}
+		view.addCodepage(filename, cp)
+	}
+
+	external.Import(view.modalStateMachine, info, codepageTypes, fileHandler, false)
+}
+
+// addCodepage registers the codepage loaded from given file. A codepage from the same file is replaced,
+// and languages that use it are updated.
+func (view *View) addCodepage(filename string, cp text.Codepage) {
+	loaded := loadedCodepage{
+		name:     filepath.Base(filename),
+		filename: filename,
+		cp:       cp,
+	}
+	for index, existing := range view.model.codepages {
+		if existing.filename == filename {
+			view.model.codepages[index] = loaded
+			for lang, assigned := range view.model.assignedCodepages {
+				if assigned == index {
+					view.assignCodepage(lang, index)
+				}
+			}
+			return
+		}
+	}
+	view.model.codepages = append(view.model.codepages, loaded)
+}
+
+// codepageIndex returns the index of the codepage loaded from given file, or -1 if not loaded.
+func (view *View) codepageIndex(filename string) int {
+	for index, loaded := range view.model.codepages {
+		if loaded.filename == filename {
+			return index
+		}
+	}
+	return -1
+}
+
+// assignCodepage sets the codepage for given language. A negative index selects the built-in codepage.
+func (view *View) assignCodepage(lang resource.Language, index int) {
+	if index < 0 {
+		delete(view.model.assignedCodepages, lang)
+		view.codepages.Assign(lang, nil)
+	} else {
+		view.model.assignedCodepages[lang] = index
+		view.codepages.Assign(lang, view.model.codepages[index].cp)
+	}
+	view.codepagesChanged()
+}
+
+// resetCodepages forgets all loaded codepages, and returns all languages to the built-in codepage.
+func (view *View) resetCodepages() {
+	for lang := range view.model.assignedCodepages {
+		view.codepages.Assign(lang, nil)
+	}
+	view.model.assignedCodepages = make(map[resource.Language]int)
+	view.model.codepages = nil
+}
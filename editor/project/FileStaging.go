@@ -12,8 +12,8 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/texture"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/resource/lgres"
-	"github.com/inkyblackness/hacked/ss1/serial"
 	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
 )
 
 type fileStaging struct {
@@ -92,20 +92,15 @@ func (staging *fileStaging) stage(name string, isOnlyStagedFile bool) {
 			})
 		}
 		if strings.ToLower(filename) == world.ObjectPropertiesFilename {
-			decoder := serial.NewDecoder(bytes.NewReader(fileData))
-			properties := object.StandardPropertiesTable()
-			properties.Code(decoder)
-			err = decoder.FirstError()
+			var properties object.PropertiesTable
+			properties, err = world.DecodeObjectProperties(fileData)
 			if err == nil {
 				staging.modify(func() { staging.objectProperties = properties })
 			}
 		}
 		if strings.ToLower(filename) == world.TexturePropertiesFilename && (len(fileData) > 4) {
-			decoder := serial.NewDecoder(bytes.NewReader(fileData))
-			entryCount := (len(fileData) - 4) / texture.PropertiesSize
-			properties := make(texture.PropertiesList, entryCount)
-			properties.Code(decoder)
-			err = decoder.FirstError()
+			var properties texture.PropertiesList
+			properties, err = world.DecodeTextureProperties(fileData)
 			if err == nil {
 				staging.modify(func() { staging.textureProperties = properties })
 			}
@@ -117,6 +112,60 @@ func (staging *fileStaging) stage(name string, isOnlyStagedFile bool) {
 	}
 }
 
+// overlay takes the staged data of the other staging, replacing any data of the same file.
+func (staging *fileStaging) overlay(other *fileStaging) {
+	for filename, viewer := range other.resources {
+		staging.resources[filename] = viewer
+	}
+	if other.objectProperties != nil {
+		staging.objectProperties = other.objectProperties
+	}
+	if other.textureProperties != nil {
+		staging.textureProperties = other.textureProperties
+	}
+}
+
+// manifestEntry returns the staged resources as an entry for the manifest, loaded from the given sources.
+// The first source identifies the entry.
+func (staging *fileStaging) manifestEntry(sources []string) *world.ManifestEntry {
+	entry := &world.ManifestEntry{
+		ID:      sources[0],
+		Sources: sources,
+	}
+	for filename, viewer := range staging.resources {
+		localized := resource.LocalizedResources{
+			ID:       filename,
+			Language: ids.LocalizeFilename(filename),
+			Viewer:   viewer,
+		}
+		entry.Resources = append(entry.Resources, localized)
+	}
+	entry.ObjectProperties = staging.objectProperties
+	entry.TextureProperties = staging.textureProperties
+	return entry
+}
+
+// modResources returns copies of the staged resources, to be used as the resources of a mod.
+func (staging *fileStaging) modResources() []*world.LocalizedResources {
+	var locs []*world.LocalizedResources
+	for filename, viewer := range staging.resources {
+		lang := ids.LocalizeFilename(filename)
+		loc := &world.LocalizedResources{
+			Filename: filename,
+			Language: lang,
+		}
+		for _, id := range viewer.IDs() {
+			view, err := viewer.View(id)
+			if err == nil {
+				_ = loc.Store.Put(id, view)
+			}
+			// TODO: handle error?
+		}
+		locs = append(locs, loc)
+	}
+	return locs
+}
+
 func (staging *fileStaging) markFailedFile() {
 	staging.modify(func() { staging.failedFiles++ })
 }
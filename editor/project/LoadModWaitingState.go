@@ -6,8 +6,6 @@ import (
 	"github.com/inkyblackness/imgui-go"
 	"github.com/sqweek/dialog"
 
-	"github.com/inkyblackness/hacked/ss1/world"
-	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
@@ -60,26 +58,8 @@ func (state *loadModWaitingState) HandleFiles(names []string) {
 	staging.stageAll(names)
 
 	if len(staging.resources) > 0 {
-		var locs []*world.LocalizedResources
-
-		for filename, viewer := range staging.resources {
-			lang := ids.LocalizeFilename(filename)
-			loc := &world.LocalizedResources{
-				Filename: filename,
-				Language: lang,
-			}
-			for _, id := range viewer.IDs() {
-				view, err := viewer.View(id)
-				if err == nil {
-					_ = loc.Store.Put(id, view)
-				}
-				// TODO: handle error?
-			}
-			locs = append(locs, loc)
-		}
-
 		state.machine.SetState(nil)
-		state.view.requestLoadMod(names[0], locs, staging.objectProperties, staging.textureProperties)
+		state.view.requestLoadMod(names[0], staging.modResources(), staging.objectProperties, staging.textureProperties)
 	} else {
 		state.failureTime = time.Now()
 	}
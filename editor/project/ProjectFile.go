@@ -0,0 +1,159 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// StoreProject sets the manifest sources, the mod path, the languages, the codepages,
+// and the settings into the given project.
+func (view *View) StoreProject(file *projectfile.File) {
+	file.Languages = ids.RegisteredLanguages()
+	manifest := view.mod.World()
+	file.Manifest = nil
+	for at := 0; at < manifest.EntryCount(); at++ {
+		entry, err := manifest.Entry(at)
+		if err == nil {
+			sources := entry.Sources
+			if len(sources) == 0 {
+				sources = []string{entry.ID}
+			}
+			file.Manifest = append(file.Manifest, append(projectfile.ManifestEntry{}, sources...))
+		}
+	}
+	file.ModPath = view.mod.Path()
+	file.Codepages = nil
+	for _, lang := range resource.Languages() {
+		if index, assigned := view.model.assignedCodepages[lang]; assigned {
+			file.Codepages = append(file.Codepages, projectfile.Codepage{
+				Language: lang.String(),
+				File:     view.model.codepages[index].filename,
+			})
+		}
+	}
+	file.Settings.AutosaveTimeoutSec = view.model.autosaveTimeoutSec
+}
+
+// RestoreProject replaces the languages, the manifest, the mod, and the codepages with those of the given project.
+// This clears the undo/redo buffer. The returned list describes the parts that could not be restored.
+func (view *View) RestoreProject(file projectfile.File) []string {
+	return view.restoreProject(file, nil)
+}
+
+// restoreProject restores the given project, with the mod overlaid by the given files.
+// The overlaid files are marked as changed.
+func (view *View) restoreProject(file projectfile.File, overlay []string) []string {
+	problems := restoreLanguages(file.Languages)
+
+	manifest := view.mod.World()
+	for manifest.EntryCount() > 0 {
+		_ = manifest.RemoveEntry(manifest.EntryCount() - 1)
+	}
+	view.model.selectedManifestEntry = -1
+	for _, sources := range file.Manifest {
+		if len(sources) == 0 {
+			continue
+		}
+		staging := newFileStaging()
+		staging.stageAll(sources)
+		if len(staging.resources) == 0 {
+			problems = append(problems, fmt.Sprintf("No usable static world data in <%s>.", strings.Join(sources, ", ")))
+			continue
+		}
+		_ = manifest.InsertEntry(manifest.EntryCount(), staging.manifestEntry(append([]string{}, sources...)))
+	}
+
+	modStaging := newFileStaging()
+	if len(file.ModPath) > 0 {
+		if _, err := os.Stat(file.ModPath); err != nil {
+			problems = append(problems, fmt.Sprintf("Mod <%s> not accessible.", file.ModPath))
+		}
+		modStaging.stageAll([]string{file.ModPath})
+	}
+	if len(overlay) > 0 {
+		overlayStaging := newFileStaging()
+		overlayStaging.stageList(overlay, false)
+		if overlayStaging.failedFiles > 0 {
+			problems = append(problems, fmt.Sprintf("%d changed file(s) could not be restored.", overlayStaging.failedFiles))
+		}
+		modStaging.overlay(overlayStaging)
+	}
+	view.requestLoadMod(file.ModPath, modStaging.modResources(), modStaging.objectProperties, modStaging.textureProperties)
+	if len(overlay) > 0 {
+		var filenames []string
+		for _, path := range overlay {
+			filenames = append(filenames, filepath.Base(path))
+		}
+		view.mod.MarkFilesChanged(filenames)
+	}
+
+	problems = append(problems, view.restoreCodepages(file.Codepages)...)
+
+	view.model.autosaveTimeoutSec = freshViewModel().autosaveTimeoutSec
+	if file.Settings.AutosaveTimeoutSec > 0 {
+		view.model.autosaveTimeoutSec = file.Settings.AutosaveTimeoutSec
+	}
+	return problems
+}
+
+func (view *View) restoreCodepages(assignments []projectfile.Codepage) []string {
+	var problems []string
+	view.resetCodepages()
+	for _, assignment := range assignments {
+		lang, known := resource.LanguageNamed(assignment.Language)
+		if !known {
+			problems = append(problems, fmt.Sprintf("Codepage for unknown language <%s> ignored.", assignment.Language))
+			continue
+		}
+		index := view.codepageIndex(assignment.File)
+		if index < 0 {
+			cp, err := loadCodepageFile(assignment.File)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("Codepage <%s> could not be loaded: %v", assignment.File, err))
+				continue
+			}
+			view.addCodepage(assignment.File, cp)
+			index = len(view.model.codepages) - 1
+		}
+		view.model.assignedCodepages[lang] = index
+		view.codepages.Assign(lang, view.model.codepages[index].cp)
+	}
+	view.codepagesChanged()
+	return problems
+}
+
+func loadCodepageFile(filename string) (text.Codepage, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return text.LoadCodepage(reader)
+}
+
+// restoreLanguages registers the given languages, if they differ from the currently registered ones.
+func restoreLanguages(defs []ids.LanguageDefinition) []string {
+	current := ids.RegisteredLanguages()
+	same := len(current) == len(defs)
+	for index := 0; same && (index < len(current)); index++ {
+		same = current[index] == defs[index]
+	}
+	if same {
+		return nil
+	}
+	var problems []string
+	ids.ResetLanguages()
+	for _, def := range defs {
+		if _, err := ids.RegisterLanguage(def); err != nil {
+			problems = append(problems, fmt.Sprintf("Language <%s> could not be registered: %v", def.Name, err))
+		}
+	}
+	return problems
+}
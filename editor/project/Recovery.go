@@ -0,0 +1,126 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/edit/recovery"
+)
+
+// OfferRecovery checks for a snapshot of unsaved changes from a previous session.
+// If there is one, the user is asked whether to restore or to discard it.
+func (view *View) OfferRecovery() {
+	if view.recoveryStore == nil {
+		return
+	}
+	snapshot, found := view.recoveryStore.Load()
+	if !found {
+		return
+	}
+	view.model.recoveryOffered = true
+	view.modalStateMachine.SetState(&recoveryOfferStartState{
+		machine:  view.modalStateMachine,
+		view:     view,
+		snapshot: snapshot,
+	})
+}
+
+// FinishRecovery records the latest unsaved changes and waits until all snapshots are written.
+// It is called before the application exits.
+func (view *View) FinishRecovery() {
+	if (view.recorder == nil) || view.model.recoveryOffered {
+		return
+	}
+	if (len(view.mod.ModifiedFilenames()) > 0) && view.changedSinceSnapshot() {
+		view.recordSnapshot()
+	}
+	view.recorder.Flush()
+}
+
+// updateRecovery records a snapshot if the mod has unsaved changes for some time,
+// and discards the snapshot once all changes are saved.
+func (view *View) updateRecovery() {
+	if (view.recorder == nil) || view.model.recoveryOffered {
+		return
+	}
+	now := time.Now()
+	if len(view.mod.ModifiedFilenames()) == 0 {
+		if !view.model.snapshotTime.IsZero() {
+			view.recorder.Discard()
+		}
+		view.model.dirtySince = time.Time{}
+		view.model.snapshotTime = time.Time{}
+		view.model.snapshotChangeCount = 0
+		view.model.snapshotFiles = nil
+		return
+	}
+	if view.model.dirtySince.IsZero() {
+		view.model.dirtySince = now
+	}
+	due := view.model.dirtySince
+	if view.model.snapshotTime.After(due) {
+		due = view.model.snapshotTime
+	}
+	due = due.Add(time.Duration(view.model.recoveryIntervalSec) * time.Second)
+	if view.changedSinceSnapshot() && !now.Before(due) {
+		view.recordSnapshot()
+	}
+}
+
+func (view *View) changedSinceSnapshot() bool {
+	return view.model.snapshotTime.IsZero() || (view.mod.ChangeCount() != view.model.snapshotChangeCount)
+}
+
+// recordSnapshot requests to save all modified files.
+// Only the files that were changed since the previous snapshot are encoded again, the others are reused.
+func (view *View) recordSnapshot() {
+	previousFiles := view.model.snapshotFiles
+	changed := view.mod.ModifiedFilenamesSince(view.model.snapshotChangeCount)
+	view.model.snapshotTime = time.Now()
+	view.model.snapshotChangeCount = view.mod.ChangeCount()
+	view.model.snapshotFiles = nil
+	encoded, err := encodeFiles(view.mod, changed)
+	if err != nil {
+		// The next attempt encodes all files again.
+		view.model.snapshotChangeCount = 0
+		return
+	}
+	files := make(map[string][]byte)
+	for _, filename := range view.mod.ModifiedFilenames() {
+		if data, known := encoded[filename]; known {
+			files[filename] = data
+		} else if data, known := previousFiles[filename]; known {
+			files[filename] = data
+		}
+	}
+	view.model.snapshotFiles = files
+	info := recovery.Info{Created: view.model.snapshotTime}
+	view.StoreProject(&info.Project)
+	view.recorder.Save(info, files)
+}
+
+func (view *View) restoreSnapshot(snapshot recovery.Snapshot) []string {
+	view.model.recoveryOffered = false
+	return view.restoreProject(snapshot.Project, snapshot.Paths())
+}
+
+func (view *View) discardSnapshot() {
+	view.model.recoveryOffered = false
+	_ = view.recoveryStore.Discard()
+}
+
+// savedAfter returns the names of the files that were saved into the mod after the snapshot was taken.
+func savedAfter(snapshot recovery.Snapshot) []string {
+	var filenames []string
+	if len(snapshot.Project.ModPath) == 0 {
+		return nil
+	}
+	for _, filename := range snapshot.Files {
+		info, err := os.Stat(filepath.Join(snapshot.Project.ModPath, filename))
+		if (err == nil) && info.ModTime().After(snapshot.Created) {
+			filenames = append(filenames, filename)
+		}
+	}
+	return filenames
+}
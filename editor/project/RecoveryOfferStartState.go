@@ -0,0 +1,27 @@
+package project
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/edit/recovery"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type recoveryOfferStartState struct {
+	machine  gui.ModalStateMachine
+	view     *View
+	snapshot recovery.Snapshot
+}
+
+func (state recoveryOfferStartState) Render() {
+	imgui.OpenPopup("Recover unsaved changes")
+	state.machine.SetState(&recoveryOfferWaitingState{
+		machine:  state.machine,
+		view:     state.view,
+		snapshot: state.snapshot,
+		newer:    savedAfter(state.snapshot),
+	})
+}
+
+func (state recoveryOfferStartState) HandleFiles(names []string) {
+}
@@ -0,0 +1,76 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/edit/recovery"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type recoveryOfferWaitingState struct {
+	machine  gui.ModalStateMachine
+	view     *View
+	snapshot recovery.Snapshot
+	newer    []string
+}
+
+func (state *recoveryOfferWaitingState) Render() {
+	if imgui.BeginPopupModalV("Recover unsaved changes", nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		imgui.Text("The previous session ended with changes that were not saved.")
+		imgui.Text(state.description())
+		if len(state.newer) > 0 {
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 1, Z: 0, W: 1})
+			imgui.Text("The mod was saved after the snapshot, restoring would revert these files:\n  " +
+				strings.Join(state.newer, "\n  "))
+			imgui.PopStyleColor()
+		}
+		imgui.Text("Restoring replaces the static world data and the mod with the state of the snapshot.\n" +
+			"Discarding removes the snapshot for good.")
+		imgui.Separator()
+		if imgui.Button("Restore") {
+			state.machine.SetState(nil)
+			imgui.CloseCurrentPopup()
+			problems := state.view.restoreSnapshot(state.snapshot)
+			if len(problems) > 0 {
+				external.Notice(state.machine, "Recover unsaved changes",
+					"The changes were restored with problems:\n"+strings.Join(problems, "\n"))
+			}
+		}
+		imgui.SameLine()
+		if imgui.Button("Discard") {
+			state.view.discardSnapshot()
+			state.machine.SetState(nil)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.view.model.recoveryOffered = false
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *recoveryOfferWaitingState) description() string {
+	project := state.snapshot.Project
+	modPath := project.ModPath
+	if len(modPath) == 0 {
+		modPath = "(new mod)"
+	}
+	lines := []string{
+		"Snapshot taken: " + state.snapshot.Created.Local().Format("2006-01-02 15:04:05"),
+		"Mod: " + modPath,
+		fmt.Sprintf("Static world data: %d manifest entries", len(project.Manifest)),
+		"Changed files:",
+	}
+	for _, filename := range state.snapshot.Files {
+		lines = append(lines, "  "+filename)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (state *recoveryOfferWaitingState) HandleFiles(names []string) {
+}
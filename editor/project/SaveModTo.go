@@ -2,11 +2,9 @@ package project
 
 import (
 	"bytes"
-	"os"
+	"io/ioutil"
 	"path/filepath"
 
-	"github.com/inkyblackness/hacked/ss1/content/object"
-	"github.com/inkyblackness/hacked/ss1/content/texture"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/resource/lgres"
 	"github.com/inkyblackness/hacked/ss1/serial"
@@ -14,8 +12,23 @@ import (
 )
 
 func saveModResourcesTo(mod *world.Mod, modPath string) error {
+	files, err := encodeFiles(mod, mod.ModifiedFilenames())
+	if err != nil {
+		return err
+	}
+	for filename, data := range files {
+		err = ioutil.WriteFile(filepath.Join(modPath, filename), data, 0666)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeFiles returns the content of the given files of the mod, keyed by their filename.
+func encodeFiles(mod *world.Mod, filenamesToSave []string) (map[string][]byte, error) {
 	localized := mod.ModifiedResources()
-	filenamesToSave := mod.ModifiedFilenames()
+	files := make(map[string][]byte)
 
 	shallBeSaved := func(filename string) bool {
 		for _, toSave := range filenamesToSave {
@@ -28,65 +41,41 @@ func saveModResourcesTo(mod *world.Mod, modPath string) error {
 
 	for _, loc := range localized {
 		if shallBeSaved(loc.Filename) {
-			err := saveResourcesTo(loc.Store, filepath.Join(modPath, loc.Filename))
+			data, err := encodeResources(loc.Store)
 			if err != nil {
-				return err
+				return nil, err
 			}
+			files[loc.Filename] = data
 		}
 	}
 
 	if shallBeSaved(world.TexturePropertiesFilename) {
-		err := saveTexturePropertiesTo(mod.TextureProperties(), filepath.Join(modPath, world.TexturePropertiesFilename))
+		data, err := encodeCodable(mod.TextureProperties())
 		if err != nil {
-			return err
+			return nil, err
 		}
+		files[world.TexturePropertiesFilename] = data
 	}
 	if shallBeSaved(world.ObjectPropertiesFilename) {
-		err := saveObjectPropertiesTo(mod.ObjectProperties(), filepath.Join(modPath, world.ObjectPropertiesFilename))
+		data, err := encodeCodable(mod.ObjectProperties())
 		if err != nil {
-			return err
+			return nil, err
 		}
+		files[world.ObjectPropertiesFilename] = data
 	}
 
-	return nil
+	return files, nil
 }
 
-func saveResourcesTo(viewer resource.Viewer, absFilename string) error {
-	file, err := os.Create(absFilename)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = file.Close() // nolint: gas
-	}()
-	err = lgres.Write(file, viewer)
-	return err
-}
-
-func saveTexturePropertiesTo(list texture.PropertiesList, absFilename string) error {
-	return saveCodableTo(list, absFilename)
+func encodeResources(viewer resource.Viewer) ([]byte, error) {
+	store := serial.NewByteStore()
+	err := lgres.Write(store, viewer)
+	return store.Data(), err
 }
 
-func saveObjectPropertiesTo(list object.PropertiesTable, absFilename string) error {
-	return saveCodableTo(list, absFilename)
-}
-
-func saveCodableTo(codable serial.Codable, absFilename string) error {
+func encodeCodable(codable serial.Codable) ([]byte, error) {
 	buffer := bytes.NewBuffer(nil)
 	encoder := serial.NewEncoder(buffer)
 	codable.Code(encoder)
-	err := encoder.FirstError()
-	if err != nil {
-		return err
-	}
-
-	file, err := os.Create(absFilename)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = file.Close() // nolint: gas
-	}()
-	_, err = file.Write(buffer.Bytes())
-	return err
+	return buffer.Bytes(), encoder.FirstError()
 }
@@ -8,7 +8,9 @@ import (
 	"github.com/inkyblackness/imgui-go"
 
 	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/recovery"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ui/gui"
@@ -16,27 +18,43 @@ import (
 
 // View handles the project display.
 type View struct {
-	mod *world.Mod
+	mod              *world.Mod
+	codepages        *text.LanguageCodepages
+	codepagesChanged func()
 
 	modalStateMachine gui.ModalStateMachine
 	guiScale          float32
 	commander         cmd.Commander
 
+	recoveryStore *recovery.Store
+	recorder      *recovery.Recorder
+
 	model viewModel
 }
 
 // NewView creates a new instance for the project display.
-func NewView(mod *world.Mod, modalStateMachine gui.ModalStateMachine,
-	guiScale float32, commander cmd.Commander) *View {
-	return &View{
-		mod: mod,
+// The given callback is called whenever the assignment of codepages to languages has changed.
+// Snapshots of unsaved changes are kept in the given store, which is optional.
+func NewView(mod *world.Mod, codepages *text.LanguageCodepages, codepagesChanged func(),
+	modalStateMachine gui.ModalStateMachine, guiScale float32, commander cmd.Commander,
+	recoveryStore *recovery.Store) *View {
+	view := &View{
+		mod:              mod,
+		codepages:        codepages,
+		codepagesChanged: codepagesChanged,
 
 		modalStateMachine: modalStateMachine,
 		guiScale:          guiScale,
 		commander:         commander,
 
+		recoveryStore: recoveryStore,
+
 		model: freshViewModel(),
 	}
+	if recoveryStore != nil {
+		view.recorder = recovery.NewRecorder(recoveryStore)
+	}
+	return view
 }
 
 // WindowOpen returns the flag address, to be used with the main menu.
@@ -69,6 +87,7 @@ func (view *View) Render() {
 			}
 		}
 	}
+	view.updateRecovery()
 	if view.model.windowOpen {
 		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
 		if imgui.BeginV(title+"###Project", view.WindowOpen(), 0) {
@@ -104,6 +123,18 @@ func (view *View) renderContent() {
 	}
 	imgui.EndGroup()
 
+	if imgui.TreeNodeV("Codepages", imgui.TreeNodeFlagsFramed) {
+		view.renderCodepages()
+		imgui.TreePop()
+	}
+	if imgui.TreeNodeV("Settings", imgui.TreeNodeFlagsFramed) {
+		autosaveTimeout := int32(view.model.autosaveTimeoutSec)
+		if imgui.SliderIntV("Auto-save Delay", &autosaveTimeout, 1, 300, "%d sec") {
+			view.model.autosaveTimeoutSec = int(autosaveTimeout)
+		}
+		imgui.TreePop()
+	}
+
 	imgui.Text("Static World Data")
 	imgui.BeginChildV("ManifestEntries", imgui.Vec2{X: -100 * view.guiScale, Y: 0}, true, 0)
 	manifest := view.mod.World()
@@ -181,7 +212,7 @@ func (view *View) requestMoveManifestEntry(to, from int) {
 		to:    to,
 		from:  from,
 	}
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{Command: command, Title: "Move static world data source"})
 }
 
 func (view *View) requestAddManifestEntry(entry *world.ManifestEntry) {
@@ -194,7 +225,7 @@ func (view *View) requestAddManifestEntry(entry *world.ManifestEntry) {
 		entry: entry,
 		adder: true,
 	}
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{Command: command, Title: "Add static world data source"})
 }
 
 func (view *View) requestRemoveManifestEntry() {
@@ -215,13 +246,15 @@ func (view *View) requestRemoveManifestEntry() {
 		entry: entry,
 		adder: false,
 	}
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{Command: command, Title: "Remove static world data source"})
 }
 
 func (view *View) requestLoadMod(modPath string, resources []*world.LocalizedResources,
 	objectProperties object.PropertiesTable, textureProperties texture.PropertiesList) {
 	view.mod.SetPath(modPath)
 	view.mod.Reset(resources, objectProperties, textureProperties)
+	view.resetCodepages()
+	view.codepagesChanged()
 	// fix list resources for any "old" mod.
 	view.mod.FixListResources()
 }
@@ -1,11 +1,27 @@
 package project
 
+import (
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
 type viewModel struct {
 	restoreFocus          bool
 	windowOpen            bool
 	selectedManifestEntry int
 
 	autosaveTimeoutSec int
+
+	recoveryIntervalSec int
+	recoveryOffered     bool
+	dirtySince          time.Time
+	snapshotTime        time.Time
+	snapshotChangeCount uint64
+	snapshotFiles       map[string][]byte
+
+	codepages         []loadedCodepage
+	assignedCodepages map[resource.Language]int
 }
 
 func freshViewModel() viewModel {
@@ -13,5 +29,7 @@ func freshViewModel() viewModel {
 		windowOpen:            true,
 		selectedManifestEntry: -1,
 		autosaveTimeoutSec:    5,
+		recoveryIntervalSec:   30,
+		assignedCodepages:     make(map[resource.Language]int),
 	}
 }
@@ -0,0 +1,251 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+const maxDisplayedLength = 80
+
+// Navigator opens the views that show the found strings.
+type Navigator interface {
+	ShowText(key resource.Key)
+	ShowMessage(key resource.Key)
+	ShowObject(triple object.Triple, lang resource.Language)
+	ShowTexture(index int, lang resource.Language)
+	ShowSubtitles(movieKey resource.Key, lang resource.Language)
+}
+
+// View provides search and replace across all strings.
+type View struct {
+	mod           *world.Mod
+	searchService undoable.SearchService
+	cps           text.Codepages
+	navigator     Navigator
+
+	modalStateMachine gui.ModalStateMachine
+	guiScale          float32
+
+	model viewModel
+}
+
+// NewSearchView returns a new instance.
+func NewSearchView(mod *world.Mod, searchService undoable.SearchService, cps text.Codepages, navigator Navigator,
+	modalStateMachine gui.ModalStateMachine, guiScale float32) *View {
+	view := &View{
+		mod:           mod,
+		searchService: searchService,
+		cps:           cps,
+		navigator:     navigator,
+
+		modalStateMachine: modalStateMachine,
+		guiScale:          guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 500 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionOnce)
+		if imgui.BeginV("Search", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *View) renderContent() {
+	imgui.PushItemWidth(-150 * view.guiScale)
+	if imgui.InputText("Find", &view.model.pattern) {
+		view.model.replacePreviewed = false
+	}
+	if imgui.InputText("Replace With", &view.model.replacement) {
+		view.model.replacePreviewed = false
+	}
+	imgui.PopItemWidth()
+	if imgui.Checkbox("Regular Expression", &view.model.useRegex) {
+		view.model.replacePreviewed = false
+	}
+	imgui.SameLine()
+	if imgui.Checkbox("Case Sensitive", &view.model.caseSensitive) {
+		view.model.replacePreviewed = false
+	}
+
+	if imgui.Button("Find All") {
+		view.find(false)
+	}
+	imgui.SameLine()
+	if imgui.Button("Preview Replace") {
+		view.find(true)
+	}
+	if view.model.replacePreviewed && (len(view.model.matches) > 0) {
+		imgui.SameLine()
+		if imgui.Button("Replace All") {
+			view.requestReplaceAll()
+		}
+	}
+	if len(view.model.patternError) > 0 {
+		imgui.Text("Invalid pattern: " + view.model.patternError)
+	} else {
+		imgui.Text(fmt.Sprintf("%d matches", len(view.model.matches)))
+	}
+	imgui.Separator()
+
+	if imgui.BeginChildV("Matches", imgui.Vec2{X: -1, Y: -1}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		for index, match := range view.model.matches {
+			label := fmt.Sprintf("[%v] %s: %s###match%d", match.Location.Lang, match.Context, displayed(match.Text), index)
+			if imgui.Selectable(label) {
+				view.show(match.Location)
+			}
+			if view.model.replacePreviewed {
+				imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 0.6, Y: 1.0, Z: 0.6, W: 1.0})
+				imgui.Text("    -> " + displayed(match.Replaced))
+				imgui.PopStyleColor()
+			}
+		}
+	}
+	imgui.EndChild()
+}
+
+func displayed(value string) string {
+	single := strings.Replace(value, "\n", " ", -1)
+	if runes := []rune(single); len(runes) > maxDisplayedLength {
+		single = string(runes[:maxDisplayedLength]) + "..."
+	}
+	return single
+}
+
+func (view *View) query() (edit.SearchQuery, error) {
+	pattern := view.model.pattern
+	if !view.model.useRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !view.model.caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	compiled, err := regexp.Compile(pattern)
+	return edit.SearchQuery{
+		Pattern:     compiled,
+		Replacement: view.model.replacement,
+		Literal:     !view.model.useRegex,
+	}, err
+}
+
+func (view *View) find(withReplace bool) {
+	view.model.matches = nil
+	view.model.replacePreviewed = false
+	view.model.patternError = ""
+	if len(view.model.pattern) == 0 {
+		return
+	}
+	query, err := view.query()
+	if err != nil {
+		view.model.patternError = err.Error()
+		return
+	}
+	view.model.matches = view.searchService.Find(query)
+	view.model.replacePreviewed = withReplace
+}
+
+func (view *View) requestReplaceAll() {
+	var accepted []edit.SearchMatch
+	var rejected []string
+	unchanged := view.searchService.Unchanged(view.model.matches)
+	staleCount := len(view.model.matches) - len(unchanged)
+	for _, match := range unchanged {
+		if len(text.Unencodable(view.cps.ForLanguage(match.Location.Lang), match.Replaced)) > 0 {
+			rejected = append(rejected, fmt.Sprintf("[%v] %s", match.Location.Lang, match.Context))
+			continue
+		}
+		accepted = append(accepted, match)
+	}
+	if len(accepted) > 0 {
+		view.searchService.RequestReplace(accepted, view.restoreFunc())
+	}
+	view.model.matches = nil
+	view.model.replacePreviewed = false
+
+	if (len(rejected) > 0) || (staleCount > 0) {
+		const maxListed = 10
+		lines := []string{fmt.Sprintf("%d strings replaced.", len(accepted))}
+		if staleCount > 0 {
+			lines = append(lines, fmt.Sprintf("%d strings skipped (modified since the search).", staleCount))
+		}
+		if len(rejected) > 0 {
+			lines = append(lines, fmt.Sprintf("%d strings rejected (characters not in codepage):", len(rejected)))
+		}
+		for index, entry := range rejected {
+			if index >= maxListed {
+				lines = append(lines, fmt.Sprintf("  ... and %d more", len(rejected)-maxListed))
+				break
+			}
+			lines = append(lines, "  "+entry)
+		}
+		external.Notice(view.modalStateMachine, "Replace All", strings.Join(lines, "\n"))
+	}
+}
+
+func (view *View) show(loc edit.SearchLocation) {
+	key := resource.KeyOf(loc.Key.ID, loc.Lang, loc.Key.Index)
+	switch {
+	case loc.Subtitle:
+		view.navigator.ShowSubtitles(loc.MovieKey(), loc.Lang)
+	case len(loc.Key.Field) > 0:
+		view.navigator.ShowMessage(key)
+	case (loc.Key.ID == ids.ObjectLongNames) || (loc.Key.ID == ids.ObjectShortNames):
+		if triple, found := view.tripleAt(loc.Key.Index); found {
+			view.navigator.ShowObject(triple, loc.Lang)
+		}
+	case (loc.Key.ID == ids.TextureNames) || (loc.Key.ID == ids.TextureUsages):
+		view.navigator.ShowTexture(loc.Key.Index, loc.Lang)
+	default:
+		view.navigator.ShowText(key)
+	}
+}
+
+func (view *View) tripleAt(index int) (object.Triple, bool) {
+	var result object.Triple
+	found := false
+	current := 0
+	view.mod.ObjectProperties().Iterate(func(triple object.Triple, _ *object.Properties) bool {
+		if current == index {
+			result = triple
+			found = true
+		}
+		current++
+		return !found
+	})
+	return result, found
+}
+
+func (view *View) restoreFunc() func() {
+	return func() {
+		view.model.restoreFocus = true
+	}
+}
@@ -0,0 +1,21 @@
+package search
+
+import "github.com/inkyblackness/hacked/ss1/edit"
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	pattern       string
+	replacement   string
+	useRegex      bool
+	caseSensitive bool
+
+	patternError     string
+	matches          []edit.SearchMatch
+	replacePreviewed bool
+}
+
+func freshViewModel() viewModel {
+	return viewModel{}
+}
@@ -0,0 +1,165 @@
+package shortcuts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/input"
+)
+
+// Action describes an operation that can be triggered by key bindings.
+type Action struct {
+	// Name identifies the action in the keymap.
+	Name string
+	// Defaults are the bindings the action has if the user did not change them.
+	Defaults []input.Binding
+}
+
+// View allows to change the key bindings of actions.
+type View struct {
+	actions []Action
+	keymap  *input.Keymap
+	changed func()
+
+	guiScale float32
+
+	model viewModel
+}
+
+// NewView returns a new instance. The changed callback is called after the keymap was modified.
+func NewView(actions []Action, keymap *input.Keymap, changed func(), guiScale float32) *View {
+	view := &View{
+		actions: actions,
+		keymap:  keymap,
+		changed: changed,
+
+		guiScale: guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// DefaultKeymap returns the keymap with the default bindings of the given actions.
+func DefaultKeymap(actions []Action) input.Keymap {
+	keymap := input.NewKeymap()
+	for _, action := range actions {
+		keymap.Set(action.Name, action.Defaults...)
+	}
+	return keymap
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Capturing returns true while the view waits for a key to bind.
+// During this time, key events must be forwarded to Capture instead of triggering actions.
+func (view *View) Capturing() bool {
+	return len(view.model.capturingAction) > 0
+}
+
+// Capture adds the given binding to the action that is waiting for a key.
+// The escape key cancels the capture.
+func (view *View) Capture(binding input.Binding) {
+	action := view.model.capturingAction
+	view.model.capturingAction = ""
+	if (len(action) == 0) || ((binding.Key == input.KeyEscape) && (binding.Modifier == input.ModNone)) {
+		return
+	}
+	bindings := view.keymap.Bindings[action]
+	for _, existing := range bindings {
+		if existing == binding {
+			return
+		}
+	}
+	view.keymap.Set(action, append(bindings, binding)...)
+	view.changed()
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 500 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionOnce)
+		if imgui.BeginV("Keyboard Shortcuts", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	} else {
+		view.model.capturingAction = ""
+	}
+}
+
+func (view *View) renderContent() {
+	conflicts := view.keymap.Conflicts()
+	if len(view.model.capturingAction) > 0 {
+		imgui.Text(fmt.Sprintf("Press the keys for <%s>, or Escape to cancel.", view.model.capturingAction))
+	} else {
+		imgui.Text(fmt.Sprintf("%d conflict(s)", len(conflicts)))
+	}
+	imgui.SameLine()
+	if imgui.Button("Reset All") {
+		*view.keymap = DefaultKeymap(view.actions)
+		view.model.capturingAction = ""
+		view.changed()
+	}
+	imgui.Separator()
+
+	if imgui.BeginChildV("Actions", imgui.Vec2{X: -1, Y: -1}, true, 0) {
+		imgui.Columns(3, "shortcuts")
+		for _, action := range view.actions {
+			view.renderAction(action, conflicts)
+		}
+		imgui.Columns(1, "")
+	}
+	imgui.EndChild()
+}
+
+func (view *View) renderAction(action Action, conflicts map[input.Binding][]string) {
+	bindings := view.keymap.Bindings[action.Name]
+	imgui.Text(action.Name)
+	imgui.NextColumn()
+
+	var texts []string
+	var conflictTexts []string
+	for _, binding := range bindings {
+		texts = append(texts, binding.String())
+		if others, conflicting := conflicts[binding]; conflicting {
+			conflictTexts = append(conflictTexts, fmt.Sprintf("%v: %s", binding, strings.Join(others, ", ")))
+		}
+	}
+	if len(conflictTexts) > 0 {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.4, Z: 0.4, W: 1.0})
+	}
+	imgui.Text(strings.Join(texts, " / "))
+	if len(conflictTexts) > 0 {
+		imgui.PopStyleColor()
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Conflicts with\n" + strings.Join(conflictTexts, "\n"))
+		}
+	}
+	imgui.NextColumn()
+
+	if imgui.Button("Add###add" + action.Name) {
+		view.model.capturingAction = action.Name
+	}
+	imgui.SameLine()
+	if imgui.Button("Clear###clear" + action.Name) {
+		view.keymap.Set(action.Name)
+		view.changed()
+	}
+	imgui.SameLine()
+	if imgui.Button("Default###default" + action.Name) {
+		view.keymap.Set(action.Name, action.Defaults...)
+		view.changed()
+	}
+	imgui.NextColumn()
+}
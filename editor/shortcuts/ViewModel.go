@@ -0,0 +1,12 @@
+package shortcuts
+
+type viewModel struct {
+	capturingAction string
+
+	windowOpen   bool
+	restoreFocus bool
+}
+
+func freshViewModel() viewModel {
+	return viewModel{}
+}
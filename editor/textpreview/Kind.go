@@ -0,0 +1,81 @@
+package textpreview
+
+import (
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// Kind identifies where a text is displayed in the game.
+type Kind int
+
+// Known kinds of text displays.
+const (
+	KindPaper Kind = iota
+	KindDatalet
+	KindMailVerbose
+	KindMailTerse
+	KindScreenMessage
+)
+
+var kinds = []Kind{KindPaper, KindDatalet, KindMailVerbose, KindMailTerse, KindScreenMessage}
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindPaper:
+		return "Paper"
+	case KindDatalet:
+		return "MFD Datalet"
+	case KindMailVerbose:
+		return "Message (Verbose)"
+	case KindMailTerse:
+		return "Message (Terse, MFD)"
+	case KindScreenMessage:
+		return "Screen Message"
+	default:
+		return "Unknown"
+	}
+}
+
+// Dimensions of the low-resolution screen, in pixels.
+const (
+	viewWidth  = 268
+	viewHeight = 108
+
+	mfdWidth  = 73
+	mfdHeight = 73
+
+	messageLineHeight = 8
+)
+
+// area describes the space a text has available, in pixels of the low-resolution screen.
+// Paged areas show further lines on following pages, others cut off what does not fit.
+type area struct {
+	width  int32
+	height int32
+	paged  bool
+}
+
+// defaultAreas are starting values. They can be adjusted in the preview to match specific displays.
+func defaultAreas() map[Kind]area {
+	return map[Kind]area{
+		KindPaper:         {width: viewWidth, height: viewHeight, paged: true},
+		KindDatalet:       {width: mfdWidth, height: mfdHeight, paged: false},
+		KindMailVerbose:   {width: viewWidth, height: viewHeight, paged: true},
+		KindMailTerse:     {width: mfdWidth, height: mfdHeight, paged: false},
+		KindScreenMessage: {width: viewWidth, height: messageLineHeight, paged: false},
+	}
+}
+
+// defaultFonts are the fonts the game uses for the displays, as far as they are identified.
+// Fonts can be changed in the preview.
+func defaultFonts() map[Kind]resource.ID {
+	mfdFont := ids.FontsStart.Plus(4)
+	viewFont := ids.FontsStart.Plus(6)
+	return map[Kind]resource.ID{
+		KindPaper:         viewFont,
+		KindDatalet:       mfdFont,
+		KindMailVerbose:   viewFont,
+		KindMailTerse:     mfdFont,
+		KindScreenMessage: ids.FontsStart.Plus(3),
+	}
+}
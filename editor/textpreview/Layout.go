@@ -0,0 +1,75 @@
+package textpreview
+
+import (
+	"bytes"
+
+	"github.com/inkyblackness/hacked/ss1/content/font"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+// layout is the result of wrapping a text within an area.
+type layout struct {
+	lines []font.Line
+	// linesPerPage is the number of lines that fit the height of the area.
+	linesPerPage int
+	// tooWide lists the indices of lines that exceed the width of the area.
+	tooWide []int
+	// overflowStart is the index of the first line that does not fit an area without pages. -1 if all fit.
+	overflowStart int
+	pages         int
+}
+
+// layoutText wraps the given text like the game does.
+// The text is split into blocks as it is stored, see text.Blocked(), and each block starts on a new line.
+// Within a block, lines are broken at spaces where they exceed the width.
+func layoutText(f font.Font, cp text.Codepage, value string, a area) layout {
+	result := layout{
+		linesPerPage:  linesPerPage(f, a),
+		overflowStart: -1,
+		pages:         1,
+	}
+	for _, block := range text.Blocked(value) {
+		if len(block) == 0 {
+			continue
+		}
+		encoded := bytes.TrimRight(cp.Encode(block), "\x00")
+		encoded = bytes.TrimSuffix(encoded, []byte{'\n'})
+		result.lines = append(result.lines, f.Wrap(encoded, int(a.width))...)
+	}
+	for index, line := range result.lines {
+		if line.Width > int(a.width) {
+			result.tooWide = append(result.tooWide, index)
+		}
+	}
+	lineCount := len(result.lines)
+	if a.paged {
+		result.pages = (lineCount + result.linesPerPage - 1) / result.linesPerPage
+		if result.pages < 1 {
+			result.pages = 1
+		}
+	} else if lineCount > result.linesPerPage {
+		result.overflowStart = result.linesPerPage
+	}
+	return result
+}
+
+func linesPerPage(f font.Font, a area) int {
+	lines := 1
+	if f.Height > 0 {
+		lines = int(a.height) / f.Height
+	}
+	if lines < 1 {
+		lines = 1
+	}
+	return lines
+}
+
+func (l layout) maxWidth() int {
+	width := 0
+	for _, line := range l.lines {
+		if line.Width > width {
+			width = line.Width
+		}
+	}
+	return width
+}
@@ -0,0 +1,77 @@
+package textpreview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/font"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+// aFixedFont returns a font with all printable characters one pixel wide and two pixels high.
+func aFixedFont() font.Font {
+	f := font.Font{FirstCharacter: ' ', LastCharacter: '~', Height: 2}
+	for index := 0; index <= int(f.LastCharacter-f.FirstCharacter)+1; index++ {
+		f.Offsets = append(f.Offsets, index)
+	}
+	return f
+}
+
+func lineTexts(l layout) []string {
+	result := make([]string, len(l.lines))
+	for index, line := range l.lines {
+		result[index] = string(line.Characters)
+	}
+	return result
+}
+
+func TestLayoutTextWrapsWithinWidth(t *testing.T) {
+	result := layoutText(aFixedFont(), text.DefaultCodepage(), "aaa bbb\nccc", area{width: 5, height: 10})
+
+	assert.Equal(t, []string{"aaa", "bbb", "ccc"}, lineTexts(result))
+	assert.Equal(t, 0, len(result.tooWide))
+	assert.Equal(t, -1, result.overflowStart)
+}
+
+func TestLayoutTextStartsEachBlockOnNewLine(t *testing.T) {
+	first := strings.Repeat("a", 70)
+	second := strings.Repeat("b", 20)
+	value := first + " " + second
+
+	result := layoutText(aFixedFont(), text.DefaultCodepage(), value, area{width: 200, height: 10})
+
+	assert.Equal(t, []string{first, second}, lineTexts(result))
+}
+
+func TestLayoutTextKeepsBlankLinesAtBlockEnd(t *testing.T) {
+	first := strings.Repeat("a", 78)
+	value := first + "\n\nb"
+
+	result := layoutText(aFixedFont(), text.DefaultCodepage(), value, area{width: 200, height: 10})
+
+	assert.Equal(t, []string{first, "", "b"}, lineTexts(result))
+}
+
+func TestLayoutTextReportsTooWideLines(t *testing.T) {
+	result := layoutText(aFixedFont(), text.DefaultCodepage(), "a bbbbbb c", area{width: 4, height: 10})
+
+	assert.Equal(t, []int{1}, result.tooWide)
+}
+
+func TestLayoutTextReportsOverflowOfUnpagedArea(t *testing.T) {
+	result := layoutText(aFixedFont(), text.DefaultCodepage(), "a\nb\nc\nd", area{width: 10, height: 5})
+
+	assert.Equal(t, 2, result.linesPerPage)
+	assert.Equal(t, 2, result.overflowStart)
+	assert.Equal(t, 1, result.pages)
+}
+
+func TestLayoutTextCountsPagesOfPagedArea(t *testing.T) {
+	result := layoutText(aFixedFont(), text.DefaultCodepage(), "a\nb\nc\nd\ne", area{width: 10, height: 4, paged: true})
+
+	assert.Equal(t, 2, result.linesPerPage)
+	assert.Equal(t, -1, result.overflowStart)
+	assert.Equal(t, 3, result.pages)
+}
@@ -0,0 +1,218 @@
+package textpreview
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/graphics"
+	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/font"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const (
+	maxImageWidth  = 512
+	maxImageHeight = 1024
+	pageGap        = 2
+	displayScale   = 2
+
+	colorBackground         = 0
+	colorText               = 1
+	colorOverflowBackground = 2
+	colorOverflowText       = 3
+	colorPageBreak          = 4
+)
+
+var palette = func() *bitmap.Palette {
+	var pal bitmap.Palette
+	pal[colorBackground] = bitmap.RGB{Red: 0x08, Green: 0x10, Blue: 0x08}
+	pal[colorText] = bitmap.RGB{Red: 0x40, Green: 0xE0, Blue: 0x40}
+	pal[colorOverflowBackground] = bitmap.RGB{Red: 0x60, Green: 0x10, Blue: 0x10}
+	pal[colorOverflowText] = bitmap.RGB{Red: 0xF0, Green: 0xA0, Blue: 0xA0}
+	pal[colorPageBreak] = bitmap.RGB{Red: 0x50, Green: 0x50, Blue: 0x50}
+	return &pal
+}()
+
+// Preview renders texts with the fonts of the game, within the space available for a kind of display.
+// Lines that are too wide, and lines that do not fit a display without pages, are highlighted.
+type Preview struct {
+	fontCache     *font.Cache
+	frameCache    *graphics.FrameCache
+	frameCacheKey graphics.FrameCacheKey
+	cps           text.Codepages
+	guiScale      float32
+
+	areas     map[Kind]area
+	fonts     map[Kind]resource.ID
+	kind      Kind
+	suggested Kind
+
+	renderedFrom string
+	imageWidth   int
+	imageHeight  int
+}
+
+// NewPreview returns a new instance.
+func NewPreview(fontCache *font.Cache, frameCache *graphics.FrameCache, cps text.Codepages, guiScale float32) *Preview {
+	return &Preview{
+		fontCache:     fontCache,
+		frameCache:    frameCache,
+		frameCacheKey: frameCache.AllocateKey(),
+		cps:           cps,
+		guiScale:      guiScale,
+
+		areas:     defaultAreas(),
+		fonts:     defaultFonts(),
+		suggested: -1,
+	}
+}
+
+// Render shows the given text as it is laid out in the game.
+// The suggested kind is selected whenever it differs from the previous call. Another kind can be
+// chosen in the preview, as can the font and the size of the area.
+func (preview *Preview) Render(value string, lang resource.Language, suggested Kind) {
+	if suggested != preview.suggested {
+		preview.suggested = suggested
+		preview.kind = suggested
+	}
+	if imgui.BeginCombo("Display", preview.kind.String()) {
+		for _, kind := range kinds {
+			if imgui.SelectableV(kind.String(), kind == preview.kind, 0, imgui.Vec2{}) {
+				preview.kind = kind
+			}
+		}
+		imgui.EndCombo()
+	}
+	available := preview.availableFonts(lang)
+	if len(available) == 0 {
+		imgui.Text("No fonts available.")
+		return
+	}
+	fontID := preview.fontFor(preview.kind, available)
+	if imgui.BeginCombo("Font", fontID.String()) {
+		for _, id := range available {
+			if imgui.SelectableV(id.String(), id == fontID, 0, imgui.Vec2{}) {
+				preview.fonts[preview.kind] = id
+				fontID = id
+			}
+		}
+		imgui.EndCombo()
+	}
+	a := preview.areas[preview.kind]
+	imgui.SliderInt("Width", &a.width, 16, maxImageWidth)
+	imgui.SliderInt("Height", &a.height, 1, 200)
+	preview.areas[preview.kind] = a
+
+	f, _ := preview.fontCache.Font(resource.KeyOf(fontID, lang, 0))
+	result := layoutText(f, preview.cps.ForLanguage(lang), value, a)
+	signature := fmt.Sprintf("%v:%v:%v:%v:%s", fontID, lang, preview.kind, a, value)
+	if signature != preview.renderedFrom {
+		preview.updateTexture(f, result, a)
+		preview.renderedFrom = signature
+	}
+	if imgui.BeginChildV("Rendered", imgui.Vec2{X: -1, Y: 160 * preview.guiScale}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		render.FrameImage("Image", preview.frameCache, preview.frameCacheKey,
+			imgui.Vec2{
+				X: float32(preview.imageWidth*displayScale) * preview.guiScale,
+				Y: float32(preview.imageHeight*displayScale) * preview.guiScale,
+			})
+	}
+	imgui.EndChild()
+	preview.renderFindings(result, a)
+}
+
+func (preview *Preview) availableFonts(lang resource.Language) []resource.ID {
+	var available []resource.ID
+	for id := ids.FontsStart; id < ids.FontsEnd; id = id.Plus(1) {
+		if _, err := preview.fontCache.Font(resource.KeyOf(id, lang, 0)); err == nil {
+			available = append(available, id)
+		}
+	}
+	return available
+}
+
+func (preview *Preview) fontFor(kind Kind, available []resource.ID) resource.ID {
+	selected, set := preview.fonts[kind]
+	if set {
+		for _, id := range available {
+			if id == selected {
+				return id
+			}
+		}
+	}
+	return available[0]
+}
+
+func (preview *Preview) renderFindings(result layout, a area) {
+	summary := fmt.Sprintf("%d lines", len(result.lines))
+	if a.paged {
+		summary += fmt.Sprintf(", %d pages", result.pages)
+	}
+	imgui.Text(summary)
+	imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.6, Z: 0.2, W: 1.0})
+	for _, index := range result.tooWide {
+		imgui.Text(fmt.Sprintf("Line %d is %d pixels too wide.", index+1, result.lines[index].Width-int(a.width)))
+	}
+	if result.overflowStart >= 0 {
+		imgui.Text(fmt.Sprintf("%d lines do not fit.", len(result.lines)-result.overflowStart))
+	}
+	imgui.PopStyleColor()
+}
+
+func (preview *Preview) updateTexture(f font.Font, result layout, a area) {
+	lineHeight := f.Height
+	if lineHeight < 1 {
+		lineHeight = 1
+	}
+	imageWidth := result.maxWidth()
+	if imageWidth < int(a.width) {
+		imageWidth = int(a.width)
+	}
+	if imageWidth > maxImageWidth {
+		imageWidth = maxImageWidth
+	}
+	lineTop := func(index int) int {
+		top := index * lineHeight
+		if a.paged {
+			top += (index / result.linesPerPage) * pageGap
+		}
+		return top
+	}
+	imageHeight := lineTop(len(result.lines))
+	if imageHeight < 1 {
+		imageHeight = 1
+	}
+	if imageHeight > maxImageHeight {
+		imageHeight = maxImageHeight
+	}
+
+	pixels := make([]byte, imageWidth*imageHeight)
+	for row := 0; row < imageHeight; row++ {
+		for column := int(a.width); column < imageWidth; column++ {
+			pixels[row*imageWidth+column] = colorOverflowBackground
+		}
+	}
+	for index, line := range result.lines {
+		top := lineTop(index)
+		textColor := byte(colorText)
+		if (result.overflowStart >= 0) && (index >= result.overflowStart) {
+			textColor = colorOverflowText
+			for offset := top * imageWidth; (offset < (top+lineHeight)*imageWidth) && (offset < len(pixels)); offset++ {
+				pixels[offset] = colorOverflowBackground
+			}
+		}
+		if a.paged && (index > 0) && ((index % result.linesPerPage) == 0) {
+			for offset := (top - pageGap) * imageWidth; (offset < (top-pageGap+1)*imageWidth) && (offset < len(pixels)); offset++ {
+				pixels[offset] = colorPageBreak
+			}
+		}
+		f.Draw(pixels, imageWidth, 0, top, line.Characters, textColor)
+	}
+	preview.frameCache.SetTexture(preview.frameCacheKey, uint16(imageWidth), uint16(imageHeight), pixels, palette)
+	preview.imageWidth = imageWidth
+	preview.imageHeight = imageHeight
+}
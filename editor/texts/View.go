@@ -6,7 +6,12 @@ import (
 	"github.com/inkyblackness/imgui-go"
 
 	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/editor/graphics"
+	"github.com/inkyblackness/hacked/editor/textpreview"
+	"github.com/inkyblackness/hacked/editor/waveform"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/font"
+	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable"
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -14,9 +19,22 @@ import (
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
+func previewKindOf(id resource.ID) textpreview.Kind {
+	switch id {
+	case ids.PaperTextsStart:
+		return textpreview.KindPaper
+	case ids.ScreenMessageTexts, ids.InfoNodeMessageTexts:
+		return textpreview.KindScreenMessage
+	default:
+		return textpreview.KindDatalet
+	}
+}
+
 // View provides edit controls for texts.
 type View struct {
-	textService undoable.AugmentedTextService
+	textService    undoable.AugmentedTextService
+	waveformEditor *waveform.Editor
+	preview        *textpreview.Preview
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
@@ -26,11 +44,13 @@ type View struct {
 }
 
 // NewTextsView returns a new instance.
-func NewTextsView(textService undoable.AugmentedTextService,
-	modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
+func NewTextsView(textService undoable.AugmentedTextService, fontCache *font.Cache, cps text.Codepages,
+	frameCache *graphics.FrameCache, modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
 	guiScale float32) *View {
 	view := &View{
-		textService: textService,
+		textService:    textService,
+		waveformEditor: waveform.NewEditor(frameCache, modalStateMachine, guiScale),
+		preview:        textpreview.NewPreview(fontCache, frameCache, cps, guiScale),
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
@@ -46,6 +66,12 @@ func (view *View) WindowOpen() *bool {
 	return &view.model.windowOpen
 }
 
+// ShowText opens the view with the text of given key selected.
+func (view *View) ShowText(key resource.Key) {
+	view.model.currentKey = key
+	view.model.restoreFocus = true
+}
+
 // Render renders the view.
 func (view *View) Render() {
 	if view.model.restoreFocus {
@@ -54,7 +80,7 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 500 * view.guiScale}, imgui.ConditionOnce)
 		if imgui.BeginV("Texts", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
 			view.renderContent()
 		}
@@ -102,12 +128,20 @@ func (view *View) renderContent() {
 		if imgui.Button("Import") {
 			view.requestImportAudio()
 		}
+		if !sound.Empty() {
+			view.waveformEditor.Render(sound, false, view.requestSetSound)
+		}
 	}
 	imgui.Separator()
 
+	currentText := view.currentText()
+	if imgui.TreeNodeV("Preview", imgui.TreeNodeFlagsFramed) {
+		view.preview.Render(currentText, view.model.currentKey.Lang, previewKindOf(view.model.currentKey.ID))
+		imgui.TreePop()
+	}
+
 	imgui.PopItemWidth()
 
-	currentText := view.currentText()
 	imgui.BeginChildV("Text", imgui.Vec2{X: -100 * view.guiScale, Y: 0}, true, 0)
 	imgui.PushTextWrapPos()
 	if len(currentText) == 0 {
@@ -153,6 +187,10 @@ func (view *View) setTextFromClipboard() {
 	if err != nil {
 		return
 	}
+	if unencodable := view.textService.Unencodable(view.model.currentKey, value); len(unencodable) > 0 {
+		external.UnencodableNotice(view.modalStateMachine, view.model.currentKey.Lang, unencodable)
+		return
+	}
 
 	view.textService.RequestSetText(view.model.currentKey, value, view.restoreFunc())
 }
@@ -183,9 +221,11 @@ func (view *View) requestExportAudio(sound audio.L8) {
 }
 
 func (view *View) requestImportAudio() {
-	external.ImportAudio(view.modalStateMachine, func(sound audio.L8) {
-		view.textService.RequestSetSound(view.model.currentKey, sound, view.restoreFunc())
-	})
+	external.ImportAudio(view.modalStateMachine, view.requestSetSound)
+}
+
+func (view *View) requestSetSound(sound audio.L8) {
+	view.textService.RequestSetSound(view.model.currentKey, sound, view.restoreFunc())
 }
 
 func (view *View) restoreFunc() func() {
@@ -0,0 +1,242 @@
+package textures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/texusage"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const maxListedReferences = 20
+
+// TableView lists the properties of all textures for bulk changes, together with their usage in the levels.
+type TableView struct {
+	mod          *world.Mod
+	textCache    *text.Cache
+	levels       []texusage.Level
+	texturesView *View
+
+	guiScale float32
+
+	model tableViewModel
+}
+
+// NewTableView returns a new instance. Changes of properties are done through the given textures view.
+func NewTableView(mod *world.Mod, textCache *text.Cache, levels []texusage.Level, texturesView *View,
+	guiScale float32) *TableView {
+	view := &TableView{
+		mod:          mod,
+		textCache:    textCache,
+		levels:       levels,
+		texturesView: texturesView,
+
+		guiScale: guiScale,
+
+		model: freshTableViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *TableView) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *TableView) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 800 * view.guiScale, Y: 600 * view.guiScale}, imgui.ConditionOnce)
+		if imgui.BeginV("Texture Table", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *TableView) renderContent() {
+	if !view.model.analyzed || !view.model.analyzedAt.Equal(view.mod.LastChangeTime()) {
+		view.analyze()
+	}
+	count := len(view.mod.TextureProperties())
+	readOnly := !view.mod.HasModifyableTextureProperties()
+
+	if imgui.BeginCombo("Language", view.model.currentLang.String()) {
+		for _, lang := range resource.Languages() {
+			if imgui.SelectableV(lang.String(), lang == view.model.currentLang, 0, imgui.Vec2{}) {
+				view.model.currentLang = lang
+				view.analyze()
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.Button("Select All") {
+		for index := 0; index < count; index++ {
+			view.model.selected[index] = view.listed(index)
+		}
+	}
+	imgui.SameLine()
+	if imgui.Button("Select None") {
+		view.model.selected = make(map[int]bool)
+	}
+	imgui.SameLine()
+	if imgui.Button("Refresh Usage") {
+		view.analyze()
+	}
+	imgui.SameLine()
+	imgui.Checkbox("Only with findings", &view.model.onlyFindings)
+
+	if imgui.BeginChildV("Table", imgui.Vec2{X: -1, Y: -260 * view.guiScale}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		view.renderTable(count)
+	}
+	imgui.EndChild()
+
+	if imgui.BeginChildV("Selection", imgui.Vec2{X: -350 * view.guiScale, Y: 0}, false, 0) {
+		selected := view.selectedIndices()
+		imgui.Text(fmt.Sprintf("%d textures selected", len(selected)))
+		if len(selected) > 0 {
+			imgui.PushItemWidth(-150 * view.guiScale)
+			view.texturesView.renderTextureProperties(readOnly, selected)
+			imgui.PopItemWidth()
+		}
+	}
+	imgui.EndChild()
+	imgui.SameLine()
+	if imgui.BeginChildV("Usage", imgui.Vec2{X: -1, Y: 0}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		view.renderUsage(view.model.detailIndex)
+	}
+	imgui.EndChild()
+}
+
+func (view *TableView) renderTable(count int) {
+	list := view.mod.TextureProperties()
+	imgui.Columns(8, "textures")
+	for _, title := range []string{"", "Name", "Distance", "Climbable", "Transparency", "Animation", "Levels", "Finding"} {
+		imgui.Text(title)
+		imgui.NextColumn()
+	}
+	imgui.Separator()
+	for index := 0; index < count; index++ {
+		if !view.listed(index) {
+			continue
+		}
+		properties := list[index]
+		selected := view.model.selected[index]
+		if imgui.Checkbox(fmt.Sprintf("%3d###selected%d", index, index), &selected) {
+			view.model.selected[index] = selected
+		}
+		imgui.NextColumn()
+		if imgui.SelectableV(view.textureText(ids.TextureNames, index)+fmt.Sprintf("###name%d", index),
+			view.model.detailIndex == index, 0, imgui.Vec2{}) {
+			view.model.detailIndex = index
+		}
+		imgui.NextColumn()
+		imgui.Text(fmt.Sprintf("%d", properties.DistanceModifier))
+		imgui.NextColumn()
+		imgui.Text(fmt.Sprintf("%d", properties.Climbable))
+		imgui.NextColumn()
+		imgui.Text(properties.TransparencyControl.String())
+		imgui.NextColumn()
+		imgui.Text(fmt.Sprintf("%d/%d", properties.AnimationGroup, properties.AnimationIndex))
+		imgui.NextColumn()
+		imgui.Text(view.levelList(index))
+		imgui.NextColumn()
+		if finding, hasFinding := view.model.findings[index]; hasFinding {
+			imgui.Text(finding.Kind.String())
+		}
+		imgui.NextColumn()
+	}
+	imgui.Columns(1, "")
+}
+
+func (view *TableView) renderUsage(index int) {
+	imgui.Text(fmt.Sprintf("Texture %d: %s", index, view.textureText(ids.TextureNames, index)))
+	imgui.Text("Use: " + view.textureText(ids.TextureUsages, index))
+	if finding, hasFinding := view.model.findings[index]; hasFinding {
+		imgui.Text("Finding: " + finding.Kind.String())
+	}
+	if index >= len(view.model.usages) {
+		return
+	}
+	imgui.Separator()
+	for _, lvlUsage := range view.model.usages[index].Levels {
+		title := fmt.Sprintf("Level %d: %d tiles, %d objects###level%d",
+			lvlUsage.Level, len(lvlUsage.Tiles), len(lvlUsage.Objects), lvlUsage.Level)
+		if imgui.TreeNodeV(title, imgui.TreeNodeFlagsFramed) {
+			imgui.Text(fmt.Sprintf("Atlas indices: %v", lvlUsage.AtlasIndices))
+			for refIndex, ref := range lvlUsage.Tiles {
+				if refIndex >= maxListedReferences {
+					imgui.Text(fmt.Sprintf("... and %d more tiles", len(lvlUsage.Tiles)-maxListedReferences))
+					break
+				}
+				imgui.Text(fmt.Sprintf("Tile %2d/%2d %s", ref.X, ref.Y, ref.Surface))
+			}
+			for refIndex, ref := range lvlUsage.Objects {
+				if refIndex >= maxListedReferences {
+					imgui.Text(fmt.Sprintf("... and %d more objects", len(lvlUsage.Objects)-maxListedReferences))
+					break
+				}
+				imgui.Text(fmt.Sprintf("Object %3d (%v) %s", ref.ID, ref.Triple, ref.Field))
+			}
+			imgui.TreePop()
+		}
+	}
+}
+
+func (view *TableView) analyze() {
+	view.model.analyzed = true
+	view.model.analyzedAt = view.mod.LastChangeTime()
+	view.model.usages = texusage.Analyze(view.levels, len(view.mod.TextureProperties()))
+	view.model.findings = make(map[int]texusage.Finding)
+	for _, finding := range texusage.CrossCheck(view.model.usages, func(index int) string {
+		return view.textureText(ids.TextureUsages, index)
+	}) {
+		view.model.findings[finding.Texture] = finding
+	}
+}
+
+func (view *TableView) listed(index int) bool {
+	if !view.model.onlyFindings {
+		return true
+	}
+	_, hasFinding := view.model.findings[index]
+	return hasFinding
+}
+
+func (view *TableView) selectedIndices() []int {
+	var indices []int
+	for index, selected := range view.model.selected {
+		if selected {
+			indices = append(indices, index)
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+func (view *TableView) levelList(index int) string {
+	if index >= len(view.model.usages) {
+		return ""
+	}
+	var levels []string
+	for _, lvlUsage := range view.model.usages[index].Levels {
+		levels = append(levels, fmt.Sprintf("%d", lvlUsage.Level))
+	}
+	return strings.Join(levels, ", ")
+}
+
+func (view *TableView) textureText(id resource.ID, index int) string {
+	value, _ := view.textCache.Text(resource.KeyOf(id, view.model.currentLang, index))
+	return value
+}
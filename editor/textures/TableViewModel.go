@@ -0,0 +1,30 @@
+package textures
+
+import (
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/edit/texusage"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+type tableViewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	currentLang  resource.Language
+	selected     map[int]bool
+	detailIndex  int
+	onlyFindings bool
+
+	analyzed   bool
+	analyzedAt time.Time
+	usages     []texusage.Usage
+	findings   map[int]texusage.Finding
+}
+
+func freshTableViewModel() tableViewModel {
+	return tableViewModel{
+		currentLang: resource.LangDefault,
+		selected:    make(map[int]bool),
+	}
+}
@@ -24,7 +24,7 @@ import (
 type View struct {
 	mod          *world.Mod
 	textCache    *text.Cache
-	cp           text.Codepage
+	cps          text.Codepages
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
 
@@ -37,14 +37,14 @@ type View struct {
 }
 
 // NewTexturesView returns a new instance.
-func NewTexturesView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
+func NewTexturesView(mod *world.Mod, textCache *text.Cache, cps text.Codepages,
 	imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
 	modalStateMachine gui.ModalStateMachine,
 	clipboard external.Clipboard, guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		textCache:    textCache,
-		cp:           cp,
+		cps:          cps,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
 
@@ -63,6 +63,13 @@ func (view *View) WindowOpen() *bool {
 	return &view.model.windowOpen
 }
 
+// ShowTexture opens the view with the texture of given index selected, in the given language.
+func (view *View) ShowTexture(index int, lang resource.Language) {
+	view.model.currentIndex = index
+	view.model.currentLang = lang
+	view.model.restoreFocus = true
+}
+
 // Render renders the view.
 func (view *View) Render() {
 	if view.model.restoreFocus {
@@ -117,7 +124,7 @@ func (view *View) renderContent() {
 		view.renderText(readOnly, "Use", use, view.requestSetTextureUsage)
 
 		imgui.Separator()
-		view.renderTextureProperties(readOnly)
+		view.renderTextureProperties(readOnly, []int{view.model.currentIndex})
 
 		imgui.PopItemWidth()
 	}
@@ -152,35 +159,42 @@ func (view *View) clipboardPopup(readOnly bool, label string, value string, chan
 	}
 }
 
-func (view *View) renderTextureProperties(readOnly bool) {
+func (view *View) renderTextureProperties(readOnly bool, indices []int) {
 	list := view.mod.TextureProperties()
+	multiple := len(indices) > 1
 	distanceUnifier := values.NewUnifier()
 	climbableUnifier := values.NewUnifier()
 	transparencyControlUnifier := values.NewUnifier()
 	animationGroupUnifier := values.NewUnifier()
 	animationIndexUnifier := values.NewUnifier()
-	if view.model.currentIndex < len(list) {
-		properties := list[view.model.currentIndex]
+	for _, index := range indices {
+		if index >= len(list) {
+			continue
+		}
+		properties := list[index]
 		distanceUnifier.Add(int(properties.DistanceModifier))
 		climbableUnifier.Add(properties.Climbable != 0)
 		transparencyControlUnifier.Add(int(properties.TransparencyControl))
 		animationGroupUnifier.Add(int(properties.AnimationGroup))
 		animationIndexUnifier.Add(int(properties.AnimationIndex))
 	}
+	requestChange := func(property string, modifier func(*texture.Properties)) {
+		view.requestChangePropertiesOf(indices, property, modifier)
+	}
 
-	values.RenderUnifiedSliderInt(readOnly, false, "Distance Modifier", distanceUnifier,
+	values.RenderUnifiedSliderInt(readOnly, multiple, "Distance Modifier", distanceUnifier,
 		func(u values.Unifier) int { return u.Unified().(int) },
 		func(value int) string { return "%d" },
 		math.MinInt16, math.MaxInt16,
 		func(newValue int) {
-			view.requestChangeProperties(func(prop *texture.Properties) {
+			requestChange("Distance Modifier", func(prop *texture.Properties) {
 				prop.DistanceModifier = int16(newValue)
 			})
 		})
 
-	values.RenderUnifiedCheckboxCombo(readOnly, false, "Climbable", climbableUnifier,
+	values.RenderUnifiedCheckboxCombo(readOnly, multiple, "Climbable", climbableUnifier,
 		func(newValue bool) {
-			view.requestChangeProperties(func(prop *texture.Properties) {
+			requestChange("Climbable", func(prop *texture.Properties) {
 				prop.Climbable = 0
 				if newValue {
 					prop.Climbable = 1
@@ -188,31 +202,31 @@ func (view *View) renderTextureProperties(readOnly bool) {
 			})
 		})
 
-	values.RenderUnifiedCombo(readOnly, false, "Transparency Control", transparencyControlUnifier,
+	values.RenderUnifiedCombo(readOnly, multiple, "Transparency Control", transparencyControlUnifier,
 		func(u values.Unifier) int { return u.Unified().(int) },
 		func(index int) string { return texture.TransparencyControl(index).String() },
 		len(texture.TransparencyControls()),
 		func(newValue int) {
-			view.requestChangeProperties(func(prop *texture.Properties) {
+			requestChange("Transparency Control", func(prop *texture.Properties) {
 				prop.TransparencyControl = texture.TransparencyControl(newValue)
 			})
 		})
 
-	values.RenderUnifiedSliderInt(readOnly, false, "Animation Group", animationGroupUnifier,
+	values.RenderUnifiedSliderInt(readOnly, multiple, "Animation Group", animationGroupUnifier,
 		func(u values.Unifier) int { return u.Unified().(int) },
 		func(value int) string { return "%d" },
 		0, 3,
 		func(newValue int) {
-			view.requestChangeProperties(func(prop *texture.Properties) {
+			requestChange("Animation Group", func(prop *texture.Properties) {
 				prop.AnimationGroup = byte(newValue)
 			})
 		})
-	values.RenderUnifiedSliderInt(readOnly, false, "Animation Index", animationIndexUnifier,
+	values.RenderUnifiedSliderInt(readOnly, multiple, "Animation Index", animationIndexUnifier,
 		func(u values.Unifier) int { return u.Unified().(int) },
 		func(value int) string { return "%d" },
 		0, 3,
 		func(newValue int) {
-			view.requestChangeProperties(func(prop *texture.Properties) {
+			requestChange("Animation Index", func(prop *texture.Properties) {
 				prop.AnimationIndex = byte(newValue)
 			})
 		})
@@ -283,17 +297,53 @@ func (view *View) hasModCurrentBitmap() bool {
 	return len(view.mod.ModifiedBlock(key.Lang, key.ID, key.Index)) > 0
 }
 
-func (view *View) requestChangeProperties(modifier func(*texture.Properties)) {
+// RequestSetProperties queues the change of all properties of the given texture.
+func (view *View) RequestSetProperties(index int, properties texture.Properties) {
+	list := view.mod.TextureProperties()
+	if index < len(list) {
+		view.commander.Queue(cmd.Named{
+			Command: setTexturePropertiesCommand{
+				model:         &view.model,
+				textureIndex:  index,
+				oldProperties: list[index],
+				newProperties: properties,
+			},
+			Title: fmt.Sprintf("Set properties of texture %d", index),
+		})
+	}
+}
+
+// requestChangePropertiesOf modifies the properties of all given textures as one undoable step.
+// Consecutive changes of the same property of the same textures are merged.
+func (view *View) requestChangePropertiesOf(indices []int, property string, modifier func(*texture.Properties)) {
 	list := view.mod.TextureProperties()
-	if view.model.currentIndex < len(list) {
+	var commands cmd.List
+	for _, index := range indices {
+		if index >= len(list) {
+			continue
+		}
 		command := setTexturePropertiesCommand{
 			model:         &view.model,
-			textureIndex:  view.model.currentIndex,
-			oldProperties: list[view.model.currentIndex],
-			newProperties: list[view.model.currentIndex],
+			textureIndex:  index,
+			oldProperties: list[index],
+			newProperties: list[index],
 		}
 		modifier(&command.newProperties)
-		view.commander.Queue(command)
+		commands = append(commands, command)
+	}
+	group := fmt.Sprintf("textures/%v/%s", indices, property)
+	if len(commands) == 1 {
+		view.commander.Queue(cmd.Named{
+			Command: commands[0],
+			Title:   fmt.Sprintf("Change properties of texture %d", commands[0].(setTexturePropertiesCommand).textureIndex),
+			Group:   group,
+		})
+	} else if len(commands) > 1 {
+		view.commander.Queue(cmd.Named{
+			Command: commands,
+			Title:   fmt.Sprintf("Change properties of %d textures", len(commands)),
+			Group:   group,
+		})
 	}
 }
 
@@ -308,15 +358,20 @@ func (view *View) requestSetTextureUsage(value string) {
 func (view *View) requestSetTextureText(id resource.ID, newValue string) {
 	key := resource.KeyOf(id, view.model.currentLang, view.model.currentIndex)
 	oldValue, _ := view.textCache.Text(key)
+	cp := view.cps.ForLanguage(key.Lang)
+	if unencodable := text.Unencodable(cp, newValue); len(unencodable) > 0 {
+		external.UnencodableNotice(view.modalStateMachine, key.Lang, unencodable)
+		return
+	}
 
 	if oldValue != newValue {
 		command := setTextureTextCommand{
 			model:   &view.model,
 			key:     key,
-			oldData: view.cp.Encode(oldValue),
-			newData: view.cp.Encode(text.Blocked(newValue)[0]),
+			oldData: cp.Encode(oldValue),
+			newData: cp.Encode(text.Blocked(newValue)[0]),
 		}
-		view.commander.Queue(command)
+		view.commander.Queue(cmd.Named{Command: command, Title: fmt.Sprintf("Set text of texture %d", key.Index)})
 	}
 }
 
@@ -398,5 +453,5 @@ func (view *View) requestSetBitmapData(id resource.ID, index int, newData []byte
 		oldData:      view.mod.ModifiedBlock(resource.LangAny, resourceKey.ID, resourceKey.Index),
 		newData:      newData,
 	}
-	view.commander.Queue(command)
+	view.commander.Queue(cmd.Named{Command: command, Title: fmt.Sprintf("Set bitmap of texture %d", index)})
 }
@@ -0,0 +1,239 @@
+package translations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/translation"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+var translationTypes = []external.TypeInfo{{
+	Title:      "Translation files (*.po, *.xlf, *.xliff)",
+	Extensions: []string{"po", "xlf", "xliff"},
+}}
+
+// View provides bulk export and import of all translatable strings.
+type View struct {
+	translationService undoable.TranslationService
+
+	modalStateMachine gui.ModalStateMachine
+	guiScale          float32
+
+	model viewModel
+}
+
+// NewTranslationsView returns a new instance.
+func NewTranslationsView(translationService undoable.TranslationService,
+	modalStateMachine gui.ModalStateMachine, guiScale float32) *View {
+	view := &View{
+		translationService: translationService,
+
+		modalStateMachine: modalStateMachine,
+		guiScale:          guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 200 * view.guiScale}, imgui.ConditionOnce)
+		if imgui.BeginV("Translations", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *View) renderContent() {
+	imgui.PushItemWidth(-150 * view.guiScale)
+	view.renderLanguageCombo("Source Language", &view.model.sourceLanguage)
+	view.renderLanguageCombo("Target Language", &view.model.targetLanguage)
+	imgui.PopItemWidth()
+
+	imgui.Separator()
+	if view.model.sourceLanguage == view.model.targetLanguage {
+		imgui.Text("Source and target language must be different.")
+		return
+	}
+	if imgui.Button("Export PO") {
+		view.requestExport("po")
+	}
+	imgui.SameLine()
+	if imgui.Button("Export XLIFF") {
+		view.requestExport("xlf")
+	}
+	imgui.SameLine()
+	if imgui.Button("Import") {
+		view.requestImport()
+	}
+}
+
+func (view *View) renderLanguageCombo(label string, lang *resource.Language) {
+	if imgui.BeginCombo(label, lang.String()) {
+		for _, other := range resource.Languages() {
+			if imgui.SelectableV(other.String(), other == *lang, 0, imgui.Vec2{}) {
+				*lang = other
+			}
+		}
+		imgui.EndCombo()
+	}
+}
+
+func (view *View) catalog() translation.Catalog {
+	source := view.model.sourceLanguage
+	target := view.model.targetLanguage
+	catalog := translation.Catalog{
+		SourceLanguage: source.String(),
+		TargetLanguage: target.String(),
+	}
+	for _, entry := range view.translationService.Entries(source, target) {
+		catalog.Units = append(catalog.Units, translation.Unit{
+			ID:      entry.Key.String(),
+			Context: entry.Context,
+			Source:  entry.Source,
+			Target:  entry.Target,
+		})
+	}
+	return catalog
+}
+
+func (view *View) requestExport(extension string) {
+	catalog := view.catalog()
+	filename := fmt.Sprintf("translation_%s_%s.%s", catalog.SourceLanguage, catalog.TargetLanguage, extension)
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		writer, err := os.Create(filepath.Join(dirname, filename))
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not create file.\n"+info, exportTo, true)
+			return
+		}
+		defer func() { _ = writer.Close() }()
+		if extension == "po" {
+			err = translation.WritePO(writer, catalog)
+		} else {
+			err = translation.WriteXLIFF(writer, catalog, "hacked")
+		}
+		if err != nil {
+			external.Export(view.modalStateMachine, info, exportTo, true)
+		}
+	}
+
+	external.Export(view.modalStateMachine, info, exportTo, false)
+}
+
+func (view *View) requestImport() {
+	info := fmt.Sprintf("File must be a PO or XLIFF file.\nThe translations are applied to the %v language.",
+		view.model.targetLanguage)
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		reader, err := os.Open(filename)
+		if err != nil {
+			external.Import(view.modalStateMachine, "Could not open file.\n"+info, translationTypes, fileHandler, true)
+			return
+		}
+		defer func() { _ = reader.Close() }()
+		var catalog translation.Catalog
+		if strings.EqualFold(filepath.Ext(filename), ".po") {
+			catalog, err = translation.ReadPO(reader)
+		} else {
+			catalog, err = translation.ReadXLIFF(reader)
+		}
+		if err != nil {
+			external.Import(view.modalStateMachine, "File could not be read.\n"+info, translationTypes, fileHandler, true)
+			return
+		}
+		view.applyCatalog(catalog)
+	}
+
+	external.Import(view.modalStateMachine, info, translationTypes, fileHandler, false)
+}
+
+func (view *View) applyCatalog(catalog translation.Catalog) {
+	var entries []edit.TranslationEntry
+	var invalidKeys []string
+	for _, unit := range catalog.Units {
+		key, err := edit.ParseTranslationKey(unit.ID)
+		if err != nil {
+			invalidKeys = append(invalidKeys, unit.ID)
+			continue
+		}
+		entries = append(entries, edit.TranslationEntry{Key: key, Context: unit.Context, Source: unit.Source, Target: unit.Target})
+	}
+	source := view.model.sourceLanguage
+	target := view.model.targetLanguage
+	changes, report := view.translationService.Review(source, target, entries)
+	if len(changes) > 0 {
+		view.translationService.RequestApply(target, changes, view.restoreFunc())
+	}
+
+	var lines []string
+	if (len(catalog.TargetLanguage) > 0) && !strings.EqualFold(catalog.TargetLanguage, target.String()) {
+		lines = append(lines, fmt.Sprintf("Note: file is for language %s, applied to %v.", catalog.TargetLanguage, target))
+	}
+	lines = append(lines, fmt.Sprintf("%d strings changed.", len(changes)))
+	lines = append(lines, keyListReport("untranslated", keyStrings(report.Untranslated))...)
+	lines = append(lines, keyListReport("stale (source text changed since export)", keyStrings(report.Stale))...)
+	lines = append(lines, keyListReport("unknown", append(invalidKeys, keyStrings(report.Unknown)...))...)
+	lines = append(lines, keyListReport("rejected (characters not in codepage)", keyStrings(report.Unencodable))...)
+	external.Notice(view.modalStateMachine, "Translation Import", strings.Join(lines, "\n"))
+}
+
+func keyStrings(keys []edit.TranslationKey) []string {
+	result := make([]string, len(keys))
+	for index, key := range keys {
+		result[index] = key.String()
+	}
+	return result
+}
+
+func keyListReport(title string, keys []string) []string {
+	const maxKeys = 10
+	if len(keys) == 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("%d strings %s:", len(keys), title)}
+	for index, key := range keys {
+		if index >= maxKeys {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(keys)-maxKeys))
+			break
+		}
+		lines = append(lines, "  "+key)
+	}
+	return lines
+}
+
+func (view *View) restoreFunc() func() {
+	oldSource := view.model.sourceLanguage
+	oldTarget := view.model.targetLanguage
+
+	return func() {
+		view.model.restoreFocus = true
+		view.model.sourceLanguage = oldSource
+		view.model.targetLanguage = oldTarget
+	}
+}
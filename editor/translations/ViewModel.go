@@ -0,0 +1,18 @@
+package translations
+
+import "github.com/inkyblackness/hacked/ss1/resource"
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	sourceLanguage resource.Language
+	targetLanguage resource.Language
+}
+
+func freshViewModel() viewModel {
+	return viewModel{
+		sourceLanguage: resource.LangDefault,
+		targetLanguage: resource.LangGerman,
+	}
+}
@@ -0,0 +1,84 @@
+package undo
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+)
+
+// Stack provides the steps of the undo history.
+type Stack interface {
+	// History returns all steps, oldest first, and the number of steps that are currently done.
+	History() ([]cmd.Entry, int)
+	// JumpTo undoes or redoes steps until the given number of steps is done.
+	JumpTo(done int)
+}
+
+// View shows the undo history and allows to jump to any step of it.
+type View struct {
+	stack Stack
+
+	guiScale float32
+
+	model viewModel
+}
+
+// NewHistoryView returns a new instance.
+func NewHistoryView(stack Stack, guiScale float32) *View {
+	view := &View{
+		stack: stack,
+
+		guiScale: guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 300 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionOnce)
+		if imgui.BeginV("Undo History", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *View) renderContent() {
+	entries, done := view.stack.History()
+	imgui.Text(fmt.Sprintf("%d steps, %d undone", len(entries), len(entries)-done))
+	imgui.Separator()
+	if imgui.BeginChildV("Steps", imgui.Vec2{X: -1, Y: -1}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		if imgui.SelectableV("(initial state)", done == 0, 0, imgui.Vec2{}) {
+			view.stack.JumpTo(0)
+		}
+		for index, entry := range entries {
+			undone := index >= done
+			if undone {
+				imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 0.6, Y: 0.6, Z: 0.6, W: 1.0})
+			}
+			label := fmt.Sprintf("%s###step%d", entry.Description, index)
+			if imgui.SelectableV(label, done == index+1, 0, imgui.Vec2{}) {
+				view.stack.JumpTo(index + 1)
+			}
+			if undone {
+				imgui.PopStyleColor()
+			}
+		}
+	}
+	imgui.EndChild()
+}
@@ -0,0 +1,10 @@
+package undo
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+}
+
+func freshViewModel() viewModel {
+	return viewModel{}
+}
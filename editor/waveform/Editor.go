@@ -0,0 +1,248 @@
+package waveform
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/editor/graphics"
+	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+const (
+	imageWidth  = 512
+	imageHeight = 96
+
+	colorBackground         = 0
+	colorWave               = 1
+	colorSelectedBackground = 2
+	colorSelectedWave       = 3
+	colorCenterLine         = 4
+
+	minSilenceDuration = 0.1
+)
+
+var palette = func() *bitmap.Palette {
+	var pal bitmap.Palette
+	pal[colorBackground] = bitmap.RGB{Red: 0x10, Green: 0x10, Blue: 0x10}
+	pal[colorWave] = bitmap.RGB{Red: 0x40, Green: 0xC0, Blue: 0x40}
+	pal[colorSelectedBackground] = bitmap.RGB{Red: 0x20, Green: 0x30, Blue: 0x60}
+	pal[colorSelectedWave] = bitmap.RGB{Red: 0xA0, Green: 0xF0, Blue: 0xA0}
+	pal[colorCenterLine] = bitmap.RGB{Red: 0x50, Green: 0x50, Blue: 0x50}
+	return &pal
+}()
+
+// Editor renders the waveform of a sound and provides controls to modify it within a selected range.
+// Modifications are not applied directly, they are reported as new sound to the caller, which is
+// expected to request the change through an undoable service.
+type Editor struct {
+	frameCache        *graphics.FrameCache
+	frameCacheKey     graphics.FrameCacheKey
+	modalStateMachine gui.ModalStateMachine
+	guiScale          float32
+
+	samples      []byte
+	selection    audio.SampleRange
+	rendered     bool
+	renderedFrom audio.SampleRange
+
+	gainDB           float32
+	silenceThreshold int32
+	silentRanges     []audio.SampleRange
+	silenceDetected  bool
+}
+
+// NewEditor returns a new instance.
+func NewEditor(frameCache *graphics.FrameCache, modalStateMachine gui.ModalStateMachine, guiScale float32) *Editor {
+	return &Editor{
+		frameCache:        frameCache,
+		frameCacheKey:     frameCache.AllocateKey(),
+		modalStateMachine: modalStateMachine,
+		guiScale:          guiScale,
+
+		silenceThreshold: 2,
+	}
+}
+
+// Render shows the waveform and the controls for given sound.
+// Unless read-only, the changeCallback is called with the result of any modifying operation.
+func (editor *Editor) Render(sound audio.L8, readOnly bool, changeCallback func(audio.L8)) {
+	if !sameSamples(editor.samples, sound.Samples) {
+		editor.samples = sound.Samples
+		editor.selection = sound.FullRange()
+		editor.rendered = false
+		editor.silenceDetected = false
+	}
+	if !editor.rendered || (editor.renderedFrom != editor.selection) {
+		editor.updateTexture()
+	}
+	render.FrameImage("Waveform", editor.frameCache, editor.frameCacheKey,
+		imgui.Vec2{X: 384 * editor.guiScale, Y: 72 * editor.guiScale})
+
+	editor.renderSelection(sound)
+	if readOnly {
+		return
+	}
+	editor.renderOperations(sound, changeCallback)
+}
+
+func (editor *Editor) renderSelection(sound audio.L8) {
+	duration := sound.Duration()
+	start := float32(editor.selection.Start) / sound.SampleRate
+	end := float32(editor.selection.End) / sound.SampleRate
+	if imgui.SliderFloatV("Selection Start", &start, 0, duration, "%.3f s", 1.0) {
+		editor.selection.Start = sound.IndexAt(start)
+		if editor.selection.End < editor.selection.Start {
+			editor.selection.End = editor.selection.Start
+		}
+	}
+	if imgui.SliderFloatV("Selection End", &end, 0, duration, "%.3f s", 1.0) {
+		editor.selection.End = sound.IndexAt(end)
+		if editor.selection.Start > editor.selection.End {
+			editor.selection.Start = editor.selection.End
+		}
+	}
+	if imgui.Button("Select All") {
+		editor.selection = sound.FullRange()
+	}
+	imgui.SameLine()
+	imgui.Text(fmt.Sprintf("%.3f s selected", float32(editor.selection.Len())/sound.SampleRate))
+
+	if imgui.SliderIntV("Silence Threshold", &editor.silenceThreshold, 0, 32, "%d") {
+		editor.silenceDetected = false
+	}
+	if !editor.silenceDetected {
+		editor.silentRanges = sound.SilentRanges(byte(editor.silenceThreshold), int(minSilenceDuration*sound.SampleRate))
+		editor.silenceDetected = true
+	}
+	if imgui.BeginCombo("Silent Ranges", fmt.Sprintf("%d found", len(editor.silentRanges))) {
+		for index, r := range editor.silentRanges {
+			label := fmt.Sprintf("%.3f s - %.3f s##%d", float32(r.Start)/sound.SampleRate, float32(r.End)/sound.SampleRate, index)
+			if imgui.SelectableV(label, editor.selection == r, 0, imgui.Vec2{}) {
+				editor.selection = r
+			}
+		}
+		imgui.EndCombo()
+	}
+}
+
+func (editor *Editor) renderOperations(sound audio.L8, changeCallback func(audio.L8)) {
+	hasSelection := editor.selection.Len() > 0
+	if hasSelection {
+		if imgui.Button("Crop") {
+			changeCallback(sound.Cropped(editor.selection))
+		}
+		imgui.SameLine()
+		if imgui.Button("Delete") {
+			changeCallback(sound.Trimmed(editor.selection))
+		}
+		imgui.SameLine()
+		if imgui.Button("Fade In") {
+			changeCallback(sound.FadedIn(editor.selection))
+		}
+		imgui.SameLine()
+		if imgui.Button("Fade Out") {
+			changeCallback(sound.FadedOut(editor.selection))
+		}
+		imgui.SameLine()
+		if imgui.Button("Normalize") {
+			changeCallback(sound.Normalized(editor.selection))
+		}
+
+		imgui.SliderFloatV("Gain", &editor.gainDB, -24, 24, "%.1f dB", 1.0)
+		imgui.SameLine()
+		if imgui.Button("Apply") {
+			factor := float32(math.Pow(10, float64(editor.gainDB)/20))
+			result, clip := sound.Amplified(editor.selection, factor)
+			changeCallback(result)
+			if clip.Occurred() {
+				external.Notice(editor.modalStateMachine, "Audio Clipped",
+					fmt.Sprintf("%d samples exceeded the range and were limited.", clip.Samples))
+			}
+		}
+	}
+	if imgui.Button("Trim Silence") {
+		changeCallback(editor.withoutSurroundingSilence(sound))
+	}
+	imgui.SameLine()
+	if imgui.Button("Append...") {
+		external.ImportAudio(editor.modalStateMachine, func(other audio.L8) {
+			changeCallback(sound.Concatenated(other))
+		})
+	}
+}
+
+// withoutSurroundingSilence removes silence at the start and the end of the sound.
+// At least one sample is kept so that the sound remains valid.
+func (editor *Editor) withoutSurroundingSilence(sound audio.L8) audio.L8 {
+	keep := sound.FullRange()
+	for _, r := range sound.SilentRanges(byte(editor.silenceThreshold), 1) {
+		if r.Start == 0 {
+			keep.Start = r.End
+		}
+		if r.End == len(sound.Samples) {
+			keep.End = r.Start
+		}
+	}
+	if keep.Len() == 0 {
+		return sound.Cropped(audio.SampleRange{Start: 0, End: 1})
+	}
+	return sound.Cropped(keep)
+}
+
+func (editor *Editor) updateTexture() {
+	pixels := make([]byte, imageWidth*imageHeight)
+	sampleCount := len(editor.samples)
+	rowOf := func(value byte) int {
+		return (0xFF - int(value)) * (imageHeight - 1) / 0xFF
+	}
+	centerRow := rowOf(0x80)
+	for column := 0; column < imageWidth; column++ {
+		first := column * sampleCount / imageWidth
+		last := (column + 1) * sampleCount / imageWidth
+		if last <= first {
+			last = first + 1
+		}
+		background, wave := byte(colorBackground), byte(colorWave)
+		if (first < editor.selection.End) && (last > editor.selection.Start) {
+			background, wave = colorSelectedBackground, colorSelectedWave
+		}
+		for row := 0; row < imageHeight; row++ {
+			pixels[row*imageWidth+column] = background
+		}
+		pixels[centerRow*imageWidth+column] = colorCenterLine
+		if first >= sampleCount {
+			continue
+		}
+		if last > sampleCount {
+			last = sampleCount
+		}
+		low, high := byte(0xFF), byte(0x00)
+		for _, sample := range editor.samples[first:last] {
+			if sample < low {
+				low = sample
+			}
+			if sample > high {
+				high = sample
+			}
+		}
+		for row := rowOf(high); row <= rowOf(low); row++ {
+			pixels[row*imageWidth+column] = wave
+		}
+	}
+	editor.frameCache.SetTexture(editor.frameCacheKey, imageWidth, imageHeight, pixels, palette)
+	editor.rendered = true
+	editor.renderedFrom = editor.selection
+}
+
+func sameSamples(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return (len(a) == 0) || (&a[0] == &b[0])
+}
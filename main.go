@@ -12,6 +12,8 @@ import (
 
 	"github.com/inkyblackness/hacked/crash"
 	"github.com/inkyblackness/hacked/editor"
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/native"
 )
 
@@ -21,12 +23,32 @@ func main() {
 	scale := flag.Float64("scale", 1.0, "factor for scaling the UI (0.5 .. 10.0). 1080p displays should use default. 4K most likely 2.0.")
 	fontFile := flag.String("fontfile", "", "Path to font file (.TTF) to use instead of the default font. Useful for HiDPI displays.")
 	fontSize := flag.Float64("fontsize", 0.0, "Size of the font to use. If not specified, a default height will be used.")
+	languagesFile := flag.String("languages", "", "Path to a JSON file describing additional languages and their resource files.")
+	projectFile := flag.String("project", "", "Path to a project file ("+projectfile.Extension+") to open on start.")
 	cpuprofile := flag.String("cpuprofile", "", "write cpu profile to file")
+	diffMod := flag.String("diffmod", "", "Path to a mod directory. Together with -diffworld, reports the property differences and exits.")
+	diffWorld := flag.String("diffworld", "", "Path to the world directory to compare the mod directory of -diffmod with.")
 	flag.Parse()
+	if (len(*diffMod) > 0) || (len(*diffWorld) > 0) {
+		err := writeDiffReport(os.Stdout, *diffMod, *diffWorld)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to compare properties: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(*languagesFile) > 0 {
+		err := loadLanguages(*languagesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load languages: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	var app editor.Application
 	app.FontFile = *fontFile
 	app.FontSize = float32(*fontSize)
 	app.GuiScale = float32(*scale)
+	app.ProjectFile = *projectFile
 	if len(version) > 0 {
 		app.Version = version
 	} else {
@@ -49,6 +71,15 @@ func main() {
 	}
 }
 
+func loadLanguages(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	return ids.LoadLanguages(file)
+}
+
 func initProfiling(filename string) (func(), error) {
 	if filename != "" {
 		f, err := os.Create(filename)
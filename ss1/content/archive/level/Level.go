@@ -143,6 +143,12 @@ func (lvl *Level) Tile(x, y int) *TileMapEntry {
 	return lvl.tileMap.Tile(x, y)
 }
 
+// RecalculateWallHeights updates the wall heights from the current tile map.
+// This is necessary after tiles were modified directly, for the heights to reflect the changes.
+func (lvl *Level) RecalculateWallHeights() {
+	lvl.wallHeightsMap.CalculateFrom(lvl.tileMap)
+}
+
 // MapGridInfo returns the information necessary to draw a 2D map.
 func (lvl *Level) MapGridInfo(x, y int) (TileType, TileSlopeControl, WallHeights) {
 	tile := lvl.tileMap.Tile(x, y)
@@ -435,7 +441,7 @@ func (lvl *Level) reloadTileMap() {
 	if err != nil {
 		lvl.clearTileMap()
 	}
-	lvl.wallHeightsMap.CalculateFrom(lvl.tileMap)
+	lvl.RecalculateWallHeights()
 }
 
 func (lvl *Level) reloadObjectMasterTable() {
@@ -0,0 +1,185 @@
+package audio
+
+import "math"
+
+// SampleRange describes a section of a sound, from the first sample up to, excluding, the end sample.
+type SampleRange struct {
+	Start int
+	End   int
+}
+
+// Len returns the number of samples in the range.
+func (r SampleRange) Len() int {
+	if r.End < r.Start {
+		return 0
+	}
+	return r.End - r.Start
+}
+
+// FullRange returns the range covering all samples of the sound.
+func (sound L8) FullRange() SampleRange {
+	return SampleRange{Start: 0, End: len(sound.Samples)}
+}
+
+// IndexAt returns the sample index for given time in seconds, limited to the length of the sound.
+func (sound L8) IndexAt(seconds float32) int {
+	index := int(math.Round(float64(seconds * sound.SampleRate)))
+	if index < 0 {
+		return 0
+	}
+	if index > len(sound.Samples) {
+		return len(sound.Samples)
+	}
+	return index
+}
+
+func (sound L8) limited(r SampleRange) SampleRange {
+	limit := func(value int) int {
+		if value < 0 {
+			return 0
+		}
+		if value > len(sound.Samples) {
+			return len(sound.Samples)
+		}
+		return value
+	}
+	r.Start = limit(r.Start)
+	r.End = limit(r.End)
+	if r.End < r.Start {
+		r.End = r.Start
+	}
+	return r
+}
+
+// ToF32 returns the sound with floating point samples.
+func (sound L8) ToF32() F32 {
+	result := F32{SampleRate: sound.SampleRate, Samples: make([]float32, len(sound.Samples))}
+	for index, sample := range sound.Samples {
+		result.Samples[index] = float32(int(sample)-0x80) / 128.0
+	}
+	return result
+}
+
+// Cropped returns a sound that only contains the samples of the given range.
+func (sound L8) Cropped(r SampleRange) L8 {
+	r = sound.limited(r)
+	result := L8{SampleRate: sound.SampleRate, Samples: make([]byte, r.Len())}
+	copy(result.Samples, sound.Samples[r.Start:r.End])
+	return result
+}
+
+// Trimmed returns a sound without the samples of the given range.
+func (sound L8) Trimmed(r SampleRange) L8 {
+	r = sound.limited(r)
+	result := L8{SampleRate: sound.SampleRate, Samples: make([]byte, 0, len(sound.Samples)-r.Len())}
+	result.Samples = append(result.Samples, sound.Samples[:r.Start]...)
+	result.Samples = append(result.Samples, sound.Samples[r.End:]...)
+	return result
+}
+
+// FadedIn returns a sound where the samples of the given range rise linearly from silence to full volume.
+func (sound L8) FadedIn(r SampleRange) L8 {
+	return sound.faded(r, func(position float32) float32 { return position })
+}
+
+// FadedOut returns a sound where the samples of the given range fall linearly from full volume to silence.
+func (sound L8) FadedOut(r SampleRange) L8 {
+	return sound.faded(r, func(position float32) float32 { return 1.0 - position })
+}
+
+func (sound L8) faded(r SampleRange, factorAt func(position float32) float32) L8 {
+	r = sound.limited(r)
+	length := r.Len()
+	result, _ := sound.processed(r, func(index int, sample float32) float32 {
+		position := float32(1.0)
+		if length > 1 {
+			position = float32(index-r.Start) / float32(length-1)
+		}
+		return sample * factorAt(position)
+	})
+	return result
+}
+
+// Amplified returns a sound where the samples of the given range are multiplied by given factor.
+// Samples that exceed the range after amplification are limited, which is reported by the returned clipping.
+func (sound L8) Amplified(r SampleRange, factor float32) (L8, Clipping) {
+	return sound.processed(sound.limited(r), func(index int, sample float32) float32 {
+		return sample * factor
+	})
+}
+
+// Normalized returns a sound where the samples of the given range are amplified so that the
+// highest amplitude reaches full scale. Silent ranges are returned unchanged.
+func (sound L8) Normalized(r SampleRange) L8 {
+	r = sound.limited(r)
+	peak := 0
+	for _, sample := range sound.Samples[r.Start:r.End] {
+		amplitude := int(sample) - 0x80
+		if amplitude < 0 {
+			amplitude = -amplitude - 1
+		}
+		if amplitude > peak {
+			peak = amplitude
+		}
+	}
+	if peak == 0 {
+		return sound.Cropped(sound.FullRange())
+	}
+	result, _ := sound.Amplified(r, 127.0/float32(peak))
+	return result
+}
+
+func (sound L8) processed(r SampleRange, modifier func(index int, sample float32) float32) (L8, Clipping) {
+	source := sound.ToF32()
+	section := F32{SampleRate: sound.SampleRate, Samples: make([]float32, r.Len())}
+	for index := r.Start; index < r.End; index++ {
+		section.Samples[index-r.Start] = modifier(index, source.Samples[index])
+	}
+	converted, clip := section.ToL8()
+	result := sound.Cropped(sound.FullRange())
+	copy(result.Samples[r.Start:r.End], converted.Samples)
+	return result, clip
+}
+
+// Concatenated returns a sound that has the samples of the other sound appended.
+// Should the other sound have a different sample rate, it is resampled.
+func (sound L8) Concatenated(other L8) L8 {
+	if (other.SampleRate != sound.SampleRate) && (sound.SampleRate > 0) {
+		other, _ = other.ToF32().Resampled(sound.SampleRate).ToL8()
+	}
+	result := L8{SampleRate: sound.SampleRate, Samples: make([]byte, 0, len(sound.Samples)+len(other.Samples))}
+	if result.SampleRate <= 0 {
+		result.SampleRate = other.SampleRate
+	}
+	result.Samples = append(result.Samples, sound.Samples...)
+	result.Samples = append(result.Samples, other.Samples...)
+	return result
+}
+
+// SilentRanges returns all ranges of at least given minimum length, in which no sample deviates more than
+// the given threshold from the center line.
+func (sound L8) SilentRanges(threshold byte, minLength int) []SampleRange {
+	var ranges []SampleRange
+	start := -1
+	closeRange := func(end int) {
+		if (start >= 0) && ((end - start) >= minLength) {
+			ranges = append(ranges, SampleRange{Start: start, End: end})
+		}
+		start = -1
+	}
+	for index, sample := range sound.Samples {
+		deviation := int(sample) - 0x80
+		if deviation < 0 {
+			deviation = -deviation
+		}
+		if deviation <= int(threshold) {
+			if start < 0 {
+				start = index
+			}
+		} else {
+			closeRange(index)
+		}
+	}
+	closeRange(len(sound.Samples))
+	return ranges
+}
@@ -0,0 +1,98 @@
+package audio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+)
+
+func TestL8Cropped(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x10, 0x20, 0x30, 0x40}}
+
+	result := sound.Cropped(audio.SampleRange{Start: 1, End: 3})
+
+	assert.Equal(t, []byte{0x20, 0x30}, result.Samples)
+	assert.Equal(t, float32(22050), result.SampleRate)
+}
+
+func TestL8CroppedLimitsRange(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x10, 0x20, 0x30, 0x40}}
+
+	result := sound.Cropped(audio.SampleRange{Start: -5, End: 10})
+
+	assert.Equal(t, sound.Samples, result.Samples)
+}
+
+func TestL8Trimmed(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x10, 0x20, 0x30, 0x40}}
+
+	result := sound.Trimmed(audio.SampleRange{Start: 1, End: 3})
+
+	assert.Equal(t, []byte{0x10, 0x40}, result.Samples)
+	assert.Equal(t, []byte{0x10, 0x20, 0x30, 0x40}, sound.Samples, "original must not be modified")
+}
+
+func TestL8Fades(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0xC0, 0xC0, 0xC0, 0xC0, 0xC0}}
+
+	fadedIn := sound.FadedIn(audio.SampleRange{Start: 0, End: 3})
+	fadedOut := sound.FadedOut(audio.SampleRange{Start: 2, End: 5})
+
+	assert.Equal(t, []byte{0x80, 0xA0, 0xC0, 0xC0, 0xC0}, fadedIn.Samples)
+	assert.Equal(t, []byte{0xC0, 0xC0, 0xC0, 0xA0, 0x80}, fadedOut.Samples)
+}
+
+func TestL8AmplifiedReportsClipping(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x90, 0xC0, 0x40}}
+
+	result, clip := sound.Amplified(sound.FullRange(), 4.0)
+
+	assert.Equal(t, []byte{0xC0, 0xFF, 0x00}, result.Samples)
+	assert.Equal(t, 2, clip.Samples)
+}
+
+func TestL8Normalized(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x80, 0xA0, 0x70, 0x80}}
+
+	result := sound.Normalized(sound.FullRange())
+
+	assert.Equal(t, byte(0xFF), result.Samples[1])
+	assert.Equal(t, byte(0x80), result.Samples[0])
+}
+
+func TestL8NormalizedKeepsSilence(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x80, 0x80}}
+
+	result := sound.Normalized(sound.FullRange())
+
+	assert.Equal(t, sound.Samples, result.Samples)
+}
+
+func TestL8Concatenated(t *testing.T) {
+	first := audio.L8{SampleRate: 22050, Samples: []byte{0x10, 0x20}}
+	second := audio.L8{SampleRate: 22050, Samples: []byte{0x30}}
+
+	result := first.Concatenated(second)
+
+	assert.Equal(t, []byte{0x10, 0x20, 0x30}, result.Samples)
+}
+
+func TestL8ConcatenatedResamplesOther(t *testing.T) {
+	first := audio.L8{SampleRate: 22050, Samples: []byte{0x80}}
+	second := audio.L8{SampleRate: 11025, Samples: make([]byte, 100)}
+
+	result := first.Concatenated(second)
+
+	assert.Equal(t, float32(22050), result.SampleRate)
+	assert.Equal(t, 201, len(result.Samples))
+}
+
+func TestL8SilentRanges(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x80, 0x81, 0x7F, 0xC0, 0x80, 0x40, 0x80, 0x82, 0x80}}
+
+	ranges := sound.SilentRanges(2, 2)
+
+	assert.Equal(t, []audio.SampleRange{{Start: 0, End: 3}, {Start: 6, End: 9}}, ranges)
+}
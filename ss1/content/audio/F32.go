@@ -0,0 +1,116 @@
+package audio
+
+import "math"
+
+// F32 is a linear sound snippet with floating point samples.
+// Full scale of the samples is the range of -1.0 to 1.0.
+type F32 struct {
+	SampleRate float32
+	Samples    []float32
+}
+
+// Clipping describes how much of a signal exceeded the representable range.
+type Clipping struct {
+	// Samples is the number of samples that were limited.
+	Samples int
+	// Peak is the highest absolute amplitude found, with 1.0 being full scale.
+	Peak float32
+}
+
+// Occurred returns true if at least one sample was limited.
+func (clip Clipping) Occurred() bool {
+	return clip.Samples > 0
+}
+
+// Duration returns the length of the sound in seconds.
+func (sound F32) Duration() float32 {
+	if sound.SampleRate <= 0 {
+		return 0.0
+	}
+	return float32(len(sound.Samples)) / sound.SampleRate
+}
+
+// ToL8 quantizes the sound to 8-bit samples.
+// Samples beyond full scale are limited, which is reported by the returned clipping information.
+func (sound F32) ToL8() (L8, Clipping) {
+	var clip Clipping
+	result := L8{
+		SampleRate: sound.SampleRate,
+		Samples:    make([]byte, len(sound.Samples)),
+	}
+	for index, sample := range sound.Samples {
+		amplitude := float32(math.Abs(float64(sample)))
+		if amplitude > clip.Peak {
+			clip.Peak = amplitude
+		}
+		if amplitude > 1.0 {
+			clip.Samples++
+		}
+		value := math.Floor(float64(sample) * 128.0)
+		if value < -128 {
+			value = -128
+		} else if value > 127 {
+			value = 127
+		}
+		result.Samples[index] = byte(int(value) + 0x80)
+	}
+	return result, clip
+}
+
+// Resampled returns the sound converted to the given sample rate.
+// The conversion uses a windowed sinc filter, which also removes frequencies above the
+// lower of the two Nyquist frequencies to avoid aliasing.
+func (sound F32) Resampled(sampleRate float32) F32 {
+	if (sound.SampleRate <= 0) || (sampleRate <= 0) || (sound.SampleRate == sampleRate) {
+		result := F32{SampleRate: sampleRate, Samples: make([]float32, len(sound.Samples))}
+		copy(result.Samples, sound.Samples)
+		return result
+	}
+	const zeroCrossings = 16
+	ratio := float64(sampleRate) / float64(sound.SampleRate)
+	cutoff := 0.5 * math.Min(1.0, ratio) * 0.97 // in cycles per source sample, slightly below Nyquist
+	halfWidth := zeroCrossings / (2.0 * cutoff)
+	inputCount := len(sound.Samples)
+	outputCount := int(math.Round(float64(inputCount) * ratio))
+	result := F32{SampleRate: sampleRate, Samples: make([]float32, outputCount)}
+
+	for index := 0; index < outputCount; index++ {
+		center := float64(index) / ratio
+		first := int(math.Ceil(center - halfWidth))
+		last := int(math.Floor(center + halfWidth))
+		if first < 0 {
+			first = 0
+		}
+		if last >= inputCount {
+			last = inputCount - 1
+		}
+		var sum float64
+		var weights float64
+		for source := first; source <= last; source++ {
+			offset := float64(source) - center
+			weight := 2.0 * cutoff * sinc(2.0*cutoff*offset) * blackman(offset/halfWidth)
+			sum += weight * float64(sound.Samples[source])
+			weights += weight
+		}
+		if weights != 0 {
+			result.Samples[index] = float32(sum / weights)
+		}
+	}
+	return result
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1.0
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman returns the window value for given position, ranging from -1.0 to 1.0.
+func blackman(x float64) float64 {
+	if (x <= -1.0) || (x >= 1.0) {
+		return 0.0
+	}
+	phase := math.Pi * (x + 1.0)
+	return 0.42 - 0.5*math.Cos(phase) + 0.08*math.Cos(2*phase)
+}
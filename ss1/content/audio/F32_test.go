@@ -0,0 +1,69 @@
+package audio_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+)
+
+func TestF32ToL8(t *testing.T) {
+	sound := audio.F32{SampleRate: 22050, Samples: []float32{-1.0, -0.5, 0.0, 0.5, 0.999}}
+
+	result, clip := sound.ToL8()
+
+	assert.Equal(t, float32(22050), result.SampleRate)
+	assert.Equal(t, []byte{0x00, 0x40, 0x80, 0xC0, 0xFF}, result.Samples)
+	assert.False(t, clip.Occurred(), "no clipping expected")
+	assert.Equal(t, float32(1.0), clip.Peak)
+}
+
+func TestF32ToL8ReportsClipping(t *testing.T) {
+	sound := audio.F32{SampleRate: 22050, Samples: []float32{-1.5, 0.0, 1.0, 2.0}}
+
+	result, clip := sound.ToL8()
+
+	assert.Equal(t, []byte{0x00, 0x80, 0xFF, 0xFF}, result.Samples)
+	assert.Equal(t, 2, clip.Samples)
+	assert.Equal(t, float32(2.0), clip.Peak)
+}
+
+func TestF32ResampledKeepsDuration(t *testing.T) {
+	sound := audio.F32{SampleRate: 44100, Samples: make([]float32, 44100)}
+
+	result := sound.Resampled(22050)
+
+	assert.Equal(t, float32(22050), result.SampleRate)
+	assert.Equal(t, 22050, len(result.Samples))
+}
+
+func TestF32ResampledKeepsLowFrequencies(t *testing.T) {
+	sound := sineWave(48000, 440, 4800)
+
+	result := sound.Resampled(22050)
+	expected := sineWave(22050, 440, len(result.Samples))
+
+	for index := 100; index < len(result.Samples)-100; index++ {
+		assert.InDelta(t, expected.Samples[index], result.Samples[index], 0.01, "mismatch at %d", index)
+	}
+}
+
+func TestF32ResampledRemovesFrequenciesAboveNyquist(t *testing.T) {
+	sound := sineWave(44100, 15000, 4410)
+
+	result := sound.Resampled(22050)
+
+	for index := 100; index < len(result.Samples)-100; index++ {
+		assert.InDelta(t, 0.0, result.Samples[index], 0.01, "residue at %d", index)
+	}
+}
+
+func sineWave(sampleRate float32, frequency float64, count int) audio.F32 {
+	sound := audio.F32{SampleRate: sampleRate, Samples: make([]float32, count)}
+	for index := range sound.Samples {
+		sound.Samples[index] = float32(0.5 * math.Sin(2*math.Pi*frequency*float64(index)/float64(sampleRate)))
+	}
+	return sound
+}
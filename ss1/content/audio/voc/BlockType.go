@@ -3,6 +3,24 @@ package voc
 type blockType byte
 
 const (
-	terminator = blockType(0x00)
-	soundData  = blockType(0x01)
+	terminator        = blockType(0x00)
+	soundData         = blockType(0x01)
+	soundContinuation = blockType(0x02)
+	silence           = blockType(0x03)
+	marker            = blockType(0x04)
+	text              = blockType(0x05)
+	repeatStart       = blockType(0x06)
+	repeatEnd         = blockType(0x07)
+	extended          = blockType(0x08)
+	soundDataNew      = blockType(0x09)
 )
+
+type codec uint16
+
+const (
+	codecUnsigned8 = codec(0x0000)
+	codecSigned16  = codec(0x0004)
+)
+
+// EndlessRepeat is the repeat count for a section that repeats until the playback is stopped.
+const EndlessRepeat = uint16(0xFFFF)
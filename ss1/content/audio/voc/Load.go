@@ -4,11 +4,17 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	"github.com/inkyblackness/hacked/ss1/content/audio"
 )
 
 // Load reads from the provided source a Creative Voice Sound and returns the data.
+//
+// All blocks are expanded into one linear sound: Silence is inserted as samples, repeated
+// sections are played once more than their stored count (endless repeats are played once),
+// and stereo data is mixed down. Should sound blocks differ in their sample rate,
+// they are resampled to the rate of the first one. Markers and texts are ignored.
 func Load(source io.Reader) (data audio.L8, err error) {
 	if source == nil {
 		return data, fmt.Errorf("source is nil")
@@ -27,7 +33,7 @@ func readAndVerifyHeader(source io.Reader) error {
 	version := uint16(0)
 	versionValidity := uint16(0)
 
-	_, err := source.Read(start)
+	_, err := io.ReadFull(source, start)
 	if err != nil {
 		return err
 	}
@@ -48,60 +54,206 @@ func readAndVerifyHeader(source io.Reader) error {
 	if calculated != versionValidity {
 		return fmt.Errorf("version validity failed: 0x%04X != 0x%04X", calculated, versionValidity)
 	}
+	if headerSize < standardHeaderSize {
+		return fmt.Errorf("invalid header size 0x%04X", headerSize)
+	}
 
-	skip := make([]byte, headerSize-standardHeaderSize)
-	_, err = source.Read(skip)
+	_, err = io.CopyN(ioutil.Discard, source, int64(headerSize-standardHeaderSize))
 	return err
 }
 
+// sampleFormat describes how the samples of sound blocks are stored.
+type sampleFormat struct {
+	sampleRate float32
+	channels   int
+	codec      codec
+}
+
+func (format sampleFormat) bytesPerValue() int {
+	if format.codec == codecSigned16 {
+		return 2
+	}
+	return 1
+}
+
+type soundLoader struct {
+	source io.Reader
+
+	sampleRate float32
+	samples    []float32
+
+	lastFormat    sampleFormat
+	pendingFormat *sampleFormat
+
+	repeatStartIndex int
+	repeatCount      uint16
+	repeating        bool
+}
+
 func readSoundData(source io.Reader) (data audio.L8, err error) {
-	sampleRate := float32(0.0)
-	var samples []byte
+	loader := soundLoader{source: source}
 	done := false
 
-	for !done {
-		blockStart := make([]byte, 4)
+	for !done && (err == nil) {
+		done, err = loader.readBlock()
+	}
+	if err != nil {
+		return
+	}
+
+	if len(loader.samples) == 0 {
+		return data, fmt.Errorf("no audio found")
+	}
+
+	data, _ = audio.F32{SampleRate: loader.sampleRate, Samples: loader.samples}.ToL8()
+	return data, nil
+}
+
+func (loader *soundLoader) readBlock() (done bool, err error) {
+	blockStart := make([]byte, 4)
+	_, err = io.ReadFull(loader.source, blockStart[:1])
+	if err == io.EOF {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if blockType(blockStart[0]) == terminator {
+		return true, nil
+	}
+	_, err = io.ReadFull(loader.source, blockStart[1:])
+	if err != nil {
+		return false, err
+	}
+	blockData := make([]byte, lengthFromBlockStart(blockStart))
+	_, err = io.ReadFull(loader.source, blockData)
+	if err != nil {
+		return false, err
+	}
+	return false, loader.handleBlock(blockType(blockStart[0]), blockData)
+}
 
-		_, err = source.Read(blockStart)
-		if err != nil {
-			return
+func (loader *soundLoader) handleBlock(block blockType, blockData []byte) error {
+	switch block {
+	case soundData:
+		if len(blockData) < 2 {
+			return errBlockTooShort(block)
 		}
-		switch blockType(blockStart[0]) {
-		case soundData:
-			{
-				meta := make([]byte, 2)
-				_, err = source.Read(meta)
-				if err != nil {
-					return
-				}
-				sampleRate = divisorToSampleRate(meta[0])
-
-				newCount := lengthFromBlockStart(blockStart) - len(meta)
-				buf := make([]byte, newCount)
-				_, err = source.Read(buf)
-				if err != nil {
-					return
-				}
-
-				oldCount := len(samples)
-				newSamples := make([]byte, oldCount+newCount)
-				copy(newSamples, samples)
-				copy(newSamples[oldCount:], buf)
-				samples = newSamples
-			}
-		case terminator:
-			{
-				done = true
+		format := sampleFormat{sampleRate: divisorToSampleRate(blockData[0]), channels: 1, codec: codecUnsigned8}
+		if blockData[1] != 0 {
+			return fmt.Errorf("unsupported packing 0x%02X", blockData[1])
+		}
+		if loader.pendingFormat != nil {
+			format = *loader.pendingFormat
+			loader.pendingFormat = nil
+		}
+		return loader.addSamples(format, blockData[2:])
+	case soundContinuation:
+		return loader.addSamples(loader.lastFormat, blockData)
+	case silence:
+		if len(blockData) < 3 {
+			return errBlockTooShort(block)
+		}
+		count := int(binary.LittleEndian.Uint16(blockData[0:2])) + 1
+		loader.addSilence(divisorToSampleRate(blockData[2]), count)
+	case repeatStart:
+		if len(blockData) < 2 {
+			return errBlockTooShort(block)
+		}
+		loader.repeatStartIndex = len(loader.samples)
+		loader.repeatCount = binary.LittleEndian.Uint16(blockData[0:2])
+		loader.repeating = true
+	case repeatEnd:
+		loader.repeatSection()
+	case extended:
+		if len(blockData) < 4 {
+			return errBlockTooShort(block)
+		}
+		if blockData[2] != 0 {
+			return fmt.Errorf("unsupported packing 0x%02X", blockData[2])
+		}
+		channels := int(blockData[3]) + 1
+		loader.pendingFormat = &sampleFormat{
+			sampleRate: timeConstantToSampleRate(binary.LittleEndian.Uint16(blockData[0:2]), channels),
+			channels:   channels,
+			codec:      codecUnsigned8,
+		}
+	case soundDataNew:
+		if len(blockData) < 12 {
+			return errBlockTooShort(block)
+		}
+		format := sampleFormat{
+			sampleRate: float32(binary.LittleEndian.Uint32(blockData[0:4])),
+			channels:   int(blockData[5]),
+			codec:      codec(binary.LittleEndian.Uint16(blockData[6:8])),
+		}
+		bitsPerSample := int(blockData[4])
+		if (format.codec != codecUnsigned8) && (format.codec != codecSigned16) {
+			return fmt.Errorf("unsupported codec 0x%04X", format.codec)
+		}
+		if (bitsPerSample != format.bytesPerValue()*8) || (format.channels < 1) || (format.sampleRate <= 0) {
+			return fmt.Errorf("unsupported sample format: %d bits, %d channels", bitsPerSample, format.channels)
+		}
+		return loader.addSamples(format, blockData[12:])
+	}
+	return nil
+}
+
+func errBlockTooShort(block blockType) error {
+	return fmt.Errorf("block of type 0x%02X is too short", byte(block))
+}
+
+func (loader *soundLoader) addSamples(format sampleFormat, data []byte) error {
+	if (format.channels < 1) || (format.sampleRate <= 0) {
+		return fmt.Errorf("sound data without format")
+	}
+	loader.lastFormat = format
+	bytesPerValue := format.bytesPerValue()
+	frameSize := format.channels * bytesPerValue
+	frameCount := len(data) / frameSize
+	decoded := make([]float32, frameCount)
+	for frame := 0; frame < frameCount; frame++ {
+		var sum float32
+		for channel := 0; channel < format.channels; channel++ {
+			offset := frame*frameSize + channel*bytesPerValue
+			if format.codec == codecSigned16 {
+				sum += float32(int16(binary.LittleEndian.Uint16(data[offset:offset+2]))) / 32768.0
+			} else {
+				sum += float32(int(data[offset])-0x80) / 128.0
 			}
 		}
+		decoded[frame] = sum / float32(format.channels)
 	}
+	if loader.sampleRate <= 0 {
+		loader.sampleRate = format.sampleRate
+	}
+	if format.sampleRate != loader.sampleRate {
+		decoded = audio.F32{SampleRate: format.sampleRate, Samples: decoded}.Resampled(loader.sampleRate).Samples
+	}
+	loader.samples = append(loader.samples, decoded...)
+	return nil
+}
 
-	if len(samples) == 0 {
-		return data, fmt.Errorf("no audio found")
+func (loader *soundLoader) addSilence(sampleRate float32, count int) {
+	if loader.sampleRate <= 0 {
+		loader.sampleRate = sampleRate
 	}
+	scaled := int(float32(count)*loader.sampleRate/sampleRate + 0.5)
+	loader.samples = append(loader.samples, make([]float32, scaled)...)
+}
 
-	return audio.L8{
-		SampleRate: sampleRate,
-		Samples:    samples,
-	}, nil
+func (loader *soundLoader) repeatSection() {
+	if !loader.repeating {
+		return
+	}
+	loader.repeating = false
+	if loader.repeatCount == EndlessRepeat {
+		return
+	}
+	section := loader.samples[loader.repeatStartIndex:]
+	repeated := make([]float32, 0, len(section)*int(loader.repeatCount))
+	for i := 0; i < int(loader.repeatCount); i++ {
+		repeated = append(repeated, section...)
+	}
+	loader.samples = append(loader.samples, repeated...)
 }
@@ -103,3 +103,76 @@ func TestLoadReturnsSoundDataWithSamples(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, samples, data.Samples)
 }
+
+func TestLoadExpandsSilence(t *testing.T) {
+	writer := newHeader()
+
+	writer.Write([]byte{0x01, 0x03, 0x00, 0x00, 0x9C, 0x00, 0xC0}) // sound data, one sample
+	writer.Write([]byte{0x03, 0x03, 0x00, 0x00, 0x02, 0x00, 0x9C}) // silence, three samples
+	writer.Write([]byte{0x02, 0x01, 0x00, 0x00, 0x40})             // continuation, one sample
+	writer.Write([]byte{0x00})                                     // Terminator
+
+	data, err := Load(bytes.NewReader(writer.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0xC0, 0x80, 0x80, 0x80, 0x40}, data.Samples)
+}
+
+func TestLoadExpandsRepeatedSections(t *testing.T) {
+	writer := newHeader()
+
+	writer.Write([]byte{0x01, 0x03, 0x00, 0x00, 0x9C, 0x00, 0x10}) // sound data
+	writer.Write([]byte{0x06, 0x02, 0x00, 0x00, 0x02, 0x00})       // repeat start, two more times
+	writer.Write([]byte{0x04, 0x02, 0x00, 0x00, 0x01, 0x00})       // marker
+	writer.Write([]byte{0x02, 0x02, 0x00, 0x00, 0x20, 0x30})       // continuation
+	writer.Write([]byte{0x07, 0x00, 0x00, 0x00})                   // repeat end
+	writer.Write([]byte{0x05, 0x02, 0x00, 0x00, 0x41, 0x00})       // text
+	writer.Write([]byte{0x00})                                     // Terminator
+
+	data, err := Load(bytes.NewReader(writer.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0x10, 0x20, 0x30, 0x20, 0x30, 0x20, 0x30}, data.Samples)
+}
+
+func TestLoadMixesDownExtendedStereo(t *testing.T) {
+	writer := newHeader()
+
+	writer.Write([]byte{0x08, 0x04, 0x00, 0x00, 0x00, 0xCE, 0x00, 0x01}) // extended, 10000 Hz stereo
+	writer.Write([]byte{0x01, 0x08, 0x00, 0x00, 0x00, 0x00})             // sound data
+	writer.Write([]byte{0xC0, 0x40, 0xFF, 0xFF, 0x00, 0x80})             // samples
+	writer.Write([]byte{0x00})                                           // Terminator
+
+	data, err := Load(bytes.NewReader(writer.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, float32(10000.0), data.SampleRate)
+	assert.Equal(t, []byte{0x80, 0xFF, 0x40}, data.Samples)
+}
+
+func TestLoadSupportsNewSoundData(t *testing.T) {
+	writer := newHeader()
+
+	writer.Write([]byte{0x09, 0x10, 0x00, 0x00})                         // new sound data
+	writer.Write([]byte{0x22, 0x56, 0x00, 0x00, 0x10, 0x01, 0x04, 0x00}) // 22050 Hz, 16-bit mono, signed
+	writer.Write([]byte{0x00, 0x00, 0x00, 0x00})                         // reserved
+	writer.Write([]byte{0x00, 0x40, 0x00, 0xC0})                         // samples
+	writer.Write([]byte{0x00})                                           // Terminator
+
+	data, err := Load(bytes.NewReader(writer.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, float32(22050.0), data.SampleRate)
+	assert.Equal(t, []byte{0xC0, 0x40}, data.Samples)
+}
+
+func TestLoadReturnsErrorOnCompressedData(t *testing.T) {
+	writer := newHeader()
+
+	writer.Write([]byte{0x01, 0x03, 0x00, 0x00, 0x9C, 0x01, 0x12}) // sound data, 4-bit ADPCM
+	writer.Write([]byte{0x00})                                     // Terminator
+
+	_, err := Load(bytes.NewReader(writer.Bytes()))
+
+	assert.NotNil(t, err)
+}
@@ -1,64 +1,168 @@
 package voc
 
 import (
-	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
 )
 
-// Save encodes the provided samples into the given writer
+// Version identifies the format revision of a Creative Voice File.
+type Version uint16
+
+const (
+	// Version110 is the format of the DOS toolchain. It supports all blocks except SoundDataNew.
+	Version110 = Version(baseVersion)
+	// Version120 is the format that also supports SoundDataNew blocks.
+	Version120 = Version(newVersion)
+)
+
+// Save encodes the provided samples into the given writer.
+// The file is written in version 1.10, with one sound data block, as the DOS tools do.
+// Samples that exceed the size of one block are stored in continuation blocks.
 func Save(writer io.Writer, sampleRate float32, samples []byte) error {
-	err := writeHeader(writer)
-	if err != nil {
-		return err
+	const maxBlockSize = 0xFFFFFF
+	voc := NewWriter(writer, Version110)
+	first := samples
+	if len(first) > maxBlockSize-2 {
+		first = first[:maxBlockSize-2]
 	}
-	err = writeBasicSoundData(writer, sampleRate, samples)
-	if err != nil {
-		return err
+	voc.SoundData(sampleRate, first)
+	for rest := samples[len(first):]; len(rest) > 0; {
+		next := rest
+		if len(next) > maxBlockSize {
+			next = next[:maxBlockSize]
+		}
+		voc.Continuation(next)
+		rest = rest[len(next):]
 	}
-	err = writeEndOfFile(writer)
-	return err
+	return voc.Close()
+}
+
+// Writer encodes a Creative Voice File block by block.
+// Any error is kept and stops further writes. It is reported by Err and Close.
+type Writer struct {
+	target  io.Writer
+	version Version
+	err     error
+}
+
+// NewWriter returns a writer for given target. The file header is written immediately.
+func NewWriter(target io.Writer, version Version) *Writer {
+	voc := &Writer{target: target, version: version}
+	voc.writeHeader()
+	return voc
 }
 
-func writeHeader(writer io.Writer) error {
-	version := baseVersion
+// Err returns the first error that occurred.
+func (voc *Writer) Err() error {
+	return voc.err
+}
+
+// SoundData writes a block of unsigned 8-bit mono samples.
+// The sample rate is stored as a divisor and thus approximated.
+func (voc *Writer) SoundData(sampleRate float32, samples []byte) {
+	voc.writeBlock(soundData, []byte{sampleRateToDivisor(sampleRate), byte(codecUnsigned8)}, samples)
+}
 
-	_, err := writer.Write(bytes.NewBufferString(fileHeader).Bytes())
-	if err != nil {
-		return err
+// ExtendedSoundData writes a block of unsigned 8-bit samples, preceded by an extended block that
+// stores the sample rate more precisely. Stereo samples are interleaved, left channel first.
+func (voc *Writer) ExtendedSoundData(sampleRate float32, stereo bool, samples []byte) {
+	channels := 1
+	if stereo {
+		channels = 2
 	}
-	err = binary.Write(writer, binary.LittleEndian, standardHeaderSize)
-	if err != nil {
-		return err
+	var header [4]byte
+	binary.LittleEndian.PutUint16(header[0:2], sampleRateToTimeConstant(sampleRate, channels))
+	header[3] = byte(channels - 1)
+	voc.writeBlock(extended, header[:])
+	voc.writeBlock(soundData, []byte{sampleRateToDivisor(sampleRate), byte(codecUnsigned8)}, samples)
+}
+
+// SoundDataNew writes a block of unsigned 8-bit mono samples in the format of version 1.20.
+func (voc *Writer) SoundDataNew(sampleRate float32, samples []byte) {
+	if (voc.err == nil) && (voc.version < Version120) {
+		voc.err = fmt.Errorf("new sound data requires version 1.20")
+		return
 	}
-	err = binary.Write(writer, binary.LittleEndian, baseVersion)
-	if err != nil {
-		return err
+	var header [12]byte
+	binary.LittleEndian.PutUint32(header[0:4], uint32(sampleRate+0.5))
+	header[4] = 8
+	header[5] = 1
+	binary.LittleEndian.PutUint16(header[6:8], uint16(codecUnsigned8))
+	voc.writeBlock(soundDataNew, header[:], samples)
+}
+
+// Continuation writes further samples in the format of the previous sound data block.
+func (voc *Writer) Continuation(samples []byte) {
+	voc.writeBlock(soundContinuation, samples)
+}
+
+// Silence writes a block that describes the given amount of silent samples.
+func (voc *Writer) Silence(sampleRate float32, count int) {
+	if (voc.err == nil) && ((count < 1) || (count > 0x10000)) {
+		voc.err = fmt.Errorf("silence of %d samples out of range", count)
+		return
 	}
-	return binary.Write(writer, binary.LittleEndian, (^version)+versionCheckValue)
+	var data [3]byte
+	binary.LittleEndian.PutUint16(data[0:2], uint16(count-1))
+	data[2] = sampleRateToDivisor(sampleRate)
+	voc.writeBlock(silence, data[:])
 }
 
-func writeBlockHeader(writer io.Writer, block blockType, dataBytes int) error {
-	_, err := writer.Write([]byte{byte(block), byte(dataBytes), byte(dataBytes >> 8), byte(dataBytes >> 16)})
-	return err
+// Marker writes a marker block with given identifier.
+func (voc *Writer) Marker(id uint16) {
+	var data [2]byte
+	binary.LittleEndian.PutUint16(data[:], id)
+	voc.writeBlock(marker, data[:])
 }
 
-func writeBasicSoundData(writer io.Writer, sampleRate float32, samples []byte) error {
-	sampleType := byte(0)
+// RepeatStart begins a section that is played once more than given count.
+// Use EndlessRepeat for sections that loop until stopped.
+func (voc *Writer) RepeatStart(count uint16) {
+	var data [2]byte
+	binary.LittleEndian.PutUint16(data[:], count)
+	voc.writeBlock(repeatStart, data[:])
+}
 
-	err := writeBlockHeader(writer, soundData, len(samples)+2)
-	if err != nil {
-		return err
-	}
-	_, err = writer.Write([]byte{sampleRateToDivisor(sampleRate), sampleType})
-	if err != nil {
-		return err
+// RepeatEnd finishes the section started by RepeatStart.
+func (voc *Writer) RepeatEnd() {
+	voc.writeBlock(repeatEnd)
+}
+
+// Close writes the terminator and returns the first error that occurred.
+// It does not close the underlying writer.
+func (voc *Writer) Close() error {
+	voc.write([]byte{byte(terminator)})
+	return voc.err
+}
+
+func (voc *Writer) write(data []byte) {
+	if voc.err == nil {
+		_, voc.err = voc.target.Write(data)
 	}
-	_, err = writer.Write(samples)
-	return err
 }
 
-func writeEndOfFile(writer io.Writer) error {
-	_, err := writer.Write([]byte{byte(terminator)})
-	return err
+func (voc *Writer) writeHeader() {
+	version := uint16(voc.version)
+	var values [6]byte
+	binary.LittleEndian.PutUint16(values[0:2], standardHeaderSize)
+	binary.LittleEndian.PutUint16(values[2:4], version)
+	binary.LittleEndian.PutUint16(values[4:6], (^version)+versionCheckValue)
+	voc.write([]byte(fileHeader))
+	voc.write(values[:])
+}
+
+func (voc *Writer) writeBlock(block blockType, parts ...[]byte) {
+	dataBytes := 0
+	for _, part := range parts {
+		dataBytes += len(part)
+	}
+	if (voc.err == nil) && (dataBytes > 0xFFFFFF) {
+		voc.err = fmt.Errorf("block of %d bytes is too large", dataBytes)
+		return
+	}
+	voc.write([]byte{byte(block), byte(dataBytes), byte(dataBytes >> 8), byte(dataBytes >> 16)})
+	for _, part := range parts {
+		voc.write(part)
+	}
 }
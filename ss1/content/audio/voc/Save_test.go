@@ -0,0 +1,50 @@
+package voc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveWritesBasicFile(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	err := Save(buf, 10000.0, []byte{0x80, 0x81})
+
+	require.Nil(t, err)
+	expected := newHeader()
+	expected.Write([]byte{0x01, 0x04, 0x00, 0x00, 0x9C, 0x00, 0x80, 0x81})
+	expected.Write([]byte{0x00})
+	assert.Equal(t, expected.Bytes(), buf.Bytes())
+}
+
+func TestWriterBlocksCanBeLoaded(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	voc := NewWriter(buf, Version120)
+
+	voc.ExtendedSoundData(22050.0, false, []byte{0x10})
+	voc.Silence(22050.0, 2)
+	voc.Marker(5)
+	voc.RepeatStart(1)
+	voc.SoundDataNew(22050.0, []byte{0x20})
+	voc.RepeatEnd()
+	voc.Continuation([]byte{0x30})
+	err := voc.Close()
+	require.Nil(t, err)
+
+	data, err := Load(bytes.NewReader(buf.Bytes()))
+
+	require.Nil(t, err)
+	assert.InDelta(t, 22050.0, data.SampleRate, 0.5)
+	assert.Equal(t, []byte{0x10, 0x80, 0x80, 0x20, 0x20, 0x30}, data.Samples)
+}
+
+func TestWriterRejectsNewSoundDataInOldVersion(t *testing.T) {
+	voc := NewWriter(bytes.NewBuffer(nil), Version110)
+
+	voc.SoundDataNew(22050.0, []byte{0x20})
+
+	assert.NotNil(t, voc.Close())
+}
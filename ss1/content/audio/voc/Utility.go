@@ -4,8 +4,10 @@ const (
 	fileHeader         string  = "Creative Voice File\u001A"
 	standardHeaderSize uint16  = 0x1A
 	baseVersion        uint16  = 0x010A
+	newVersion         uint16  = 0x0114
 	versionCheckValue  uint16  = 0x1234
 	rateBase           float32 = 1000000.0
+	extendedRateBase   float32 = 256000000.0
 )
 
 func lengthFromBlockStart(blockStart []byte) int {
@@ -19,3 +21,11 @@ func divisorToSampleRate(divisor byte) float32 {
 func sampleRateToDivisor(sampleRate float32) byte {
 	return byte(256 - int(rateBase/sampleRate))
 }
+
+func timeConstantToSampleRate(timeConstant uint16, channels int) float32 {
+	return extendedRateBase / float32(channels*(65536-int(timeConstant)))
+}
+
+func sampleRateToTimeConstant(sampleRate float32, channels int) uint16 {
+	return uint16(65536 - int(extendedRateBase/(float32(channels)*sampleRate)+0.5))
+}
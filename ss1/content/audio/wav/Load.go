@@ -10,7 +10,21 @@ import (
 var errNotASupportedWave = fmt.Errorf("not a supported WAV")
 
 // Load reads from the provided source and returns the data.
+// The samples are reduced to 8-bit, keeping the original sample rate.
+// See LoadFloat for the supported formats.
 func Load(source io.Reader) (data audio.L8, err error) {
+	sound, err := LoadFloat(source)
+	if err != nil {
+		return data, err
+	}
+	data, _ = sound.ToL8()
+	return
+}
+
+// LoadFloat reads from the provided source and returns the data with full precision.
+// Supported are uncompressed files with 8, 16, 24, or 32 bit integer samples, as well as
+// 32 or 64 bit floating point samples. Multiple channels are mixed down to one.
+func LoadFloat(source io.Reader) (data audio.F32, err error) {
 	if source == nil {
 		return data, fmt.Errorf("source is nil")
 	}
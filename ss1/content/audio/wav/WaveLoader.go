@@ -5,38 +5,53 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 )
 
-func l8FromL8(input []byte) []byte {
-	return input
+type sampleDecoder func(data []byte) float32
+
+func decodeL8(data []byte) float32 {
+	return float32(int(data[0])-0x80) / 128.0
 }
 
-func l8FromL16(input []byte) []byte {
-	samples := len(input) / 2
-	output := make([]byte, samples)
+func decodeL16(data []byte) float32 {
+	return float32(int16(binary.LittleEndian.Uint16(data))) / 32768.0
+}
 
-	for i := 0; i < samples; i++ {
-		output[i] = byte(int(input[i*2+1]) + 0x80)
-	}
+func decodeL24(data []byte) float32 {
+	value := int32(uint32(data[0])<<8|uint32(data[1])<<16|uint32(data[2])<<24) >> 8
+	return float32(value) / 8388608.0
+}
+
+func decodeL32(data []byte) float32 {
+	return float32(float64(int32(binary.LittleEndian.Uint32(data))) / 2147483648.0)
+}
+
+func decodeF32(data []byte) float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(data))
+}
 
-	return output
+func decodeF64(data []byte) float32 {
+	return float32(math.Float64frombits(binary.LittleEndian.Uint64(data)))
 }
 
 type waveLoader struct {
 	dataRead   bool
 	formatRead bool
-	samples    []byte
+	samples    []float32
 	sampleRate float32
 
 	reader io.Reader
 	err    error
 
-	dataConverter func([]byte) []byte
+	channels      int
+	bytesPerValue int
+	decoder       sampleDecoder
 }
 
 func (loader *waveLoader) load(reader io.Reader) {
 	loader.reader = reader
-	loader.dataConverter = l8FromL8
 	loader.loadRiff()
 }
 
@@ -49,10 +64,18 @@ func (loader *waveLoader) read(data interface{}) bool {
 
 func (loader *waveLoader) readBytes(size uint32) (data []byte) {
 	data = make([]byte, int(size))
-	_, loader.err = loader.reader.Read(data)
+	if loader.err == nil {
+		_, loader.err = io.ReadFull(loader.reader, data)
+	}
 	return
 }
 
+func (loader *waveLoader) skipBytes(size uint32) {
+	if loader.err == nil {
+		_, loader.err = io.CopyN(ioutil.Discard, loader.reader, int64(size))
+	}
+}
+
 func (loader *waveLoader) loadChunk(handler func(riffChunkType, uint32)) {
 	var tag riffChunkTag
 
@@ -82,7 +105,6 @@ func (loader *waveLoader) loadWave() {
 	for !loader.isDone() {
 		loader.loadFormatOrData()
 	}
-	loader.samples = loader.dataConverter(loader.samples)
 }
 
 func (loader *waveLoader) loadFormatOrData() {
@@ -90,37 +112,93 @@ func (loader *waveLoader) loadFormatOrData() {
 }
 
 func (loader *waveLoader) handleFormatOrData(chunkType riffChunkType, size uint32) {
-	if chunkType == riffChunkTypeFmt {
+	switch chunkType {
+	case riffChunkTypeFmt:
 		loader.loadFormat(size)
-	} else if chunkType == riffChunkTypeData {
+	case riffChunkTypeData:
 		loader.loadData(size)
+	default:
+		loader.skipBytes(size)
+	}
+	if ((size % 2) != 0) && !loader.isDone() {
+		loader.skipBytes(1)
 	}
 }
 
 func (loader *waveLoader) loadFormat(size uint32) {
 	headerData := loader.readBytes(size)
+	if loader.err != nil {
+		return
+	}
 	headerReader := bytes.NewReader(headerData)
 	var header formatHeader
 
 	loader.formatRead = true
-	loader.err = binary.Read(headerReader, binary.LittleEndian, &header.base)
-	loader.err = binary.Read(headerReader, binary.LittleEndian, &header.extension.BitsPerSample)
+	if binary.Read(headerReader, binary.LittleEndian, &header.base) != nil {
+		loader.err = errNotASupportedWave
+		return
+	}
+	_ = binary.Read(headerReader, binary.LittleEndian, &header.extension.BitsPerSample)
+	_ = binary.Read(headerReader, binary.LittleEndian, &header.extension.ExtensionSize)
+	formatType := header.base.FormatType
+	if formatType == waveFormatTypeExtensible {
+		var extensible waveFormatExtensible
+		if binary.Read(headerReader, binary.LittleEndian, &extensible) != nil {
+			loader.err = errNotASupportedWave
+			return
+		}
+		formatType = waveFormatType(binary.LittleEndian.Uint16(extensible.SubFormat[:2]))
+	}
 	loader.sampleRate = float32(header.base.SamplesPerSec)
-
-	if header.extension.BitsPerSample == 16 {
-		loader.dataConverter = l8FromL16
+	loader.channels = int(header.base.Channels)
+	loader.bytesPerValue = int(header.extension.BitsPerSample+7) / 8
+
+	switch {
+	case (formatType == waveFormatTypePcm) && (loader.bytesPerValue == 1):
+		loader.decoder = decodeL8
+	case (formatType == waveFormatTypePcm) && (loader.bytesPerValue == 2):
+		loader.decoder = decodeL16
+	case (formatType == waveFormatTypePcm) && (loader.bytesPerValue == 3):
+		loader.decoder = decodeL24
+	case (formatType == waveFormatTypePcm) && (loader.bytesPerValue == 4):
+		loader.decoder = decodeL32
+	case (formatType == waveFormatTypeFloat) && (loader.bytesPerValue == 4):
+		loader.decoder = decodeF32
+	case (formatType == waveFormatTypeFloat) && (loader.bytesPerValue == 8):
+		loader.decoder = decodeF64
 	}
 
-	if (header.base.FormatType != waveFormatTypePcm) ||
-		(header.base.Channels != 1) ||
-		((header.extension.BitsPerSample != 8) && (header.extension.BitsPerSample != 16)) {
+	if (loader.decoder == nil) || (loader.channels < 1) || (loader.sampleRate <= 0) {
 		loader.err = fmt.Errorf("unsupported WAVE format")
 	}
 }
 
 func (loader *waveLoader) loadData(size uint32) {
 	loader.dataRead = true
-	loader.samples = loader.readBytes(size)
+	data := loader.readBytes(size)
+	if (loader.err != nil) || !loader.formatRead {
+		if loader.err == nil {
+			loader.err = errNotASupportedWave
+		}
+		return
+	}
+	loader.samples = loader.downmixed(data)
+}
+
+// downmixed decodes the interleaved frames of the data and returns the average of all channels per frame.
+func (loader *waveLoader) downmixed(data []byte) []float32 {
+	frameSize := loader.channels * loader.bytesPerValue
+	frameCount := len(data) / frameSize
+	samples := make([]float32, frameCount)
+	for frame := 0; frame < frameCount; frame++ {
+		var sum float32
+		for channel := 0; channel < loader.channels; channel++ {
+			offset := frame*frameSize + channel*loader.bytesPerValue
+			sum += loader.decoder(data[offset : offset+loader.bytesPerValue])
+		}
+		samples[frame] = sum / float32(loader.channels)
+	}
+	return samples
 }
 
 func (loader *waveLoader) isDone() bool {
@@ -7,7 +7,9 @@ import (
 type waveFormatType uint16
 
 const (
-	waveFormatTypePcm = 1
+	waveFormatTypePcm        = 1
+	waveFormatTypeFloat      = 3
+	waveFormatTypeExtensible = 0xFFFE
 )
 
 type waveFormat struct {
@@ -31,3 +33,11 @@ type formatHeader struct {
 func (header formatHeader) size() uint32 {
 	return uint32(binary.Size(&header.base) + binary.Size(&header.extension))
 }
+
+// waveFormatExtensible is the remainder of a format header of type waveFormatTypeExtensible.
+// The first two bytes of the sub format identify the actual format type.
+type waveFormatExtensible struct {
+	ValidBitsPerSample uint16
+	ChannelMask        uint32
+	SubFormat          [16]byte
+}
@@ -0,0 +1,62 @@
+package font
+
+import (
+	"errors"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// Cache retrieves fonts from a localizer and keeps them decoded until they are invalidated.
+type Cache struct {
+	localizer resource.Localizer
+
+	fonts map[resource.Key]Font
+}
+
+// NewCache returns a new instance.
+func NewCache(localizer resource.Localizer) *Cache {
+	cache := &Cache{
+		localizer: localizer,
+
+		fonts: make(map[resource.Key]Font),
+	}
+	return cache
+}
+
+// InvalidateResources lets the cache remove any fonts from resources that are specified in the given slice.
+func (cache *Cache) InvalidateResources(ids []resource.ID) {
+	for _, id := range ids {
+		for key := range cache.fonts {
+			if key.ID == id {
+				delete(cache.fonts, key)
+			}
+		}
+	}
+}
+
+// Font retrieves and caches the font of given key.
+func (cache *Cache) Font(key resource.Key) (Font, error) {
+	cacheKey := resource.KeyOf(key.ID.Plus(key.Index), key.Lang, 0)
+	value, existing := cache.fonts[cacheKey]
+	if existing {
+		return value, nil
+	}
+	selector := cache.localizer.LocalizedResources(key.Lang)
+	view, err := selector.Select(cacheKey.ID)
+	if err != nil {
+		return Font{}, errors.New("no font found")
+	}
+	if (view.ContentType() != resource.Font) || (view.BlockCount() < 1) {
+		return Font{}, errors.New("invalid resource type")
+	}
+	reader, err := view.Block(0)
+	if err != nil {
+		return Font{}, err
+	}
+	value, err = Decode(reader)
+	if err != nil {
+		return Font{}, err
+	}
+	cache.fonts[cacheKey] = value
+	return value, nil
+}
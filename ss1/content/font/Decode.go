@@ -0,0 +1,73 @@
+package font
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	typeMonochrome = 0x0000
+	typeColor      = 0xCCCC
+
+	headerSize = 0x54
+)
+
+// Decode reads a font from given reader.
+func Decode(reader io.Reader) (Font, error) {
+	var f Font
+	if reader == nil {
+		return f, errors.New("reader is nil")
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return f, err
+	}
+	if len(data) < headerSize {
+		return f, errors.New("data too short for header")
+	}
+	word := func(offset int) int { return int(binary.LittleEndian.Uint16(data[offset:])) }
+	long := func(offset int) int { return int(binary.LittleEndian.Uint32(data[offset:])) }
+
+	switch word(0x00) {
+	case typeMonochrome:
+		f.Monochrome = true
+	case typeColor:
+	default:
+		return f, errors.New("unknown font type")
+	}
+	first, last := word(0x24), word(0x26)
+	if (first > last) || (last > 0xFF) {
+		return f, errors.New("invalid character range")
+	}
+	f.FirstCharacter = byte(first)
+	f.LastCharacter = byte(last)
+	offsetTableStart := long(0x48)
+	bitmapStart := long(0x4C)
+	f.Stride = word(0x50)
+	f.Height = word(0x52)
+
+	offsetCount := last - first + 2
+	if (offsetTableStart < headerSize) || ((offsetTableStart + offsetCount*2) > len(data)) {
+		return f, errors.New("offset table out of range")
+	}
+	bitmapSize := f.Stride * f.Height
+	if (bitmapStart < headerSize) || ((bitmapStart + bitmapSize) > len(data)) {
+		return f, errors.New("bitmap out of range")
+	}
+	rowWidth := f.Stride
+	if f.Monochrome {
+		rowWidth *= 8
+	}
+	f.Offsets = make([]int, offsetCount)
+	for index := range f.Offsets {
+		offset := word(offsetTableStart + index*2)
+		if (offset > rowWidth) || ((index > 0) && (offset < f.Offsets[index-1])) {
+			return f, errors.New("invalid glyph offsets")
+		}
+		f.Offsets[index] = offset
+	}
+	f.Bitmap = data[bitmapStart : bitmapStart+bitmapSize]
+	return f, nil
+}
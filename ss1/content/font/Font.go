@@ -0,0 +1,82 @@
+package font
+
+// Font describes the glyphs of the characters in a range of codepage values.
+type Font struct {
+	// Monochrome is set for fonts with one bit per pixel.
+	Monochrome bool
+	// FirstCharacter is the codepage value of the first glyph.
+	FirstCharacter byte
+	// LastCharacter is the codepage value of the last glyph (inclusive).
+	LastCharacter byte
+	// Height is the height of all glyphs, in pixels.
+	Height int
+
+	// Offsets holds the horizontal start of each glyph within the bitmap.
+	// There is one more entry than glyphs, marking the end of the last glyph.
+	Offsets []int
+	// Stride is the number of bytes of one row within the bitmap.
+	Stride int
+	// Bitmap contains the pixel data of all the glyphs.
+	Bitmap []byte
+}
+
+// Contains returns true if the font has a glyph for the given character.
+func (f Font) Contains(ch byte) bool {
+	return (ch >= f.FirstCharacter) && (ch <= f.LastCharacter)
+}
+
+// GlyphWidth returns the width, in pixels, of the glyph for given character.
+// Characters without a glyph have a width of zero.
+func (f Font) GlyphWidth(ch byte) int {
+	if !f.Contains(ch) {
+		return 0
+	}
+	index := int(ch - f.FirstCharacter)
+	return f.Offsets[index+1] - f.Offsets[index]
+}
+
+// Width returns the width, in pixels, of the given encoded characters.
+func (f Font) Width(encoded []byte) int {
+	width := 0
+	for _, ch := range encoded {
+		width += f.GlyphWidth(ch)
+	}
+	return width
+}
+
+// IsSet returns true if the pixel of the given glyph is drawn.
+func (f Font) IsSet(ch byte, x, y int) bool {
+	if (x < 0) || (x >= f.GlyphWidth(ch)) || (y < 0) || (y >= f.Height) {
+		return false
+	}
+	column := f.Offsets[int(ch-f.FirstCharacter)] + x
+	if f.Monochrome {
+		return (f.Bitmap[y*f.Stride+column/8] & (0x80 >> uint(column%8))) != 0
+	}
+	return f.Bitmap[y*f.Stride+column] != 0
+}
+
+// Draw renders the encoded characters into a paletted image, starting at the given top-left position.
+// All drawn pixels are set to the given color index. Pixels outside the image are clipped.
+// It returns the width of the drawn characters.
+func (f Font) Draw(pixels []byte, imageWidth int, left, top int, encoded []byte, colorIndex byte) int {
+	imageHeight := len(pixels) / imageWidth
+	x := left
+	for _, ch := range encoded {
+		glyphWidth := f.GlyphWidth(ch)
+		for glyphY := 0; glyphY < f.Height; glyphY++ {
+			pixelY := top + glyphY
+			if (pixelY < 0) || (pixelY >= imageHeight) {
+				continue
+			}
+			for glyphX := 0; glyphX < glyphWidth; glyphX++ {
+				pixelX := x + glyphX
+				if (pixelX >= 0) && (pixelX < imageWidth) && f.IsSet(ch, glyphX, glyphY) {
+					pixels[pixelY*imageWidth+pixelX] = colorIndex
+				}
+			}
+		}
+		x += glyphWidth
+	}
+	return x - left
+}
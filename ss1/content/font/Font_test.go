@@ -0,0 +1,101 @@
+package font_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/font"
+)
+
+// monochromeFontData returns a font with the characters ' ', '!', and '"'.
+// The space is two pixels wide, '!' one pixel, '"' three pixels. The font is two pixels high.
+func monochromeFontData() []byte {
+	buf := bytes.NewBuffer(nil)
+	write := func(value interface{}) { _ = binary.Write(buf, binary.LittleEndian, value) }
+	write(uint16(0x0000))
+	write(make([]byte, 34))
+	write(uint16(' '))
+	write(uint16('"'))
+	write(make([]byte, 32))
+	write(uint32(0x54))
+	write(uint32(0x54 + 4*2))
+	write(uint16(1))
+	write(uint16(2))
+	write([]uint16{0, 2, 3, 6})
+	write([]byte{0x2A, 0x3C})
+	return buf.Bytes()
+}
+
+func aMonochromeFont(t *testing.T) font.Font {
+	f, err := font.Decode(bytes.NewReader(monochromeFontData()))
+	require.Nil(t, err)
+	return f
+}
+
+func TestDecodeMonochromeFont(t *testing.T) {
+	f := aMonochromeFont(t)
+
+	assert.True(t, f.Monochrome, "monochrome expected")
+	assert.Equal(t, byte(' '), f.FirstCharacter)
+	assert.Equal(t, byte('"'), f.LastCharacter)
+	assert.Equal(t, 2, f.Height)
+	assert.Equal(t, []int{0, 2, 3, 6}, f.Offsets)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tt := []struct {
+		name   string
+		modify func(data []byte) []byte
+	}{
+		{name: "too short", modify: func(data []byte) []byte { return data[:0x50] }},
+		{name: "unknown type", modify: func(data []byte) []byte { data[0] = 0x12; return data }},
+		{name: "invalid range", modify: func(data []byte) []byte { data[0x24] = 0x30; return data }},
+		{name: "offset table out of range", modify: func(data []byte) []byte { data[0x48] = 0xF0; return data }},
+		{name: "bitmap out of range", modify: func(data []byte) []byte { data[0x52] = 0x10; return data }},
+		{name: "decreasing offsets", modify: func(data []byte) []byte { data[0x56] = 0x04; return data }},
+		{name: "offsets beyond bitmap", modify: func(data []byte) []byte { data[0x5A] = 0x09; return data }},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := font.Decode(bytes.NewReader(td.modify(monochromeFontData())))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestGlyphWidth(t *testing.T) {
+	f := aMonochromeFont(t)
+
+	assert.Equal(t, 2, f.GlyphWidth(' '))
+	assert.Equal(t, 1, f.GlyphWidth('!'))
+	assert.Equal(t, 3, f.GlyphWidth('"'))
+	assert.Equal(t, 0, f.GlyphWidth('A'), "unknown characters have no width")
+	assert.Equal(t, 6, f.Width([]byte(" !\"A")))
+}
+
+func TestDraw(t *testing.T) {
+	f := aMonochromeFont(t)
+	pixels := make([]byte, 6*2)
+
+	width := f.Draw(pixels, 6, 0, 0, []byte("!\""), 7)
+
+	assert.Equal(t, 4, width)
+	assert.Equal(t, []byte{
+		7, 0, 7, 0, 0, 0,
+		7, 7, 7, 7, 0, 0,
+	}, pixels)
+}
+
+func TestDrawClipsOutsideImage(t *testing.T) {
+	f := aMonochromeFont(t)
+	pixels := make([]byte, 2*1)
+
+	f.Draw(pixels, 2, 0, -1, []byte("!\""), 7)
+
+	assert.Equal(t, []byte{7, 7}, pixels)
+}
@@ -0,0 +1,42 @@
+package font
+
+import "bytes"
+
+// Line is one line of text, laid out with a font.
+type Line struct {
+	// Characters are the encoded characters of the line, without trailing spaces.
+	Characters []byte
+	// Width is the width of the characters, in pixels.
+	Width int
+}
+
+// Wrap splits the encoded text into lines that fit the given width, breaking at spaces.
+// Line breaks within the text start a new line. Single words that are wider than the
+// width are kept on a line of their own, which then exceeds the width.
+func (f Font) Wrap(encoded []byte, maxWidth int) []Line {
+	var lines []Line
+	spaceWidth := f.GlyphWidth(' ')
+	for _, paragraph := range bytes.Split(encoded, []byte{'\n'}) {
+		var current Line
+		hasWord := false
+		for _, word := range bytes.Split(paragraph, []byte{' '}) {
+			wordWidth := f.Width(word)
+			if hasWord && ((current.Width + spaceWidth + wordWidth) > maxWidth) {
+				lines = append(lines, current)
+				current = Line{}
+				hasWord = false
+			}
+			if hasWord {
+				current.Characters = append(current.Characters, ' ')
+				current.Width += spaceWidth
+			}
+			current.Characters = append(current.Characters, word...)
+			current.Width += wordWidth
+			hasWord = true
+		}
+		current.Characters = bytes.TrimRight(current.Characters, " ")
+		current.Width = f.Width(current.Characters)
+		lines = append(lines, current)
+	}
+	return lines
+}
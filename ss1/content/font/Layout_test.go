@@ -0,0 +1,43 @@
+package font_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/font"
+)
+
+func lineTexts(lines []font.Line) []string {
+	result := make([]string, len(lines))
+	for index, line := range lines {
+		result[index] = string(line.Characters)
+	}
+	return result
+}
+
+func TestWrapBreaksAtSpaces(t *testing.T) {
+	f := aMonochromeFont(t)
+
+	lines := f.Wrap([]byte("!! !! \"\" !"), 6)
+
+	assert.Equal(t, []string{"!! !!", "\"\"", "!"}, lineTexts(lines))
+	assert.Equal(t, []int{6, 6, 1}, []int{lines[0].Width, lines[1].Width, lines[2].Width})
+}
+
+func TestWrapKeepsLineBreaks(t *testing.T) {
+	f := aMonochromeFont(t)
+
+	lines := f.Wrap([]byte("!\n\n!"), 100)
+
+	assert.Equal(t, []string{"!", "", "!"}, lineTexts(lines))
+}
+
+func TestWrapKeepsWideWordsOnOwnLine(t *testing.T) {
+	f := aMonochromeFont(t)
+
+	lines := f.Wrap([]byte("! \"\"\"\" !"), 5)
+
+	assert.Equal(t, []string{"!", "\"\"\"\"", "!"}, lineTexts(lines))
+	assert.Equal(t, 12, lines[1].Width)
+}
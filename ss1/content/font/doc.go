@@ -0,0 +1,2 @@
+// Package font handles the bitmap fonts of the game.
+package font
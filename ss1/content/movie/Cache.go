@@ -14,7 +14,7 @@ const sizeLimit = 0xFFFFFF
 
 // Cache retrieves movie container from a localizer and keeps them decoded until they are invalidated.
 type Cache struct {
-	cp text.Codepage
+	cps text.Codepages
 
 	localizer resource.Localizer
 
@@ -36,9 +36,9 @@ func (cached *cachedMovie) video() []Scene {
 }
 
 // NewCache returns a new instance.
-func NewCache(cp text.Codepage, localizer resource.Localizer) *Cache {
+func NewCache(cps text.Codepages, localizer resource.Localizer) *Cache {
 	cache := &Cache{
-		cp:        cp,
+		cps:       cps,
 		localizer: localizer,
 
 		movies: make(map[resource.Key]*cachedMovie),
@@ -57,6 +57,11 @@ func (cache *Cache) InvalidateResources(ids []resource.ID) {
 	}
 }
 
+// InvalidateAll lets the cache remove all movies. This is necessary if the codepages changed.
+func (cache *Cache) InvalidateAll() {
+	cache.movies = make(map[resource.Key]*cachedMovie)
+}
+
 func (cache *Cache) cached(key resource.Key) (*cachedMovie, error) {
 	value, existing := cache.movies[key]
 	if existing {
@@ -78,7 +83,7 @@ func (cache *Cache) cached(key resource.Key) (*cachedMovie, error) {
 	if err != nil {
 		return nil, err
 	}
-	container, err := Read(bytes.NewReader(data), cache.cp)
+	container, err := Read(bytes.NewReader(data), cache.cps)
 	if err != nil {
 		return nil, err
 	}
@@ -129,5 +134,5 @@ func (cache *Cache) Subtitles(key resource.Key, language resource.Language) (Sub
 	if err != nil {
 		return SubtitleList{}, err
 	}
-	return cached.container.Subtitles.PerLanguage[language], nil
+	return cached.container.Subtitles.Of(language), nil
 }
@@ -13,6 +13,6 @@ func ContainSoundData(soundData audio.L8) []byte {
 	container.Audio.Sound = soundData
 
 	buffer := bytes.NewBuffer(nil)
-	_ = Write(buffer, container, text.DefaultCodepage())
+	_ = Write(buffer, container, text.NewLanguageCodepages(text.DefaultCodepage()))
 	return buffer.Bytes()
 }
@@ -11,14 +11,14 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
 	"github.com/inkyblackness/hacked/ss1/content/movie/internal/format"
 	"github.com/inkyblackness/hacked/ss1/content/text"
-	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/serial"
 )
 
 // Read tries to extract a MOVI container from the provided reader.
 // On success the position of the reader is past the last data entry.
 // On failure the position of the reader is undefined.
-func Read(source io.ReadSeeker, cp text.Codepage) (Container, error) {
+// Subtitles are decoded with the codepage of their language.
+func Read(source io.ReadSeeker, cps text.Codepages) (Container, error) {
 	if source == nil {
 		return Container{}, fmt.Errorf("source is nil")
 	}
@@ -44,7 +44,7 @@ func Read(source io.ReadSeeker, cp text.Codepage) (Container, error) {
 		return Container{}, err
 	}
 	err = parseEntries(entries, &container,
-		cp, startPalette, format.Timestamp{Second: byte(header.Duration.Number), Fraction: header.Duration.Fraction})
+		cps, startPalette, format.Timestamp{Second: byte(header.Duration.Number), Fraction: header.Duration.Fraction})
 	if err != nil {
 		return Container{}, err
 	}
@@ -129,7 +129,7 @@ func readEntry(entryType format.DataType, source io.Reader, dataSize int) (forma
 }
 
 func parseEntries(entries []format.Entry, container *Container,
-	cp text.Codepage, startPalette bitmap.Palette, endTimestamp format.Timestamp) error {
+	cps text.Codepages, startPalette bitmap.Palette, endTimestamp format.Timestamp) error {
 	palette := startPalette
 	var paletteLookup []byte
 	var controlDictionary []compression.ControlWord
@@ -154,16 +154,8 @@ func parseEntries(entries []format.Entry, container *Container,
 		case format.AudioEntryData:
 			container.Audio.Sound.Samples = append(container.Audio.Sound.Samples, data.Samples...)
 		case format.SubtitleEntryData:
-			subText := cp.Decode(data.Text)
-			switch data.Control {
-			case format.SubtitleTextStd:
-				container.Subtitles.add(resource.LangDefault, entry.Timestamp.ToDuration(), subText)
-			case format.SubtitleTextGer:
-				container.Subtitles.add(resource.LangGerman, entry.Timestamp.ToDuration(), subText)
-			case format.SubtitleTextFrn:
-				container.Subtitles.add(resource.LangFrench, entry.Timestamp.ToDuration(), subText)
-			case format.SubtitleArea:
-			default:
+			if lang, isText := format.LanguageForSubtitleControl(data.Control); isText {
+				container.Subtitles.add(lang, entry.Timestamp.ToDuration(), cps.ForLanguage(lang).Decode(data.Text))
 			}
 		case format.LowResVideoEntryData:
 			// ignored for now
@@ -12,7 +12,7 @@ import (
 )
 
 func TestReadReturnsErrorOnNil(t *testing.T) {
-	_, err := movie.Read(nil, text.DefaultCodepage())
+	_, err := movie.Read(nil, text.NewLanguageCodepages(text.DefaultCodepage()))
 
 	assert.Errorf(t, err, "source is nil")
 }
@@ -22,7 +22,7 @@ func TestReadReturnsContainerOnEmptyFile(t *testing.T) {
 	buffer.Write(make([]byte, 0x100+0x300-len(format.Tag)))
 	emptyFile := buffer.Bytes()
 	source := bytes.NewReader(emptyFile)
-	container, _ := movie.Read(source, text.DefaultCodepage())
+	container, _ := movie.Read(source, text.NewLanguageCodepages(text.DefaultCodepage()))
 
 	assert.NotNil(t, container)
 }
@@ -30,7 +30,7 @@ func TestReadReturnsContainerOnEmptyFile(t *testing.T) {
 func TestReadReturnsErrorOnMissingTag(t *testing.T) {
 	emptyFile := make([]byte, 0x100+0x300)
 	source := bytes.NewReader(emptyFile)
-	_, err := movie.Read(source, text.DefaultCodepage())
+	_, err := movie.Read(source, text.NewLanguageCodepages(text.DefaultCodepage()))
 
 	assert.Errorf(t, err, "Not a MOVI format")
 }
@@ -51,7 +51,7 @@ func TestReadReturnsContainerWithBasicPropertiesSet(t *testing.T) {
 	emptyFile[0x27] = 0x56
 
 	source := bytes.NewReader(emptyFile)
-	container, _ := movie.Read(source, text.DefaultCodepage())
+	container, _ := movie.Read(source, text.NewLanguageCodepages(text.DefaultCodepage()))
 
 	assert.Equal(t, uint16(640), container.Video.Width)
 	assert.Equal(t, uint16(480), container.Video.Height)
@@ -81,7 +81,7 @@ func TestReadReturnsContainerWithAudioData(t *testing.T) {
 	raw[0x0408+5] = 0x10
 
 	source := bytes.NewReader(raw)
-	container, _ := movie.Read(source, text.DefaultCodepage())
+	container, _ := movie.Read(source, text.NewLanguageCodepages(text.DefaultCodepage()))
 
 	assert.Equal(t, testData, container.Audio.Sound.Samples)
 }
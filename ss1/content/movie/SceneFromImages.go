@@ -0,0 +1,160 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// ImageFrame is a true-color picture that shall be displayed for a given time.
+type ImageFrame struct {
+	Image       image.Image
+	DisplayTime time.Duration
+}
+
+// Color indices with special meaning in a scene palette.
+const (
+	// SceneBackgroundColorIndex is used for the empty area of a frame.
+	SceneBackgroundColorIndex = 0x00
+	// SceneSubtitleColorIndex is reserved for the text of subtitles.
+	SceneSubtitleColorIndex = 0xFF
+)
+
+var sceneSubtitleColor = bitmap.RGB{Red: 0x9A, Green: 0x35, Blue: 0x35}
+
+// SceneFromImages creates a scene from a sequence of true-color images.
+// Each image is scaled to fit the given size, keeping its aspect ratio. Remaining areas are filled
+// with the background color (letterbox). All frames are then quantized to one shared palette,
+// leaving the background and subtitle color entries reserved.
+// The function returns the error of the context in case it was cancelled.
+func SceneFromImages(ctx context.Context, frames []ImageFrame, width, height int) (Scene, error) {
+	if (width <= 0) || (height <= 0) {
+		return Scene{}, errors.New("invalid scene size")
+	}
+	fitted := make([]*image.RGBA, len(frames))
+	var hist colorHistogram
+	hist.init()
+	for index, frame := range frames {
+		if frame.Image == nil {
+			return Scene{}, errors.New("frame without image")
+		}
+		fitted[index] = fitImage(frame.Image, width, height)
+		hist.addImage(fitted[index])
+		if ctx.Err() != nil {
+			return Scene{}, ctx.Err()
+		}
+	}
+
+	var scene Scene
+	colors := hist.medianCut(254)
+	for index, clr := range colors {
+		scene.Palette[index+1] = clr
+	}
+	scene.Palette[SceneBackgroundColorIndex] = bitmap.RGB{}
+	scene.Palette[SceneSubtitleColorIndex] = sceneSubtitleColor
+
+	mapper := newNearestColorMapper(scene.Palette[1:len(colors)+1], 1)
+	scene.Frames = make([]Frame, len(frames))
+	for index, img := range fitted {
+		pixels := make([]byte, width*height)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				offset := img.PixOffset(x, y)
+				if img.Pix[offset+3] == 0 {
+					pixels[y*width+x] = SceneBackgroundColorIndex
+					continue
+				}
+				pixels[y*width+x] = mapper.indexOf(img.Pix[offset], img.Pix[offset+1], img.Pix[offset+2])
+			}
+		}
+		scene.Frames[index] = Frame{Pixels: pixels, DisplayTime: frames[index].DisplayTime}
+		if ctx.Err() != nil {
+			return Scene{}, ctx.Err()
+		}
+	}
+	return scene, nil
+}
+
+// fitImage scales the given image to fit into the requested size, keeping the aspect ratio.
+// The result is centered, with the uncovered area left fully transparent.
+func fitImage(src image.Image, width, height int) *image.RGBA {
+	result := image.NewRGBA(image.Rect(0, 0, width, height))
+	bounds := src.Bounds()
+	srcWidth := bounds.Dx()
+	srcHeight := bounds.Dy()
+	if (srcWidth <= 0) || (srcHeight <= 0) {
+		return result
+	}
+	targetWidth := width
+	targetHeight := (srcHeight * width) / srcWidth
+	if targetHeight > height {
+		targetHeight = height
+		targetWidth = (srcWidth * height) / srcHeight
+	}
+	if targetWidth < 1 {
+		targetWidth = 1
+	}
+	if targetHeight < 1 {
+		targetHeight = 1
+	}
+	left := (width - targetWidth) / 2
+	top := (height - targetHeight) / 2
+
+	sampleAt := func(x, y int) (r, g, b, a float64) {
+		clr := color.NRGBAModel.Convert(src.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+		return float64(clr.R), float64(clr.G), float64(clr.B), float64(clr.A)
+	}
+	clampIndex := func(value, limit int) int {
+		if value < 0 {
+			return 0
+		}
+		if value >= limit {
+			return limit - 1
+		}
+		return value
+	}
+	scaleX := float64(srcWidth) / float64(targetWidth)
+	scaleY := float64(srcHeight) / float64(targetHeight)
+	for y := 0; y < targetHeight; y++ {
+		srcY := (float64(y)+0.5)*scaleY - 0.5
+		y0 := clampIndex(int(srcY), srcHeight)
+		y1 := clampIndex(y0+1, srcHeight)
+		fy := srcY - float64(y0)
+		if fy < 0 {
+			fy = 0
+		}
+		for x := 0; x < targetWidth; x++ {
+			srcX := (float64(x)+0.5)*scaleX - 0.5
+			x0 := clampIndex(int(srcX), srcWidth)
+			x1 := clampIndex(x0+1, srcWidth)
+			fx := srcX - float64(x0)
+			if fx < 0 {
+				fx = 0
+			}
+			r00, g00, b00, a00 := sampleAt(x0, y0)
+			r10, g10, b10, a10 := sampleAt(x1, y0)
+			r01, g01, b01, a01 := sampleAt(x0, y1)
+			r11, g11, b11, a11 := sampleAt(x1, y1)
+			lerp := func(v00, v10, v01, v11 float64) uint8 {
+				top := v00 + (v10-v00)*fx
+				bottom := v01 + (v11-v01)*fx
+				return uint8(top + (bottom-top)*fy + 0.5)
+			}
+			alpha := lerp(a00, a10, a01, a11)
+			if alpha < 0x80 {
+				continue
+			}
+			result.SetRGBA(left+x, top+y, color.RGBA{
+				R: lerp(r00, r10, r01, r11),
+				G: lerp(g00, g10, g01, g11),
+				B: lerp(b00, b10, b01, b11),
+				A: 0xFF,
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package movie
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func TestSceneFromImagesKeepsDisplayTimes(t *testing.T) {
+	frames := []ImageFrame{
+		{Image: uniformImage(4, 2, color.RGBA{R: 0xFF, A: 0xFF}), DisplayTime: 100 * time.Millisecond},
+		{Image: uniformImage(4, 2, color.RGBA{G: 0xFF, A: 0xFF}), DisplayTime: 200 * time.Millisecond},
+	}
+
+	scene, err := SceneFromImages(context.Background(), frames, 4, 2)
+
+	require.Nil(t, err)
+	require.Equal(t, 2, len(scene.Frames))
+	assert.Equal(t, 100*time.Millisecond, scene.Frames[0].DisplayTime)
+	assert.Equal(t, 200*time.Millisecond, scene.Frames[1].DisplayTime)
+}
+
+func TestSceneFromImagesSharesPaletteAcrossFrames(t *testing.T) {
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	blue := color.RGBA{B: 0xFF, A: 0xFF}
+	frames := []ImageFrame{
+		{Image: uniformImage(2, 2, red)},
+		{Image: uniformImage(2, 2, blue)},
+	}
+
+	scene, err := SceneFromImages(context.Background(), frames, 2, 2)
+
+	require.Nil(t, err)
+	redIndex := scene.Frames[0].Pixels[0]
+	blueIndex := scene.Frames[1].Pixels[0]
+	assert.NotEqual(t, redIndex, blueIndex)
+	assert.Equal(t, bitmap.RGB{Red: 0xFF}, scene.Palette[redIndex])
+	assert.Equal(t, bitmap.RGB{Blue: 0xFF}, scene.Palette[blueIndex])
+}
+
+func TestSceneFromImagesReservesSpecialColors(t *testing.T) {
+	frames := []ImageFrame{{Image: uniformImage(2, 2, color.RGBA{R: 0x9A, G: 0x35, B: 0x35, A: 0xFF})}}
+
+	scene, err := SceneFromImages(context.Background(), frames, 2, 2)
+
+	require.Nil(t, err)
+	for _, pixel := range scene.Frames[0].Pixels {
+		assert.NotEqual(t, byte(SceneBackgroundColorIndex), pixel)
+		assert.NotEqual(t, byte(SceneSubtitleColorIndex), pixel)
+	}
+	assert.Equal(t, bitmap.RGB{}, scene.Palette[SceneBackgroundColorIndex])
+}
+
+func TestSceneFromImagesLetterboxesWideImages(t *testing.T) {
+	frames := []ImageFrame{{Image: uniformImage(4, 1, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})}}
+
+	scene, err := SceneFromImages(context.Background(), frames, 4, 3)
+
+	require.Nil(t, err)
+	pixels := scene.Frames[0].Pixels
+	assert.Equal(t, []byte{0, 0, 0, 0}, pixels[0:4], "top bar expected")
+	assert.NotEqual(t, byte(0), pixels[4], "image row expected")
+	assert.Equal(t, []byte{0, 0, 0, 0}, pixels[8:12], "bottom bar expected")
+}
+
+func TestSceneFromImagesReducesToPaletteSize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.RGBA{R: byte(x * 4), G: byte(y * 4), B: byte((x + y) * 2), A: 0xFF})
+		}
+	}
+
+	scene, err := SceneFromImages(context.Background(), []ImageFrame{{Image: img}}, 64, 64)
+
+	require.Nil(t, err)
+	for _, pixel := range scene.Frames[0].Pixels {
+		assert.True(t, (pixel > 0) && (pixel < 0xFF), "pixel index out of range")
+	}
+}
+
+func TestSceneFromImagesReturnsErrorOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := SceneFromImages(ctx, []ImageFrame{{Image: uniformImage(2, 2, color.White)}}, 2, 2)
+
+	assert.Equal(t, context.Canceled, err)
+}
+
+func uniformImage(width, height int, clr color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, clr)
+		}
+	}
+	return img
+}
@@ -10,14 +10,35 @@ import (
 
 // Subtitles contains all the subtitles in all languages.
 type Subtitles struct {
-	PerLanguage [resource.LanguageCount]SubtitleList
+	PerLanguage map[resource.Language]SubtitleList
 }
 
 func (sub *Subtitles) add(lang resource.Language, timestamp time.Duration, text string) {
-	sub.PerLanguage[lang].Entries = append(sub.PerLanguage[lang].Entries, Subtitle{
+	if sub.PerLanguage == nil {
+		sub.PerLanguage = make(map[resource.Language]SubtitleList)
+	}
+	list := sub.PerLanguage[lang]
+	list.Entries = append(list.Entries, Subtitle{
 		Timestamp: timestamp,
 		Text:      text,
 	})
+	sub.PerLanguage[lang] = list
+}
+
+// Of returns the subtitles of given language.
+func (sub Subtitles) Of(lang resource.Language) SubtitleList {
+	return sub.PerLanguage[lang]
+}
+
+// Set replaces the subtitles of given language.
+// The map of the languages is copied first, so that other copies of the structure are not modified.
+func (sub *Subtitles) Set(lang resource.Language, list SubtitleList) {
+	perLanguage := make(map[resource.Language]SubtitleList, len(sub.PerLanguage)+1)
+	for otherLang, otherList := range sub.PerLanguage {
+		perLanguage[otherLang] = otherList
+	}
+	perLanguage[lang] = list
+	sub.PerLanguage = perLanguage
 }
 
 // ArePresent returns true if at least one language makes use of subtitles.
@@ -42,11 +63,11 @@ func (sub Subtitles) duration() format.Timestamp {
 	return format.TimestampFromDuration(highest)
 }
 
-func (sub Subtitles) encode(cp text.Codepage) [][]format.EntryBucket {
+func (sub Subtitles) encode(cps text.Codepages) [][]format.EntryBucket {
 	if !sub.ArePresent() {
 		return nil
 	}
-	bucketsPerLanguage := make([][]format.EntryBucket, len(sub.PerLanguage)+1)
+	bucketsPerLanguage := make([][]format.EntryBucket, 1, len(sub.PerLanguage)+1)
 
 	// Ensure a subtitle area is defined.
 	// The area is hardcoded. While the engine respects any area, placing the text in the
@@ -59,12 +80,17 @@ func (sub Subtitles) encode(cp text.Codepage) [][]format.EntryBucket {
 			Timestamp: format.Timestamp{},
 			Data: format.SubtitleEntryData{
 				Control: format.SubtitleArea,
-				Text:    cp.Encode("20 365 620 395 CLR"),
+				Text:    cps.ForLanguage(resource.LangDefault).Encode("20 365 620 395 CLR"),
 			},
 		}},
 	}}
-	for index, lang := range sub.PerLanguage {
-		bucketsPerLanguage[index+1] = lang.encode(format.SubtitleControlForLanguage(resource.Language(index)), cp)
+	for _, lang := range resource.Languages() {
+		list := sub.PerLanguage[lang]
+		control, known := format.SubtitleControlForLanguage(lang)
+		if !known || (len(list.Entries) == 0) {
+			continue
+		}
+		bucketsPerLanguage = append(bucketsPerLanguage, list.encode(control, cps.ForLanguage(lang)))
 	}
 	return bucketsPerLanguage
 }
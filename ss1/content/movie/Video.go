@@ -33,6 +33,11 @@ func (video Video) StartPalette() bitmap.Palette {
 	return video.Scenes[0].palette
 }
 
+// Duration returns the total display time of all scenes.
+func (video Video) Duration() time.Duration {
+	return video.duration().ToDuration()
+}
+
 func (video Video) duration() format.Timestamp {
 	var sum format.Timestamp
 	for _, scene := range video.Scenes {
@@ -13,7 +13,8 @@ import (
 const indexHeaderSizeIncrement = 0x0400
 
 // Write encodes the provided container into the given writer.
-func Write(dest io.Writer, container Container, cp text.Codepage) error {
+// Subtitles are encoded with the codepage of their language.
+func Write(dest io.Writer, container Container, cps text.Codepages) error {
 	var indexEntries []format.IndexTableEntry
 	var header format.Header
 	palette := paletteDataFromContainer(container)
@@ -40,7 +41,7 @@ func Write(dest io.Writer, container Container, cp text.Codepage) error {
 	var buckets []format.EntryBucket
 	buckets = append(buckets, container.Audio.encode()...)
 	buckets = append(buckets, container.Video.encode()...)
-	subtitleBucketsList := container.Subtitles.encode(cp)
+	subtitleBucketsList := container.Subtitles.encode(cps)
 	for _, subtitleBuckets := range subtitleBucketsList {
 		buckets = append(buckets, subtitleBuckets...)
 	}
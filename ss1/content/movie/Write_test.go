@@ -14,7 +14,7 @@ func TestWriteOfEmptyContainerCreatesMinimumSizeData(t *testing.T) {
 	var container Container
 	buffer := bytes.NewBuffer(nil)
 
-	err := Write(buffer, container, text.DefaultCodepage())
+	err := Write(buffer, container, text.NewLanguageCodepages(text.DefaultCodepage()))
 	require.Nil(t, err)
 	assert.Equal(t, 0x0800, len(buffer.Bytes()))
 }
@@ -23,10 +23,10 @@ func TestWriteCanSaveEmptyContainer(t *testing.T) {
 	var container Container
 	buffer := bytes.NewBuffer(nil)
 
-	err := Write(buffer, container, text.DefaultCodepage())
+	err := Write(buffer, container, text.NewLanguageCodepages(text.DefaultCodepage()))
 	require.Nil(t, err)
 
-	_, err = Read(bytes.NewReader(buffer.Bytes()), text.DefaultCodepage())
+	_, err = Read(bytes.NewReader(buffer.Bytes()), text.NewLanguageCodepages(text.DefaultCodepage()))
 
 	require.Nil(t, err)
 }
@@ -38,10 +38,10 @@ func TestWriteSavesAudio(t *testing.T) {
 	container.Audio.Sound.Samples = dataBytes
 	buffer := bytes.NewBuffer(nil)
 
-	err := Write(buffer, container, text.DefaultCodepage())
+	err := Write(buffer, container, text.NewLanguageCodepages(text.DefaultCodepage()))
 	require.Nil(t, err)
 
-	result, err := Read(bytes.NewReader(buffer.Bytes()), text.DefaultCodepage())
+	result, err := Read(bytes.NewReader(buffer.Bytes()), text.NewLanguageCodepages(text.DefaultCodepage()))
 
 	require.Nil(t, err)
 	require.NotNil(t, result)
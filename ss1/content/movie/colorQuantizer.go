@@ -0,0 +1,188 @@
+package movie
+
+import (
+	"image"
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+type colorHistogram struct {
+	counts map[uint32]uint32
+}
+
+type histogramEntry struct {
+	rgb   [3]uint8
+	count uint32
+}
+
+type colorBox struct {
+	entries []histogramEntry
+}
+
+func (hist *colorHistogram) init() {
+	hist.counts = make(map[uint32]uint32)
+}
+
+func (hist *colorHistogram) addImage(img *image.RGBA) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			offset := img.PixOffset(x, y)
+			if img.Pix[offset+3] == 0 {
+				continue
+			}
+			key := uint32(img.Pix[offset])<<16 | uint32(img.Pix[offset+1])<<8 | uint32(img.Pix[offset+2])
+			hist.counts[key]++
+		}
+	}
+}
+
+// medianCut reduces the collected colors to at most the given amount of representatives.
+func (hist colorHistogram) medianCut(limit int) []bitmap.RGB {
+	if len(hist.counts) == 0 {
+		return nil
+	}
+	all := make([]histogramEntry, 0, len(hist.counts))
+	for key, count := range hist.counts {
+		all = append(all, histogramEntry{
+			rgb:   [3]uint8{uint8(key >> 16), uint8(key >> 8), uint8(key)},
+			count: count,
+		})
+	}
+	sort.Slice(all, func(a, b int) bool { return all[a].key() < all[b].key() })
+
+	boxes := []colorBox{{entries: all}}
+	for len(boxes) < limit {
+		splitIndex := -1
+		var splitScore uint64
+		for index, box := range boxes {
+			if len(box.entries) < 2 {
+				continue
+			}
+			_, spread := box.widestChannel()
+			score := uint64(spread) * uint64(box.population())
+			if (splitIndex < 0) || (score > splitScore) {
+				splitIndex = index
+				splitScore = score
+			}
+		}
+		if splitIndex < 0 {
+			break
+		}
+		first, second := boxes[splitIndex].split()
+		boxes[splitIndex] = first
+		boxes = append(boxes, second)
+	}
+
+	result := make([]bitmap.RGB, len(boxes))
+	for index, box := range boxes {
+		result[index] = box.average()
+	}
+	return result
+}
+
+func (entry histogramEntry) key() uint32 {
+	return uint32(entry.rgb[0])<<16 | uint32(entry.rgb[1])<<8 | uint32(entry.rgb[2])
+}
+
+func (box colorBox) population() uint32 {
+	var sum uint32
+	for _, entry := range box.entries {
+		sum += entry.count
+	}
+	return sum
+}
+
+func (box colorBox) widestChannel() (channel int, spread int) {
+	for ch := 0; ch < 3; ch++ {
+		low, high := 0xFF, 0x00
+		for _, entry := range box.entries {
+			value := int(entry.rgb[ch])
+			if value < low {
+				low = value
+			}
+			if value > high {
+				high = value
+			}
+		}
+		if (high - low) > spread {
+			channel = ch
+			spread = high - low
+		}
+	}
+	return
+}
+
+func (box colorBox) split() (colorBox, colorBox) {
+	channel, _ := box.widestChannel()
+	entries := make([]histogramEntry, len(box.entries))
+	copy(entries, box.entries)
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].rgb[channel] < entries[b].rgb[channel] })
+
+	half := box.population() / 2
+	var sum uint32
+	cut := 1
+	for index, entry := range entries[:len(entries)-1] {
+		sum += entry.count
+		cut = index + 1
+		if sum >= half {
+			break
+		}
+	}
+	return colorBox{entries: entries[:cut]}, colorBox{entries: entries[cut:]}
+}
+
+func (box colorBox) average() bitmap.RGB {
+	var sums [3]uint64
+	var total uint64
+	for _, entry := range box.entries {
+		for ch := 0; ch < 3; ch++ {
+			sums[ch] += uint64(entry.rgb[ch]) * uint64(entry.count)
+		}
+		total += uint64(entry.count)
+	}
+	if total == 0 {
+		return bitmap.RGB{}
+	}
+	return bitmap.RGB{
+		Red:   uint8((sums[0] + total/2) / total),
+		Green: uint8((sums[1] + total/2) / total),
+		Blue:  uint8((sums[2] + total/2) / total),
+	}
+}
+
+// nearestColorMapper maps colors to the closest entry of a palette, caching its results.
+type nearestColorMapper struct {
+	colors      []bitmap.RGB
+	baseIndex   int
+	lookupByRGB map[uint32]byte
+}
+
+func newNearestColorMapper(colors []bitmap.RGB, baseIndex int) *nearestColorMapper {
+	return &nearestColorMapper{
+		colors:      colors,
+		baseIndex:   baseIndex,
+		lookupByRGB: make(map[uint32]byte),
+	}
+}
+
+func (mapper *nearestColorMapper) indexOf(r, g, b uint8) byte {
+	key := uint32(r)<<16 | uint32(g)<<8 | uint32(b)
+	if index, known := mapper.lookupByRGB[key]; known {
+		return index
+	}
+	square := func(value int) int { return value * value }
+	closestIndex := 0
+	closestDistance := -1
+	for index, clr := range mapper.colors {
+		distance := square(int(clr.Red)-int(r))*3 + square(int(clr.Green)-int(g))*4 + square(int(clr.Blue)-int(b))*2
+		if (closestDistance < 0) || (distance < closestDistance) {
+			closestIndex = index
+			closestDistance = distance
+		}
+	}
+	result := byte(mapper.baseIndex + closestIndex)
+	mapper.lookupByRGB[key] = result
+	return result
+}
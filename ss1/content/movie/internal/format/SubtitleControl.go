@@ -1,6 +1,10 @@
 package format
 
-import "github.com/inkyblackness/hacked/ss1/resource"
+import (
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
 
 // SubtitleControl specifies how to interpret a subtitle entry.
 type SubtitleControl uint32
@@ -19,15 +23,25 @@ func (ctrl SubtitleControl) String() string {
 }
 
 // SubtitleControlForLanguage returns the corresponding subtitle control for given language.
-func SubtitleControlForLanguage(lang resource.Language) SubtitleControl {
-	switch lang {
-	case resource.LangDefault:
-		return SubtitleTextStd
-	case resource.LangFrench:
-		return SubtitleTextFrn
-	case resource.LangGerman:
-		return SubtitleTextGer
-	default:
-		panic("unknown language")
+// The control is made of the language code, followed by a space. Returns false for unknown languages.
+func SubtitleControlForLanguage(lang resource.Language) (SubtitleControl, bool) {
+	info, known := lang.Info()
+	if !known {
+		return SubtitleArea, false
+	}
+	var ctrl SubtitleControl
+	for index, r := range []byte(info.Code + " ") {
+		ctrl |= SubtitleControl(r) << (uint(index) * 8)
+	}
+	return ctrl, true
+}
+
+// LanguageForSubtitleControl returns the language the given control is for.
+// Returns false if the control is not a text control of a registered language.
+func LanguageForSubtitleControl(ctrl SubtitleControl) (resource.Language, bool) {
+	code := ctrl.String()
+	if (ctrl == SubtitleArea) || !strings.HasSuffix(code, " ") {
+		return resource.LangAny, false
 	}
+	return resource.LanguageWithCode(strings.TrimSuffix(code, " "))
 }
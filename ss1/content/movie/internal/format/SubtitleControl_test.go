@@ -0,0 +1,45 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/format"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestSubtitleControlForBuiltinLanguages(t *testing.T) {
+	tt := []struct {
+		lang     resource.Language
+		expected format.SubtitleControl
+	}{
+		{resource.LangDefault, format.SubtitleTextStd},
+		{resource.LangFrench, format.SubtitleTextFrn},
+		{resource.LangGerman, format.SubtitleTextGer},
+	}
+	for _, tc := range tt {
+		ctrl, known := format.SubtitleControlForLanguage(tc.lang)
+		assert.True(t, known, "language should be known")
+		assert.Equal(t, tc.expected, ctrl)
+		lang, isText := format.LanguageForSubtitleControl(ctrl)
+		assert.True(t, isText, "control should be a text control")
+		assert.Equal(t, tc.lang, lang)
+	}
+}
+
+func TestSubtitleControlForRegisteredLanguage(t *testing.T) {
+	defer resource.ResetLanguages()
+	lang, _ := resource.RegisterLanguage(resource.LanguageInfo{Name: "Polish", Code: "POL"})
+	ctrl, known := format.SubtitleControlForLanguage(lang)
+	assert.True(t, known, "language should be known")
+	assert.Equal(t, "POL ", ctrl.String())
+	result, isText := format.LanguageForSubtitleControl(ctrl)
+	assert.True(t, isText, "control should be a text control")
+	assert.Equal(t, lang, result)
+}
+
+func TestLanguageForSubtitleControlIgnoresArea(t *testing.T) {
+	_, isText := format.LanguageForSubtitleControl(format.SubtitleArea)
+	assert.False(t, isText)
+}
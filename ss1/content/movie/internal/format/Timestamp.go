@@ -31,15 +31,16 @@ func TimestampFromSeconds(value float32) Timestamp {
 }
 
 // TimestampFromDuration creates a timestamp instance from given duration value.
+// The duration is rounded to the nearest fraction, which makes the conversion the inverse of ToDuration().
 func TimestampFromDuration(d time.Duration) Timestamp {
 	limit := TimestampLimit()
 	if d >= limit.ToDuration() {
 		return limit
 	}
-	return Timestamp{
-		Second:   uint8(d / time.Second),
-		Fraction: uint16(((d - time.Second) * fractionDivisor) / time.Second),
+	if d <= 0 {
+		return Timestamp{}
 	}
+	return timestampFromLinear(uint32(((d * fractionDivisor) + time.Second/2) / time.Second))
 }
 
 // ToDuration returns the equivalent duration for this timestamp.
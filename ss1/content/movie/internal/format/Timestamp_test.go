@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -25,15 +26,31 @@ func TestTimeToRawB(t *testing.T) {
 }
 
 func TestDurationConversion(t *testing.T) {
-	for fraction := 0; fraction <= math.MaxUint16; fraction++ {
-		ts := format.Timestamp{Second: 0, Fraction: uint16(fraction)}
-		dur := ts.ToDuration()
-		ts2 := format.TimestampFromDuration(dur)
+	for _, second := range []uint8{0, 1, 2, 100, math.MaxUint8} {
+		for fraction := 0; fraction <= math.MaxUint16; fraction++ {
+			ts := format.Timestamp{Second: second, Fraction: uint16(fraction)}
+			dur := ts.ToDuration()
+			ts2 := format.TimestampFromDuration(dur)
 
-		assert.Equal(t, ts, ts2, fmt.Sprintf("Mismatch for fraction %v", fraction))
+			if ts != ts2 {
+				assert.Fail(t, fmt.Sprintf("Mismatch for second %v, fraction %v: %v", second, fraction, ts2))
+				return
+			}
+		}
 	}
 }
 
+func TestTimestampFromDurationRoundsToNearestFraction(t *testing.T) {
+	assert.Equal(t, format.Timestamp{Second: 1, Fraction: 0x8000}, format.TimestampFromDuration(1500*time.Millisecond))
+	assert.Equal(t, format.Timestamp{Second: 0, Fraction: 0x0000}, format.TimestampFromDuration(7*time.Microsecond))
+	assert.Equal(t, format.Timestamp{Second: 0, Fraction: 0x0001}, format.TimestampFromDuration(8*time.Microsecond))
+	assert.Equal(t, format.Timestamp{Second: 3, Fraction: 0x0000}, format.TimestampFromDuration(3*time.Second-time.Microsecond))
+}
+
+func TestTimestampFromDurationClipsNegativeValues(t *testing.T) {
+	assert.Equal(t, format.Timestamp{}, format.TimestampFromDuration(-time.Second))
+}
+
 func TestTimestampPlus(t *testing.T) {
 	tt := []struct {
 		a        format.Timestamp
@@ -12,7 +12,7 @@ type textReader func(resource.Selector, resource.Key, Codepage) (string, error)
 
 // Cache retrieves texts from a localizer and keeps them decoded until they are invalidated.
 type Cache struct {
-	cp        Codepage
+	cps       Codepages
 	localizer resource.Localizer
 	reader    textReader
 
@@ -20,9 +20,9 @@ type Cache struct {
 	texts       map[resource.Key]string
 }
 
-func newCache(cp Codepage, localizer resource.Localizer, keyResolver keyResolver, reader textReader) *Cache {
+func newCache(cps Codepages, localizer resource.Localizer, keyResolver keyResolver, reader textReader) *Cache {
 	cache := &Cache{
-		cp:        cp,
+		cps:       cps,
 		localizer: localizer,
 		reader:    reader,
 
@@ -33,13 +33,15 @@ func newCache(cp Codepage, localizer resource.Localizer, keyResolver keyResolver
 }
 
 // NewLineCache returns a cache for single-block texts.
-func NewLineCache(cp Codepage, localizer resource.Localizer) *Cache {
-	return newCache(cp, localizer, func(key resource.Key) resource.Key { return key }, readLine)
+// The texts of each language are decoded with the codepage of that language.
+func NewLineCache(cps Codepages, localizer resource.Localizer) *Cache {
+	return newCache(cps, localizer, func(key resource.Key) resource.Key { return key }, readLine)
 }
 
 // NewPageCache returns a cache for resource-based texts.
-func NewPageCache(cp Codepage, localizer resource.Localizer) *Cache {
-	return newCache(cp, localizer, func(key resource.Key) resource.Key {
+// The texts of each language are decoded with the codepage of that language.
+func NewPageCache(cps Codepages, localizer resource.Localizer) *Cache {
+	return newCache(cps, localizer, func(key resource.Key) resource.Key {
 		return resource.KeyOf(key.ID.Plus(key.Index), key.Lang, 0)
 	}, readPage)
 }
@@ -98,6 +100,11 @@ func (cache *Cache) InvalidateResources(ids []resource.ID) {
 	}
 }
 
+// InvalidateAll lets the cache remove all texts. This is necessary if the codepages changed.
+func (cache *Cache) InvalidateAll() {
+	cache.texts = make(map[resource.Key]string)
+}
+
 // Text retrieves and caches the text of given key.
 func (cache *Cache) Text(key resource.Key) (string, error) {
 	cacheKey := cache.keyResolver(key)
@@ -106,7 +113,7 @@ func (cache *Cache) Text(key resource.Key) (string, error) {
 		return value, nil
 	}
 	selector := cache.localizer.LocalizedResources(key.Lang)
-	value, err := cache.reader(selector, key, cache.cp)
+	value, err := cache.reader(selector, key, cache.cps.ForLanguage(key.Lang))
 	if err != nil {
 		return "", err
 	}
@@ -115,11 +115,11 @@ func (suite *CacheSuite) TestTextReturnsErrorIfResourceIsNotATextForPageCache()
 }
 
 func (suite *CacheSuite) givenALineCache() {
-	suite.instance = text.NewLineCache(suite.cp, suite)
+	suite.instance = text.NewLineCache(text.NewLanguageCodepages(suite.cp), suite)
 }
 
 func (suite *CacheSuite) givenAPageCache() {
-	suite.instance = text.NewPageCache(suite.cp, suite)
+	suite.instance = text.NewPageCache(text.NewLanguageCodepages(suite.cp), suite)
 }
 
 func (suite *CacheSuite) givenResourcesAre(resources ...resource.LocalizedResources) {
@@ -8,3 +8,21 @@ type Codepage interface {
 	// Decode converts the provided byte slice to a string. It ignores trailing 0x00 bytes.
 	Decode(data []byte) string
 }
+
+// Unencodable returns the characters of given string that the codepage can not represent.
+// Each character is listed only once, in order of their first occurrence.
+func Unencodable(cp Codepage, value string) []rune {
+	var result []rune
+	known := make(map[rune]bool)
+	for _, r := range value {
+		if known[r] {
+			continue
+		}
+		known[r] = true
+		decoded := []rune(cp.Decode(cp.Encode(string(r))))
+		if (len(decoded) != 1) || (decoded[0] != r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package text
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LoadCodepage reads a codepage definition from given reader.
+//
+// Each line maps one byte to a Unicode code point, both written in hexadecimal, such as "0x80 0x0410".
+// Anything after a '#' is a comment; empty lines are ignored. This is compatible to the mapping
+// files provided by the Unicode Consortium.
+// Bytes that are not listed keep the mapping of the default codepage. The byte 0x00 can not be mapped,
+// as it terminates texts.
+func LoadCodepage(reader io.Reader) (Codepage, error) {
+	table := cp437ToRune
+	mapped := make(map[byte]bool)
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if commentStart := strings.IndexRune(line, '#'); commentStart >= 0 {
+			line = line[:commentStart]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected byte and code point", lineNumber)
+		}
+		value, err := parseHex(fields[0], 8)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid byte <%s>", lineNumber, fields[0])
+		}
+		codePoint, err := parseHex(fields[1], 32)
+		if (err != nil) || (codePoint > 0x10FFFF) {
+			return nil, fmt.Errorf("line %d: invalid code point <%s>", lineNumber, fields[1])
+		}
+		if value == 0x00 {
+			return nil, fmt.Errorf("line %d: byte 0x00 can not be mapped", lineNumber)
+		}
+		if codePoint == 0x00 {
+			return nil, fmt.Errorf("line %d: code point 0x00 can not be mapped", lineNumber)
+		}
+		if mapped[byte(value)] {
+			return nil, fmt.Errorf("line %d: byte 0x%02X mapped more than once", lineNumber, value)
+		}
+		mapped[byte(value)] = true
+		table[value] = rune(codePoint)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewTabledCodepage(table), nil
+}
+
+func parseHex(value string, bitSize int) (uint64, error) {
+	lowercase := strings.ToLower(value)
+	if !strings.HasPrefix(lowercase, "0x") {
+		return 0, fmt.Errorf("missing 0x prefix")
+	}
+	return strconv.ParseUint(lowercase[2:], 16, bitSize)
+}
@@ -0,0 +1,67 @@
+package text_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestLoadCodepageMapsListedBytes(t *testing.T) {
+	source := "# Cyrillic patch\n" +
+		"0x80\t0x0410\t# CYRILLIC CAPITAL LETTER A\n" +
+		"\n" +
+		"0xA0 0x0430\n"
+	cp, err := text.LoadCodepage(strings.NewReader(source))
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []byte{0x80, 0xA0, 0x00}, cp.Encode("Аа"))
+	assert.Equal(t, "Аа", cp.Decode([]byte{0x80, 0xA0}))
+}
+
+func TestLoadCodepageKeepsDefaultForUnlistedBytes(t *testing.T) {
+	cp, err := text.LoadCodepage(strings.NewReader("0x80 0x0410\n"))
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, "Hü", cp.Decode([]byte{0x48, 0x81}))
+	assert.Equal(t, []rune{'Ç'}, text.Unencodable(cp, "Ç"), "replaced character should no longer be encodable")
+}
+
+func TestLoadCodepageReportsErrors(t *testing.T) {
+	tt := []struct {
+		name   string
+		source string
+	}{
+		{name: "missing value", source: "0x80\n"},
+		{name: "invalid byte", source: "0x100 0x0410\n"},
+		{name: "missing prefix", source: "80 0x0410\n"},
+		{name: "invalid code point", source: "0x80 0x110000\n"},
+		{name: "terminator byte", source: "0x00 0x0410\n"},
+		{name: "terminator code point", source: "0x80 0x0000\n"},
+		{name: "duplicate byte", source: "0x80 0x0410\n0x80 0x0411\n"},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := text.LoadCodepage(strings.NewReader(td.source))
+			assert.NotNil(t, err, "error expected")
+		})
+	}
+}
+
+func TestLanguageCodepagesUseFallbackWithoutAssignment(t *testing.T) {
+	fallback := text.DefaultCodepage()
+	custom, _ := text.LoadCodepage(strings.NewReader("0x80 0x0410\n"))
+	cps := text.NewLanguageCodepages(fallback)
+
+	cps.Assign(resource.LangGerman, custom)
+	assert.Equal(t, custom, cps.ForLanguage(resource.LangGerman))
+	assert.Equal(t, fallback, cps.ForLanguage(resource.LangDefault))
+
+	cps.Assign(resource.LangGerman, nil)
+	assert.Equal(t, fallback, cps.ForLanguage(resource.LangGerman))
+}
@@ -0,0 +1,21 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+func TestUnencodableReturnsNilForRepresentableText(t *testing.T) {
+	result := text.Unencodable(text.DefaultCodepage(), "Grüße, Ça va?\n")
+
+	assert.Nil(t, result)
+}
+
+func TestUnencodableReturnsUnknownCharactersOnce(t *testing.T) {
+	result := text.Unencodable(text.DefaultCodepage(), "„Łódź” ł„")
+
+	assert.Equal(t, []rune{'„', 'Ł', 'ź', '”', 'ł'}, result)
+}
@@ -0,0 +1,40 @@
+package text
+
+import "github.com/inkyblackness/hacked/ss1/resource"
+
+// Codepages provides the codepage that the texts of a language are stored with.
+type Codepages interface {
+	ForLanguage(lang resource.Language) Codepage
+}
+
+// LanguageCodepages assigns codepages to languages.
+// Languages without an assignment use a fallback codepage.
+type LanguageCodepages struct {
+	fallback    Codepage
+	perLanguage map[resource.Language]Codepage
+}
+
+// NewLanguageCodepages returns a new instance with the given fallback.
+func NewLanguageCodepages(fallback Codepage) *LanguageCodepages {
+	return &LanguageCodepages{
+		fallback:    fallback,
+		perLanguage: make(map[resource.Language]Codepage),
+	}
+}
+
+// ForLanguage returns the codepage assigned to the given language.
+func (cps *LanguageCodepages) ForLanguage(lang resource.Language) Codepage {
+	if cp, assigned := cps.perLanguage[lang]; assigned {
+		return cp
+	}
+	return cps.fallback
+}
+
+// Assign sets the codepage for given language. A nil codepage removes the assignment.
+func (cps *LanguageCodepages) Assign(lang resource.Language, cp Codepage) {
+	if cp == nil {
+		delete(cps.perLanguage, lang)
+		return
+	}
+	cps.perLanguage[lang] = cp
+}
@@ -8,16 +8,17 @@ import (
 
 // ElectronicMessageCache retrieves messages from a localizer and keeps them decoded until they are invalidated.
 type ElectronicMessageCache struct {
-	cp        Codepage
+	cps       Codepages
 	localizer resource.Localizer
 
 	messages map[resource.Key]ElectronicMessage
 }
 
 // NewElectronicMessageCache returns a new instance.
-func NewElectronicMessageCache(cp Codepage, localizer resource.Localizer) *ElectronicMessageCache {
+// The messages of each language are decoded with the codepage of that language.
+func NewElectronicMessageCache(cps Codepages, localizer resource.Localizer) *ElectronicMessageCache {
 	cache := &ElectronicMessageCache{
-		cp:        cp,
+		cps:       cps,
 		localizer: localizer,
 
 		messages: make(map[resource.Key]ElectronicMessage),
@@ -36,6 +37,11 @@ func (cache *ElectronicMessageCache) InvalidateResources(ids []resource.ID) {
 	}
 }
 
+// InvalidateAll lets the cache remove all messages. This is necessary if the codepages changed.
+func (cache *ElectronicMessageCache) InvalidateAll() {
+	cache.messages = make(map[resource.Key]ElectronicMessage)
+}
+
 // Message retrieves and caches the message of given key.
 func (cache *ElectronicMessageCache) Message(key resource.Key) (ElectronicMessage, error) {
 	cacheKey := resource.KeyOf(key.ID.Plus(key.Index), key.Lang, 0)
@@ -51,7 +57,7 @@ func (cache *ElectronicMessageCache) Message(key resource.Key) (ElectronicMessag
 	if (view.ContentType() != resource.Text) || !view.Compound() {
 		return EmptyElectronicMessage(), errors.New("invalid resource type")
 	}
-	value, err = DecodeElectronicMessage(cache.cp, view)
+	value, err = DecodeElectronicMessage(cache.cps.ForLanguage(key.Lang), view)
 	if err != nil {
 		return EmptyElectronicMessage(), err
 	}
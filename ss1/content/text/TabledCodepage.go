@@ -5,6 +5,16 @@ type tabledCodepage struct {
 	tableToByte map[rune]byte
 }
 
+// NewTabledCodepage returns a Codepage that maps each byte to the rune at the corresponding index of the table.
+// If a rune is listed more than once, it is encoded with the highest byte.
+func NewTabledCodepage(table [256]rune) Codepage {
+	tableToByte := make(map[rune]byte)
+	for i, r := range &table {
+		tableToByte[r] = byte(i)
+	}
+	return &tabledCodepage{tableToRune: table[:], tableToByte: tableToByte}
+}
+
 func (cp *tabledCodepage) Encode(value string) []byte {
 	result := make([]byte, 0, len(value)+1)
 
@@ -55,6 +55,11 @@ func (service AugmentedTextService) Text(key resource.Key) string {
 	return service.textViewer.Text(key)
 }
 
+// Unencodable returns the characters of the given value that can not be stored in the language of the key.
+func (service AugmentedTextService) Unencodable(key resource.Key, value string) []rune {
+	return service.textSetter.Unencodable(key, value)
+}
+
 // SetText changes the textual value of a text resource.
 func (service AugmentedTextService) SetText(setter AugmentedTextBlockSetter, key resource.Key, value string) {
 	service.textSetter.Set(setter, key, value)
@@ -12,17 +12,17 @@ import (
 
 // MovieService provides read/write functionality.
 type MovieService struct {
-	cp text.Codepage
+	cps text.Codepages
 
 	movieViewer media.MovieViewerService
 	movieSetter media.MovieSetterService
 }
 
 // NewMovieService returns a new instance based on given accessor.
-func NewMovieService(codepage text.Codepage,
+func NewMovieService(cps text.Codepages,
 	movieViewer media.MovieViewerService, movieSetter media.MovieSetterService) MovieService {
 	return MovieService{
-		cp: codepage,
+		cps: cps,
 
 		movieViewer: movieViewer,
 		movieSetter: movieSetter,
@@ -91,6 +91,68 @@ func (service MovieService) AddScene(setter media.MovieBlockSetter, key resource
 	service.movieSetter.Set(setter, key, baseContainer)
 }
 
+// AddSceneWithMedia adds the given scene at the end of the movie, together with accompanying media.
+// A non-empty sound is placed at the start of the new scene, replacing any existing audio from that point on.
+// The sound is converted to the sample rate of existing audio.
+// Subtitles are shifted to the start of the new scene and replace any existing subtitles from that point on.
+// The returned clipping information reports any samples that were limited during the conversion.
+func (service MovieService) AddSceneWithMedia(setter media.MovieBlockSetter, key resource.Key,
+	scene movie.HighResScene, sound audio.L8, subtitles map[resource.Language]movie.SubtitleList) audio.Clipping {
+	var clip audio.Clipping
+	baseContainer := service.getBaseContainer(key)
+	sceneStart := baseContainer.Video.Duration()
+	baseContainer.Video.Scenes = append(baseContainer.Video.Scenes, scene)
+	if !sound.Empty() {
+		baseContainer.Audio.Sound, clip = soundAppendedAt(baseContainer.Audio.Sound, sceneStart, sound)
+	}
+	for lang, list := range subtitles {
+		var newList movie.SubtitleList
+		for _, entry := range baseContainer.Subtitles.Of(lang).Entries {
+			if entry.Timestamp < sceneStart {
+				newList.Entries = append(newList.Entries, entry)
+			}
+		}
+		for _, entry := range list.Entries {
+			newList.Entries = append(newList.Entries, movie.Subtitle{
+				Timestamp: sceneStart + entry.Timestamp,
+				Text:      entry.Text,
+			})
+		}
+		baseContainer.Subtitles.Set(lang, newList)
+	}
+	service.movieSetter.Set(setter, key, baseContainer)
+	return clip
+}
+
+// SoundForScene returns the given sound converted to the sample rate of the existing audio of the movie.
+// The returned clipping information reports any samples that were limited during the conversion.
+func (service MovieService) SoundForScene(key resource.Key, sound audio.L8) (audio.L8, audio.Clipping) {
+	return soundResampledFor(service.movieViewer.Audio(key), sound)
+}
+
+func soundResampledFor(base audio.L8, sound audio.L8) (audio.L8, audio.Clipping) {
+	if base.Empty() || (base.SampleRate <= 0) || (sound.SampleRate == base.SampleRate) {
+		return sound, audio.Clipping{}
+	}
+	return sound.ToF32().Resampled(base.SampleRate).ToL8()
+}
+
+func soundAppendedAt(base audio.L8, start time.Duration, sound audio.L8) (audio.L8, audio.Clipping) {
+	sound, clip := soundResampledFor(base, sound)
+	sampleRate := sound.SampleRate
+	startOffset := 0
+	if start > 0 {
+		startOffset = int((start * time.Duration(sampleRate)) / time.Second)
+	}
+	samples := make([]byte, startOffset, startOffset+len(sound.Samples))
+	copy(samples, base.Samples)
+	for index := len(base.Samples); index < startOffset; index++ {
+		samples[index] = 0x80
+	}
+	samples = append(samples, sound.Samples...)
+	return audio.L8{SampleRate: sampleRate, Samples: samples}, clip
+}
+
 // RemoveScene cuts out the given scene from the movie.
 func (service MovieService) RemoveScene(setter media.MovieBlockSetter, key resource.Key, scene int) {
 	baseContainer := service.getBaseContainer(key)
@@ -136,7 +198,17 @@ func (service MovieService) Subtitles(key resource.Key, language resource.Langua
 func (service MovieService) SetSubtitles(setter media.MovieBlockSetter, key resource.Key,
 	language resource.Language, subtitles movie.SubtitleList) {
 	baseContainer := service.getBaseContainer(key)
-	baseContainer.Subtitles.PerLanguage[language] = subtitles
+	baseContainer.Subtitles.Set(language, subtitles)
+	service.movieSetter.Set(setter, key, baseContainer)
+}
+
+// SetSubtitlesPerLanguage sets the subtitles of identified movie for several languages at once.
+func (service MovieService) SetSubtitlesPerLanguage(setter media.MovieBlockSetter, key resource.Key,
+	subtitles map[resource.Language]movie.SubtitleList) {
+	baseContainer := service.getBaseContainer(key)
+	for language, list := range subtitles {
+		baseContainer.Subtitles.Set(language, list)
+	}
 	service.movieSetter.Set(setter, key, baseContainer)
 }
 
@@ -0,0 +1,199 @@
+package edit
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+var searchableMovies = []struct {
+	id    resource.ID
+	title string
+}{
+	{id: ids.MovieIntro, title: "Intro"},
+	{id: ids.MovieDeath, title: "Death"},
+	{id: ids.MovieEnd, title: "End"},
+}
+
+// SearchLocation identifies one string in one language.
+type SearchLocation struct {
+	// Key identifies the string. For subtitles, the ID is that of the movie, and Index that of the entry.
+	Key TranslationKey
+	// Lang is the language of the string.
+	Lang resource.Language
+	// Subtitle is set if the string is an entry of the subtitles of a movie.
+	Subtitle bool
+}
+
+// MovieKey returns the key of the movie the subtitle is stored in.
+// Movies that are stored per language contain the subtitles of their language.
+func (loc SearchLocation) MovieKey() resource.Key {
+	lang := resource.LangDefault
+	info, _ := ids.Info(loc.Key.ID)
+	if _, localized := info.ResFile.(resource.I18nFile); localized {
+		lang = loc.Lang
+	}
+	return resource.KeyOf(loc.Key.ID, lang, 0)
+}
+
+// SearchQuery describes what to look for, and what to replace it with.
+type SearchQuery struct {
+	Pattern *regexp.Regexp
+	// Replacement is the text for each match. Unless Literal is set, it can refer to groups, such as "$1".
+	Replacement string
+	Literal     bool
+}
+
+func (query SearchQuery) replaced(value string) string {
+	if query.Literal {
+		return query.Pattern.ReplaceAllLiteralString(value, query.Replacement)
+	}
+	return query.Pattern.ReplaceAllString(value, query.Replacement)
+}
+
+// SearchMatch is a string that matches a query.
+type SearchMatch struct {
+	Location SearchLocation
+	Context  string
+	Text     string
+	// Replaced is the string after replacing all matches of the query.
+	Replaced string
+}
+
+// SearchService finds and replaces strings across all texts, messages, and subtitles, in all languages.
+type SearchService struct {
+	translations TranslationService
+	movies       MovieService
+}
+
+// NewSearchService returns a new instance.
+func NewSearchService(translations TranslationService, movies MovieService) SearchService {
+	return SearchService{
+		translations: translations,
+		movies:       movies,
+	}
+}
+
+// Find returns all strings that match the query.
+func (service SearchService) Find(query SearchQuery) []SearchMatch {
+	var matches []SearchMatch
+	add := func(loc SearchLocation, context string, value string) {
+		if query.Pattern.MatchString(value) {
+			matches = append(matches, SearchMatch{
+				Location: loc,
+				Context:  context,
+				Text:     value,
+				Replaced: query.replaced(value),
+			})
+		}
+	}
+	for _, lang := range resource.Languages() {
+		for _, entry := range service.translations.Entries(lang, lang) {
+			add(SearchLocation{Key: entry.Key, Lang: lang}, entry.Context, entry.Source)
+		}
+	}
+	for _, lang := range resource.Languages() {
+		for _, info := range searchableMovies {
+			loc := SearchLocation{Key: TranslationKey{ID: info.id}, Lang: lang, Subtitle: true}
+			subtitles := service.movies.Subtitles(loc.MovieKey(), lang)
+			for index, entry := range subtitles.Entries {
+				loc.Key.Index = index
+				add(loc, fmt.Sprintf("%s Movie Subtitle #%d", info.title, index), entry.Text)
+			}
+		}
+	}
+	return matches
+}
+
+// Unchanged returns those of the given matches of which the string was not modified since they were found.
+func (service SearchService) Unchanged(matches []SearchMatch) []SearchMatch {
+	var unchanged []SearchMatch
+	for _, match := range matches {
+		if service.currentText(match.Location) == match.Text {
+			unchanged = append(unchanged, match)
+		}
+	}
+	return unchanged
+}
+
+func (service SearchService) currentText(loc SearchLocation) string {
+	if !loc.Subtitle {
+		return service.translations.Text(loc.Lang, loc.Key)
+	}
+	entries := service.movies.Subtitles(loc.MovieKey(), loc.Lang).Entries
+	if (loc.Key.Index < 0) || (loc.Key.Index >= len(entries)) {
+		return ""
+	}
+	return entries[loc.Key.Index].Text
+}
+
+// Replace sets the replaced strings of all given matches.
+// Matches of which the string was modified since they were found are skipped, so that no changes are lost.
+func (service SearchService) Replace(setter media.TextBlockSetter, matches []SearchMatch) {
+	entries := make(map[resource.Language][]TranslationEntry)
+	subtitles := make(map[resource.Key]map[resource.Language]movie.SubtitleList)
+	var movieKeys []resource.Key
+	for _, match := range service.Unchanged(matches) {
+		loc := match.Location
+		if !loc.Subtitle {
+			entries[loc.Lang] = append(entries[loc.Lang], TranslationEntry{Key: loc.Key, Target: match.Replaced})
+			continue
+		}
+		movieKey := loc.MovieKey()
+		perLanguage, existing := subtitles[movieKey]
+		if !existing {
+			perLanguage = make(map[resource.Language]movie.SubtitleList)
+			subtitles[movieKey] = perLanguage
+			movieKeys = append(movieKeys, movieKey)
+		}
+		list, existing := perLanguage[loc.Lang]
+		if !existing {
+			current := service.movies.Subtitles(movieKey, loc.Lang)
+			list.Entries = append([]movie.Subtitle{}, current.Entries...)
+		}
+		if (loc.Key.Index >= 0) && (loc.Key.Index < len(list.Entries)) {
+			list.Entries[loc.Key.Index].Text = match.Replaced
+		}
+		perLanguage[loc.Lang] = list
+	}
+	for _, lang := range resource.Languages() {
+		if len(entries[lang]) > 0 {
+			service.translations.Apply(setter, lang, entries[lang])
+		}
+	}
+	for _, movieKey := range movieKeys {
+		service.movies.SetSubtitlesPerLanguage(setter, movieKey, subtitles[movieKey])
+	}
+}
+
+// RestoreFunc creates a snapshot of all strings the given matches refer to, and returns a function to restore them.
+func (service SearchService) RestoreFunc(matches []SearchMatch) func(setter media.TextBlockSetter) {
+	var restorers []func(setter media.TextBlockSetter)
+	entries := make(map[resource.Language][]TranslationEntry)
+	moviesCovered := make(map[resource.Key]bool)
+	for _, match := range matches {
+		loc := match.Location
+		if !loc.Subtitle {
+			entries[loc.Lang] = append(entries[loc.Lang], TranslationEntry{Key: loc.Key})
+			continue
+		}
+		movieKey := loc.MovieKey()
+		if !moviesCovered[movieKey] {
+			moviesCovered[movieKey] = true
+			restore := service.movies.RestoreFunc(movieKey)
+			restorers = append(restorers, func(setter media.TextBlockSetter) { restore(setter) })
+		}
+	}
+	for lang, langEntries := range entries {
+		restorers = append(restorers, service.translations.RestoreFunc(lang, langEntries))
+	}
+	return func(setter media.TextBlockSetter) {
+		for _, restore := range restorers {
+			restore(setter)
+		}
+	}
+}
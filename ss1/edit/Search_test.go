@@ -0,0 +1,126 @@
+package edit_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+type searchFixture struct {
+	mod        *world.Mod
+	textViewer media.TextViewerService
+	textSetter media.TextSetterService
+	search     edit.SearchService
+}
+
+func newSearchFixture() *searchFixture {
+	cps := text.NewLanguageCodepages(text.DefaultCodepage())
+	var caches []interface{ InvalidateResources([]resource.ID) }
+	mod := world.NewMod(func(modified []resource.ID, _ []resource.ID) {
+		for _, cache := range caches {
+			cache.InvalidateResources(modified)
+		}
+	}, func() {})
+	mod.Reset(nil, object.StandardPropertiesTable(), make(texture.PropertiesList, 4))
+	lines := text.NewLineCache(cps, mod)
+	pages := text.NewPageCache(cps, mod)
+	messages := text.NewElectronicMessageCache(cps, mod)
+	movies := movie.NewCache(cps, mod)
+	caches = append(caches, lines, pages, messages, movies)
+
+	fixture := &searchFixture{
+		mod:        mod,
+		textViewer: media.NewTextViewerService(lines, pages, mod),
+		textSetter: media.NewTextSetterService(cps),
+	}
+	translations := edit.NewTranslationService(cps, fixture.textViewer, fixture.textSetter, messages, mod)
+	movieService := edit.NewMovieService(cps, media.NewMovieViewerService(movies, mod), media.NewMovieSetterService(cps))
+	fixture.search = edit.NewSearchService(translations, movieService)
+	return fixture
+}
+
+func (fixture *searchFixture) setText(index int, value string) {
+	fixture.mod.Modify(func(modder world.Modder) {
+		fixture.textSetter.Set(modder, fixture.keyOf(index), value)
+	})
+}
+
+func (fixture *searchFixture) textOf(index int) string {
+	return fixture.textViewer.Text(fixture.keyOf(index))
+}
+
+func (fixture *searchFixture) keyOf(index int) resource.Key {
+	return resource.KeyOf(ids.TextureNames, resource.LangDefault, index)
+}
+
+func (fixture *searchFixture) replace(matches []edit.SearchMatch) {
+	fixture.mod.Modify(func(modder world.Modder) {
+		fixture.search.Replace(modder, matches)
+	})
+}
+
+func TestSearchFindMatchesWithReplacement(t *testing.T) {
+	fixture := newSearchFixture()
+	fixture.setText(1, "Red door")
+	fixture.setText(2, "Blue wall")
+
+	matches := fixture.search.Find(edit.SearchQuery{Pattern: regexp.MustCompile("(?i)door"), Replacement: "gate", Literal: true})
+
+	require.Equal(t, 1, len(matches))
+	assert.Equal(t, edit.TranslationKey{ID: ids.TextureNames, Index: 1}, matches[0].Location.Key)
+	assert.Equal(t, resource.LangDefault, matches[0].Location.Lang)
+	assert.Equal(t, "Red door", matches[0].Text)
+	assert.Equal(t, "Red gate", matches[0].Replaced)
+}
+
+func TestSearchFindExpandsGroupsUnlessLiteral(t *testing.T) {
+	fixture := newSearchFixture()
+	fixture.setText(1, "Red door")
+	pattern := regexp.MustCompile("(Red) (door)")
+
+	expanded := fixture.search.Find(edit.SearchQuery{Pattern: pattern, Replacement: "$2 $1"})
+	literal := fixture.search.Find(edit.SearchQuery{Pattern: pattern, Replacement: "$2 $1", Literal: true})
+
+	require.Equal(t, 1, len(expanded))
+	assert.Equal(t, "door Red", expanded[0].Replaced)
+	require.Equal(t, 1, len(literal))
+	assert.Equal(t, "$2 $1", literal[0].Replaced)
+}
+
+func TestSearchReplaceSetsReplacedStrings(t *testing.T) {
+	fixture := newSearchFixture()
+	fixture.setText(1, "Red door")
+	fixture.setText(2, "Green door")
+	matches := fixture.search.Find(edit.SearchQuery{Pattern: regexp.MustCompile("door"), Replacement: "gate", Literal: true})
+
+	fixture.replace(matches)
+
+	assert.Equal(t, "Red gate", fixture.textOf(1))
+	assert.Equal(t, "Green gate", fixture.textOf(2))
+}
+
+func TestSearchReplaceSkipsStringsModifiedAfterFind(t *testing.T) {
+	fixture := newSearchFixture()
+	fixture.setText(1, "Red door")
+	fixture.setText(2, "Green door")
+	matches := fixture.search.Find(edit.SearchQuery{Pattern: regexp.MustCompile("door"), Replacement: "gate", Literal: true})
+	fixture.setText(2, "Green door, edited")
+
+	assert.Equal(t, 1, len(fixture.search.Unchanged(matches)))
+	fixture.replace(matches)
+
+	assert.Equal(t, "Red gate", fixture.textOf(1))
+	assert.Equal(t, "Green door, edited", fixture.textOf(2))
+}
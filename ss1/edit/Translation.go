@@ -0,0 +1,368 @@
+package edit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// MessageField identifies one text of an electronic message.
+type MessageField string
+
+// Fields of electronic messages that are translatable.
+const (
+	MessageTitle   MessageField = "title"
+	MessageSender  MessageField = "sender"
+	MessageSubject MessageField = "subject"
+	MessageVerbose MessageField = "verbose"
+	MessageTerse   MessageField = "terse"
+)
+
+var messageFields = []MessageField{MessageTitle, MessageSender, MessageSubject, MessageVerbose, MessageTerse}
+
+func (field MessageField) title() string {
+	return strings.ToUpper(string(field[:1])) + string(field[1:])
+}
+
+func (field MessageField) of(msg *text.ElectronicMessage) *string {
+	switch field {
+	case MessageTitle:
+		return &msg.Title
+	case MessageSender:
+		return &msg.Sender
+	case MessageSubject:
+		return &msg.Subject
+	case MessageVerbose:
+		return &msg.VerboseText
+	case MessageTerse:
+		return &msg.TerseText
+	default:
+		return nil
+	}
+}
+
+// TranslationKey identifies one translatable string. The key is stable across sessions and mods.
+type TranslationKey struct {
+	// ID is the base resource identifier of the text group.
+	ID resource.ID
+	// Index is the number of the text within its group.
+	Index int
+	// Field is set for texts of electronic messages.
+	Field MessageField
+}
+
+// String returns the key in the form "ID:Index" or "ID:Index:Field", with the ID in hexadecimal.
+func (key TranslationKey) String() string {
+	result := fmt.Sprintf("%04X:%d", key.ID.Value(), key.Index)
+	if len(key.Field) > 0 {
+		result += ":" + string(key.Field)
+	}
+	return result
+}
+
+// ParseTranslationKey returns the key represented by given string.
+func ParseTranslationKey(value string) (TranslationKey, error) {
+	var key TranslationKey
+	parts := strings.Split(value, ":")
+	if (len(parts) < 2) || (len(parts) > 3) {
+		return key, fmt.Errorf("invalid key <%s>", value)
+	}
+	id, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return key, fmt.Errorf("invalid ID in key <%s>", value)
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 16)
+	if err != nil {
+		return key, fmt.Errorf("invalid index in key <%s>", value)
+	}
+	key.ID = resource.ID(id)
+	key.Index = int(index)
+	if len(parts) == 3 {
+		key.Field = MessageField(parts[2])
+		if key.Field.of(&text.ElectronicMessage{}) == nil {
+			return key, fmt.Errorf("invalid field in key <%s>", value)
+		}
+	}
+	return key, nil
+}
+
+// TranslationEntry is one translatable string with its text in a source and a target language.
+type TranslationEntry struct {
+	Key     TranslationKey
+	Context string
+	Source  string
+	Target  string
+}
+
+// TranslationReport lists the findings of a review of translated entries.
+type TranslationReport struct {
+	// Untranslated lists the keys of entries without a target text.
+	Untranslated []TranslationKey
+	// Stale lists the keys of entries of which the source text has changed since the export.
+	Stale []TranslationKey
+	// Unknown lists the keys that do not refer to translatable strings.
+	Unknown []TranslationKey
+	// Unencodable lists the keys of entries with characters the codepage of the target language can not represent.
+	Unencodable []TranslationKey
+}
+
+// TranslationResources provides access to raw resources and the object properties.
+type TranslationResources interface {
+	media.TextBlockGetter
+	ObjectProperties() object.PropertiesTable
+}
+
+// TranslationService provides bulk access to all translatable strings.
+type TranslationService struct {
+	cps          text.Codepages
+	textViewer   media.TextViewerService
+	textSetter   media.TextSetterService
+	messageCache *text.ElectronicMessageCache
+	resources    TranslationResources
+}
+
+// NewTranslationService returns a new instance.
+func NewTranslationService(cps text.Codepages,
+	textViewer media.TextViewerService, textSetter media.TextSetterService,
+	messageCache *text.ElectronicMessageCache, resources TranslationResources) TranslationService {
+	return TranslationService{
+		cps:          cps,
+		textViewer:   textViewer,
+		textSetter:   textSetter,
+		messageCache: messageCache,
+		resources:    resources,
+	}
+}
+
+type translationGroup struct {
+	id      resource.ID
+	title   string
+	count   int
+	nameFor func(index int) string
+}
+
+var translatableMessages = []struct {
+	id    resource.ID
+	title string
+}{
+	{id: ids.MailsStart, title: "Mails"},
+	{id: ids.LogsStart, title: "Logs"},
+	{id: ids.FragmentsStart, title: "Fragments"},
+}
+
+func (service TranslationService) textGroups() []translationGroup {
+	var groups []translationGroup
+	maxCount := func(id resource.ID) int {
+		info, _ := ids.Info(id)
+		return info.MaxCount
+	}
+	numbered := func(title string) func(int) string {
+		return func(index int) string { return fmt.Sprintf("%s #%d", title, index) }
+	}
+	for _, info := range KnownTexts() {
+		groups = append(groups, translationGroup{id: info.ID, title: info.Title, count: maxCount(info.ID), nameFor: numbered(info.Title)})
+	}
+
+	var triples []object.Triple
+	service.resources.ObjectProperties().Iterate(func(triple object.Triple, _ *object.Properties) bool {
+		triples = append(triples, triple)
+		return true
+	})
+	objectName := func(title string) func(int) string {
+		return func(index int) string { return fmt.Sprintf("%s %v", title, triples[index]) }
+	}
+	groups = append(groups,
+		translationGroup{id: ids.ObjectLongNames, title: "Object Long Names", count: len(triples), nameFor: objectName("Object Long Name")},
+		translationGroup{id: ids.ObjectShortNames, title: "Object Short Names", count: len(triples), nameFor: objectName("Object Short Name")},
+		translationGroup{id: ids.TextureNames, title: "Texture Names", count: maxCount(ids.TextureNames), nameFor: numbered("Texture Name")},
+		translationGroup{id: ids.TextureUsages, title: "Texture Usages", count: maxCount(ids.TextureUsages), nameFor: numbered("Texture Usage")})
+	return groups
+}
+
+// Entries returns all translatable strings, with their texts in the given languages.
+// Strings that are empty in both languages are skipped.
+func (service TranslationService) Entries(source, target resource.Language) []TranslationEntry {
+	var entries []TranslationEntry
+	add := func(entry TranslationEntry) {
+		if (len(entry.Source) > 0) || (len(entry.Target) > 0) {
+			entries = append(entries, entry)
+		}
+	}
+	for _, group := range service.textGroups() {
+		for index := 0; index < group.count; index++ {
+			add(TranslationEntry{
+				Key:     TranslationKey{ID: group.id, Index: index},
+				Context: group.nameFor(index),
+				Source:  service.textViewer.Text(resource.KeyOf(group.id, source, index)),
+				Target:  service.textViewer.Text(resource.KeyOf(group.id, target, index)),
+			})
+		}
+	}
+	for _, group := range translatableMessages {
+		info, _ := ids.Info(group.id)
+		for index := 0; index < info.MaxCount; index++ {
+			sourceMsg, sourceErr := service.messageCache.Message(resource.KeyOf(group.id, source, index))
+			targetMsg, targetErr := service.messageCache.Message(resource.KeyOf(group.id, target, index))
+			if (sourceErr != nil) && (targetErr != nil) {
+				continue
+			}
+			for _, field := range messageFields {
+				add(TranslationEntry{
+					Key:     TranslationKey{ID: group.id, Index: index, Field: field},
+					Context: fmt.Sprintf("%s #%d %s", group.title, index, field.title()),
+					Source:  *field.of(&sourceMsg),
+					Target:  *field.of(&targetMsg),
+				})
+			}
+		}
+	}
+	return entries
+}
+
+// Text returns the current string of the given key in given language.
+func (service TranslationService) Text(lang resource.Language, key TranslationKey) string {
+	resourceKey := resource.KeyOf(key.ID, lang, key.Index)
+	if len(key.Field) == 0 {
+		return service.textViewer.Text(resourceKey)
+	}
+	msg, err := service.messageCache.Message(resourceKey)
+	if err != nil {
+		return ""
+	}
+	return *key.Field.of(&msg)
+}
+
+// Known returns true if the key refers to a translatable string.
+func (service TranslationService) Known(key TranslationKey) bool {
+	if len(key.Field) > 0 {
+		for _, group := range translatableMessages {
+			info, _ := ids.Info(group.id)
+			if (group.id == key.ID) && (key.Index >= 0) && (key.Index < info.MaxCount) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, group := range service.textGroups() {
+		if (group.id == key.ID) && (key.Index >= 0) && (key.Index < group.count) {
+			return true
+		}
+	}
+	return false
+}
+
+// Review compares the given translated entries with the current texts.
+// It returns the entries that would change the target language, together with a report.
+// Stale entries are still considered for changes, entries that can not be encoded are not.
+func (service TranslationService) Review(source, target resource.Language,
+	translated []TranslationEntry) ([]TranslationEntry, TranslationReport) {
+	var report TranslationReport
+	var changes []TranslationEntry
+	current := make(map[TranslationKey]TranslationEntry)
+	for _, entry := range service.Entries(source, target) {
+		current[entry.Key] = entry
+	}
+	for _, entry := range translated {
+		if !service.Known(entry.Key) {
+			report.Unknown = append(report.Unknown, entry.Key)
+			continue
+		}
+		if len(entry.Target) == 0 {
+			report.Untranslated = append(report.Untranslated, entry.Key)
+			continue
+		}
+		if len(text.Unencodable(service.cps.ForLanguage(target), entry.Target)) > 0 {
+			report.Unencodable = append(report.Unencodable, entry.Key)
+			continue
+		}
+		existing := current[entry.Key]
+		if existing.Source != entry.Source {
+			report.Stale = append(report.Stale, entry.Key)
+		}
+		if existing.Target != entry.Target {
+			changes = append(changes, entry)
+		}
+	}
+	return changes, report
+}
+
+// Apply sets the target texts of the given entries in the target language.
+// Messages that do not exist in the target language are based on the message in the default language.
+func (service TranslationService) Apply(setter media.TextBlockSetter, target resource.Language, entries []TranslationEntry) {
+	messages := make(map[resource.Key]*text.ElectronicMessage)
+	var messageKeys []resource.Key
+	for _, entry := range entries {
+		key := resource.KeyOf(entry.Key.ID, target, entry.Key.Index)
+		if len(entry.Key.Field) == 0 {
+			service.textSetter.Set(setter, key, entry.Target)
+			continue
+		}
+		msg, existing := messages[key]
+		if !existing {
+			msg = service.baseMessage(key)
+			messages[key] = msg
+			messageKeys = append(messageKeys, key)
+		}
+		*entry.Key.Field.of(msg) = entry.Target
+	}
+	for _, key := range messageKeys {
+		setter.SetResourceBlocks(key.Lang, key.ID.Plus(key.Index), messages[key].Encode(service.cps.ForLanguage(key.Lang)))
+	}
+}
+
+func (service TranslationService) baseMessage(key resource.Key) *text.ElectronicMessage {
+	msg, err := service.messageCache.Message(key)
+	if err != nil {
+		defaultKey := key
+		defaultKey.Lang = resource.LangDefault
+		msg, err = service.messageCache.Message(defaultKey)
+		if err != nil {
+			msg = text.EmptyElectronicMessage()
+		}
+	}
+	return &msg
+}
+
+// RestoreFunc creates a snapshot of all texts the given entries refer to in the target language,
+// and returns a function to restore them.
+func (service TranslationService) RestoreFunc(target resource.Language,
+	entries []TranslationEntry) func(setter media.TextBlockSetter) {
+	var restorers []func(setter media.TextBlockSetter)
+	messagesCovered := make(map[resource.ID]bool)
+	for _, entry := range entries {
+		key := resource.KeyOf(entry.Key.ID, target, entry.Key.Index)
+		if len(entry.Key.Field) == 0 {
+			oldText := service.textViewer.Text(key)
+			if service.textViewer.Modified(key) {
+				restorers = append(restorers, func(setter media.TextBlockSetter) { service.textSetter.Set(setter, key, oldText) })
+			} else {
+				restorers = append(restorers, func(setter media.TextBlockSetter) { service.textSetter.Remove(setter, key) })
+			}
+			continue
+		}
+		id := key.ID.Plus(key.Index)
+		if messagesCovered[id] {
+			continue
+		}
+		messagesCovered[id] = true
+		oldData := service.resources.ModifiedBlocks(target, id)
+		restorers = append(restorers, func(setter media.TextBlockSetter) {
+			if len(oldData) > 0 {
+				setter.SetResourceBlocks(target, id, oldData)
+			} else {
+				setter.DelResource(target, id)
+			}
+		})
+	}
+	return func(setter media.TextBlockSetter) {
+		for _, restore := range restorers {
+			restore(setter)
+		}
+	}
+}
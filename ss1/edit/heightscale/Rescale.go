@@ -0,0 +1,126 @@
+package heightscale
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+// Issue describes a value that can not be represented exactly with the new scale.
+type Issue struct {
+	// Property names the converted value.
+	Property string
+	// X and Y are the tile coordinates the value belongs to.
+	X, Y int
+	// Object is the identifier of the object the value belongs to. Zero for tile properties.
+	Object level.ObjectID
+	// Exact is the value as it would be in the new scale.
+	Exact float64
+	// Result is the value that is stored instead.
+	Result int
+}
+
+// String returns a readable form of the issue.
+func (issue Issue) String() string {
+	location := fmt.Sprintf("tile %d/%d", issue.X, issue.Y)
+	if issue.Object != 0 {
+		location = fmt.Sprintf("object %d at %s", issue.Object, location)
+	}
+	return fmt.Sprintf("%s of %s: %.2f becomes %d", issue.Property, location, issue.Exact, issue.Result)
+}
+
+// Preview returns the issues a rescale of the level to the given shift would have, without modifying the level.
+func Preview(lvl *level.Level, newShift level.HeightShift) ([]Issue, error) {
+	return rescale(lvl, newShift, false)
+}
+
+// Apply converts all heights of the level to the given shift and sets the shift.
+// The returned issues describe the values that had to be limited or rounded.
+func Apply(lvl *level.Level, newShift level.HeightShift) ([]Issue, error) {
+	return rescale(lvl, newShift, true)
+}
+
+type converter struct {
+	factor float64
+	issues []Issue
+}
+
+// convert returns the value in the new scale, limited to the given range.
+// If the value can not be represented exactly, an issue is recorded.
+func (conv *converter) convert(issue Issue, raw int, min, max int) int {
+	exact := float64(raw) * conv.factor
+	result := int(math.Max(float64(min), math.Min(float64(max), math.Round(exact))))
+	if float64(result) != exact {
+		issue.Exact = exact
+		issue.Result = result
+		conv.issues = append(conv.issues, issue)
+	}
+	return result
+}
+
+func rescale(lvl *level.Level, newShift level.HeightShift, apply bool) ([]Issue, error) {
+	_, _, oldShift := lvl.Size()
+	oldHeight, err := oldShift.ValueFromTileHeight(level.TileHeightUnitMax)
+	if err != nil {
+		return nil, err
+	}
+	newHeight, err := newShift.ValueFromTileHeight(level.TileHeightUnitMax)
+	if err != nil {
+		return nil, err
+	}
+	conv := converter{factor: float64(oldHeight) / float64(newHeight)}
+	if conv.factor == 1 {
+		return nil, nil
+	}
+
+	rescaleTiles(lvl, &conv, apply)
+	rescaleObjects(lvl, &conv, apply)
+	if apply {
+		lvl.SetHeightShift(newShift)
+		lvl.RecalculateWallHeights()
+	}
+	return conv.issues, nil
+}
+
+// rescaleTiles converts the heights of all tiles that are not solid.
+// Solid tiles are skipped, as their heights have no effect on the world.
+func rescaleTiles(lvl *level.Level, conv *converter, apply bool) {
+	columns, rows, _ := lvl.Size()
+	maxUnit := int(level.TileHeightUnitMax)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			tile := lvl.Tile(x, y)
+			if (tile == nil) || (tile.Type == level.TileTypeSolid) {
+				continue
+			}
+			at := func(property string) Issue { return Issue{Property: property, X: x, Y: y} }
+			floor := conv.convert(at("floor height"), int(tile.Floor.AbsoluteHeight()), 0, maxUnit-1)
+			ceiling := conv.convert(at("ceiling height"), int(tile.Ceiling.AbsoluteHeight()), 1, maxUnit)
+			slope := conv.convert(at("slope height"), int(tile.SlopeHeight), 0, maxUnit-1)
+			var wallOffset int
+			if !lvl.IsCyberspace() {
+				wallOffset = conv.convert(at("wall texture offset"), int(tile.Flags.ForRealWorld().WallTextureOffset()), 0, maxUnit-1)
+			}
+			if !apply {
+				continue
+			}
+			tile.Floor = tile.Floor.WithAbsoluteHeight(level.TileHeightUnit(floor))
+			tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(level.TileHeightUnit(ceiling))
+			tile.SlopeHeight = level.TileHeightUnit(slope)
+			if !lvl.IsCyberspace() {
+				tile.Flags = tile.Flags.ForRealWorld().WithWallTextureOffset(level.TileHeightUnit(wallOffset)).AsTileFlag()
+			}
+		}
+	}
+}
+
+func rescaleObjects(lvl *level.Level, conv *converter, apply bool) {
+	lvl.ForEachObject(func(id level.ObjectID, entry level.ObjectMasterEntry) {
+		issue := Issue{Property: "Z", X: int(entry.X.Tile()), Y: int(entry.Y.Tile()), Object: id}
+		z := conv.convert(issue, int(entry.Z), 0, math.MaxUint8)
+		if apply {
+			lvl.Object(id).Z = level.HeightUnit(z)
+		}
+	})
+}
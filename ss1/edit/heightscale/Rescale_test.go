@@ -0,0 +1,123 @@
+package heightscale_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/heightscale"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const levelID = 1
+
+func newTestLevel(t *testing.T, shift level.HeightShift) *level.Level {
+	t.Helper()
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	levelData := level.EmptyLevelData(level.EmptyLevelParameters{
+		MapModifier: func(m level.TileMap) {
+			tile := m.Tile(2, 3)
+			tile.Type = level.TileTypeOpen
+			tile.Floor = tile.Floor.WithAbsoluteHeight(4)
+			tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(16)
+			tile.SlopeHeight = 3
+			tile.Flags = tile.Flags.ForRealWorld().WithWallTextureOffset(2).AsTileFlag()
+		},
+	})
+	mod.Modify(func(modder world.Modder) {
+		for offset, data := range &levelData {
+			if len(data) > 0 {
+				modder.SetResourceBlocks(resource.LangAny, ids.LevelResourcesStart.Plus(lvlids.PerLevel*levelID+offset), [][]byte{data})
+			}
+		}
+	})
+	lvl := level.NewLevel(ids.LevelResourcesStart, levelID, mod)
+	lvl.SetHeightShift(shift)
+	return lvl
+}
+
+func placeObject(t *testing.T, lvl *level.Level, z level.HeightUnit) level.ObjectID {
+	t.Helper()
+	id, err := lvl.NewObject(object.ClassSmallStuff)
+	require.Nil(t, err, "no error expected creating object")
+	obj := lvl.Object(id)
+	obj.X = level.CoordinateAt(2, 0x80)
+	obj.Y = level.CoordinateAt(3, 0x80)
+	obj.Z = z
+	lvl.UpdateObjectLocation(id)
+	return id
+}
+
+func TestApplyKeepsGeometryForFinerScale(t *testing.T) {
+	lvl := newTestLevel(t, 3)
+	id := placeObject(t, lvl, 0x20)
+
+	issues, err := heightscale.Apply(lvl, 4)
+	require.Nil(t, err, "no error expected")
+	assert.Empty(t, issues)
+
+	_, _, shift := lvl.Size()
+	assert.Equal(t, level.HeightShift(4), shift)
+	tile := lvl.Tile(2, 3)
+	assert.Equal(t, level.TileHeightUnit(8), tile.Floor.AbsoluteHeight())
+	assert.Equal(t, level.TileHeightUnit(32), tile.Ceiling.AbsoluteHeight())
+	assert.Equal(t, level.TileHeightUnit(6), tile.SlopeHeight)
+	assert.Equal(t, level.TileHeightUnit(4), tile.Flags.ForRealWorld().WallTextureOffset())
+	assert.Equal(t, level.HeightUnit(0x40), lvl.Object(id).Z)
+}
+
+func TestApplyReportsUnrepresentableValues(t *testing.T) {
+	lvl := newTestLevel(t, 4)
+	tile := lvl.Tile(2, 3)
+	tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(20)
+	id := placeObject(t, lvl, 0xC0)
+
+	issues, err := heightscale.Apply(lvl, 5)
+	require.Nil(t, err, "no error expected")
+
+	var properties []string
+	for _, issue := range issues {
+		properties = append(properties, issue.Property)
+	}
+	assert.Equal(t, []string{"ceiling height", "Z"}, properties)
+	assert.Equal(t, level.TileHeightUnit(32), lvl.Tile(2, 3).Ceiling.AbsoluteHeight())
+	assert.Equal(t, level.HeightUnit(0xFF), lvl.Object(id).Z)
+	assert.Equal(t, id, issues[1].Object)
+}
+
+func TestApplyRoundsValuesForCoarserScale(t *testing.T) {
+	lvl := newTestLevel(t, 3)
+
+	issues, err := heightscale.Apply(lvl, 2)
+	require.Nil(t, err, "no error expected")
+
+	require.Equal(t, 1, len(issues))
+	assert.Equal(t, "slope height", issues[0].Property)
+	assert.Equal(t, 1.5, issues[0].Exact)
+	assert.Equal(t, level.TileHeightUnit(2), lvl.Tile(2, 3).Floor.AbsoluteHeight())
+}
+
+func TestPreviewDoesNotModifyLevel(t *testing.T) {
+	lvl := newTestLevel(t, 3)
+
+	issues, err := heightscale.Preview(lvl, 2)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, 1, len(issues))
+	_, _, shift := lvl.Size()
+	assert.Equal(t, level.HeightShift(3), shift)
+	assert.Equal(t, level.TileHeightUnit(4), lvl.Tile(2, 3).Floor.AbsoluteHeight())
+}
+
+func TestApplyFailsForInvalidShift(t *testing.T) {
+	lvl := newTestLevel(t, 3)
+
+	_, err := heightscale.Apply(lvl, 10)
+	assert.NotNil(t, err, "error expected")
+}
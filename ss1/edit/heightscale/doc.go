@@ -0,0 +1,2 @@
+// Package heightscale changes the vertical scale of a level while keeping its geometry.
+package heightscale
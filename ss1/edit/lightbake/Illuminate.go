@@ -0,0 +1,86 @@
+package lightbake
+
+import "math"
+
+// MaxLevel is the highest light level of a tile.
+const MaxLevel = 15
+
+// occlusionStep is the distance, in tiles, between the points that are checked for blocking tiles.
+const occlusionStep = 0.25
+
+// Source is a point that emits light.
+type Source struct {
+	// X and Y are the position in tiles, with the fraction being the position within the tile.
+	X, Y float64
+	// Intensity is the brightness at the source, in range [0.0 .. 1.0].
+	Intensity float64
+	// Radius is the distance, in tiles, at which the light has faded completely.
+	Radius float64
+}
+
+// Brightness is a table of light values, one per tile, in range [0.0 .. 1.0].
+// The table is indexed by tile row first, then by tile column.
+type Brightness [][]float64
+
+// Illuminate returns the brightness that the given sources cast on a map of given size.
+// The solid function reports tiles that block light. Solid tiles themselves receive no light.
+func Illuminate(columns, rows int, solid func(x, y int) bool, sources []Source) Brightness {
+	brightness := make(Brightness, rows)
+	for y := range brightness {
+		brightness[y] = make([]float64, columns)
+	}
+	for _, source := range sources {
+		if source.Radius <= 0 {
+			continue
+		}
+		fromX := int(math.Max(0, math.Floor(source.X-source.Radius)))
+		toX := int(math.Min(float64(columns-1), math.Floor(source.X+source.Radius)))
+		fromY := int(math.Max(0, math.Floor(source.Y-source.Radius)))
+		toY := int(math.Min(float64(rows-1), math.Floor(source.Y+source.Radius)))
+		for y := fromY; y <= toY; y++ {
+			for x := fromX; x <= toX; x++ {
+				if solid(x, y) {
+					continue
+				}
+				centerX, centerY := float64(x)+0.5, float64(y)+0.5
+				distance := math.Hypot(centerX-source.X, centerY-source.Y)
+				if (distance >= source.Radius) || !visible(solid, source.X, source.Y, centerX, centerY) {
+					continue
+				}
+				value := brightness[y][x] + source.Intensity*(1-distance/source.Radius)
+				brightness[y][x] = math.Min(1, value)
+			}
+		}
+	}
+	return brightness
+}
+
+// visible returns true if no solid tile is between the two points.
+// The tile of the start point is not considered, as sources may be placed at walls.
+func visible(solid func(x, y int) bool, fromX, fromY, toX, toY float64) bool {
+	startX, startY := int(math.Floor(fromX)), int(math.Floor(fromY))
+	distance := math.Hypot(toX-fromX, toY-fromY)
+	steps := int(math.Ceil(distance / occlusionStep))
+	for step := 1; step < steps; step++ {
+		fraction := float64(step) / float64(steps)
+		x := int(math.Floor(fromX + (toX-fromX)*fraction))
+		y := int(math.Floor(fromY + (toY-fromY)*fraction))
+		if ((x != startX) || (y != startY)) && solid(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// LevelAt returns the light level of the tile at given position, in range [0 .. MaxLevel].
+// The base level is the light a tile has without any source.
+func (brightness Brightness) LevelAt(x, y int, base int) int {
+	if (y < 0) || (y >= len(brightness)) || (x < 0) || (x >= len(brightness[y])) {
+		return base
+	}
+	level := base + int(math.Round(brightness[y][x]*MaxLevel))
+	if level > MaxLevel {
+		return MaxLevel
+	}
+	return level
+}
@@ -0,0 +1,58 @@
+package lightbake_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/edit/lightbake"
+)
+
+func noSolids(x, y int) bool {
+	return false
+}
+
+func TestIlluminateFadesWithDistance(t *testing.T) {
+	brightness := lightbake.Illuminate(8, 1, noSolids, []lightbake.Source{{X: 0.5, Y: 0.5, Intensity: 1.0, Radius: 4}})
+
+	assert.InDelta(t, 1.0, brightness[0][0], 0.001)
+	assert.InDelta(t, 0.75, brightness[0][1], 0.001)
+	assert.InDelta(t, 0.25, brightness[0][3], 0.001)
+	assert.InDelta(t, 0.0, brightness[0][4], 0.001, "light should have faded at radius")
+}
+
+func TestIlluminateAddsSourcesUpToFullBrightness(t *testing.T) {
+	sources := []lightbake.Source{
+		{X: 0.5, Y: 0.5, Intensity: 0.5, Radius: 10},
+		{X: 1.5, Y: 0.5, Intensity: 0.8, Radius: 10},
+	}
+	brightness := lightbake.Illuminate(4, 1, noSolids, sources)
+
+	assert.InDelta(t, 1.0, brightness[0][0], 0.001)
+	assert.InDelta(t, 0.5*0.7+0.8*0.8, brightness[0][3], 0.001)
+}
+
+func TestIlluminateIsBlockedBySolidTiles(t *testing.T) {
+	solid := func(x, y int) bool { return x == 2 }
+	brightness := lightbake.Illuminate(5, 1, solid, []lightbake.Source{{X: 0.5, Y: 0.5, Intensity: 1.0, Radius: 10}})
+
+	assert.True(t, brightness[0][1] > 0, "tile before wall should be lit")
+	assert.Equal(t, 0.0, brightness[0][2], "solid tile should not be lit")
+	assert.Equal(t, 0.0, brightness[0][3], "tile behind wall should not be lit")
+}
+
+func TestIlluminateIgnoresSolidSourceTile(t *testing.T) {
+	solid := func(x, y int) bool { return x == 0 }
+	brightness := lightbake.Illuminate(3, 1, solid, []lightbake.Source{{X: 0.9, Y: 0.5, Intensity: 1.0, Radius: 10}})
+
+	assert.True(t, brightness[0][2] > 0, "source in a wall should still light the room")
+}
+
+func TestLevelAt(t *testing.T) {
+	brightness := lightbake.Brightness{{0.0, 0.5, 1.0}}
+
+	assert.Equal(t, 3, brightness.LevelAt(0, 0, 3))
+	assert.Equal(t, 11, brightness.LevelAt(1, 0, 3))
+	assert.Equal(t, lightbake.MaxLevel, brightness.LevelAt(2, 0, 3))
+	assert.Equal(t, 3, brightness.LevelAt(5, 0, 3), "outside should return base")
+}
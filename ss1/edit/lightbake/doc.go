@@ -0,0 +1,2 @@
+// Package lightbake computes the lighting of tiles from light sources.
+package lightbake
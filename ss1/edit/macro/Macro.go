@@ -0,0 +1,109 @@
+package macro
+
+import "fmt"
+
+// Target identifies what a step changes.
+type Target string
+
+// Targets of steps.
+const (
+	// TargetTiles changes all selected tiles.
+	TargetTiles Target = "tiles"
+	// TargetObjects changes all selected objects.
+	TargetObjects Target = "objects"
+	// TargetLevel changes the parameters of the level.
+	TargetLevel Target = "level"
+)
+
+// Step is one recorded operation.
+type Step struct {
+	Target Target `json:"target"`
+	// Property names the changed property. The names are defined by the editor.
+	Property string `json:"property"`
+	// Index selects the entry of properties that are lists, such as the texture atlas of a level.
+	Index int `json:"index,omitempty"`
+	// Value is the new value of the property.
+	Value int64 `json:"value"`
+	// Mask selects the bits of the value that are set, the other bits of the property are kept.
+	// Zero sets the whole value.
+	Mask uint32 `json:"mask,omitempty"`
+	// OffsetX and OffsetY position the changed tile relative to each selected tile.
+	// They only apply to steps for tiles.
+	OffsetX int `json:"offsetX,omitempty"`
+	OffsetY int `json:"offsetY,omitempty"`
+	// Parameter names the argument that replaces the value when the macro is replayed.
+	// The recorded value is used if no argument is given.
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// Arguments are the values for the parameters of a macro, keyed by parameter name.
+type Arguments map[string]int64
+
+// String returns a textual representation of the step.
+func (step Step) String() string {
+	property := step.Property
+	if step.Index != 0 {
+		property = fmt.Sprintf("%s[%d]", step.Property, step.Index)
+	}
+	target := string(step.Target)
+	if (step.OffsetX != 0) || (step.OffsetY != 0) {
+		target = fmt.Sprintf("%s%+d%+d", step.Target, step.OffsetX, step.OffsetY)
+	}
+	value := fmt.Sprintf("%d", step.Value)
+	if len(step.Parameter) > 0 {
+		value = fmt.Sprintf("$%s (%d)", step.Parameter, step.Value)
+	}
+	if step.Mask != 0 {
+		return fmt.Sprintf("%s: %s = %s (mask 0x%X)", target, property, value, step.Mask)
+	}
+	return fmt.Sprintf("%s: %s = %s", target, property, value)
+}
+
+// Applied returns the given value of the property with the step applied.
+func (step Step) Applied(oldValue int64) int64 {
+	if step.Mask == 0 {
+		return step.Value
+	}
+	mask := int64(step.Mask)
+	return (oldValue &^ mask) | (step.Value & mask)
+}
+
+// Macro is a named sequence of steps.
+type Macro struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// Targets returns true for each target the macro has steps for.
+func (macro Macro) Targets() map[Target]bool {
+	targets := make(map[Target]bool)
+	for _, step := range macro.Steps {
+		targets[step.Target] = true
+	}
+	return targets
+}
+
+// Parameters returns the names of all parameters of the macro, in order of their first use.
+func (macro Macro) Parameters() []string {
+	var names []string
+	known := make(map[string]bool)
+	for _, step := range macro.Steps {
+		if (len(step.Parameter) > 0) && !known[step.Parameter] {
+			known[step.Parameter] = true
+			names = append(names, step.Parameter)
+		}
+	}
+	return names
+}
+
+// Resolved returns the steps of the macro with the values of parameters set from the given arguments.
+func (macro Macro) Resolved(args Arguments) []Step {
+	steps := make([]Step, len(macro.Steps))
+	for index, step := range macro.Steps {
+		if value, given := args[step.Parameter]; given && (len(step.Parameter) > 0) {
+			step.Value = value
+		}
+		steps[index] = step
+	}
+	return steps
+}
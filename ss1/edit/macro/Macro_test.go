@@ -0,0 +1,73 @@
+package macro_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
+)
+
+type testTiles map[image.Point]map[string]int64
+
+func (tiles testTiles) replay(m macro.Macro, args macro.Arguments, selected ...image.Point) {
+	for _, step := range m.Resolved(args) {
+		for _, pos := range selected {
+			target := pos.Add(image.Pt(step.OffsetX, step.OffsetY))
+			if tiles[target] == nil {
+				tiles[target] = make(map[string]int64)
+			}
+			tiles[target][step.Property] = step.Applied(tiles[target][step.Property])
+		}
+	}
+}
+
+func TestMacroReplayWithDifferentArguments(t *testing.T) {
+	m := macro.Macro{Name: "doorway", Steps: []macro.Step{
+		{Target: macro.TargetTiles, Property: "floor height", Value: 2, Parameter: "height"},
+		{Target: macro.TargetTiles, Property: "floor height", Value: 2, Parameter: "height", OffsetX: 1},
+		{Target: macro.TargetTiles, Property: "ceiling height", Value: 20, OffsetY: -1},
+	}}
+	first := make(testTiles)
+	first.replay(m, macro.Arguments{"height": 5}, image.Pt(10, 10))
+	second := make(testTiles)
+	second.replay(m, macro.Arguments{"height": 7}, image.Pt(3, 4))
+	recorded := make(testTiles)
+	recorded.replay(m, nil, image.Pt(0, 1))
+
+	assert.Equal(t, testTiles{
+		image.Pt(10, 10): {"floor height": 5},
+		image.Pt(11, 10): {"floor height": 5},
+		image.Pt(10, 9):  {"ceiling height": 20},
+	}, first)
+	assert.Equal(t, testTiles{
+		image.Pt(3, 4): {"floor height": 7},
+		image.Pt(4, 4): {"floor height": 7},
+		image.Pt(3, 3): {"ceiling height": 20},
+	}, second)
+	assert.Equal(t, testTiles{
+		image.Pt(0, 1): {"floor height": 2},
+		image.Pt(1, 1): {"floor height": 2},
+		image.Pt(0, 0): {"ceiling height": 20},
+	}, recorded)
+}
+
+func TestMacroParametersAreListedOnce(t *testing.T) {
+	m := macro.Macro{Steps: []macro.Step{
+		{Parameter: "b"}, {Parameter: "a"}, {}, {Parameter: "b"},
+	}}
+	assert.Equal(t, []string{"b", "a"}, m.Parameters())
+}
+
+func TestResolvedKeepsRecordedStepsUnchanged(t *testing.T) {
+	m := macro.Macro{Steps: []macro.Step{{Value: 1, Parameter: "a"}, {Value: 2}}}
+	resolved := m.Resolved(macro.Arguments{"a": 10, "": 20})
+	assert.Equal(t, []macro.Step{{Value: 10, Parameter: "a"}, {Value: 2}}, resolved)
+	assert.Equal(t, int64(1), m.Steps[0].Value)
+}
+
+func TestStepStringShowsOffsetAndParameter(t *testing.T) {
+	step := macro.Step{Target: macro.TargetTiles, Property: "floor height", Value: 2, OffsetX: 1, OffsetY: -1, Parameter: "h"}
+	assert.Equal(t, "tiles+1-1: floor height = $h (2)", step.String())
+}
@@ -0,0 +1,55 @@
+package macro
+
+// Recorder collects steps while it is active.
+type Recorder struct {
+	active bool
+	steps  []Step
+}
+
+// Start begins a new recording. Any previously recorded steps are dropped.
+func (rec *Recorder) Start() {
+	rec.active = true
+	rec.steps = nil
+}
+
+// Active returns true while the recorder collects steps.
+func (rec *Recorder) Active() bool {
+	return rec.active
+}
+
+// StepCount returns the number of steps recorded so far.
+func (rec *Recorder) StepCount() int {
+	return len(rec.steps)
+}
+
+// Record adds the given step, if the recorder is active.
+// A step for the same property as the previous step is merged into it, as this happens
+// when a value is adjusted gradually, such as with a slider.
+func (rec *Recorder) Record(step Step) {
+	if !rec.active {
+		return
+	}
+	if last := len(rec.steps) - 1; last >= 0 {
+		previous := rec.steps[last]
+		if (previous.Target == step.Target) && (previous.Property == step.Property) && (previous.Index == step.Index) {
+			merged := step
+			merged.Value = step.Applied(previous.Value)
+			if (step.Mask != 0) && (previous.Mask != 0) {
+				merged.Mask = step.Mask | previous.Mask
+			} else {
+				merged.Mask = 0
+			}
+			rec.steps[last] = merged
+			return
+		}
+	}
+	rec.steps = append(rec.steps, step)
+}
+
+// Stop ends the recording and returns a macro with the recorded steps.
+func (rec *Recorder) Stop(name string) Macro {
+	macro := Macro{Name: name, Steps: rec.steps}
+	rec.active = false
+	rec.steps = nil
+	return macro
+}
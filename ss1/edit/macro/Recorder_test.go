@@ -0,0 +1,63 @@
+package macro_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
+)
+
+func TestRecorderIgnoresStepsWhileInactive(t *testing.T) {
+	var rec macro.Recorder
+	rec.Record(macro.Step{Target: macro.TargetTiles, Property: "floor height", Value: 1})
+	rec.Start()
+	assert.Equal(t, 0, rec.StepCount())
+	rec.Record(macro.Step{Target: macro.TargetTiles, Property: "ceiling height", Value: 2})
+	result := rec.Stop("test")
+	assert.Equal(t, macro.Macro{Name: "test", Steps: []macro.Step{
+		{Target: macro.TargetTiles, Property: "ceiling height", Value: 2},
+	}}, result)
+	assert.False(t, rec.Active(), "recorder should be inactive after stop")
+}
+
+func TestRecorderReplacesRepeatedProperty(t *testing.T) {
+	var rec macro.Recorder
+	rec.Start()
+	rec.Record(macro.Step{Target: macro.TargetLevel, Property: "texture", Index: 1, Value: 10})
+	rec.Record(macro.Step{Target: macro.TargetLevel, Property: "texture", Index: 1, Value: 11})
+	rec.Record(macro.Step{Target: macro.TargetLevel, Property: "texture", Index: 2, Value: 12})
+	rec.Record(macro.Step{Target: macro.TargetObjects, Property: "Z", Value: 3})
+	rec.Record(macro.Step{Target: macro.TargetLevel, Property: "texture", Index: 2, Value: 13})
+	result := rec.Stop("test")
+	assert.Equal(t, []macro.Step{
+		{Target: macro.TargetLevel, Property: "texture", Index: 1, Value: 11},
+		{Target: macro.TargetLevel, Property: "texture", Index: 2, Value: 12},
+		{Target: macro.TargetObjects, Property: "Z", Value: 3},
+		{Target: macro.TargetLevel, Property: "texture", Index: 2, Value: 13},
+	}, result.Steps)
+}
+
+func TestRecorderMergesMaskedSteps(t *testing.T) {
+	var rec macro.Recorder
+	rec.Start()
+	rec.Record(macro.Step{Target: macro.TargetObjects, Property: "Class.Flags", Value: 0x01, Mask: 0x01})
+	rec.Record(macro.Step{Target: macro.TargetObjects, Property: "Class.Flags", Value: 0x00, Mask: 0x04})
+	rec.Record(macro.Step{Target: macro.TargetObjects, Property: "Extra.Value", Value: 0x10})
+	rec.Record(macro.Step{Target: macro.TargetObjects, Property: "Extra.Value", Value: 0x02, Mask: 0x0F})
+	result := rec.Stop("test")
+	assert.Equal(t, []macro.Step{
+		{Target: macro.TargetObjects, Property: "Class.Flags", Value: 0x01, Mask: 0x05},
+		{Target: macro.TargetObjects, Property: "Extra.Value", Value: 0x12},
+	}, result.Steps)
+}
+
+func TestStepAppliedKeepsBitsOutsideMask(t *testing.T) {
+	assert.Equal(t, int64(0x35), macro.Step{Value: 0x05, Mask: 0x0F}.Applied(0x3A))
+	assert.Equal(t, int64(0x05), macro.Step{Value: 0x05}.Applied(0x3A))
+}
+
+func TestMacroTargets(t *testing.T) {
+	m := macro.Macro{Steps: []macro.Step{{Target: macro.TargetTiles}, {Target: macro.TargetLevel}}}
+	assert.Equal(t, map[macro.Target]bool{macro.TargetTiles: true, macro.TargetLevel: true}, m.Targets())
+}
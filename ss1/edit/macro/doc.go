@@ -0,0 +1,2 @@
+// Package macro provides recorded sequences of editing operations that can be replayed.
+package macro
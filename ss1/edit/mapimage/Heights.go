@@ -0,0 +1,55 @@
+package mapimage
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+// Heights is a table of brightness values, one per tile, in range [0.0 .. 1.0].
+// The table is indexed by tile row first, then by tile column.
+type Heights [][]float32
+
+// HeightsFrom samples the brightness of given image for a map of given size.
+func HeightsFrom(img image.Image, columns, rows int) Heights {
+	heights := make(Heights, rows)
+	for y := 0; y < rows; y++ {
+		heights[y] = make([]float32, columns)
+		for x := 0; x < columns; x++ {
+			px, py := samplePoint(img.Bounds(), x, y, columns, rows)
+			gray := color.Gray16Model.Convert(img.At(px, py)).(color.Gray16)
+			heights[y][x] = float32(gray.Y) / math.MaxUint16
+		}
+	}
+	return heights
+}
+
+// FloorAt returns the floor height of the tile at given position.
+// Black results in the lowest, white in the highest possible floor.
+func (heights Heights) FloorAt(x, y int) level.TileHeightUnit {
+	return level.TileHeightUnit(heights.scaledAt(x, y, float32(level.TileHeightUnitMax-1)))
+}
+
+// CeilingAt returns the ceiling height of the tile at given position.
+// Black results in the lowest, white in the highest possible ceiling.
+func (heights Heights) CeilingAt(x, y int) level.TileHeightUnit {
+	return 1 + level.TileHeightUnit(heights.scaledAt(x, y, float32(level.TileHeightUnitMax-1)))
+}
+
+func (heights Heights) covers(x, y int) bool {
+	return (y >= 0) && (y < len(heights)) && (x >= 0) && (x < len(heights[y]))
+}
+
+func (heights Heights) scaledAt(x, y int, scale float32) int {
+	return int(math.Round(float64(heights[y][x] * scale)))
+}
+
+// samplePoint returns the pixel position that corresponds to the center of a tile.
+func samplePoint(bounds image.Rectangle, x, y, columns, rows int) (int, int) {
+	row := rows - 1 - y
+	px := bounds.Min.X + ((2*x+1)*bounds.Dx())/(2*columns)
+	py := bounds.Min.Y + ((2*row+1)*bounds.Dy())/(2*rows)
+	return px, py
+}
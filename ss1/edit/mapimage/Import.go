@@ -0,0 +1,92 @@
+package mapimage
+
+import (
+	"image"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+// TileMap provides access to the tiles of a map.
+type TileMap interface {
+	Tile(x, y int) *level.TileMapEntry
+}
+
+// Import combines the sampled images that are applied to a map.
+// Any of the parts may be empty, in which case the corresponding properties are kept.
+type Import struct {
+	Floor   Heights
+	Ceiling Heights
+	Layout  Layout
+	Slots   map[uint8]Slot
+}
+
+// Empty returns true if the import would not change anything.
+func (imp Import) Empty() bool {
+	return (len(imp.Floor) == 0) && (len(imp.Ceiling) == 0) && (len(imp.Layout.Indices) == 0)
+}
+
+// Apply sets the imported properties on all the tiles of the given map.
+// Tiles outside of the sampled area are not changed.
+// Imported heights that would put the floor above the ceiling are clamped, see Clamped().
+func (imp Import) Apply(tileMap TileMap, columns, rows int) {
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			tile := tileMap.Tile(x, y)
+			if tile != nil {
+				imp.applyTo(tile, x, y)
+			}
+		}
+	}
+}
+
+// Clamped returns the positions of the tiles that would have their floor above the ceiling.
+// For these tiles, Apply() lowers an imported floor to the ceiling, or raises an imported ceiling to the floor.
+func (imp Import) Clamped(tileMap TileMap, columns, rows int) []image.Point {
+	var positions []image.Point
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			tile := tileMap.Tile(x, y)
+			if tile == nil {
+				continue
+			}
+			result := *tile
+			if imp.applyTo(&result, x, y) {
+				positions = append(positions, image.Point{X: x, Y: y})
+			}
+		}
+	}
+	return positions
+}
+
+// applyTo sets the imported properties on the given tile. It returns true if a height had to be clamped.
+func (imp Import) applyTo(tile *level.TileMapEntry, x, y int) bool {
+	if imp.Layout.covers(x, y) {
+		if slot, known := imp.Slots[imp.Layout.Indices[y][x]]; known {
+			slot.Apply(tile)
+		}
+	}
+	floor := tile.Floor.AbsoluteHeight()
+	ceiling := tile.Ceiling.AbsoluteHeight()
+	floorImported := imp.Floor.covers(x, y)
+	if floorImported {
+		floor = imp.Floor.FloorAt(x, y)
+	}
+	if imp.Ceiling.covers(x, y) {
+		ceiling = imp.Ceiling.CeilingAt(x, y)
+	}
+	clamped := (tile.Type != level.TileTypeSolid) && (floor > ceiling)
+	if clamped {
+		if floorImported {
+			floor = ceiling
+		} else {
+			ceiling = floor
+		}
+	}
+	if floorImported {
+		tile.Floor = tile.Floor.WithAbsoluteHeight(floor)
+	}
+	if imp.Ceiling.covers(x, y) {
+		tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(ceiling)
+	}
+	return clamped
+}
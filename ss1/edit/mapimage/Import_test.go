@@ -0,0 +1,137 @@
+package mapimage_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/mapimage"
+)
+
+func grayImage(width, height int, values func(x, y int) uint8) image.Image {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: values(x, y)})
+		}
+	}
+	return img
+}
+
+func TestHeightsFromMapsTopRowToNorth(t *testing.T) {
+	img := grayImage(2, 2, func(x, y int) uint8 {
+		if y == 0 {
+			return 0xFF
+		}
+		return 0x00
+	})
+	heights := mapimage.HeightsFrom(img, 2, 2)
+
+	assert.Equal(t, level.TileHeightUnit(31), heights.FloorAt(0, 1), "north floor")
+	assert.Equal(t, level.TileHeightUnit(0), heights.FloorAt(0, 0), "south floor")
+	assert.Equal(t, level.TileHeightUnit(32), heights.CeilingAt(1, 1), "north ceiling")
+	assert.Equal(t, level.TileHeightUnit(1), heights.CeilingAt(1, 0), "south ceiling")
+}
+
+func TestHeightsFromScalesImageToMapSize(t *testing.T) {
+	img := grayImage(128, 128, func(x, y int) uint8 {
+		if x < 64 {
+			return 0x00
+		}
+		return 0xFF
+	})
+	heights := mapimage.HeightsFrom(img, 64, 64)
+
+	require.Equal(t, 64, len(heights))
+	require.Equal(t, 64, len(heights[0]))
+	assert.Equal(t, level.TileHeightUnit(0), heights.FloorAt(31, 10))
+	assert.Equal(t, level.TileHeightUnit(31), heights.FloorAt(32, 10))
+}
+
+func TestLayoutFromRequiresIndexedImage(t *testing.T) {
+	_, err := mapimage.LayoutFrom(grayImage(2, 2, func(x, y int) uint8 { return 0 }), 2, 2)
+	assert.Equal(t, mapimage.ErrNotIndexed, err)
+}
+
+func TestLayoutFromSamplesIndices(t *testing.T) {
+	palette := color.Palette{color.Black, color.White, color.RGBA{R: 0xFF, A: 0xFF}}
+	img := image.NewPaletted(image.Rect(0, 0, 2, 2), palette)
+	img.SetColorIndex(0, 0, 2)
+	img.SetColorIndex(1, 1, 1)
+	layout, err := mapimage.LayoutFrom(img, 2, 2)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, [][]uint8{{0, 1}, {2, 0}}, layout.Indices)
+	assert.Equal(t, []uint8{0, 1, 2}, layout.UsedIndices())
+}
+
+func TestDefaultSlotsMapsDarkColorsToSolid(t *testing.T) {
+	layout := mapimage.Layout{
+		Palette: color.Palette{color.Black, color.White},
+		Indices: [][]uint8{{0, 1}},
+	}
+	slots := layout.DefaultSlots()
+
+	assert.Equal(t, level.TileTypeSolid, slots[0].Type)
+	assert.Equal(t, level.TileTypeOpen, slots[1].Type)
+	assert.Equal(t, -1, slots[1].FloorTexture)
+}
+
+func TestImportApplyKeepsUnsetProperties(t *testing.T) {
+	tileMap := level.NewTileMap(2, 1)
+	tileMap.Tile(0, 0).TextureInfo = tileMap.Tile(0, 0).TextureInfo.WithWallTextureIndex(7)
+	imp := mapimage.Import{
+		Floor: mapimage.Heights{{1.0, 0.5}},
+		Layout: mapimage.Layout{
+			Indices: [][]uint8{{0, 1}},
+		},
+		Slots: map[uint8]mapimage.Slot{
+			0: {Type: level.TileTypeDiagonalOpenNorthEast, FloorTexture: 3, CeilingTexture: -1, WallTexture: -1},
+		},
+	}
+	imp.Apply(tileMap, 2, 1)
+
+	first := tileMap.Tile(0, 0)
+	assert.Equal(t, level.TileTypeDiagonalOpenNorthEast, first.Type)
+	assert.Equal(t, 3, first.TextureInfo.FloorTextureIndex())
+	assert.Equal(t, 7, first.TextureInfo.WallTextureIndex())
+	assert.Equal(t, level.TileHeightUnit(31), first.Floor.AbsoluteHeight())
+
+	second := tileMap.Tile(1, 0)
+	assert.Equal(t, level.TileTypeSolid, second.Type, "unmapped index should keep type")
+	assert.Equal(t, level.TileHeightUnit(16), second.Floor.AbsoluteHeight())
+}
+
+func TestImportClampsFloorAboveCeiling(t *testing.T) {
+	tileMap := level.NewTileMap(3, 1)
+	for x := 0; x < 3; x++ {
+		tile := tileMap.Tile(x, 0)
+		tile.Type = level.TileTypeOpen
+		tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(16)
+	}
+	tileMap.Tile(2, 0).Type = level.TileTypeSolid
+	imp := mapimage.Import{Floor: mapimage.Heights{{1.0, 0.0, 1.0}}}
+
+	assert.Equal(t, []image.Point{{X: 0, Y: 0}}, imp.Clamped(tileMap, 3, 1))
+	assert.Equal(t, level.TileHeightUnit(0), tileMap.Tile(0, 0).Floor.AbsoluteHeight(), "clamped should not modify")
+
+	imp.Apply(tileMap, 3, 1)
+	assert.Equal(t, level.TileHeightUnit(16), tileMap.Tile(0, 0).Floor.AbsoluteHeight(), "floor should be lowered")
+	assert.Equal(t, level.TileHeightUnit(0), tileMap.Tile(1, 0).Floor.AbsoluteHeight())
+	assert.Equal(t, level.TileHeightUnit(31), tileMap.Tile(2, 0).Floor.AbsoluteHeight(), "solid tiles are not clamped")
+}
+
+func TestImportRaisesCeilingBelowFloor(t *testing.T) {
+	tileMap := level.NewTileMap(1, 1)
+	tile := tileMap.Tile(0, 0)
+	tile.Type = level.TileTypeOpen
+	tile.Floor = tile.Floor.WithAbsoluteHeight(20)
+	imp := mapimage.Import{Ceiling: mapimage.Heights{{0.0}}}
+
+	imp.Apply(tileMap, 1, 1)
+	assert.Equal(t, level.TileHeightUnit(20), tile.Ceiling.AbsoluteHeight())
+}
@@ -0,0 +1,100 @@
+package mapimage
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+// ErrNotIndexed is returned for layout images that do not have a color palette.
+var ErrNotIndexed = errors.New("image has no indexed colors")
+
+// Layout is a table of palette indices, one per tile.
+// The table is indexed by tile row first, then by tile column.
+type Layout struct {
+	Palette color.Palette
+	Indices [][]uint8
+}
+
+// LayoutFrom samples the palette indices of given image for a map of given size.
+// The image must be an indexed color image.
+func LayoutFrom(img image.Image, columns, rows int) (Layout, error) {
+	paletted, isPaletted := img.(image.PalettedImage)
+	if !isPaletted {
+		return Layout{}, ErrNotIndexed
+	}
+	palette, hasPalette := paletted.ColorModel().(color.Palette)
+	if !hasPalette {
+		return Layout{}, ErrNotIndexed
+	}
+	layout := Layout{
+		Palette: palette,
+		Indices: make([][]uint8, rows),
+	}
+	for y := 0; y < rows; y++ {
+		layout.Indices[y] = make([]uint8, columns)
+		for x := 0; x < columns; x++ {
+			px, py := samplePoint(img.Bounds(), x, y, columns, rows)
+			layout.Indices[y][x] = paletted.ColorIndexAt(px, py)
+		}
+	}
+	return layout, nil
+}
+
+func (layout Layout) covers(x, y int) bool {
+	return (y >= 0) && (y < len(layout.Indices)) && (x >= 0) && (x < len(layout.Indices[y]))
+}
+
+// UsedIndices returns the palette indices that appear in the layout, in ascending order.
+func (layout Layout) UsedIndices() []uint8 {
+	used := make(map[uint8]bool)
+	for _, row := range layout.Indices {
+		for _, index := range row {
+			used[index] = true
+		}
+	}
+	result := make([]uint8, 0, len(used))
+	for index := range used {
+		result = append(result, index)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a] < result[b] })
+	return result
+}
+
+// Slot describes what a palette index of a layout is mapped to.
+// Texture values refer to entries of the texture atlas of the level. Negative values keep the current texture.
+type Slot struct {
+	Type           level.TileType
+	FloorTexture   int
+	CeilingTexture int
+	WallTexture    int
+}
+
+// Apply sets the properties of the slot on given tile.
+func (slot Slot) Apply(tile *level.TileMapEntry) {
+	tile.Type = slot.Type
+	tile.TextureInfo = tile.TextureInfo.
+		WithFloorTextureIndex(slot.FloorTexture).
+		WithCeilingTextureIndex(slot.CeilingTexture).
+		WithWallTextureIndex(slot.WallTexture)
+}
+
+// DefaultSlots returns an initial mapping for the used indices of the layout.
+// Dark colors are mapped to solid tiles, bright colors to open tiles. Textures are kept.
+func (layout Layout) DefaultSlots() map[uint8]Slot {
+	slots := make(map[uint8]Slot)
+	for _, index := range layout.UsedIndices() {
+		slot := Slot{Type: level.TileTypeOpen, FloorTexture: -1, CeilingTexture: -1, WallTexture: -1}
+		if int(index) < len(layout.Palette) {
+			gray := color.GrayModel.Convert(layout.Palette[index]).(color.Gray)
+			if gray.Y < 0x80 {
+				slot.Type = level.TileTypeSolid
+			}
+		}
+		slots[index] = slot
+	}
+	return slots
+}
@@ -0,0 +1,2 @@
+// Package mapimage converts images into properties of level maps.
+package mapimage
@@ -0,0 +1,163 @@
+package maptransform
+
+import (
+	"errors"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/tilepaint"
+)
+
+// ErrOutOfBounds is returned for operations that would move tiles outside of the map.
+var ErrOutOfBounds = errors.New("region would be outside of the map")
+
+// fineUnits is the number of fine coordinate steps per tile.
+const fineUnits = 0x100
+
+// Operation describes a transformation of a region.
+// The region is re-oriented within its bounding box, keeping the south-west corner, and then moved by the offset.
+type Operation struct {
+	Orientation
+	OffsetX int
+	OffsetY int
+}
+
+// Rotate returns an operation that turns a region clockwise by given number of quarter turns.
+func Rotate(turns int) Operation {
+	return Operation{Orientation: Orientation{Turns: turns}}
+}
+
+// MirrorEastWest returns an operation that flips a region from east to west.
+func MirrorEastWest() Operation {
+	return Operation{Orientation: Orientation{Mirrored: true}}
+}
+
+// MirrorNorthSouth returns an operation that flips a region from north to south.
+func MirrorNorthSouth() Operation {
+	return Operation{Orientation: Orientation{Mirrored: true, Turns: 2}}
+}
+
+// Translate returns an operation that moves a region by given offset.
+func Translate(dx, dy int) Operation {
+	return Operation{OffsetX: dx, OffsetY: dy}
+}
+
+type bounds struct {
+	minX, minY    int
+	width, height int
+}
+
+func boundsOf(area tilepaint.Area) bounds {
+	points := area.Points()
+	b := bounds{minX: points[0].X, minY: points[0].Y}
+	maxX, maxY := b.minX, b.minY
+	for _, point := range points {
+		b.minX, maxX = minInt(b.minX, point.X), maxInt(maxX, point.X)
+		b.minY, maxY = minInt(b.minY, point.Y), maxInt(maxY, point.Y)
+	}
+	b.width = maxX - b.minX + 1
+	b.height = maxY - b.minY + 1
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// target returns the position a tile of the region is moved to.
+func (op Operation) target(b bounds, point tilepaint.Point) tilepaint.Point {
+	x, y := op.Point(point.X-b.minX, point.Y-b.minY, b.width, b.height)
+	return tilepaint.Point{X: b.minX + x + op.OffsetX, Y: b.minY + y + op.OffsetY}
+}
+
+// targetCoordinates returns the coordinates an object of the region is moved to.
+func (op Operation) targetCoordinates(b bounds, x, y level.Coordinate) (level.Coordinate, level.Coordinate) {
+	fineX, fineY := op.Position(int(x)-b.minX*fineUnits, int(y)-b.minY*fineUnits, b.width*fineUnits, b.height*fineUnits)
+	newX := fineX + (b.minX+op.OffsetX)*fineUnits
+	newY := fineY + (b.minY+op.OffsetY)*fineUnits
+	return level.Coordinate(newX), level.Coordinate(newY)
+}
+
+// Apply transforms the given region of the level. Tiles that are left by the region are reset to solid ones.
+// Objects within the region move with it. Objects at the destination that were not part of the region stay.
+// The returned area is the region at its new place.
+// If any tile would be outside of the map, the level is not modified and ErrOutOfBounds is returned.
+func (op Operation) Apply(lvl *level.Level, region tilepaint.Area) (tilepaint.Area, error) {
+	if len(region) == 0 {
+		return tilepaint.Area{}, nil
+	}
+	b := boundsOf(region)
+	columns, rows, _ := lvl.Size()
+	result := tilepaint.Area{}
+	sources := make(map[tilepaint.Point]level.TileMapEntry)
+	for point := range region {
+		tile := lvl.Tile(point.X, point.Y)
+		if tile == nil {
+			continue
+		}
+		target := op.target(b, point)
+		if (target.X < 0) || (target.X >= columns) || (target.Y < 0) || (target.Y >= rows) {
+			return tilepaint.Area{}, ErrOutOfBounds
+		}
+		sources[point] = *tile
+		result.Add(target)
+	}
+
+	// The references to objects belong to the position, not the tile properties.
+	// They are kept, and updated for the moved objects below.
+	cyberspace := lvl.IsCyberspace()
+	for point := range sources {
+		if !result[point] {
+			tile := lvl.Tile(point.X, point.Y)
+			firstObjectIndex := tile.FirstObjectIndex
+			tile.Reset()
+			tile.FirstObjectIndex = firstObjectIndex
+		}
+	}
+	for point, source := range sources {
+		target := op.target(b, point)
+		parityChanged := (point.X+point.Y)%2 != (target.X+target.Y)%2
+		tile := lvl.Tile(target.X, target.Y)
+		firstObjectIndex := tile.FirstObjectIndex
+		*tile = op.orientedTile(source, cyberspace, parityChanged)
+		tile.FirstObjectIndex = firstObjectIndex
+	}
+
+	var objects []level.ObjectID
+	lvl.ForEachObject(func(id level.ObjectID, entry level.ObjectMasterEntry) {
+		if _, within := sources[tilepaint.Point{X: int(entry.X.Tile()), Y: int(entry.Y.Tile())}]; within {
+			objects = append(objects, id)
+		}
+	})
+	for _, id := range objects {
+		obj := lvl.Object(id)
+		obj.X, obj.Y = op.targetCoordinates(b, obj.X, obj.Y)
+		obj.ZRotation = op.Heading(obj.ZRotation)
+		lvl.UpdateObjectLocation(id)
+	}
+
+	lvl.RecalculateWallHeights()
+	return result, nil
+}
+
+// orientedTile returns a copy of the tile with all its orientation dependent properties re-oriented.
+// The slope control and the usage of adjacent wall textures are kept, as they do not depend on the orientation.
+func (op Operation) orientedTile(tile level.TileMapEntry, cyberspace bool, parityChanged bool) level.TileMapEntry {
+	tile.Type = op.TileType(tile.Type)
+	if !cyberspace {
+		tile.Floor = tile.Floor.WithTextureRotations(op.TextureRotations(tile.Floor.TextureRotations()))
+		tile.Ceiling = tile.Ceiling.WithTextureRotations(op.TextureRotations(tile.Ceiling.TextureRotations()))
+		flags := tile.Flags.ForRealWorld()
+		tile.Flags = flags.WithWallTexturePattern(op.WallTexturePattern(flags.WallTexturePattern(), parityChanged)).AsTileFlag()
+	}
+	return tile
+}
@@ -0,0 +1,154 @@
+package maptransform_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/maptransform"
+	"github.com/inkyblackness/hacked/ss1/edit/tilepaint"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const levelID = 1
+
+func levelWithTiles(t *testing.T, modifier func(m level.TileMap)) *level.Level {
+	t.Helper()
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	levelData := level.EmptyLevelData(level.EmptyLevelParameters{MapModifier: modifier})
+	mod.Modify(func(modder world.Modder) {
+		for offset, data := range &levelData {
+			if len(data) > 0 {
+				modder.SetResourceBlocks(resource.LangAny, ids.LevelResourcesStart.Plus(lvlids.PerLevel*levelID+offset), [][]byte{data})
+			}
+		}
+	})
+	return level.NewLevel(ids.LevelResourcesStart, levelID, mod)
+}
+
+func openTile(m level.TileMap) {
+	m.Tile(10, 10).Type = level.TileTypeOpen
+}
+
+func openTileWithSlope(m level.TileMap) {
+	openTile(m)
+	tile := m.Tile(11, 10)
+	tile.Type = level.TileTypeSlopeWestToEast
+	tile.Floor = tile.Floor.WithTextureRotations(1)
+	tile.Flags = tile.Flags.ForRealWorld().WithWallTexturePattern(level.WallTexturePatternFlipHorizontal).AsTileFlag()
+}
+
+func placeObject(t *testing.T, lvl *level.Level, x, y level.Coordinate) level.ObjectID {
+	t.Helper()
+	id, err := lvl.NewObject(object.ClassSmallStuff)
+	require.Nil(t, err, "no error expected creating object")
+	obj := lvl.Object(id)
+	obj.X = x
+	obj.Y = y
+	obj.ZRotation = 0x40
+	lvl.UpdateObjectLocation(id)
+	return id
+}
+
+func region(points ...tilepaint.Point) tilepaint.Area {
+	area := tilepaint.Area{}
+	for _, point := range points {
+		area.Add(point)
+	}
+	return area
+}
+
+func objectsAt(lvl *level.Level, x, y int) []level.ObjectID {
+	var result []level.ObjectID
+	lvl.ForEachObject(func(id level.ObjectID, entry level.ObjectMasterEntry) {
+		if (int(entry.X.Tile()) == x) && (int(entry.Y.Tile()) == y) {
+			result = append(result, id)
+		}
+	})
+	return result
+}
+
+func TestApplyRotatesTilesAndObjects(t *testing.T) {
+	lvl := levelWithTiles(t, openTileWithSlope)
+	id := placeObject(t, lvl, level.CoordinateAt(11, 0x40), level.CoordinateAt(10, 0x20))
+
+	moved, err := maptransform.Rotate(1).Apply(lvl, region(tilepaint.Point{X: 10, Y: 10}, tilepaint.Point{X: 11, Y: 10}))
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []tilepaint.Point{{X: 10, Y: 10}, {X: 10, Y: 11}}, moved.Points())
+	assert.Equal(t, level.TileTypeOpen, lvl.Tile(10, 11).Type)
+	slope := lvl.Tile(10, 10)
+	assert.Equal(t, level.TileTypeSlopeNorthToSouth, slope.Type)
+	assert.Equal(t, 2, slope.Floor.TextureRotations())
+	assert.Equal(t, level.WallTexturePatternFlipHorizontal, slope.Flags.ForRealWorld().WallTexturePattern())
+	assert.Equal(t, level.TileTypeSolid, lvl.Tile(11, 10).Type, "vacated tile should be solid")
+
+	obj := lvl.Object(id)
+	assert.Equal(t, level.CoordinateAt(10, 0x20), obj.X)
+	assert.Equal(t, level.CoordinateAt(10, 0xC0), obj.Y)
+	assert.Equal(t, level.RotationUnit(0x80), obj.ZRotation)
+	assert.Equal(t, []level.ObjectID{id}, objectsAt(lvl, 10, 10))
+	assert.NotEqual(t, int16(0), lvl.Tile(10, 10).FirstObjectIndex, "cross reference expected at new tile")
+	assert.Equal(t, int16(0), lvl.Tile(11, 10).FirstObjectIndex, "cross reference expected to be removed")
+}
+
+func TestApplyMirrorsTiles(t *testing.T) {
+	lvl := levelWithTiles(t, openTileWithSlope)
+
+	_, err := maptransform.MirrorEastWest().Apply(lvl, region(tilepaint.Point{X: 10, Y: 10}, tilepaint.Point{X: 11, Y: 10}))
+	require.Nil(t, err, "no error expected")
+
+	mirrored := lvl.Tile(10, 10)
+	assert.Equal(t, level.TileTypeSlopeEastToWest, mirrored.Type)
+	assert.Equal(t, 3, mirrored.Floor.TextureRotations())
+	assert.Equal(t, level.WallTexturePatternRegular, mirrored.Flags.ForRealWorld().WallTexturePattern())
+	assert.Equal(t, level.TileTypeOpen, lvl.Tile(11, 10).Type)
+}
+
+func TestApplyMirrorsObjectsAroundTileGrid(t *testing.T) {
+	lvl := levelWithTiles(t, openTileWithSlope)
+	area := region(tilepaint.Point{X: 10, Y: 10}, tilepaint.Point{X: 11, Y: 10})
+	centre := placeObject(t, lvl, level.CoordinateAt(11, 0x00), level.CoordinateAt(10, 0x80))
+	offCentre := placeObject(t, lvl, level.CoordinateAt(10, 0x30), level.CoordinateAt(10, 0x80))
+
+	_, err := maptransform.MirrorEastWest().Apply(lvl, area)
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, level.CoordinateAt(11, 0x00), lvl.Object(centre).X, "centre should stay centre")
+	assert.Equal(t, level.CoordinateAt(11, 0xD0), lvl.Object(offCentre).X)
+
+	_, err = maptransform.MirrorEastWest().Apply(lvl, area)
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, level.CoordinateAt(11, 0x00), lvl.Object(centre).X)
+	assert.Equal(t, level.CoordinateAt(10, 0x30), lvl.Object(offCentre).X, "mirroring twice should restore")
+	assert.Equal(t, level.CoordinateAt(10, 0x80), lvl.Object(offCentre).Y)
+}
+
+func TestApplyTranslatesObjects(t *testing.T) {
+	lvl := levelWithTiles(t, openTile)
+	id := placeObject(t, lvl, level.CoordinateAt(10, 0x40), level.CoordinateAt(10, 0x20))
+
+	moved, err := maptransform.Translate(3, -2).Apply(lvl, region(tilepaint.Point{X: 10, Y: 10}))
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []tilepaint.Point{{X: 13, Y: 8}}, moved.Points())
+	assert.Equal(t, level.TileTypeOpen, lvl.Tile(13, 8).Type)
+	obj := lvl.Object(id)
+	assert.Equal(t, level.CoordinateAt(13, 0x40), obj.X)
+	assert.Equal(t, level.CoordinateAt(8, 0x20), obj.Y)
+	assert.Equal(t, level.RotationUnit(0x40), obj.ZRotation)
+	assert.Equal(t, []level.ObjectID{id}, objectsAt(lvl, 13, 8))
+}
+
+func TestApplyFailsOutsideOfMap(t *testing.T) {
+	lvl := levelWithTiles(t, openTile)
+
+	_, err := maptransform.Translate(-20, 0).Apply(lvl, region(tilepaint.Point{X: 10, Y: 10}))
+	assert.Equal(t, maptransform.ErrOutOfBounds, err)
+	assert.Equal(t, level.TileTypeOpen, lvl.Tile(10, 10).Type, "level should not be modified")
+}
@@ -0,0 +1,127 @@
+package maptransform
+
+import "github.com/inkyblackness/hacked/ss1/content/archive/level"
+
+// Orientation describes how a region is re-oriented.
+// Mirroring, if set, is applied first, followed by the turns.
+type Orientation struct {
+	// Mirrored flips the region from east to west.
+	Mirrored bool
+	// Turns is the number of clockwise quarter turns.
+	Turns int
+}
+
+func (o Orientation) normalizedTurns() int {
+	return ((o.Turns % 4) + 4) % 4
+}
+
+// Size returns the size of a region of given size after re-orientation.
+func (o Orientation) Size(width, height int) (int, int) {
+	if o.normalizedTurns()%2 == 1 {
+		return height, width
+	}
+	return width, height
+}
+
+// Point returns the position of a point within a region of given size after re-orientation.
+// The positions are relative to the south-west corner of the region.
+func (o Orientation) Point(x, y, width, height int) (int, int) {
+	if o.Mirrored {
+		x = width - 1 - x
+	}
+	for turn := 0; turn < o.normalizedTurns(); turn++ {
+		x, y = y, width-1-x
+		width, height = height, width
+	}
+	return x, y
+}
+
+// Position returns a position within an area of given size after re-orientation.
+// Unlike with Point, the values are positions on continuous axes, which are mirrored at the middle
+// of the area. A position on the lower edge is reflected onto the last position within the area.
+func (o Orientation) Position(x, y, width, height int) (int, int) {
+	if o.Mirrored {
+		x = width - x
+	}
+	for turn := 0; turn < o.normalizedTurns(); turn++ {
+		x, y = y, width-x
+		width, height = height, width
+	}
+	return minInt(x, width-1), minInt(y, height-1)
+}
+
+// Direction returns the direction after re-orientation.
+func (o Orientation) Direction(dir level.Direction) level.Direction {
+	if o.Mirrored {
+		dir = level.DirNorth.Offset(-int(dir))
+	}
+	return dir.Offset(2 * o.normalizedTurns())
+}
+
+// Heading returns the rotation of an object after re-orientation.
+// The rotation is considered to start north and to turn clockwise, as directions do.
+func (o Orientation) Heading(rotation level.RotationUnit) level.RotationUnit {
+	if o.Mirrored {
+		rotation = -rotation
+	}
+	return rotation + level.RotationUnit(64*o.normalizedTurns())
+}
+
+// TextureRotations returns the rotation steps of a floor or ceiling texture after re-orientation.
+func (o Orientation) TextureRotations(rotations int) int {
+	return int(o.Direction(level.DirNorth.Offset(2*rotations))) / 2
+}
+
+// TileType returns the type that has the shape of given type after re-orientation.
+// Solid sides and slopes are re-oriented.
+func (o Orientation) TileType(tileType level.TileType) level.TileType {
+	info := tileType.Info()
+	var sides level.DirectionMask
+	var factors level.SlopeFactors
+	for dir := level.DirNorth; dir <= level.DirNorthWest; dir++ {
+		if (info.SolidSides & dir.AsMask()) != 0 {
+			sides = sides.Plus(o.Direction(dir))
+		}
+		factors[o.Direction(dir)] = info.SlopeFloorFactors[dir]
+	}
+	for _, candidate := range level.TileTypes() {
+		candidateInfo := candidate.Info()
+		if (candidateInfo.SolidSides == sides) && (candidateInfo.SlopeFloorFactors == factors) {
+			return candidate
+		}
+	}
+	return tileType
+}
+
+// WallTexturePattern returns the pattern of wall textures after re-orientation.
+// Mirroring flips the textures. Alternating patterns are also swapped if the parity
+// of the tile position changes, as the alternation depends on it.
+func (o Orientation) WallTexturePattern(pattern level.WallTexturePattern, parityChanged bool) level.WallTexturePattern {
+	if o.Mirrored {
+		pattern = flippedPattern(pattern)
+	}
+	if parityChanged {
+		switch pattern {
+		case level.WallTexturePatternFlipAlternating:
+			pattern = level.WallTexturePatternFlipAlternatingInverted
+		case level.WallTexturePatternFlipAlternatingInverted:
+			pattern = level.WallTexturePatternFlipAlternating
+		}
+	}
+	return pattern
+}
+
+func flippedPattern(pattern level.WallTexturePattern) level.WallTexturePattern {
+	switch pattern {
+	case level.WallTexturePatternRegular:
+		return level.WallTexturePatternFlipHorizontal
+	case level.WallTexturePatternFlipHorizontal:
+		return level.WallTexturePatternRegular
+	case level.WallTexturePatternFlipAlternating:
+		return level.WallTexturePatternFlipAlternatingInverted
+	case level.WallTexturePatternFlipAlternatingInverted:
+		return level.WallTexturePatternFlipAlternating
+	default:
+		return pattern
+	}
+}
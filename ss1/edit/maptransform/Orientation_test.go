@@ -0,0 +1,93 @@
+package maptransform_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/maptransform"
+)
+
+func TestOrientationPointTurnsClockwise(t *testing.T) {
+	o := maptransform.Orientation{Turns: 1}
+	x, y := o.Point(0, 2, 2, 3)
+	assert.Equal(t, []int{2, 1}, []int{x, y}, "north-west corner should become north-east corner")
+	width, height := o.Size(2, 3)
+	assert.Equal(t, []int{3, 2}, []int{width, height})
+}
+
+func TestOrientationPointMirrors(t *testing.T) {
+	x, y := maptransform.Orientation{Mirrored: true}.Point(0, 1, 3, 2)
+	assert.Equal(t, []int{2, 1}, []int{x, y}, "east-west")
+	x, y = maptransform.Orientation{Mirrored: true, Turns: 2}.Point(0, 1, 3, 2)
+	assert.Equal(t, []int{0, 0}, []int{x, y}, "north-south")
+}
+
+func TestOrientationPositionKeepsCentre(t *testing.T) {
+	for _, o := range []maptransform.Orientation{{Mirrored: true}, {Turns: 1}, {Mirrored: true, Turns: 3}} {
+		x, y := o.Position(256, 128, 512, 256)
+		width, height := o.Size(512, 256)
+		assert.Equal(t, []int{width / 2, height / 2}, []int{x, y}, "centre should stay for %v", o)
+	}
+	x, y := maptransform.Orientation{Mirrored: true}.Position(128, 128, 256, 256)
+	assert.Equal(t, []int{128, 128}, []int{x, y}, "tile centre should stay")
+	x, y = maptransform.Orientation{Mirrored: true}.Position(0, 10, 256, 256)
+	assert.Equal(t, []int{255, 10}, []int{x, y}, "lower edge should stay within area")
+}
+
+func TestOrientationDirection(t *testing.T) {
+	assert.Equal(t, level.DirEast, maptransform.Orientation{Turns: 1}.Direction(level.DirNorth))
+	assert.Equal(t, level.DirNorth, maptransform.Orientation{Turns: -1}.Direction(level.DirEast))
+	assert.Equal(t, level.DirNorthWest, maptransform.Orientation{Mirrored: true}.Direction(level.DirNorthEast))
+	assert.Equal(t, level.DirSouthEast, maptransform.Orientation{Mirrored: true, Turns: 2}.Direction(level.DirNorthEast))
+}
+
+func TestOrientationTileType(t *testing.T) {
+	tt := []struct {
+		o        maptransform.Orientation
+		from     level.TileType
+		expected level.TileType
+	}{
+		{maptransform.Orientation{Turns: 1}, level.TileTypeOpen, level.TileTypeOpen},
+		{maptransform.Orientation{Turns: 1}, level.TileTypeDiagonalOpenSouthEast, level.TileTypeDiagonalOpenSouthWest},
+		{maptransform.Orientation{Turns: 2}, level.TileTypeDiagonalOpenSouthEast, level.TileTypeDiagonalOpenNorthWest},
+		{maptransform.Orientation{Mirrored: true}, level.TileTypeDiagonalOpenSouthEast, level.TileTypeDiagonalOpenSouthWest},
+		{maptransform.Orientation{Turns: 1}, level.TileTypeSlopeSouthToNorth, level.TileTypeSlopeWestToEast},
+		{maptransform.Orientation{Mirrored: true}, level.TileTypeSlopeWestToEast, level.TileTypeSlopeEastToWest},
+		{maptransform.Orientation{Mirrored: true}, level.TileTypeSlopeSouthToNorth, level.TileTypeSlopeSouthToNorth},
+		{maptransform.Orientation{Turns: 1}, level.TileTypeValleySouthEastToNorthWest, level.TileTypeValleySouthWestToNorthEast},
+		{maptransform.Orientation{Turns: 3}, level.TileTypeRidgeNorthWestToSouthEast, level.TileTypeRidgeSouthWestToNorthEast},
+	}
+	for _, tc := range tt {
+		assert.Equal(t, tc.expected, tc.o.TileType(tc.from), "%v of %v", tc.o, tc.from)
+	}
+}
+
+func TestOrientationTileTypeRoundTrip(t *testing.T) {
+	for _, tileType := range level.TileTypes() {
+		result := tileType
+		for turn := 0; turn < 4; turn++ {
+			result = maptransform.Orientation{Turns: 1}.TileType(result)
+		}
+		assert.Equal(t, tileType, result, "four turns of %v", tileType)
+		mirror := maptransform.Orientation{Mirrored: true}
+		assert.Equal(t, tileType, mirror.TileType(mirror.TileType(tileType)), "double mirror of %v", tileType)
+	}
+}
+
+func TestOrientationHeadingAndTextureRotations(t *testing.T) {
+	assert.Equal(t, level.RotationUnit(0x50), maptransform.Orientation{Turns: 1}.Heading(0x10))
+	assert.Equal(t, level.RotationUnit(0xC0), maptransform.Orientation{Mirrored: true}.Heading(0x40))
+	assert.Equal(t, 0, maptransform.Orientation{Turns: 1}.TextureRotations(3))
+	assert.Equal(t, 3, maptransform.Orientation{Mirrored: true}.TextureRotations(1))
+}
+
+func TestOrientationWallTexturePattern(t *testing.T) {
+	mirror := maptransform.Orientation{Mirrored: true}
+	assert.Equal(t, level.WallTexturePatternFlipHorizontal, mirror.WallTexturePattern(level.WallTexturePatternRegular, false))
+	assert.Equal(t, level.WallTexturePatternFlipAlternating, mirror.WallTexturePattern(level.WallTexturePatternFlipAlternating, true))
+	assert.Equal(t, level.WallTexturePatternFlipAlternatingInverted,
+		maptransform.Orientation{}.WallTexturePattern(level.WallTexturePatternFlipAlternating, true))
+	assert.Equal(t, level.WallTexturePatternRegular, maptransform.Orientation{Turns: 1}.WallTexturePattern(level.WallTexturePatternRegular, true))
+}
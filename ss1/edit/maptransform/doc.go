@@ -0,0 +1,2 @@
+// Package maptransform moves and re-orients regions of a level map.
+package maptransform
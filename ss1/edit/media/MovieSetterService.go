@@ -16,12 +16,12 @@ type MovieBlockSetter interface {
 
 // MovieSetterService can be used to set movie data.
 type MovieSetterService struct {
-	cp text.Codepage
+	cps text.Codepages
 }
 
 // NewMovieSetterService returns a new instance.
-func NewMovieSetterService(cp text.Codepage) MovieSetterService {
-	return MovieSetterService{cp: cp}
+func NewMovieSetterService(cps text.Codepages) MovieSetterService {
+	return MovieSetterService{cps: cps}
 }
 
 // Remove deletes any movie resource for given key.
@@ -32,6 +32,6 @@ func (service MovieSetterService) Remove(setter MovieBlockSetter, key resource.K
 // Set exports the given container.
 func (service MovieSetterService) Set(setter MovieBlockSetter, key resource.Key, container movie.Container) {
 	buf := bytes.NewBuffer(nil)
-	_ = movie.Write(buf, container, service.cp)
+	_ = movie.Write(buf, container, service.cps)
 	setter.SetResourceBlocks(key.Lang, key.ID, [][]byte{buf.Bytes()})
 }
@@ -15,16 +15,21 @@ type TextBlockSetter interface {
 
 // TextSetterService provides methods to change text resources.
 type TextSetterService struct {
-	cp text.Codepage
+	cps text.Codepages
 }
 
 // NewTextSetterService returns a new instance.
-func NewTextSetterService(cp text.Codepage) TextSetterService {
+func NewTextSetterService(cps text.Codepages) TextSetterService {
 	return TextSetterService{
-		cp: cp,
+		cps: cps,
 	}
 }
 
+// Unencodable returns the characters of the given value that can not be stored in the language of the key.
+func (service TextSetterService) Unencodable(key resource.Key, value string) []rune {
+	return text.Unencodable(service.cps.ForLanguage(key.Lang), value)
+}
+
 // Remove deletes any text resource for given key.
 func (service TextSetterService) Remove(setter TextBlockSetter, key resource.Key) {
 	info, _ := ids.Info(key.ID)
@@ -44,14 +49,15 @@ func (service TextSetterService) Clear(setter TextBlockSetter, key resource.Key)
 // Set stores the given text as the identified resource.
 func (service TextSetterService) Set(setter TextBlockSetter, key resource.Key, value string) {
 	blockedValue := text.Blocked(value)
+	cp := service.cps.ForLanguage(key.Lang)
 	info, _ := ids.Info(key.ID)
 	if info.List {
-		newData := service.cp.Encode(blockedValue[0])
+		newData := cp.Encode(blockedValue[0])
 		setter.SetResourceBlock(key.Lang, key.ID, key.Index, newData)
 	} else {
 		newData := make([][]byte, len(blockedValue))
 		for index, blockLine := range blockedValue {
-			newData[index] = service.cp.Encode(blockLine)
+			newData[index] = cp.Encode(blockLine)
 		}
 		id := key.ID.Plus(key.Index)
 		setter.SetResourceBlocks(key.Lang, id, newData)
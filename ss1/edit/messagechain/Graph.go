@@ -0,0 +1,243 @@
+package messagechain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// videoMailDisplayBase is the first value of LeftDisplay that selects a video mail instead of a bitmap.
+const videoMailDisplayBase = 256
+
+// Source provides the messages and the resources they refer to.
+type Source interface {
+	// Message returns the message with given key. Returns an error if the message does not exist.
+	Message(key resource.Key) (text.ElectronicMessage, error)
+	// HasAudio returns true if the message with given key has a sound.
+	HasAudio(key resource.Key) bool
+	// DisplayCount returns the number of MFD bitmaps that are available in given language.
+	DisplayCount(lang resource.Language) int
+}
+
+// Node is one existing message within the graph.
+type Node struct {
+	Key         resource.Key
+	Title       string
+	Next        int
+	IsInterrupt bool
+	// Predecessors lists the messages that link to this message.
+	Predecessors []resource.Key
+}
+
+// Graph describes all messages of one language, together with the issues found.
+type Graph struct {
+	Nodes  []Node
+	Issues []Issue
+
+	nodeIndices map[resource.Key]int
+}
+
+// Analyze builds the graph of all messages in given language and checks them for issues.
+func Analyze(source Source, lang resource.Language) Graph {
+	graph := Graph{nodeIndices: make(map[resource.Key]int)}
+	for _, group := range Groups {
+		info, _ := ids.Info(group)
+		for index := 0; index < info.MaxCount; index++ {
+			key := resource.KeyOf(group, lang, index)
+			msg, err := source.Message(key)
+			if err != nil {
+				continue
+			}
+			graph.nodeIndices[key] = len(graph.Nodes)
+			graph.Nodes = append(graph.Nodes, Node{
+				Key:         key,
+				Title:       msg.Title,
+				Next:        msg.NextMessage,
+				IsInterrupt: msg.IsInterrupt,
+			})
+			graph.checkDisplays(key, msg, source.DisplayCount(lang))
+			graph.checkAudio(source, key)
+		}
+	}
+	for _, node := range graph.Nodes {
+		graph.checkTarget(source, node)
+	}
+	for _, node := range graph.Nodes {
+		if node.IsInterrupt && (len(node.Predecessors) == 0) {
+			graph.report(node.Key, UnreferencedInterrupt, "no message links to this interrupt")
+		}
+	}
+	graph.checkCycles()
+	return graph
+}
+
+// Node returns the node of the message with given key. Returns false if the message does not exist.
+func (graph Graph) Node(key resource.Key) (Node, bool) {
+	index, exists := graph.nodeIndices[key]
+	if !exists {
+		return Node{}, false
+	}
+	return graph.Nodes[index], true
+}
+
+// Successor returns the key of the message the given node links to.
+// Returns false if the node has no link, or the link refers to a message that does not exist.
+func (graph Graph) Successor(node Node) (resource.Key, bool) {
+	if node.Next < 0 {
+		return resource.Key{}, false
+	}
+	key, valid := KeyOfIndex(node.Key.Lang, node.Next)
+	if !valid {
+		return key, false
+	}
+	_, exists := graph.nodeIndices[key]
+	return key, exists
+}
+
+// Chains returns the sequences of linked messages, each starting with a message no other links to.
+// Messages that are only reachable within a cycle start a sequence of their own.
+// Every message is part of exactly one sequence.
+func (graph Graph) Chains() [][]resource.Key {
+	var chains [][]resource.Key
+	visited := make(map[resource.Key]bool)
+	follow := func(start Node) {
+		var chain []resource.Key
+		node := start
+		for !visited[node.Key] {
+			visited[node.Key] = true
+			chain = append(chain, node.Key)
+			next, exists := graph.Successor(node)
+			if !exists {
+				break
+			}
+			node, _ = graph.Node(next)
+		}
+		chains = append(chains, chain)
+	}
+	for _, node := range graph.Nodes {
+		if len(node.Predecessors) == 0 {
+			follow(node)
+		}
+	}
+	for _, node := range graph.Nodes {
+		if !visited[node.Key] {
+			follow(node)
+		}
+	}
+	return chains
+}
+
+// IssuesOf returns the issues of the message with given key.
+func (graph Graph) IssuesOf(key resource.Key) []Issue {
+	var issues []Issue
+	for _, issue := range graph.Issues {
+		if issue.Key == key {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func (graph *Graph) report(key resource.Key, kind IssueKind, format string, args ...interface{}) {
+	graph.Issues = append(graph.Issues, Issue{Key: key, Kind: kind, Description: fmt.Sprintf(format, args...)})
+}
+
+func (graph *Graph) checkDisplays(key resource.Key, msg text.ElectronicMessage, available int) {
+	if msg.LeftDisplay >= videoMailDisplayBase {
+		return
+	}
+	if msg.LeftDisplay >= available {
+		graph.report(key, DisplayOutOfRange, "left display %d is beyond the %d available bitmaps", msg.LeftDisplay, available)
+	}
+	if msg.RightDisplay >= available {
+		graph.report(key, DisplayOutOfRange, "right display %d is beyond the %d available bitmaps", msg.RightDisplay, available)
+	}
+}
+
+func (graph *Graph) checkAudio(source Source, key resource.Key) {
+	if (key.ID != ids.MailsStart) && (key.ID != ids.LogsStart) {
+		return
+	}
+	if source.HasAudio(key) {
+		return
+	}
+	var others []string
+	for _, lang := range resource.Languages() {
+		otherKey := key
+		otherKey.Lang = lang
+		if (lang != key.Lang) && source.HasAudio(otherKey) {
+			others = append(others, lang.String())
+		}
+	}
+	if len(others) > 0 {
+		graph.report(key, MissingAudio, "audio is missing, while available in %s", strings.Join(others, ", "))
+	}
+}
+
+func (graph *Graph) checkTarget(source Source, node Node) {
+	if node.Next < 0 {
+		return
+	}
+	targetKey, valid := KeyOfIndex(node.Key.Lang, node.Next)
+	if !valid {
+		graph.report(node.Key, EmptyTarget, "next message %d does not refer to a message", node.Next)
+		return
+	}
+	targetIndex, exists := graph.nodeIndices[targetKey]
+	if !exists {
+		graph.report(node.Key, EmptyTarget, "next message %s does not exist", NameOf(targetKey))
+		return
+	}
+	graph.Nodes[targetIndex].Predecessors = append(graph.Nodes[targetIndex].Predecessors, node.Key)
+	target, _ := source.Message(targetKey)
+	if (len(target.Title) == 0) && (len(target.VerboseText) == 0) && (len(target.TerseText) == 0) {
+		graph.report(node.Key, EmptyTarget, "next message %s has no text", NameOf(targetKey))
+	}
+}
+
+// checkCycles reports each cycle once, at the first message of the cycle.
+func (graph *Graph) checkCycles() {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	states := make([]int, len(graph.Nodes))
+	for start := range graph.Nodes {
+		var path []int
+		current := start
+		for states[current] == unvisited {
+			states[current] = inProgress
+			path = append(path, current)
+			next, exists := graph.Successor(graph.Nodes[current])
+			if !exists {
+				break
+			}
+			current = graph.nodeIndices[next]
+			if states[current] == inProgress {
+				graph.reportCycle(path, current)
+			}
+		}
+		for _, index := range path {
+			states[index] = done
+		}
+	}
+}
+
+func (graph *Graph) reportCycle(path []int, closing int) {
+	var names []string
+	first := -1
+	for _, index := range path {
+		if index == closing {
+			first = index
+		}
+		if first >= 0 {
+			names = append(names, NameOf(graph.Nodes[index].Key))
+		}
+	}
+	names = append(names, NameOf(graph.Nodes[closing].Key))
+	graph.report(graph.Nodes[closing].Key, Cycle, "messages form a cycle: %s", strings.Join(names, " -> "))
+}
@@ -0,0 +1,188 @@
+package messagechain_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit/messagechain"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+type testingSource struct {
+	messages     map[resource.Key]text.ElectronicMessage
+	audio        map[resource.Key]bool
+	displayCount int
+}
+
+func newTestingSource() *testingSource {
+	return &testingSource{
+		messages:     make(map[resource.Key]text.ElectronicMessage),
+		audio:        make(map[resource.Key]bool),
+		displayCount: 10,
+	}
+}
+
+func (source *testingSource) Message(key resource.Key) (text.ElectronicMessage, error) {
+	msg, exists := source.messages[key]
+	if !exists {
+		return msg, fmt.Errorf("not found")
+	}
+	return msg, nil
+}
+
+func (source *testingSource) HasAudio(key resource.Key) bool {
+	return source.audio[key]
+}
+
+func (source *testingSource) DisplayCount(lang resource.Language) int {
+	return source.displayCount
+}
+
+func (source *testingSource) add(key resource.Key, modifier func(msg *text.ElectronicMessage)) {
+	msg := text.EmptyElectronicMessage()
+	msg.Title = "Title of " + messagechain.NameOf(key)
+	modifier(&msg)
+	source.messages[key] = msg
+}
+
+func mail(index int) resource.Key {
+	return resource.KeyOf(ids.MailsStart, resource.LangDefault, index)
+}
+
+func log(index int) resource.Key {
+	return resource.KeyOf(ids.LogsStart, resource.LangDefault, index)
+}
+
+func fragment(index int) resource.Key {
+	return resource.KeyOf(ids.FragmentsStart, resource.LangDefault, index)
+}
+
+func linkTo(key resource.Key) func(*text.ElectronicMessage) {
+	return func(msg *text.ElectronicMessage) { msg.NextMessage = messagechain.IndexOfKey(key) }
+}
+
+func noChange(*text.ElectronicMessage) {}
+
+func issueKinds(issues []messagechain.Issue) []messagechain.IssueKind {
+	var kinds []messagechain.IssueKind
+	for _, issue := range issues {
+		kinds = append(kinds, issue.Kind)
+	}
+	return kinds
+}
+
+func TestKeyOfIndex(t *testing.T) {
+	tt := []struct {
+		index    int
+		expected resource.Key
+		valid    bool
+	}{
+		{index: 0, expected: mail(0), valid: true},
+		{index: 46, expected: mail(46), valid: true},
+		{index: 47, expected: log(0), valid: true},
+		{index: 47 + 135, expected: log(135), valid: true},
+		{index: 47 + 136, valid: false},
+		{index: -1, valid: false},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(fmt.Sprintf("%d", td.index), func(t *testing.T) {
+			key, valid := messagechain.KeyOfIndex(resource.LangDefault, td.index)
+			assert.Equal(t, td.valid, valid)
+			if td.valid {
+				assert.Equal(t, td.expected, key)
+				assert.Equal(t, td.index, messagechain.IndexOfKey(key))
+			}
+		})
+	}
+}
+
+func TestAnalyzeCollectsChains(t *testing.T) {
+	source := newTestingSource()
+	source.add(mail(1), linkTo(log(2)))
+	source.add(log(2), linkTo(log(3)))
+	source.add(log(3), func(msg *text.ElectronicMessage) { msg.IsInterrupt = true })
+	source.add(fragment(0), noChange)
+
+	graph := messagechain.Analyze(source, resource.LangDefault)
+
+	assert.Equal(t, [][]resource.Key{{mail(1), log(2), log(3)}, {fragment(0)}}, graph.Chains())
+	assert.Equal(t, 0, len(graph.Issues), "no issues expected")
+	node, exists := graph.Node(log(3))
+	require.True(t, exists)
+	assert.Equal(t, []resource.Key{log(2)}, node.Predecessors)
+}
+
+func TestAnalyzeReportsLinksToEmptyMessages(t *testing.T) {
+	source := newTestingSource()
+	source.add(mail(1), linkTo(log(2)))
+	source.add(mail(2), linkTo(log(3)))
+	source.add(log(3), func(msg *text.ElectronicMessage) { msg.Title = "" })
+	source.add(mail(3), func(msg *text.ElectronicMessage) { msg.NextMessage = 250 })
+
+	graph := messagechain.Analyze(source, resource.LangDefault)
+
+	assert.Equal(t, []messagechain.IssueKind{messagechain.EmptyTarget}, issueKinds(graph.IssuesOf(mail(1))), "missing")
+	assert.Equal(t, []messagechain.IssueKind{messagechain.EmptyTarget}, issueKinds(graph.IssuesOf(mail(2))), "without text")
+	assert.Equal(t, []messagechain.IssueKind{messagechain.EmptyTarget}, issueKinds(graph.IssuesOf(mail(3))), "out of range")
+}
+
+func TestAnalyzeReportsCyclesOnce(t *testing.T) {
+	source := newTestingSource()
+	source.add(mail(0), linkTo(log(1)))
+	source.add(log(1), linkTo(log(2)))
+	source.add(log(2), linkTo(log(1)))
+	source.add(log(5), linkTo(log(5)))
+
+	graph := messagechain.Analyze(source, resource.LangDefault)
+
+	assert.Equal(t, []messagechain.IssueKind{messagechain.Cycle, messagechain.Cycle}, issueKinds(graph.Issues))
+	assert.Equal(t, []messagechain.IssueKind{messagechain.Cycle}, issueKinds(graph.IssuesOf(log(1))))
+	assert.Equal(t, []messagechain.IssueKind{messagechain.Cycle}, issueKinds(graph.IssuesOf(log(5))))
+	assert.Equal(t, [][]resource.Key{{mail(0), log(1), log(2)}, {log(5)}}, graph.Chains())
+}
+
+func TestAnalyzeReportsUnreferencedInterrupts(t *testing.T) {
+	source := newTestingSource()
+	source.add(log(4), func(msg *text.ElectronicMessage) { msg.IsInterrupt = true })
+
+	graph := messagechain.Analyze(source, resource.LangDefault)
+
+	assert.Equal(t, []messagechain.IssueKind{messagechain.UnreferencedInterrupt}, issueKinds(graph.Issues))
+}
+
+func TestAnalyzeReportsDisplaysOutOfRange(t *testing.T) {
+	source := newTestingSource()
+	source.add(log(0), func(msg *text.ElectronicMessage) { msg.LeftDisplay = 10 })
+	source.add(log(1), func(msg *text.ElectronicMessage) { msg.LeftDisplay = 9; msg.RightDisplay = 12 })
+	source.add(mail(0), func(msg *text.ElectronicMessage) { msg.LeftDisplay = 256; msg.RightDisplay = 300 })
+
+	graph := messagechain.Analyze(source, resource.LangDefault)
+
+	assert.Equal(t, []messagechain.IssueKind{messagechain.DisplayOutOfRange}, issueKinds(graph.IssuesOf(log(0))), "left")
+	assert.Equal(t, []messagechain.IssueKind{messagechain.DisplayOutOfRange}, issueKinds(graph.IssuesOf(log(1))), "right")
+	assert.Equal(t, 0, len(graph.IssuesOf(mail(0))), "video mail")
+}
+
+func TestAnalyzeReportsMissingAudio(t *testing.T) {
+	source := newTestingSource()
+	source.add(log(0), noChange)
+	source.add(log(1), noChange)
+	source.add(fragment(0), noChange)
+	germanLog := log(0)
+	germanLog.Lang = resource.LangGerman
+	source.audio[germanLog] = true
+	germanFragment := fragment(0)
+	germanFragment.Lang = resource.LangGerman
+	source.audio[germanFragment] = true
+
+	graph := messagechain.Analyze(source, resource.LangDefault)
+
+	assert.Equal(t, []messagechain.IssueKind{messagechain.MissingAudio}, issueKinds(graph.Issues))
+	assert.Equal(t, log(0), graph.Issues[0].Key)
+}
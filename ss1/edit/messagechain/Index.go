@@ -0,0 +1,40 @@
+package messagechain
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// Groups lists the first identifiers of the message groups, in the order of their indices.
+var Groups = []resource.ID{ids.MailsStart, ids.LogsStart, ids.FragmentsStart}
+
+var groupNames = map[resource.ID]string{
+	ids.MailsStart:     "Mail",
+	ids.LogsStart:      "Log",
+	ids.FragmentsStart: "Fragment",
+}
+
+// KeyOfIndex returns the key of the message with given index, as it is used for NextMessage.
+// Returns false if the index does not refer to a message.
+func KeyOfIndex(lang resource.Language, index int) (resource.Key, bool) {
+	for _, group := range Groups {
+		info, _ := ids.Info(group)
+		groupIndex := index - int(group.Value()-ids.MailsStart.Value())
+		if (groupIndex >= 0) && (groupIndex < info.MaxCount) {
+			return resource.KeyOf(group, lang, groupIndex), true
+		}
+	}
+	return resource.KeyOf(ids.MailsStart, lang, 0), false
+}
+
+// IndexOfKey returns the index of the message with given key, as it is used for NextMessage.
+func IndexOfKey(key resource.Key) int {
+	return int(key.ID.Value()-ids.MailsStart.Value()) + key.Index
+}
+
+// NameOf returns a human readable name of the message, such as "Log #12".
+func NameOf(key resource.Key) string {
+	return fmt.Sprintf("%s #%d", groupNames[key.ID], key.Index)
+}
@@ -0,0 +1,44 @@
+package messagechain
+
+import "github.com/inkyblackness/hacked/ss1/resource"
+
+// IssueKind identifies the type of a finding.
+type IssueKind int
+
+// Kinds of issues within message chains.
+const (
+	// EmptyTarget marks a message that links to a message which does not exist or has no text.
+	EmptyTarget IssueKind = iota
+	// Cycle marks a message that is part of a sequence which leads back to itself.
+	Cycle
+	// UnreferencedInterrupt marks an interrupt message that no other message links to.
+	UnreferencedInterrupt
+	// DisplayOutOfRange marks a message that shows an MFD bitmap which is not available.
+	DisplayOutOfRange
+	// MissingAudio marks a message that has no audio, while it has in other languages.
+	MissingAudio
+)
+
+func (kind IssueKind) String() string {
+	switch kind {
+	case EmptyTarget:
+		return "Empty Target"
+	case Cycle:
+		return "Cycle"
+	case UnreferencedInterrupt:
+		return "Unreferenced Interrupt"
+	case DisplayOutOfRange:
+		return "Display Out Of Range"
+	case MissingAudio:
+		return "Missing Audio"
+	default:
+		return "Unknown"
+	}
+}
+
+// Issue is a finding about one message.
+type Issue struct {
+	Key         resource.Key
+	Kind        IssueKind
+	Description string
+}
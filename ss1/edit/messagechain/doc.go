@@ -0,0 +1,2 @@
+// Package messagechain analyzes how electronic messages are linked into sequences.
+package messagechain
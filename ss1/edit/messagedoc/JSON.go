@@ -0,0 +1,59 @@
+package messagedoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type jsonDocument struct {
+	Messages []Message `json:"messages"`
+}
+
+// WriteJSON encodes the given messages as one JSON document.
+func WriteJSON(writer io.Writer, messages []Message) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(jsonDocument{Messages: messages})
+}
+
+// ReadJSON decodes the messages of a JSON document.
+// Properties that are not specified keep the values of an empty message.
+func ReadJSON(reader io.Reader) ([]Message, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	var raw struct {
+		Messages []json.RawMessage `json:"messages"`
+	}
+	err := decoder.Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]Message, 0, len(raw.Messages))
+	for index, entry := range raw.Messages {
+		msg := emptyMessage()
+		entryDecoder := json.NewDecoder(bytes.NewReader(entry))
+		entryDecoder.DisallowUnknownFields()
+		err = entryDecoder.Decode(&msg)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %v", index, err)
+		}
+		err = msg.validate()
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %v", index, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func emptyMessage() Message {
+	return Message{
+		NextMessage:  -1,
+		ColorIndex:   -1,
+		LeftDisplay:  -1,
+		RightDisplay: -1,
+	}
+}
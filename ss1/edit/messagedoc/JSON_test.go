@@ -0,0 +1,63 @@
+package messagedoc_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/messagedoc"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	other := aMessage()
+	other.Type = messagedoc.TypeFragment
+	other.Audio = ""
+	messages := []messagedoc.Message{aMessage(), other}
+
+	err := messagedoc.WriteJSON(buf, messages)
+	require.Nil(t, err)
+	result, err := messagedoc.ReadJSON(bytes.NewReader(buf.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, messages, result)
+}
+
+func TestReadJSONUsesDefaultsForMissingProperties(t *testing.T) {
+	input := `{"messages":[{"type":"log","index":3,"language":"Default","title":"Hello"}]}`
+
+	result, err := messagedoc.ReadJSON(strings.NewReader(input))
+
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, "Hello", result[0].Title)
+	assert.Equal(t, -1, result[0].NextMessage, "NextMessage")
+	assert.Equal(t, -1, result[0].ColorIndex, "ColorIndex")
+	assert.Equal(t, -1, result[0].LeftDisplay, "LeftDisplay")
+	assert.Equal(t, -1, result[0].RightDisplay, "RightDisplay")
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+	}{
+		{name: "invalid syntax", input: `{"messages":[`},
+		{name: "unknown document property", input: `{"msgs":[]}`},
+		{name: "unknown message property", input: `{"messages":[{"type":"log","index":1,"language":"Default","font":2}]}`},
+		{name: "unknown type", input: `{"messages":[{"type":"memo","index":1,"language":"Default"}]}`},
+		{name: "negative index", input: `{"messages":[{"type":"log","index":-1,"language":"Default"}]}`},
+		{name: "missing language", input: `{"messages":[{"type":"log","index":1}]}`},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := messagedoc.ReadJSON(strings.NewReader(td.input))
+			assert.NotNil(t, err)
+		})
+	}
+}
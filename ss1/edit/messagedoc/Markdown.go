@@ -0,0 +1,164 @@
+package messagedoc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+const (
+	frontMatterDelimiter = "---"
+	terseTextMarker      = "<!-- terse -->"
+)
+
+// WriteMarkdown encodes a single message as a Markdown document.
+//
+// The properties and short texts are stored in a front-matter header, as "key: value" lines between
+// two "---" lines. Text values are quoted. The verbose text follows as body of the document.
+// The terse text, if present, is appended after a line containing only "<!-- terse -->".
+// Leading and trailing line breaks of the texts are not preserved.
+func WriteMarkdown(writer io.Writer, msg Message) error {
+	buffered := bufio.NewWriter(writer)
+	writeLine := func(line string) { _, _ = buffered.WriteString(line + "\n") }
+	writeInt := func(key string, value int) { writeLine(key + ": " + strconv.Itoa(value)) }
+	writeString := func(key string, value string) { writeLine(key + ": " + strconv.Quote(value)) }
+
+	writeLine(frontMatterDelimiter)
+	writeString("type", msg.Type)
+	writeInt("index", msg.Index)
+	writeString("language", msg.Language)
+	writeInt("nextMessage", msg.NextMessage)
+	writeLine("interrupt: " + strconv.FormatBool(msg.IsInterrupt))
+	writeInt("color", msg.ColorIndex)
+	writeInt("leftDisplay", msg.LeftDisplay)
+	writeInt("rightDisplay", msg.RightDisplay)
+	writeString("title", msg.Title)
+	writeString("sender", msg.Sender)
+	writeString("subject", msg.Subject)
+	if len(msg.Audio) > 0 {
+		writeString("audio", msg.Audio)
+	}
+	writeLine(frontMatterDelimiter)
+	writeLine(msg.VerboseText)
+	if len(msg.TerseText) > 0 {
+		writeLine("")
+		writeLine(terseTextMarker)
+		writeLine(msg.TerseText)
+	}
+	return buffered.Flush()
+}
+
+// ReadMarkdown decodes a single message from a Markdown document, as written by WriteMarkdown.
+// Properties that are not specified keep the values of an empty message.
+func ReadMarkdown(reader io.Reader) (Message, error) {
+	msg := emptyMessage()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return msg, err
+	}
+	lines := strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n")
+	if (len(lines) == 0) || (strings.TrimSpace(lines[0]) != frontMatterDelimiter) {
+		return msg, fmt.Errorf("missing front-matter")
+	}
+	bodyStart := -1
+	for index := 1; (index < len(lines)) && (bodyStart < 0); index++ {
+		line := lines[index]
+		if strings.TrimSpace(line) == frontMatterDelimiter {
+			bodyStart = index + 1
+			continue
+		}
+		if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		err = msg.setProperty(line)
+		if err != nil {
+			return msg, fmt.Errorf("line %d: %v", index+1, err)
+		}
+	}
+	if bodyStart < 0 {
+		return msg, fmt.Errorf("unterminated front-matter")
+	}
+	body := lines[bodyStart:]
+	terseStart := len(body)
+	for index, line := range body {
+		if strings.TrimSpace(line) == terseTextMarker {
+			terseStart = index
+			break
+		}
+	}
+	msg.VerboseText = joinedText(body[:terseStart])
+	if terseStart < len(body) {
+		msg.TerseText = joinedText(body[terseStart+1:])
+	}
+	return msg, msg.validate()
+}
+
+// joinedText returns the lines as one text, without leading and trailing empty lines.
+func joinedText(lines []string) string {
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func (msg *Message) setProperty(line string) error {
+	separator := strings.Index(line, ":")
+	if separator < 0 {
+		return fmt.Errorf("missing separator")
+	}
+	key := strings.TrimSpace(line[:separator])
+	value := strings.TrimSpace(line[separator+1:])
+	stringValue := func(target *string) error {
+		if strings.HasPrefix(value, "\"") {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("invalid text for <%s>", key)
+			}
+			value = unquoted
+		}
+		*target = value
+		return nil
+	}
+	intValue := func(target *int) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid number for <%s>", key)
+		}
+		*target = parsed
+		return nil
+	}
+
+	switch key {
+	case "type":
+		return stringValue(&msg.Type)
+	case "index":
+		return intValue(&msg.Index)
+	case "language":
+		return stringValue(&msg.Language)
+	case "nextMessage":
+		return intValue(&msg.NextMessage)
+	case "interrupt":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid flag for <%s>", key)
+		}
+		msg.IsInterrupt = parsed
+		return nil
+	case "color":
+		return intValue(&msg.ColorIndex)
+	case "leftDisplay":
+		return intValue(&msg.LeftDisplay)
+	case "rightDisplay":
+		return intValue(&msg.RightDisplay)
+	case "title":
+		return stringValue(&msg.Title)
+	case "sender":
+		return stringValue(&msg.Sender)
+	case "subject":
+		return stringValue(&msg.Subject)
+	case "audio":
+		return stringValue(&msg.Audio)
+	default:
+		return fmt.Errorf("unknown property <%s>", key)
+	}
+}
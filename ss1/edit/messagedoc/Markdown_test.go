@@ -0,0 +1,88 @@
+package messagedoc_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/messagedoc"
+)
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	msg := aMessage()
+
+	err := messagedoc.WriteMarkdown(buf, msg)
+	require.Nil(t, err)
+	result, err := messagedoc.ReadMarkdown(bytes.NewReader(buf.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, msg, result)
+}
+
+func TestMarkdownRoundTripWithoutTerseText(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	msg := aMessage()
+	msg.TerseText = ""
+	msg.Audio = ""
+
+	err := messagedoc.WriteMarkdown(buf, msg)
+	require.Nil(t, err)
+	result, err := messagedoc.ReadMarkdown(bytes.NewReader(buf.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, msg, result)
+}
+
+func TestReadMarkdownAcceptsHandWrittenDocuments(t *testing.T) {
+	input := "---\r\n" +
+		"type: log\r\n" +
+		"index: 7\r\n" +
+		"# comment\r\n" +
+		"language: French\r\n" +
+		"title: Unquoted: title\r\n" +
+		"---\r\n" +
+		"\r\n" +
+		"Some text.\r\n" +
+		"\r\n" +
+		"<!-- terse -->\r\n" +
+		"Terse.\r\n"
+
+	result, err := messagedoc.ReadMarkdown(strings.NewReader(input))
+
+	require.Nil(t, err)
+	assert.Equal(t, messagedoc.TypeLog, result.Type)
+	assert.Equal(t, 7, result.Index)
+	assert.Equal(t, "French", result.Language)
+	assert.Equal(t, "Unquoted: title", result.Title)
+	assert.Equal(t, "Some text.", result.VerboseText)
+	assert.Equal(t, "Terse.", result.TerseText)
+	assert.Equal(t, -1, result.NextMessage, "NextMessage")
+}
+
+func TestReadMarkdownErrors(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing front-matter", input: "type: log\n"},
+		{name: "unterminated front-matter", input: "---\ntype: log\nindex: 1\nlanguage: Default\n"},
+		{name: "unknown property", input: "---\nfont: 2\n---\n"},
+		{name: "missing separator", input: "---\ntype log\n---\n"},
+		{name: "invalid number", input: "---\ntype: log\nindex: one\nlanguage: Default\n---\n"},
+		{name: "invalid flag", input: "---\ntype: log\nindex: 1\nlanguage: Default\ninterrupt: maybe\n---\n"},
+		{name: "invalid quoting", input: "---\ntype: \"log\nindex: 1\nlanguage: Default\n---\n"},
+		{name: "missing language", input: "---\ntype: log\nindex: 1\n---\n"},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := messagedoc.ReadMarkdown(strings.NewReader(td.input))
+			assert.NotNil(t, err)
+		})
+	}
+}
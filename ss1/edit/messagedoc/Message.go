@@ -0,0 +1,97 @@
+package messagedoc
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+// Message types of the documents.
+const (
+	TypeMail     = "mail"
+	TypeLog      = "log"
+	TypeFragment = "fragment"
+)
+
+// Message is one electronic message in one language.
+type Message struct {
+	// Type is one of "mail", "log", or "fragment".
+	Type string `json:"type"`
+	// Index is the number of the message within its type.
+	Index int `json:"index"`
+	// Language is the name of the human language of the texts.
+	Language string `json:"language"`
+
+	NextMessage  int  `json:"nextMessage"`
+	IsInterrupt  bool `json:"interrupt"`
+	ColorIndex   int  `json:"color"`
+	LeftDisplay  int  `json:"leftDisplay"`
+	RightDisplay int  `json:"rightDisplay"`
+
+	Title       string `json:"title"`
+	Sender      string `json:"sender"`
+	Subject     string `json:"subject"`
+	VerboseText string `json:"verboseText"`
+	TerseText   string `json:"terseText"`
+
+	// Audio optionally refers to a WAV file, relative to the document.
+	Audio string `json:"audio,omitempty"`
+}
+
+// FromElectronicMessage returns a message with the properties and texts of given message.
+func FromElectronicMessage(msgType string, index int, language string, msg text.ElectronicMessage) Message {
+	return Message{
+		Type:     msgType,
+		Index:    index,
+		Language: language,
+
+		NextMessage:  msg.NextMessage,
+		IsInterrupt:  msg.IsInterrupt,
+		ColorIndex:   msg.ColorIndex,
+		LeftDisplay:  msg.LeftDisplay,
+		RightDisplay: msg.RightDisplay,
+
+		Title:       msg.Title,
+		Sender:      msg.Sender,
+		Subject:     msg.Subject,
+		VerboseText: msg.VerboseText,
+		TerseText:   msg.TerseText,
+	}
+}
+
+// ElectronicMessage returns the properties and texts as an electronic message.
+func (msg Message) ElectronicMessage() text.ElectronicMessage {
+	return text.ElectronicMessage{
+		NextMessage:  msg.NextMessage,
+		IsInterrupt:  msg.IsInterrupt,
+		ColorIndex:   msg.ColorIndex,
+		LeftDisplay:  msg.LeftDisplay,
+		RightDisplay: msg.RightDisplay,
+
+		Title:       msg.Title,
+		Sender:      msg.Sender,
+		Subject:     msg.Subject,
+		VerboseText: msg.VerboseText,
+		TerseText:   msg.TerseText,
+	}
+}
+
+// BaseName returns the file name, without extension, that the message is typically stored under.
+func (msg Message) BaseName() string {
+	return fmt.Sprintf("%s_%03d_%s", msg.Type, msg.Index, msg.Language)
+}
+
+func (msg Message) validate() error {
+	switch msg.Type {
+	case TypeMail, TypeLog, TypeFragment:
+	default:
+		return fmt.Errorf("unknown message type <%s>", msg.Type)
+	}
+	if msg.Index < 0 {
+		return fmt.Errorf("invalid index %d", msg.Index)
+	}
+	if len(msg.Language) == 0 {
+		return fmt.Errorf("missing language")
+	}
+	return nil
+}
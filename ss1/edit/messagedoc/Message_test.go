@@ -0,0 +1,24 @@
+package messagedoc_test
+
+import (
+	"github.com/inkyblackness/hacked/ss1/edit/messagedoc"
+)
+
+func aMessage() messagedoc.Message {
+	return messagedoc.Message{
+		Type:         messagedoc.TypeMail,
+		Index:        12,
+		Language:     "German",
+		NextMessage:  13,
+		IsInterrupt:  true,
+		ColorIndex:   0x46,
+		LeftDisplay:  20,
+		RightDisplay: -1,
+		Title:        "Title",
+		Sender:       "Rebecca \"Becky\" Lansing",
+		Subject:      "Subject: important",
+		VerboseText:  "First paragraph.\n\nSecond paragraph, with ---\nin it.",
+		TerseText:    "Short.",
+		Audio:        "mail_012_German.wav",
+	}
+}
@@ -0,0 +1,2 @@
+// Package messagedoc provides file formats to exchange electronic messages with text editors.
+package messagedoc
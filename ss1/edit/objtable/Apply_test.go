@@ -0,0 +1,37 @@
+package objtable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+func kindIs(kind uint32) interpreters.Predicate {
+	return func(inst *interpreters.Instance) bool { return inst.Get("Kind") == kind }
+}
+
+func TestApplyFieldsResolvesDependentFieldsAfterEachValue(t *testing.T) {
+	first := interpreters.New().With("Value", 0, 2)
+	second := interpreters.New().With("Other", 0, 1)
+	desc := interpreters.New().
+		With("Kind", 0, 1).
+		Refining("First", 1, 2, first, kindIs(0)).
+		Refining("Second", 1, 2, second, kindIs(1))
+	data := []byte{0x00, 0xAA, 0xBB}
+	instance := desc.For(data)
+	row := Row{Triple: object.TripleFrom(1, 2, 3), Values: map[string]string{
+		"x.Kind":         "1",
+		"x.First.Value":  "513",
+		"x.Second.Other": "7",
+	}}
+
+	issues := applyFields(row, func() []field { return interpreterFields("x.", instance) })
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "x.First.Value", issues[0].Column, "field of inactive refinement should not be set")
+	assert.Equal(t, []byte{0x01, 0x07, 0xBB}, data)
+}
@@ -0,0 +1,98 @@
+package objtable
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+var csvKeyColumns = []string{"Class", "Subclass", "Type", "Name"}
+
+// WriteCSV encodes the table with a header line. The first columns identify the object type,
+// cells of properties that are not available for a type are left empty.
+func WriteCSV(writer io.Writer, table Table) error {
+	csvWriter := csv.NewWriter(writer)
+	err := csvWriter.Write(append(append([]string{}, csvKeyColumns...), table.Columns...))
+	if err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		record := []string{
+			strconv.Itoa(int(row.Triple.Class)),
+			strconv.Itoa(int(row.Triple.Subclass)),
+			strconv.Itoa(int(row.Triple.Type)),
+			row.Name,
+		}
+		for _, column := range table.Columns {
+			record = append(record, row.Values[column])
+		}
+		err = csvWriter.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// ReadCSV decodes a table as written by WriteCSV.
+// The name column is optional, and the property columns may be in any order.
+func ReadCSV(reader io.Reader) (Table, error) {
+	var table Table
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err != nil {
+		return table, err
+	}
+	for index, key := range csvKeyColumns[:3] {
+		if (len(header) <= index) || !strings.EqualFold(strings.TrimSpace(header[index]), key) {
+			return table, fmt.Errorf("header must start with columns %s", strings.Join(csvKeyColumns[:3], ", "))
+		}
+	}
+	firstValue := 3
+	if (len(header) > firstValue) && strings.EqualFold(strings.TrimSpace(header[firstValue]), csvKeyColumns[3]) {
+		firstValue++
+	}
+	for _, column := range header[firstValue:] {
+		table.Columns = append(table.Columns, strings.TrimSpace(column))
+	}
+
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return table, err
+		}
+		triple, err := parseTriple(record[0], record[1], record[2])
+		if err != nil {
+			return table, fmt.Errorf("line %d: %v", line, err)
+		}
+		row := Row{Triple: triple, Values: make(map[string]string)}
+		if firstValue > 3 {
+			row.Name = record[3]
+		}
+		for index, column := range table.Columns {
+			row.Values[column] = record[firstValue+index]
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+func parseTriple(class, subclass, objType string) (object.Triple, error) {
+	var values [3]int
+	for index, text := range []string{class, subclass, objType} {
+		value, err := strconv.ParseUint(strings.TrimSpace(text), 10, 8)
+		if err != nil {
+			return object.Triple{}, fmt.Errorf("invalid object type <%s/%s/%s>", class, subclass, objType)
+		}
+		values[index] = int(value)
+	}
+	return object.TripleFrom(values[0], values[1], values[2]), nil
+}
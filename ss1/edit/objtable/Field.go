@@ -0,0 +1,223 @@
+package objtable
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+)
+
+// Column prefixes of the property groups.
+const (
+	CommonPrefix   = "common."
+	GenericPrefix  = "generic."
+	SpecificPrefix = "specific."
+)
+
+// valueRange describes which values a field accepts.
+// If names are given, only their keys are accepted, and the limits are not considered.
+type valueRange struct {
+	minValue int64
+	maxValue int64
+	names    map[uint32]string
+}
+
+// decoded returns the raw value as a number of the range. Negative values are sign-extended.
+func (r valueRange) decoded(raw uint32) int64 {
+	value := int64(raw)
+	if (r.minValue < 0) && (value > r.maxValue) {
+		for _, bits := range []uint{8, 16, 32} {
+			if value < (1 << bits) {
+				return value - (1 << bits)
+			}
+		}
+	}
+	return value
+}
+
+func (r valueRange) format(raw uint32) string {
+	if name, known := r.names[raw]; known {
+		return name
+	}
+	return strconv.FormatInt(r.decoded(raw), 10)
+}
+
+func (r valueRange) parse(cell string) (uint32, error) {
+	cell = strings.TrimSpace(cell)
+	for key, name := range r.names {
+		if strings.EqualFold(name, cell) {
+			return key, nil
+		}
+	}
+	value, err := strconv.ParseInt(cell, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value <%s>", cell)
+	}
+	if r.names != nil {
+		if _, known := r.names[uint32(value)]; !known || (value < 0) {
+			return 0, fmt.Errorf("unknown value <%s>", cell)
+		}
+		return uint32(value), nil
+	}
+	if (value < r.minValue) || (value > r.maxValue) {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", value, r.minValue, r.maxValue)
+	}
+	return uint32(value), nil
+}
+
+// field is one named property of an object.
+type field struct {
+	column string
+	valueRange
+	get func() uint32
+	set func(uint32)
+}
+
+func (f field) cell() string {
+	return f.format(f.get())
+}
+
+// fieldsOf returns all currently available fields of the given properties.
+// The fields refer to the data of the properties; Specific nested fields depend on the current values.
+func fieldsOf(triple object.Triple, prop *object.Properties) []field {
+	fields := commonFields(&prop.Common)
+	fields = append(fields, interpreterFields(GenericPrefix, objprop.GenericProperties(triple.Class, prop.Generic))...)
+	fields = append(fields, interpreterFields(SpecificPrefix, objprop.SpecificProperties(triple, prop.Specific))...)
+	return fields
+}
+
+func interpreterFields(path string, instance *interpreters.Instance) []field {
+	var fields []field
+	for _, key := range instance.Keys() {
+		interpreterKey := key
+		f := field{
+			column: path + key,
+			get:    func() uint32 { return instance.Get(interpreterKey) },
+			set:    func(value uint32) { instance.Set(interpreterKey, value) },
+		}
+		instance.Describe(key, rangeSimplifier(&f.valueRange))
+		fields = append(fields, f)
+	}
+	for _, key := range instance.ActiveRefinements() {
+		fields = append(fields, interpreterFields(path+key+".", instance.Refined(key))...)
+	}
+	return fields
+}
+
+func rangeSimplifier(r *valueRange) *interpreters.Simplifier {
+	simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {
+		r.minValue = minValue
+		r.maxValue = maxValue
+	})
+	simplifier.SetEnumValueHandler(func(values map[uint32]string) {
+		r.names = values
+	})
+	simplifier.SetBitfieldHandler(func(values map[uint32]string) {
+		var allBits uint32
+		for mask := range values {
+			allBits |= mask
+		}
+		r.maxValue = 1
+		for r.maxValue <= int64(allBits) {
+			r.maxValue <<= 8
+		}
+		r.maxValue--
+	})
+	return simplifier
+}
+
+func commonFields(common *object.CommonProperties) []field {
+	byteField := func(name string, maxValue int64, value *byte) field {
+		return field{
+			column:     CommonPrefix + name,
+			valueRange: valueRange{minValue: 0, maxValue: maxValue},
+			get:        func() uint32 { return uint32(*value) },
+			set:        func(newValue uint32) { *value = byte(newValue) },
+		}
+	}
+	wordField := func(name string, value *uint16) field {
+		return field{
+			column:     CommonPrefix + name,
+			valueRange: valueRange{minValue: 0, maxValue: math.MaxUint16},
+			get:        func() uint32 { return uint32(*value) },
+			set:        func(newValue uint32) { *value = uint16(newValue) },
+		}
+	}
+	namesOf := func(count int, nameFor func(int) string) map[uint32]string {
+		names := make(map[uint32]string)
+		for index := 0; index < count; index++ {
+			names[uint32(index)] = nameFor(index)
+		}
+		return names
+	}
+
+	return []field{
+		{
+			column:     CommonPrefix + "Mass",
+			valueRange: valueRange{minValue: math.MinInt32, maxValue: math.MaxInt32},
+			get:        func() uint32 { return uint32(common.Mass) },
+			set:        func(newValue uint32) { common.Mass = int32(newValue) },
+		},
+		{
+			column:     CommonPrefix + "Hitpoints",
+			valueRange: valueRange{minValue: math.MinInt16, maxValue: math.MaxInt16},
+			get:        func() uint32 { return uint32(uint16(common.Hitpoints)) },
+			set:        func(newValue uint32) { common.Hitpoints = int16(newValue) },
+		},
+		byteField("Armor", math.MaxUint8, &common.Armor),
+		{
+			column: CommonPrefix + "RenderType",
+			valueRange: valueRange{names: namesOf(len(object.RenderTypes()),
+				func(index int) string { return object.RenderType(index).String() })},
+			get: func() uint32 { return uint32(common.RenderType) },
+			set: func(newValue uint32) { common.RenderType = object.RenderType(newValue) },
+		},
+		{
+			column: CommonPrefix + "PhysicsModel",
+			valueRange: valueRange{names: namesOf(len(object.PhysicsModels()),
+				func(index int) string { return object.PhysicsModel(index).String() })},
+			get: func() uint32 { return uint32(common.PhysicsModel) },
+			set: func(newValue uint32) { common.PhysicsModel = object.PhysicsModel(newValue) },
+		},
+		byteField("Hardness", object.HardnessLimit, &common.Hardness),
+		byteField("PhysicsXR", object.PhysicsXRLimit, &common.PhysicsXR),
+		byteField("PhysicsZ", math.MaxUint8, &common.PhysicsZ),
+		{
+			column:     CommonPrefix + "Vulnerabilities",
+			valueRange: valueRange{minValue: 0, maxValue: math.MaxUint8},
+			get:        func() uint32 { return uint32(common.Vulnerabilities) },
+			set:        func(newValue uint32) { common.Vulnerabilities = object.DamageTypeMask(newValue) },
+		},
+		{
+			column:     CommonPrefix + "SpecialVulnerabilities",
+			valueRange: valueRange{minValue: 0, maxValue: math.MaxUint8},
+			get:        func() uint32 { return uint32(common.SpecialVulnerabilities) },
+			set:        func(newValue uint32) { common.SpecialVulnerabilities = object.SpecialDamageType(newValue) },
+		},
+		byteField("Defense", math.MaxUint8, &common.Defense),
+		byteField("Toughness", math.MaxUint8, &common.Toughness),
+		{
+			column:     CommonPrefix + "Flags",
+			valueRange: valueRange{minValue: 0, maxValue: math.MaxUint16},
+			get:        func() uint32 { return uint32(common.Flags) },
+			set:        func(newValue uint32) { common.Flags = object.CommonFlagField(newValue) },
+		},
+		wordField("MfdOrMeshID", &common.MfdOrMeshID),
+		{
+			column:     CommonPrefix + "Bitmap3D",
+			valueRange: valueRange{minValue: 0, maxValue: math.MaxUint16},
+			get:        func() uint32 { return uint32(common.Bitmap3D) },
+			set:        func(newValue uint32) { common.Bitmap3D = object.Bitmap3D(newValue) },
+		},
+		{
+			column:     CommonPrefix + "DestroyEffect",
+			valueRange: valueRange{minValue: 0, maxValue: math.MaxUint8},
+			get:        func() uint32 { return uint32(common.DestroyEffect) },
+			set:        func(newValue uint32) { common.DestroyEffect = object.DestroyEffect(newValue) },
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package objtable_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/objtable"
+)
+
+func aTable() objtable.Table {
+	return objtable.Table{
+		Columns: []string{"common.Mass", "common.RenderType", "generic.FireRate"},
+		Rows: []objtable.Row{
+			{
+				Triple: object.TripleFrom(0, 0, 1),
+				Name:   "rifle, \"big\"",
+				Values: map[string]string{"common.Mass": "-5", "common.RenderType": "Bitmap", "generic.FireRate": "3"},
+			},
+			{
+				Triple: object.TripleFrom(2, 1, 0),
+				Values: map[string]string{"common.Mass": "10", "common.RenderType": "TPoly"},
+			},
+		},
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, objtable.WriteCSV(&buf, aTable()))
+	table, err := objtable.ReadCSV(&buf)
+	require.Nil(t, err)
+
+	expected := aTable()
+	expected.Rows[1].Values["generic.FireRate"] = ""
+	assert.Equal(t, expected, table)
+}
+
+func TestReadCSVWithoutNameColumn(t *testing.T) {
+	table, err := objtable.ReadCSV(bytes.NewBufferString("class,subclass,type,common.Mass\n1,2,3,40\n"))
+	require.Nil(t, err)
+	require.Len(t, table.Rows, 1)
+	assert.Equal(t, object.TripleFrom(1, 2, 3), table.Rows[0].Triple)
+	assert.Equal(t, map[string]string{"common.Mass": "40"}, table.Rows[0].Values)
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing header", input: ""},
+		{name: "wrong key columns", input: "Type,Class,Subclass\n"},
+		{name: "invalid triple", input: "Class,Subclass,Type\n1,x,3\n"},
+		{name: "triple out of range", input: "Class,Subclass,Type\n1,2,300\n"},
+		{name: "inconsistent column count", input: "Class,Subclass,Type,a\n1,2,3\n"},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := objtable.ReadCSV(bytes.NewBufferString(td.input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, objtable.WriteJSON(&buf, aTable()))
+	assert.Contains(t, buf.String(), `"common.Mass": -5`, "numbers should be written as such")
+	table, err := objtable.ReadJSON(&buf)
+	require.Nil(t, err)
+
+	assert.Equal(t, aTable(), table)
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+	}{
+		{name: "invalid document", input: "["},
+		{name: "unknown field", input: `{"objects":[{"class":1,"extra":2}]}`},
+		{name: "triple out of range", input: `{"objects":[{"class":1,"subclass":256,"type":0}]}`},
+		{name: "invalid value type", input: `{"objects":[{"class":1,"subclass":0,"type":0,"values":{"a":true}}]}`},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := objtable.ReadJSON(bytes.NewBufferString(td.input))
+			assert.Error(t, err)
+		})
+	}
+}
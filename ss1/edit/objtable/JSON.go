@@ -0,0 +1,89 @@
+package objtable
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+type jsonRow struct {
+	Class    int                    `json:"class"`
+	Subclass int                    `json:"subclass"`
+	Type     int                    `json:"type"`
+	Name     string                 `json:"name,omitempty"`
+	Values   map[string]interface{} `json:"values"`
+}
+
+type jsonDocument struct {
+	Objects []jsonRow `json:"objects"`
+}
+
+// WriteJSON encodes the table as one JSON document.
+// Numerical values are written as numbers, named values as strings.
+func WriteJSON(writer io.Writer, table Table) error {
+	var doc jsonDocument
+	for _, row := range table.Rows {
+		entry := jsonRow{
+			Class:    int(row.Triple.Class),
+			Subclass: int(row.Triple.Subclass),
+			Type:     int(row.Triple.Type),
+			Name:     row.Name,
+			Values:   make(map[string]interface{}),
+		}
+		for column, value := range row.Values {
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+				entry.Values[column] = json.Number(value)
+			} else {
+				entry.Values[column] = value
+			}
+		}
+		doc.Objects = append(doc.Objects, entry)
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(doc)
+}
+
+// ReadJSON decodes a table as written by WriteJSON.
+// The columns of the returned table are in alphabetical order.
+func ReadJSON(reader io.Reader) (Table, error) {
+	var table Table
+	var doc jsonDocument
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	decoder.UseNumber()
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return table, err
+	}
+	columns := make(map[string]string)
+	for index, entry := range doc.Objects {
+		if (entry.Class < 0) || (entry.Class > 0xFF) || (entry.Subclass < 0) || (entry.Subclass > 0xFF) ||
+			(entry.Type < 0) || (entry.Type > 0xFF) {
+			return table, fmt.Errorf("object %d: invalid object type", index)
+		}
+		row := Row{
+			Triple: object.TripleFrom(entry.Class, entry.Subclass, entry.Type),
+			Name:   entry.Name,
+			Values: make(map[string]string),
+		}
+		for column, value := range entry.Values {
+			switch typed := value.(type) {
+			case json.Number:
+				row.Values[column] = typed.String()
+			case string:
+				row.Values[column] = typed
+			default:
+				return table, fmt.Errorf("object %d: value of %s must be a number or a string", index, column)
+			}
+			columns[column] = column
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	table.Columns = sortedColumns(columns)
+	return table, nil
+}
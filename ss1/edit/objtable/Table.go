@@ -0,0 +1,149 @@
+package objtable
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+// Row contains the property values of one object type, keyed by column.
+// Empty values are not considered during import.
+type Row struct {
+	Triple object.Triple
+	Name   string
+	Values map[string]string
+}
+
+// Table is a list of rows, with the columns in order of their first appearance.
+type Table struct {
+	Columns []string
+	Rows    []Row
+}
+
+// Issue describes a cell that could not be imported.
+type Issue struct {
+	Triple  object.Triple
+	Column  string
+	Message string
+}
+
+// String returns a textual representation of the issue.
+func (issue Issue) String() string {
+	if len(issue.Column) == 0 {
+		return fmt.Sprintf("%v: %s", issue.Triple, issue.Message)
+	}
+	return fmt.Sprintf("%v %s: %s", issue.Triple, issue.Column, issue.Message)
+}
+
+// Change is the new state of the properties of an object type.
+type Change struct {
+	Triple     object.Triple
+	Properties object.Properties
+}
+
+// Export returns the properties of the given object types as a table.
+// The name function may be nil.
+func Export(table object.PropertiesTable, triples []object.Triple, nameOf func(object.Triple) string) Table {
+	var result Table
+	known := make(map[string]bool)
+	for _, triple := range triples {
+		prop, err := table.ForObject(triple)
+		if err != nil {
+			continue
+		}
+		row := Row{Triple: triple, Values: make(map[string]string)}
+		if nameOf != nil {
+			row.Name = nameOf(triple)
+		}
+		for _, f := range fieldsOf(triple, prop) {
+			row.Values[f.column] = f.cell()
+			if !known[f.column] {
+				known[f.column] = true
+				result.Columns = append(result.Columns, f.column)
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result
+}
+
+// Review determines the changes the given table would do to the current properties.
+// Cells that are invalid, or that are not available for the type of their row, are reported and skipped.
+// Rows for unknown types are reported as well.
+func Review(table object.PropertiesTable, imported Table) ([]Change, []Issue) {
+	var changes []Change
+	var issues []Issue
+	for _, row := range imported.Rows {
+		current, err := table.ForObject(row.Triple)
+		if err != nil {
+			issues = append(issues, Issue{Triple: row.Triple, Message: "unknown object type"})
+			continue
+		}
+		prop := current.Clone()
+		issues = append(issues, apply(row, &prop)...)
+		if !equalProperties(*current, prop) {
+			changes = append(changes, Change{Triple: row.Triple, Properties: prop})
+		}
+	}
+	return changes, issues
+}
+
+// apply sets the values of the row into the given properties.
+func apply(row Row, prop *object.Properties) []Issue {
+	return applyFields(row, func() []field { return fieldsOf(row.Triple, prop) })
+}
+
+// applyFields sets the values of the row through the fields returned by resolve.
+// As nested fields depend on the values of others, the fields are resolved again after each set value,
+// until no further cell can be considered.
+func applyFields(row Row, resolve func() []field) []Issue {
+	var issues []Issue
+	pending := make(map[string]string)
+	for column, value := range row.Values {
+		if len(strings.TrimSpace(value)) > 0 {
+			pending[column] = value
+		}
+	}
+	for len(pending) > 0 {
+		f, found := firstPendingField(resolve(), pending)
+		if !found {
+			break
+		}
+		cell := pending[f.column]
+		delete(pending, f.column)
+		value, err := f.parse(cell)
+		if err != nil {
+			issues = append(issues, Issue{Triple: row.Triple, Column: f.column, Message: err.Error()})
+			continue
+		}
+		f.set(value)
+	}
+	for _, column := range sortedColumns(pending) {
+		issues = append(issues, Issue{Triple: row.Triple, Column: column, Message: "not available for this type"})
+	}
+	return issues
+}
+
+func firstPendingField(fields []field, pending map[string]string) (field, bool) {
+	for _, f := range fields {
+		if _, available := pending[f.column]; available {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+func equalProperties(a, b object.Properties) bool {
+	return (a.Common == b.Common) && (string(a.Generic) == string(b.Generic)) && (string(a.Specific) == string(b.Specific))
+}
+
+func sortedColumns(values map[string]string) []string {
+	result := make([]string, 0, len(values))
+	for column := range values {
+		result = append(result, column)
+	}
+	sort.Strings(result)
+	return result
+}
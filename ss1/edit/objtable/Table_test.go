@@ -0,0 +1,108 @@
+package objtable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/objtable"
+)
+
+var gun = object.TripleFrom(0, 0, 0)
+
+func aPropertiesTable(t *testing.T) object.PropertiesTable {
+	t.Helper()
+	table := object.StandardPropertiesTable()
+	prop, err := table.ForObject(gun)
+	require.Nil(t, err)
+	prop.Common.Mass = -1
+	prop.Common.RenderType = object.RenderTypeBitmap
+	prop.Generic[0] = 12
+	return table
+}
+
+func TestExportProvidesNamedColumns(t *testing.T) {
+	table := aPropertiesTable(t)
+	exported := objtable.Export(table, []object.Triple{gun}, func(object.Triple) string { return "pistol" })
+
+	require.Len(t, exported.Rows, 1)
+	row := exported.Rows[0]
+	assert.Equal(t, "pistol", row.Name)
+	assert.Equal(t, "-1", row.Values["common.Mass"], "signed value expected")
+	assert.Equal(t, "Bitmap", row.Values["common.RenderType"], "enumerated value expected")
+	assert.Equal(t, "12", row.Values["generic.FireRate"])
+	assert.Contains(t, exported.Columns, "generic.AmmoType")
+	assert.Equal(t, "common.Mass", exported.Columns[0])
+}
+
+func TestExportSkipsUnknownTypes(t *testing.T) {
+	table := aPropertiesTable(t)
+	exported := objtable.Export(table, []object.Triple{object.TripleFrom(20, 0, 0)}, nil)
+	assert.Empty(t, exported.Rows)
+}
+
+func TestReviewReturnsChangesForModifiedCells(t *testing.T) {
+	table := aPropertiesTable(t)
+	imported := objtable.Table{Rows: []objtable.Row{
+		{Triple: gun, Values: map[string]string{
+			"common.Mass":       "200",
+			"common.RenderType": "TPoly",
+			"generic.FireRate":  "",
+		}},
+	}}
+
+	changes, issues := objtable.Review(table, imported)
+	assert.Empty(t, issues)
+	require.Len(t, changes, 1)
+	assert.Equal(t, int32(200), changes[0].Properties.Common.Mass)
+	assert.Equal(t, object.RenderTypeTPoly, changes[0].Properties.Common.RenderType)
+	assert.Equal(t, byte(12), changes[0].Properties.Generic[0], "empty cell should keep value")
+
+	current, _ := table.ForObject(gun)
+	assert.Equal(t, int32(-1), current.Common.Mass, "table must not be modified")
+}
+
+func TestReviewSkipsUnchangedRows(t *testing.T) {
+	table := aPropertiesTable(t)
+	changes, issues := objtable.Review(table, objtable.Export(table, table.TriplesInClass(object.ClassGun), nil))
+	assert.Empty(t, issues)
+	assert.Empty(t, changes)
+}
+
+func TestReviewReportsInvalidCells(t *testing.T) {
+	tt := []struct {
+		name   string
+		column string
+		value  string
+	}{
+		{name: "not a number", column: "common.Armor", value: "much"},
+		{name: "out of range", column: "common.Hardness", value: "254"},
+		{name: "unknown enumeration", column: "common.PhysicsModel", value: "Floating"},
+		{name: "not available", column: "specific.Unknown", value: "1"},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			table := aPropertiesTable(t)
+			imported := objtable.Table{Rows: []objtable.Row{
+				{Triple: gun, Values: map[string]string{td.column: td.value, "common.Toughness": "2"}},
+			}}
+			changes, issues := objtable.Review(table, imported)
+			require.Len(t, issues, 1)
+			assert.Equal(t, td.column, issues[0].Column)
+			require.Len(t, changes, 1, "valid cells should still be applied")
+			assert.Equal(t, byte(2), changes[0].Properties.Common.Toughness)
+		})
+	}
+}
+
+func TestReviewReportsUnknownTypes(t *testing.T) {
+	table := aPropertiesTable(t)
+	imported := objtable.Table{Rows: []objtable.Row{{Triple: object.TripleFrom(0, 0, 40)}}}
+	changes, issues := objtable.Review(table, imported)
+	assert.Empty(t, changes)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "", issues[0].Column)
+}
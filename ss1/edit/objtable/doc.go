@@ -0,0 +1,2 @@
+// Package objtable provides object properties as tables, to exchange them with spreadsheets.
+package objtable
@@ -0,0 +1,160 @@
+package projectfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// Extension is the filename extension of project files.
+const Extension = ".hacked.json"
+
+// HistoryExtension is the filename extension of the undo history that accompanies a project file.
+const HistoryExtension = ".hacked-history"
+
+// CurrentVersion is the version of the format that is written.
+const CurrentVersion = 1
+
+// Codepage assigns a codepage mapping file to a language.
+type Codepage struct {
+	Language string `json:"language"`
+	File     string `json:"file"`
+}
+
+// Layout describes the arrangement of the windows.
+type Layout struct {
+	// OpenWindows lists the titles of the windows that are open.
+	OpenWindows []string `json:"openWindows,omitempty"`
+}
+
+// Settings contains the options that are specific to the mod.
+type Settings struct {
+	// AutosaveTimeoutSec is the time after the last change after which the mod is saved.
+	// Zero keeps the default of the editor.
+	AutosaveTimeoutSec int `json:"autosaveTimeoutSec,omitempty"`
+}
+
+// ManifestEntry lists the sources that are loaded together as one entry of the static world data.
+// Each source is either a directory or a single resource file.
+type ManifestEntry []string
+
+// File is the content of a project file.
+type File struct {
+	Version int `json:"version"`
+	// Manifest lists the entries of the static world data, from lowest to highest priority.
+	Manifest []ManifestEntry `json:"manifest"`
+	// ModPath is the directory of the mod. It is empty for a new mod.
+	ModPath string `json:"modPath,omitempty"`
+	// Languages describes the languages that are registered in addition to the built-in ones.
+	Languages []ids.LanguageDefinition `json:"languages,omitempty"`
+	// Codepages lists the codepages that are assigned to languages.
+	Codepages []Codepage `json:"codepages,omitempty"`
+	Layout    Layout     `json:"layout"`
+	Settings  Settings   `json:"settings"`
+	// Macros are the recorded editing operations of the project.
+	Macros []macro.Macro `json:"macros,omitempty"`
+}
+
+// Read decodes a project file from given reader. Paths are returned as they are stored.
+func Read(reader io.Reader) (File, error) {
+	var file File
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&file)
+	if err != nil {
+		return File{}, err
+	}
+	if (file.Version < 1) || (file.Version > CurrentVersion) {
+		return File{}, fmt.Errorf("unsupported project file version %d", file.Version)
+	}
+	return file, nil
+}
+
+// Write encodes the given project file to given writer. The version is set to the current one.
+func Write(writer io.Writer, file File) error {
+	file.Version = CurrentVersion
+	if file.Manifest == nil {
+		file.Manifest = []ManifestEntry{}
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// Load reads the project file with given name. Relative paths are resolved against the directory of the file.
+func Load(filename string) (File, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return File{}, err
+	}
+	defer func() { _ = reader.Close() }()
+	file, err := Read(reader)
+	if err != nil {
+		return File{}, err
+	}
+	file.convertPaths(func(path string) string {
+		if (len(path) == 0) || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(filepath.Dir(filename), filepath.FromSlash(path))
+	})
+	return file, nil
+}
+
+// Save writes the project file with given name. Paths within the directory of the file are stored relative to it.
+func Save(filename string, file File) error {
+	baseDir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return err
+	}
+	manifest := make([]ManifestEntry, len(file.Manifest))
+	for index, entry := range file.Manifest {
+		manifest[index] = append(ManifestEntry{}, entry...)
+	}
+	file.Manifest = manifest
+	file.Codepages = append([]Codepage{}, file.Codepages...)
+	file.convertPaths(func(path string) string {
+		absPath, absErr := filepath.Abs(path)
+		if (len(path) == 0) || (absErr != nil) {
+			return path
+		}
+		relPath, relErr := filepath.Rel(baseDir, absPath)
+		if (relErr != nil) || (relPath == "..") || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return absPath
+		}
+		return filepath.ToSlash(relPath)
+	})
+	writer, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = Write(writer, file)
+	closeErr := writer.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// HistoryFilename returns the name of the undo history file of the given project file.
+func HistoryFilename(projectFilename string) string {
+	return strings.TrimSuffix(projectFilename, Extension) + HistoryExtension
+}
+
+func (file *File) convertPaths(converter func(string) string) {
+	for _, entry := range file.Manifest {
+		for index, path := range entry {
+			entry[index] = converter(path)
+		}
+	}
+	file.ModPath = converter(file.ModPath)
+	for index := range file.Codepages {
+		file.Codepages[index].File = converter(file.Codepages[index].File)
+	}
+}
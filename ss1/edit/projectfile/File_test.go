@@ -0,0 +1,110 @@
+package projectfile_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/macro"
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	file := projectfile.File{
+		Manifest:  []projectfile.ManifestEntry{{"game/data"}, {"patch/cd1.res", "patch/cd2.res"}},
+		ModPath:   "mymod",
+		Codepages: []projectfile.Codepage{{Language: "German", File: "cp850.txt"}},
+		Layout:    projectfile.Layout{OpenWindows: []string{"Project", "Texts"}},
+		Settings:  projectfile.Settings{AutosaveTimeoutSec: 10},
+		Macros: []macro.Macro{{Name: "retexture", Steps: []macro.Step{
+			{Target: macro.TargetTiles, Property: "floor texture", Value: 3},
+			{Target: macro.TargetLevel, Property: "level texture", Index: 2, Value: 100},
+		}}},
+	}
+	buf := bytes.NewBuffer(nil)
+	err := projectfile.Write(buf, file)
+	require.Nil(t, err, "no error expected writing")
+
+	read, err := projectfile.Read(buf)
+	require.Nil(t, err, "no error expected reading")
+	file.Version = projectfile.CurrentVersion
+	assert.Equal(t, file, read)
+}
+
+func TestReadRejectsInvalidContent(t *testing.T) {
+	tt := []struct {
+		name   string
+		source string
+	}{
+		{name: "no JSON", source: "project"},
+		{name: "missing version", source: `{"manifest":[]}`},
+		{name: "future version", source: `{"version":1000,"manifest":[]}`},
+		{name: "unknown field", source: `{"version":1,"manifest":[],"other":1}`},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := projectfile.Read(strings.NewReader(td.source))
+			assert.NotNil(t, err, "error expected")
+		})
+	}
+}
+
+func TestSaveAndLoadResolvePaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "projectfile")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	outside, err := filepath.Abs(filepath.Join(dir, "..", "outside"))
+	require.Nil(t, err)
+	filename := filepath.Join(dir, "test"+projectfile.Extension)
+
+	file := projectfile.File{
+		Manifest:  []projectfile.ManifestEntry{{filepath.Join(dir, "game", "data"), outside}},
+		ModPath:   filepath.Join(dir, "mod"),
+		Codepages: []projectfile.Codepage{{Language: "French", File: filepath.Join(dir, "cp.txt")}},
+	}
+	err = projectfile.Save(filename, file)
+	require.Nil(t, err, "no error expected saving")
+
+	reader, err := os.Open(filename)
+	require.Nil(t, err)
+	stored, err := projectfile.Read(reader)
+	_ = reader.Close()
+	require.Nil(t, err)
+	assert.Equal(t, []projectfile.ManifestEntry{{"game/data", outside}}, stored.Manifest,
+		"manifest should be stored relative where possible")
+	assert.Equal(t, "mod", stored.ModPath)
+	assert.Equal(t, "cp.txt", stored.Codepages[0].File)
+	assert.Equal(t, filepath.Join(dir, "game", "data"), file.Manifest[0][0], "given file should not be modified")
+
+	loaded, err := projectfile.Load(filename)
+	require.Nil(t, err, "no error expected loading")
+	assert.Equal(t, file.Manifest, loaded.Manifest)
+	assert.Equal(t, file.ModPath, loaded.ModPath)
+	assert.Equal(t, file.Codepages, loaded.Codepages)
+}
+
+func TestSaveKeepsEmptyModPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "projectfile")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	filename := filepath.Join(dir, "new"+projectfile.Extension)
+
+	err = projectfile.Save(filename, projectfile.File{})
+	require.Nil(t, err)
+	loaded, err := projectfile.Load(filename)
+	require.Nil(t, err)
+	assert.Equal(t, "", loaded.ModPath)
+	assert.Equal(t, 0, len(loaded.Manifest))
+}
+
+func TestHistoryFilename(t *testing.T) {
+	assert.Equal(t, filepath.Join("dir", "mymod"+projectfile.HistoryExtension),
+		projectfile.HistoryFilename(filepath.Join("dir", "mymod"+projectfile.Extension)))
+}
@@ -0,0 +1,52 @@
+package projectfile
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+)
+
+// MaxRecentFiles is the number of entries a recent list keeps.
+const MaxRecentFiles = 10
+
+// RecentList is the list of recently used project files, with the most recent one first.
+type RecentList struct {
+	Files []string `json:"files"`
+}
+
+// Add puts the given file at the start of the list. Entries beyond the limit are dropped.
+func (list *RecentList) Add(filename string) {
+	list.Remove(filename)
+	list.Files = append([]string{filename}, list.Files...)
+	if len(list.Files) > MaxRecentFiles {
+		list.Files = list.Files[:MaxRecentFiles]
+	}
+}
+
+// Remove takes the given file from the list.
+func (list *RecentList) Remove(filename string) {
+	var remaining []string
+	for _, existing := range list.Files {
+		if filepath.Clean(existing) != filepath.Clean(filename) {
+			remaining = append(remaining, existing)
+		}
+	}
+	list.Files = remaining
+}
+
+// ReadRecentList decodes a recent list from given reader.
+func ReadRecentList(reader io.Reader) (RecentList, error) {
+	var list RecentList
+	err := json.NewDecoder(reader).Decode(&list)
+	if len(list.Files) > MaxRecentFiles {
+		list.Files = list.Files[:MaxRecentFiles]
+	}
+	return list, err
+}
+
+// WriteRecentList encodes the given list to given writer.
+func WriteRecentList(writer io.Writer, list RecentList) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(list)
+}
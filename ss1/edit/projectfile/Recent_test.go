@@ -0,0 +1,45 @@
+package projectfile_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+)
+
+func TestRecentListAddMovesToFront(t *testing.T) {
+	var list projectfile.RecentList
+	list.Add("a")
+	list.Add("b")
+	list.Add("a")
+	assert.Equal(t, []string{"a", "b"}, list.Files)
+}
+
+func TestRecentListAddLimitsEntries(t *testing.T) {
+	var list projectfile.RecentList
+	for index := 0; index < projectfile.MaxRecentFiles+2; index++ {
+		list.Add(fmt.Sprintf("file%d", index))
+	}
+	require.Equal(t, projectfile.MaxRecentFiles, len(list.Files))
+	assert.Equal(t, fmt.Sprintf("file%d", projectfile.MaxRecentFiles+1), list.Files[0])
+}
+
+func TestRecentListRemove(t *testing.T) {
+	list := projectfile.RecentList{Files: []string{"a", "b", "c"}}
+	list.Remove("b")
+	assert.Equal(t, []string{"a", "c"}, list.Files)
+}
+
+func TestRecentListWriteAndRead(t *testing.T) {
+	list := projectfile.RecentList{Files: []string{"one", "two"}}
+	buf := bytes.NewBuffer(nil)
+	err := projectfile.WriteRecentList(buf, list)
+	require.Nil(t, err)
+	read, err := projectfile.ReadRecentList(buf)
+	require.Nil(t, err)
+	assert.Equal(t, list, read)
+}
@@ -0,0 +1,2 @@
+// Package projectfile provides the project files of the editor.
+package projectfile
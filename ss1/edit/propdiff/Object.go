@@ -0,0 +1,80 @@
+package propdiff
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/objtable"
+)
+
+// ObjectDifference describes one property of an object type that differs between mod and world.
+// A value is empty if the property is not available in the respective table.
+type ObjectDifference struct {
+	Triple     object.Triple
+	Column     string
+	ModValue   string
+	WorldValue string
+}
+
+// CompareObjects returns all properties of the mod that differ from the world.
+// Object types that are not part of the world are not considered.
+func CompareObjects(mod, world object.PropertiesTable) []ObjectDifference {
+	var diffs []ObjectDifference
+	mod.Iterate(func(triple object.Triple, _ *object.Properties) bool {
+		modTable := objtable.Export(mod, []object.Triple{triple}, nil)
+		worldTable := objtable.Export(world, []object.Triple{triple}, nil)
+		if len(worldTable.Rows) == 0 {
+			return true
+		}
+		modValues := modTable.Rows[0].Values
+		worldValues := worldTable.Rows[0].Values
+		for _, column := range columnUnion(modTable.Columns, worldTable.Columns) {
+			if modValues[column] != worldValues[column] {
+				diffs = append(diffs, ObjectDifference{
+					Triple:     triple,
+					Column:     column,
+					ModValue:   modValues[column],
+					WorldValue: worldValues[column],
+				})
+			}
+		}
+		return true
+	})
+	return diffs
+}
+
+func columnUnion(first, second []string) []string {
+	known := make(map[string]bool)
+	var result []string
+	for _, column := range append(append([]string{}, first...), second...) {
+		if !known[column] {
+			known[column] = true
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+// RevertedObject returns the properties of the mod with the property of the difference set to the world value.
+// Properties that are not available in the world can not be reverted on their own.
+func RevertedObject(mod object.PropertiesTable, diff ObjectDifference) (object.Properties, error) {
+	if len(diff.WorldValue) == 0 {
+		return object.Properties{}, fmt.Errorf("%s is not available in the world", diff.Column)
+	}
+	imported := objtable.Table{
+		Columns: []string{diff.Column},
+		Rows:    []objtable.Row{{Triple: diff.Triple, Values: map[string]string{diff.Column: diff.WorldValue}}},
+	}
+	changes, issues := objtable.Review(mod, imported)
+	if len(issues) > 0 {
+		return object.Properties{}, fmt.Errorf("%v", issues[0])
+	}
+	if len(changes) == 0 {
+		current, err := mod.ForObject(diff.Triple)
+		if err != nil {
+			return object.Properties{}, err
+		}
+		return current.Clone(), nil
+	}
+	return changes[0].Properties, nil
+}
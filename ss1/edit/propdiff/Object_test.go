@@ -0,0 +1,60 @@
+package propdiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/propdiff"
+)
+
+var gun = object.TripleFrom(0, 0, 0)
+
+func aModifiedTable(t *testing.T) object.PropertiesTable {
+	t.Helper()
+	mod := object.StandardPropertiesTable()
+	prop, err := mod.ForObject(gun)
+	require.Nil(t, err)
+	prop.Common.Mass = 300
+	prop.Generic[0] = 7
+	return mod
+}
+
+func TestCompareObjectsReturnsDifferentFields(t *testing.T) {
+	mod := aModifiedTable(t)
+	world := object.StandardPropertiesTable()
+
+	diffs := propdiff.CompareObjects(mod, world)
+	assert.Equal(t, []propdiff.ObjectDifference{
+		{Triple: gun, Column: "common.Mass", ModValue: "300", WorldValue: "0"},
+		{Triple: gun, Column: "generic.FireRate", ModValue: "7", WorldValue: "0"},
+	}, diffs)
+}
+
+func TestCompareObjectsIgnoresMissingWorld(t *testing.T) {
+	mod := aModifiedTable(t)
+	diffs := propdiff.CompareObjects(mod, nil)
+	assert.Empty(t, diffs)
+}
+
+func TestRevertedObjectSetsWorldValue(t *testing.T) {
+	mod := aModifiedTable(t)
+	world := object.StandardPropertiesTable()
+	diffs := propdiff.CompareObjects(mod, world)
+	require.Len(t, diffs, 2)
+
+	prop, err := propdiff.RevertedObject(mod, diffs[1])
+	require.Nil(t, err)
+	assert.Equal(t, byte(0), prop.Generic[0], "field should be reverted")
+	assert.Equal(t, int32(300), prop.Common.Mass, "other field should be kept")
+	current, _ := mod.ForObject(gun)
+	assert.Equal(t, byte(7), current.Generic[0], "table must not be modified")
+}
+
+func TestRevertedObjectFailsForUnavailableWorldValue(t *testing.T) {
+	mod := aModifiedTable(t)
+	_, err := propdiff.RevertedObject(mod, propdiff.ObjectDifference{Triple: gun, Column: "specific.Extra", ModValue: "1"})
+	assert.Error(t, err)
+}
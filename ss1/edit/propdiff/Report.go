@@ -0,0 +1,34 @@
+package propdiff
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteReport writes the differences as lines of text, one per property.
+func WriteReport(writer io.Writer, objects []ObjectDifference, textures []TextureDifference) error {
+	lines := []string{fmt.Sprintf("%d object properties differ.", len(objects))}
+	for _, diff := range objects {
+		lines = append(lines, fmt.Sprintf("object %v %s: %s -> %s", diff.Triple, diff.Column,
+			reportValue(diff.WorldValue), reportValue(diff.ModValue)))
+	}
+	lines = append(lines, fmt.Sprintf("%d texture properties differ.", len(textures)))
+	for _, diff := range textures {
+		lines = append(lines, fmt.Sprintf("texture %3d %s: %s -> %s", diff.Index, diff.Field,
+			reportValue(diff.WorldValue), reportValue(diff.ModValue)))
+	}
+	for _, line := range lines {
+		_, err := fmt.Fprintln(writer, line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reportValue(value string) string {
+	if len(value) == 0 {
+		return "(n/a)"
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package propdiff
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+)
+
+type textureField struct {
+	name   string
+	format func(prop *texture.Properties) string
+	copy   func(to, from *texture.Properties)
+}
+
+var textureFields = []textureField{
+	{
+		name:   "DistanceModifier",
+		format: func(prop *texture.Properties) string { return strconv.Itoa(int(prop.DistanceModifier)) },
+		copy:   func(to, from *texture.Properties) { to.DistanceModifier = from.DistanceModifier },
+	},
+	{
+		name:   "Climbable",
+		format: func(prop *texture.Properties) string { return strconv.Itoa(int(prop.Climbable)) },
+		copy:   func(to, from *texture.Properties) { to.Climbable = from.Climbable },
+	},
+	{
+		name:   "TransparencyControl",
+		format: func(prop *texture.Properties) string { return prop.TransparencyControl.String() },
+		copy:   func(to, from *texture.Properties) { to.TransparencyControl = from.TransparencyControl },
+	},
+	{
+		name:   "AnimationGroup",
+		format: func(prop *texture.Properties) string { return strconv.Itoa(int(prop.AnimationGroup)) },
+		copy:   func(to, from *texture.Properties) { to.AnimationGroup = from.AnimationGroup },
+	},
+	{
+		name:   "AnimationIndex",
+		format: func(prop *texture.Properties) string { return strconv.Itoa(int(prop.AnimationIndex)) },
+		copy:   func(to, from *texture.Properties) { to.AnimationIndex = from.AnimationIndex },
+	},
+}
+
+// TextureDifference describes one property of a texture that differs between mod and world.
+type TextureDifference struct {
+	Index      int
+	Field      string
+	ModValue   string
+	WorldValue string
+}
+
+// CompareTextures returns all properties of the mod that differ from the world.
+// Textures that are not part of the world are not considered.
+func CompareTextures(mod, world texture.PropertiesList) []TextureDifference {
+	var diffs []TextureDifference
+	for index := 0; (index < len(mod)) && (index < len(world)); index++ {
+		for _, field := range textureFields {
+			modValue := field.format(&mod[index])
+			worldValue := field.format(&world[index])
+			if modValue != worldValue {
+				diffs = append(diffs, TextureDifference{
+					Index:      index,
+					Field:      field.name,
+					ModValue:   modValue,
+					WorldValue: worldValue,
+				})
+			}
+		}
+	}
+	return diffs
+}
+
+// RevertedTexture returns the properties of the mod with the field of the difference set to the world value.
+func RevertedTexture(mod, world texture.PropertiesList, diff TextureDifference) (texture.Properties, error) {
+	if (diff.Index < 0) || (diff.Index >= len(mod)) || (diff.Index >= len(world)) {
+		return texture.Properties{}, fmt.Errorf("texture %d not available", diff.Index)
+	}
+	prop := mod[diff.Index]
+	for _, field := range textureFields {
+		if field.name == diff.Field {
+			field.copy(&prop, &world[diff.Index])
+			return prop, nil
+		}
+	}
+	return texture.Properties{}, fmt.Errorf("unknown field %s", diff.Field)
+}
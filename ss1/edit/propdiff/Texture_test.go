@@ -0,0 +1,55 @@
+package propdiff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/propdiff"
+)
+
+func TestCompareTexturesReturnsDifferentFields(t *testing.T) {
+	world := make(texture.PropertiesList, 3)
+	mod := make(texture.PropertiesList, 4)
+	mod[1].Climbable = 1
+	mod[1].TransparencyControl = texture.TransparencyControlSpace
+	mod[3].AnimationGroup = 2
+
+	diffs := propdiff.CompareTextures(mod, world)
+	assert.Equal(t, []propdiff.TextureDifference{
+		{Index: 1, Field: "Climbable", ModValue: "1", WorldValue: "0"},
+		{Index: 1, Field: "TransparencyControl", ModValue: "Space", WorldValue: "Regular"},
+	}, diffs)
+}
+
+func TestRevertedTextureSetsWorldValue(t *testing.T) {
+	world := make(texture.PropertiesList, 2)
+	world[1].DistanceModifier = -5
+	mod := make(texture.PropertiesList, 2)
+	mod[1].AnimationIndex = 3
+
+	prop, err := propdiff.RevertedTexture(mod, world, propdiff.TextureDifference{Index: 1, Field: "DistanceModifier"})
+	require.Nil(t, err)
+	assert.Equal(t, int16(-5), prop.DistanceModifier)
+	assert.Equal(t, byte(3), prop.AnimationIndex)
+
+	_, err = propdiff.RevertedTexture(mod, world, propdiff.TextureDifference{Index: 2, Field: "DistanceModifier"})
+	assert.Error(t, err, "error expected for unknown index")
+	_, err = propdiff.RevertedTexture(mod, world, propdiff.TextureDifference{Index: 1, Field: "Size"})
+	assert.Error(t, err, "error expected for unknown field")
+}
+
+func TestWriteReport(t *testing.T) {
+	var buf bytes.Buffer
+	err := propdiff.WriteReport(&buf,
+		[]propdiff.ObjectDifference{{Triple: gun, Column: "specific.Extra", ModValue: "1"}},
+		[]propdiff.TextureDifference{{Index: 12, Field: "Climbable", ModValue: "1", WorldValue: "0"}})
+	require.Nil(t, err)
+	assert.Equal(t, "1 object properties differ.\n"+
+		"object  0/0/ 0 specific.Extra: (n/a) -> 1\n"+
+		"1 texture properties differ.\n"+
+		"texture  12 Climbable: 0 -> 1\n", buf.String())
+}
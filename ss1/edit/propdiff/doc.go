@@ -0,0 +1,2 @@
+// Package propdiff compares the object and texture properties of a mod with those of the world.
+package propdiff
@@ -0,0 +1,41 @@
+package recovery
+
+// Recorder saves and discards the snapshots of a store in the background, in order of request.
+type Recorder struct {
+	store    *Store
+	requests chan func()
+}
+
+// NewRecorder returns a new instance for given store.
+func NewRecorder(store *Store) *Recorder {
+	recorder := &Recorder{
+		store:    store,
+		requests: make(chan func(), 16),
+	}
+	go recorder.run()
+	return recorder
+}
+
+func (recorder *Recorder) run() {
+	for request := range recorder.requests {
+		request()
+	}
+}
+
+// Save requests to replace the snapshot with given files.
+// The files must not be modified afterwards. Errors are ignored, as recovery is on a best-effort basis.
+func (recorder *Recorder) Save(info Info, files map[string][]byte) {
+	recorder.requests <- func() { _ = recorder.store.Save(info, files) }
+}
+
+// Discard requests to remove the snapshot.
+func (recorder *Recorder) Discard() {
+	recorder.requests <- func() { _ = recorder.store.Discard() }
+}
+
+// Flush waits until all previous requests have been handled.
+func (recorder *Recorder) Flush() {
+	done := make(chan struct{})
+	recorder.requests <- func() { close(done) }
+	<-done
+}
@@ -0,0 +1,30 @@
+package recovery_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/recovery"
+)
+
+func TestRecorderHandlesRequestsInOrder(t *testing.T) {
+	store, _, cleanup := tempStore(t)
+	defer cleanup()
+	recorder := recovery.NewRecorder(store)
+
+	recorder.Save(recovery.Info{}, map[string][]byte{"a.res": {0x01}})
+	recorder.Discard()
+	recorder.Save(recovery.Info{}, map[string][]byte{"b.res": {0x02}})
+	recorder.Flush()
+
+	snapshot, found := store.Load()
+	require.True(t, found, "snapshot expected")
+	assert.Equal(t, []string{"b.res"}, snapshot.Files)
+
+	recorder.Discard()
+	recorder.Flush()
+	_, found = store.Load()
+	assert.False(t, found, "no snapshot expected")
+}
@@ -0,0 +1,145 @@
+package recovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+)
+
+const infoFilename = "recovery.json"
+
+// Info describes a snapshot.
+type Info struct {
+	// Created is the time the snapshot was taken.
+	Created time.Time `json:"created"`
+	// Project describes the session the changes were made in, including the mod path.
+	Project projectfile.File `json:"project"`
+	// Files lists the names of the changed files.
+	Files []string `json:"files"`
+}
+
+// Snapshot is a stored set of changed files.
+type Snapshot struct {
+	Info
+	dir string
+}
+
+// Paths returns the full paths of the changed files.
+func (snapshot Snapshot) Paths() []string {
+	paths := make([]string, len(snapshot.Files))
+	for index, filename := range snapshot.Files {
+		paths[index] = filepath.Join(snapshot.dir, filename)
+	}
+	return paths
+}
+
+// Store keeps the latest snapshot in a directory.
+type Store struct {
+	mutex sync.Mutex
+	dir   string
+}
+
+// NewStore returns a new instance that uses given directory. The directory is created when required.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (store *Store) pendingDir() string {
+	return store.dir + ".new"
+}
+
+// Save replaces the stored snapshot with the given files, keyed by their filename.
+// The files are first written next to the previous snapshot, which is only then replaced.
+func (store *Store) Save(info Info, files map[string][]byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	pendingDir := store.pendingDir()
+	err := os.RemoveAll(pendingDir)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(pendingDir, 0755)
+	if err != nil {
+		return err
+	}
+	info.Files = nil
+	for filename, data := range files {
+		if filepath.Base(filename) != filename {
+			return fmt.Errorf("invalid filename <%s>", filename)
+		}
+		err = ioutil.WriteFile(filepath.Join(pendingDir, filename), data, 0644)
+		if err != nil {
+			return err
+		}
+		info.Files = append(info.Files, filename)
+	}
+	sort.Strings(info.Files)
+	infoData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The info file is written last, it marks the snapshot as complete.
+	err = ioutil.WriteFile(filepath.Join(pendingDir, infoFilename), infoData, 0644)
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(store.dir)
+	if err != nil {
+		return err
+	}
+	return os.Rename(pendingDir, store.dir)
+}
+
+// Load returns the stored snapshot. It returns false if there is no complete snapshot.
+func (store *Store) Load() (Snapshot, bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, dir := range []string{store.dir, store.pendingDir()} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, infoFilename))
+		if err != nil {
+			continue
+		}
+		snapshot := Snapshot{dir: dir}
+		err = json.Unmarshal(data, &snapshot.Info)
+		if (err == nil) && snapshot.complete() {
+			return snapshot, true
+		}
+	}
+	return Snapshot{}, false
+}
+
+func (snapshot Snapshot) complete() bool {
+	if len(snapshot.Files) == 0 {
+		return false
+	}
+	for _, filename := range snapshot.Files {
+		if filepath.Base(filename) != filename {
+			return false
+		}
+		if _, err := os.Stat(filepath.Join(snapshot.dir, filename)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Discard removes the stored snapshot.
+func (store *Store) Discard() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	err := os.RemoveAll(store.pendingDir())
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(store.dir)
+}
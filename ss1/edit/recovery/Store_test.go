@@ -0,0 +1,101 @@
+package recovery_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/projectfile"
+	"github.com/inkyblackness/hacked/ss1/edit/recovery"
+)
+
+func tempStore(t *testing.T) (*recovery.Store, string, func()) {
+	t.Helper()
+	baseDir, err := ioutil.TempDir("", "recovery")
+	require.Nil(t, err)
+	dir := filepath.Join(baseDir, "snapshot")
+	return recovery.NewStore(dir), dir, func() { _ = os.RemoveAll(baseDir) }
+}
+
+func TestStoreLoadWithoutSnapshot(t *testing.T) {
+	store, _, cleanup := tempStore(t)
+	defer cleanup()
+	_, found := store.Load()
+	assert.False(t, found, "no snapshot expected")
+}
+
+func TestStoreSaveAndLoad(t *testing.T) {
+	store, _, cleanup := tempStore(t)
+	defer cleanup()
+	info := recovery.Info{
+		Created: time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Project: projectfile.File{ModPath: "mymod", Manifest: []projectfile.ManifestEntry{{"data"}}},
+	}
+	err := store.Save(info, map[string][]byte{"objprop.dat": {0x01}, "citmat.res": {0x02, 0x03}})
+	require.Nil(t, err, "no error expected saving")
+
+	snapshot, found := store.Load()
+	require.True(t, found, "snapshot expected")
+	assert.True(t, info.Created.Equal(snapshot.Created), "created time should match")
+	assert.Equal(t, info.Project, snapshot.Project)
+	assert.Equal(t, []string{"citmat.res", "objprop.dat"}, snapshot.Files)
+	paths := snapshot.Paths()
+	require.Equal(t, 2, len(paths))
+	data, err := ioutil.ReadFile(paths[0])
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0x02, 0x03}, data)
+}
+
+func TestStoreSaveReplacesPreviousSnapshot(t *testing.T) {
+	store, _, cleanup := tempStore(t)
+	defer cleanup()
+	require.Nil(t, store.Save(recovery.Info{}, map[string][]byte{"a.res": {0x01}}))
+	require.Nil(t, store.Save(recovery.Info{}, map[string][]byte{"b.res": {0x02}}))
+
+	snapshot, found := store.Load()
+	require.True(t, found)
+	assert.Equal(t, []string{"b.res"}, snapshot.Files)
+}
+
+func TestStoreSaveRejectsPaths(t *testing.T) {
+	store, _, cleanup := tempStore(t)
+	defer cleanup()
+	err := store.Save(recovery.Info{}, map[string][]byte{filepath.Join("..", "a.res"): {0x01}})
+	assert.NotNil(t, err, "error expected")
+}
+
+func TestStoreLoadIgnoresIncompleteSnapshot(t *testing.T) {
+	store, dir, cleanup := tempStore(t)
+	defer cleanup()
+	require.Nil(t, store.Save(recovery.Info{}, map[string][]byte{"a.res": {0x01}}))
+	require.Nil(t, os.Remove(filepath.Join(dir, "a.res")))
+
+	_, found := store.Load()
+	assert.False(t, found, "incomplete snapshot should not be found")
+}
+
+func TestStoreLoadFindsInterruptedReplacement(t *testing.T) {
+	store, dir, cleanup := tempStore(t)
+	defer cleanup()
+	require.Nil(t, store.Save(recovery.Info{}, map[string][]byte{"a.res": {0x01}}))
+	require.Nil(t, os.Rename(dir, dir+".new"))
+
+	snapshot, found := store.Load()
+	require.True(t, found, "pending snapshot should be found")
+	assert.Equal(t, []string{"a.res"}, snapshot.Files)
+}
+
+func TestStoreDiscard(t *testing.T) {
+	store, _, cleanup := tempStore(t)
+	defer cleanup()
+	require.Nil(t, store.Save(recovery.Info{}, map[string][]byte{"a.res": {0x01}}))
+	require.Nil(t, store.Discard())
+
+	_, found := store.Load()
+	assert.False(t, found, "no snapshot expected after discard")
+}
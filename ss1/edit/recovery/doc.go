@@ -0,0 +1,2 @@
+// Package recovery keeps snapshots of unsaved changes, to restore them after a crash or a forced exit.
+package recovery
@@ -0,0 +1,58 @@
+package texusage
+
+import "strings"
+
+// FindingKind classifies a finding of the cross-check.
+type FindingKind int
+
+// Kinds of findings.
+const (
+	// Unused textures are not in the atlas of any level. They are free to be replaced.
+	Unused FindingKind = iota
+	// Unreferenced textures are in the atlas of a level, yet no tile or object shows them.
+	Unreferenced
+	// Undescribed textures are used, yet have no usage text.
+	Undescribed
+)
+
+// String returns the textual representation.
+func (kind FindingKind) String() string {
+	switch kind {
+	case Unused:
+		return "unused"
+	case Unreferenced:
+		return "unreferenced"
+	case Undescribed:
+		return "undescribed"
+	default:
+		return "unknown"
+	}
+}
+
+// Finding is the result of a cross-check of one texture.
+type Finding struct {
+	Texture     int
+	Kind        FindingKind
+	Description string
+}
+
+// CrossCheck compares the usage of the textures with their usage texts, as provided by the given function.
+func CrossCheck(usages []Usage, usageText func(index int) string) []Finding {
+	var findings []Finding
+	for _, usage := range usages {
+		description := strings.TrimSpace(usageText(usage.Texture))
+		finding := Finding{Texture: usage.Texture, Description: description}
+		switch {
+		case !usage.Used():
+			finding.Kind = Unused
+		case !usage.Referenced():
+			finding.Kind = Unreferenced
+		case len(description) == 0:
+			finding.Kind = Undescribed
+		default:
+			continue
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
@@ -0,0 +1,183 @@
+package texusage
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+// Level describes the level data that refer to textures.
+type Level interface {
+	ID() int
+	IsCyberspace() bool
+	TextureAtlas() level.TextureAtlas
+	Size() (x, y int, z level.HeightShift)
+	Tile(x, y int) *level.TileMapEntry
+	ForEachObject(handler func(level.ObjectID, level.ObjectMasterEntry))
+	ObjectClassData(id level.ObjectID) []byte
+}
+
+// Surface names the side of a tile that shows a texture.
+type Surface string
+
+// Surfaces of tiles.
+const (
+	SurfaceFloor   Surface = "floor"
+	SurfaceCeiling Surface = "ceiling"
+	SurfaceWall    Surface = "wall"
+)
+
+// TileReference is a tile that shows a texture.
+type TileReference struct {
+	X, Y    int
+	Surface Surface
+}
+
+// ObjectReference is an object that shows a texture.
+type ObjectReference struct {
+	ID     level.ObjectID
+	Triple object.Triple
+	Field  string
+}
+
+// LevelUsage describes how one level uses a texture.
+type LevelUsage struct {
+	Level        int
+	AtlasIndices []int
+	Tiles        []TileReference
+	Objects      []ObjectReference
+}
+
+// Referenced returns true if any tile or object shows the texture.
+func (usage LevelUsage) Referenced() bool {
+	return (len(usage.Tiles) > 0) || (len(usage.Objects) > 0)
+}
+
+// Usage describes how all levels use one texture.
+type Usage struct {
+	Texture int
+	Levels  []LevelUsage
+}
+
+// Used returns true if any level has the texture in its atlas.
+func (usage Usage) Used() bool {
+	return len(usage.Levels) > 0
+}
+
+// Referenced returns true if any tile or object of any level shows the texture.
+func (usage Usage) Referenced() bool {
+	for _, lvl := range usage.Levels {
+		if lvl.Referenced() {
+			return true
+		}
+	}
+	return false
+}
+
+// Analyze returns the usage of the given amount of textures in the given levels.
+// Cyberspace levels are not considered, as they do not show textures. Nil entries are skipped.
+func Analyze(levels []Level, textureCount int) []Usage {
+	usages := make([]Usage, textureCount)
+	for index := range usages {
+		usages[index].Texture = index
+	}
+	for _, lvl := range levels {
+		if (lvl == nil) || lvl.IsCyberspace() {
+			continue
+		}
+		for _, lvlUsage := range analyzeLevel(lvl, textureCount) {
+			if lvlUsage != nil {
+				usages[lvlUsage.textureIndex].Levels = append(usages[lvlUsage.textureIndex].Levels, lvlUsage.LevelUsage)
+			}
+		}
+	}
+	return usages
+}
+
+type indexedLevelUsage struct {
+	LevelUsage
+	textureIndex int
+}
+
+func analyzeLevel(lvl Level, textureCount int) []*indexedLevelUsage {
+	atlas := lvl.TextureAtlas()
+	usages := make([]*indexedLevelUsage, textureCount)
+	forAtlasIndex := func(atlasIndex int) *indexedLevelUsage {
+		if (atlasIndex < 0) || (atlasIndex >= len(atlas)) {
+			return nil
+		}
+		textureIndex := int(atlas[atlasIndex])
+		if (textureIndex < 0) || (textureIndex >= textureCount) {
+			return nil
+		}
+		return usages[textureIndex]
+	}
+	for atlasIndex, textureIndex := range atlas {
+		if (textureIndex < 0) || (int(textureIndex) >= textureCount) {
+			continue
+		}
+		usage := usages[textureIndex]
+		if usage == nil {
+			usage = &indexedLevelUsage{LevelUsage: LevelUsage{Level: lvl.ID()}, textureIndex: int(textureIndex)}
+			usages[textureIndex] = usage
+		}
+		usage.AtlasIndices = append(usage.AtlasIndices, atlasIndex)
+	}
+
+	width, height, _ := lvl.Size()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			tile := lvl.Tile(x, y)
+			if (tile == nil) || (tile.Type == level.TileTypeSolid) {
+				continue
+			}
+			surfaces := []struct {
+				surface    Surface
+				atlasIndex int
+			}{
+				{surface: SurfaceFloor, atlasIndex: tile.TextureInfo.FloorTextureIndex()},
+				{surface: SurfaceCeiling, atlasIndex: tile.TextureInfo.CeilingTextureIndex()},
+				{surface: SurfaceWall, atlasIndex: tile.TextureInfo.WallTextureIndex()},
+			}
+			for _, entry := range surfaces {
+				if usage := forAtlasIndex(entry.atlasIndex); usage != nil {
+					usage.Tiles = append(usage.Tiles, TileReference{X: x, Y: y, Surface: entry.surface})
+				}
+			}
+		}
+	}
+
+	lvl.ForEachObject(func(id level.ObjectID, entry level.ObjectMasterEntry) {
+		triple := entry.Triple()
+		forTextureFields(lvlobj.ForRealWorld(triple, lvl.ObjectClassData(id)), "", func(field string, atlasIndex int) {
+			if usage := forAtlasIndex(atlasIndex); usage != nil {
+				usage.Objects = append(usage.Objects, ObjectReference{ID: id, Triple: triple, Field: field})
+			}
+		})
+	})
+	return usages
+}
+
+// materialOrLevelTextureFlag marks values of MaterialOrLevelTexture fields that refer to the atlas.
+const materialOrLevelTextureFlag = 0x80
+
+func forTextureFields(instance *interpreters.Instance, path string, consumer func(field string, atlasIndex int)) {
+	for _, key := range instance.Keys() {
+		fullKey := path + key
+		value := instance.Get(key)
+		simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {})
+		simplifier.SetSpecialHandler("LevelTexture", func() {
+			consumer(fullKey, int(value))
+		})
+		simplifier.SetSpecialHandler("MaterialOrLevelTexture", func() {
+			if (value & materialOrLevelTextureFlag) != 0 {
+				consumer(fullKey, int(value&^materialOrLevelTextureFlag))
+			}
+		})
+		instance.Describe(key, simplifier)
+	}
+	for _, key := range instance.ActiveRefinements() {
+		forTextureFields(instance.Refined(key), path+key+".", consumer)
+	}
+}
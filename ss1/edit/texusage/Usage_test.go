@@ -0,0 +1,129 @@
+package texusage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/texusage"
+)
+
+type testingLevel struct {
+	id         int
+	cyberspace bool
+	atlas      level.TextureAtlas
+	tiles      level.TileMap
+	objects    map[level.ObjectID]level.ObjectMasterEntry
+	classData  map[level.ObjectID][]byte
+}
+
+func (lvl *testingLevel) ID() int                          { return lvl.id }
+func (lvl *testingLevel) IsCyberspace() bool               { return lvl.cyberspace }
+func (lvl *testingLevel) TextureAtlas() level.TextureAtlas { return lvl.atlas }
+func (lvl *testingLevel) Size() (x, y int, z level.HeightShift) {
+	return len(lvl.tiles[0]), len(lvl.tiles), 0
+}
+func (lvl *testingLevel) Tile(x, y int) *level.TileMapEntry { return lvl.tiles.Tile(x, y) }
+func (lvl *testingLevel) ForEachObject(handler func(level.ObjectID, level.ObjectMasterEntry)) {
+	for id := level.ObjectID(0); int(id) < 10; id++ {
+		if entry, existing := lvl.objects[id]; existing {
+			handler(id, entry)
+		}
+	}
+}
+func (lvl *testingLevel) ObjectClassData(id level.ObjectID) []byte { return lvl.classData[id] }
+
+func aLevel(id int) *testingLevel {
+	lvl := &testingLevel{
+		id:        id,
+		atlas:     level.TextureAtlas{5, 6, 7, 5},
+		tiles:     level.NewTileMap(2, 2),
+		objects:   make(map[level.ObjectID]level.ObjectMasterEntry),
+		classData: make(map[level.ObjectID][]byte),
+	}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			lvl.tiles[y][x].TextureInfo = lvl.tiles[y][x].TextureInfo.WithWallTextureIndex(1).WithCeilingTextureIndex(1)
+		}
+	}
+	return lvl
+}
+
+func (lvl *testingLevel) withTextureMap(id level.ObjectID, atlasIndex byte) *testingLevel {
+	lvl.objects[id] = level.ObjectMasterEntry{InUse: 1, Class: object.ClassBigStuff, Subclass: 2, Type: 7}
+	data := make([]byte, 16)
+	data[6] = atlasIndex
+	lvl.classData[id] = data
+	return lvl
+}
+
+func TestAnalyzeFindsTileUsage(t *testing.T) {
+	lvl := aLevel(1)
+	lvl.tiles[1][0].Type = level.TileTypeOpen
+	lvl.tiles[1][0].TextureInfo = lvl.tiles[1][0].TextureInfo.WithFloorTextureIndex(3)
+
+	usages := texusage.Analyze([]texusage.Level{lvl}, 10)
+	require.Len(t, usages, 10)
+	usage := usages[5]
+	assert.Equal(t, 5, usage.Texture)
+	require.Len(t, usage.Levels, 1)
+	assert.Equal(t, []int{0, 3}, usage.Levels[0].AtlasIndices)
+	assert.Equal(t, []texusage.TileReference{{X: 0, Y: 1, Surface: texusage.SurfaceFloor}}, usage.Levels[0].Tiles)
+	assert.Equal(t, []texusage.TileReference{
+		{X: 0, Y: 1, Surface: texusage.SurfaceCeiling},
+		{X: 0, Y: 1, Surface: texusage.SurfaceWall},
+	}, usages[6].Levels[0].Tiles)
+	assert.False(t, usages[7].Referenced(), "texture only in atlas should not be referenced")
+	assert.True(t, usages[7].Used(), "texture in atlas should be used")
+	assert.False(t, usages[0].Used(), "texture not in atlas should be unused")
+}
+
+func TestAnalyzeFindsObjectUsage(t *testing.T) {
+	lvl := aLevel(2).withTextureMap(3, 2)
+
+	usages := texusage.Analyze([]texusage.Level{lvl}, 10)
+	require.Len(t, usages[7].Levels, 1)
+	assert.Equal(t, []texusage.ObjectReference{
+		{ID: 3, Triple: object.TripleFrom(7, 2, 7), Field: "TextureIndex"},
+	}, usages[7].Levels[0].Objects)
+	assert.True(t, usages[7].Referenced())
+}
+
+func TestAnalyzeSkipsCyberspaceAndMissingLevels(t *testing.T) {
+	lvl := aLevel(3)
+	lvl.cyberspace = true
+
+	usages := texusage.Analyze([]texusage.Level{lvl, nil}, 10)
+	for _, usage := range usages {
+		assert.False(t, usage.Used(), "no usage expected for texture %d", usage.Texture)
+	}
+}
+
+func TestAnalyzeIgnoresTexturesOutOfRange(t *testing.T) {
+	lvl := aLevel(4)
+	lvl.atlas = level.TextureAtlas{-1, 20}
+	lvl.tiles[0][0].Type = level.TileTypeOpen
+
+	usages := texusage.Analyze([]texusage.Level{lvl}, 10)
+	assert.Len(t, usages, 10)
+}
+
+func TestCrossCheck(t *testing.T) {
+	lvl := aLevel(1).withTextureMap(1, 0)
+	usages := texusage.Analyze([]texusage.Level{lvl}, 8)
+	descriptions := map[int]string{0: "unused one", 5: "map", 7: " "}
+
+	findings := texusage.CrossCheck(usages, func(index int) string { return descriptions[index] })
+	assert.Equal(t, []texusage.Finding{
+		{Texture: 0, Kind: texusage.Unused, Description: "unused one"},
+		{Texture: 1, Kind: texusage.Unused},
+		{Texture: 2, Kind: texusage.Unused},
+		{Texture: 3, Kind: texusage.Unused},
+		{Texture: 4, Kind: texusage.Unused},
+		{Texture: 6, Kind: texusage.Unreferenced},
+		{Texture: 7, Kind: texusage.Unreferenced},
+	}, findings)
+}
@@ -0,0 +1,2 @@
+// Package texusage determines where the game textures are used within the levels.
+package texusage
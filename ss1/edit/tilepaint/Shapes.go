@@ -0,0 +1,145 @@
+package tilepaint
+
+import "sort"
+
+// Point is the position of a tile on the map.
+type Point struct {
+	X, Y int
+}
+
+// Area is a set of tile positions.
+type Area map[Point]bool
+
+// Add includes all given points in the area.
+func (area Area) Add(points ...Point) {
+	for _, point := range points {
+		area[point] = true
+	}
+}
+
+// Points returns the positions of the area, sorted by Y and then X.
+func (area Area) Points() []Point {
+	points := make([]Point, 0, len(area))
+	for point := range area {
+		points = append(points, point)
+	}
+	sortPoints(points)
+	return points
+}
+
+// Within returns the area without the points that are outside the given map size.
+func (area Area) Within(columns, rows int) Area {
+	result := make(Area)
+	for point := range area {
+		if (point.X >= 0) && (point.X < columns) && (point.Y >= 0) && (point.Y < rows) {
+			result[point] = true
+		}
+	}
+	return result
+}
+
+// Rectangle returns the filled rectangle spanned between two corners.
+func Rectangle(from, to Point) []Point {
+	minX, maxX := ordered(from.X, to.X)
+	minY, maxY := ordered(from.Y, to.Y)
+	points := make([]Point, 0, (maxX-minX+1)*(maxY-minY+1))
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			points = append(points, Point{X: x, Y: y})
+		}
+	}
+	return points
+}
+
+// Line returns the points of a straight line between, and including, the two given points.
+// Subsequent points are connected at least diagonally.
+func Line(from, to Point) []Point {
+	dx, stepX := distance(from.X, to.X)
+	dy, stepY := distance(from.Y, to.Y)
+	points := make([]Point, 0, maxOf(dx, dy)+1)
+	err := dx - dy
+	current := from
+	for {
+		points = append(points, current)
+		if current == to {
+			return points
+		}
+		doubled := 2 * err
+		if doubled > -dy {
+			err -= dy
+			current.X += stepX
+		}
+		if doubled < dx {
+			err += dx
+			current.Y += stepY
+		}
+	}
+}
+
+// Brush returns the points of a square brush of given size, placed at the center point.
+// Even sizes extend further to the top and left.
+func Brush(center Point, size int) []Point {
+	if size < 1 {
+		size = 1
+	}
+	offset := size / 2
+	return Rectangle(Point{X: center.X - offset, Y: center.Y - offset},
+		Point{X: center.X - offset + size - 1, Y: center.Y - offset + size - 1})
+}
+
+// FloodFill returns all points that are connected to the start point, horizontally or vertically,
+// and that match the given function. The start point is always part of the area.
+// Only points within the given map size are considered.
+func FloodFill(start Point, columns, rows int, matches func(Point) bool) Area {
+	area := make(Area)
+	if (start.X < 0) || (start.X >= columns) || (start.Y < 0) || (start.Y >= rows) {
+		return area
+	}
+	pending := []Point{start}
+	area[start] = true
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, next := range []Point{
+			{X: current.X - 1, Y: current.Y}, {X: current.X + 1, Y: current.Y},
+			{X: current.X, Y: current.Y - 1}, {X: current.X, Y: current.Y + 1},
+		} {
+			if area[next] || (next.X < 0) || (next.X >= columns) || (next.Y < 0) || (next.Y >= rows) || !matches(next) {
+				continue
+			}
+			area[next] = true
+			pending = append(pending, next)
+		}
+	}
+	return area
+}
+
+func ordered(a, b int) (int, int) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+func distance(from, to int) (int, int) {
+	if to < from {
+		return from - to, -1
+	}
+	return to - from, 1
+}
+
+func maxOf(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func sortPoints(points []Point) {
+	sort.Slice(points, func(a, b int) bool {
+		if points[a].Y != points[b].Y {
+			return points[a].Y < points[b].Y
+		}
+		return points[a].X < points[b].X
+	})
+}
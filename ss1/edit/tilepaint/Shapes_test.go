@@ -0,0 +1,63 @@
+package tilepaint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/edit/tilepaint"
+)
+
+func TestRectangleCoversAllPointsIrrespectiveOfCornerOrder(t *testing.T) {
+	points := tilepaint.Rectangle(tilepaint.Point{X: 3, Y: 2}, tilepaint.Point{X: 2, Y: 3})
+
+	assert.Equal(t, []tilepaint.Point{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 2, Y: 3}, {X: 3, Y: 3}}, points)
+}
+
+func TestLineConnectsEndPoints(t *testing.T) {
+	points := tilepaint.Line(tilepaint.Point{X: 0, Y: 0}, tilepaint.Point{X: 4, Y: 2})
+
+	assert.Equal(t, tilepaint.Point{X: 0, Y: 0}, points[0])
+	assert.Equal(t, tilepaint.Point{X: 4, Y: 2}, points[len(points)-1])
+	assert.Equal(t, 5, len(points))
+	for index := 1; index < len(points); index++ {
+		dx := points[index].X - points[index-1].X
+		dy := points[index].Y - points[index-1].Y
+		assert.True(t, (dx >= -1) && (dx <= 1) && (dy >= -1) && (dy <= 1), "points must be adjacent")
+	}
+}
+
+func TestLineOfSinglePoint(t *testing.T) {
+	points := tilepaint.Line(tilepaint.Point{X: 5, Y: 5}, tilepaint.Point{X: 5, Y: 5})
+
+	assert.Equal(t, []tilepaint.Point{{X: 5, Y: 5}}, points)
+}
+
+func TestBrushIsSquareAroundCenter(t *testing.T) {
+	assert.Equal(t, []tilepaint.Point{{X: 1, Y: 1}}, tilepaint.Brush(tilepaint.Point{X: 1, Y: 1}, 1))
+	assert.Equal(t, 9, len(tilepaint.Brush(tilepaint.Point{X: 1, Y: 1}, 3)))
+	assert.Equal(t, tilepaint.Point{X: 0, Y: 0}, tilepaint.Brush(tilepaint.Point{X: 1, Y: 1}, 3)[0])
+}
+
+func TestFloodFillStopsAtNonMatchingPoints(t *testing.T) {
+	wall := func(p tilepaint.Point) bool { return p.X != 2 }
+
+	area := tilepaint.FloodFill(tilepaint.Point{X: 0, Y: 0}, 4, 3, wall)
+
+	assert.Equal(t, 6, len(area))
+	assert.False(t, area[tilepaint.Point{X: 2, Y: 1}])
+	assert.False(t, area[tilepaint.Point{X: 3, Y: 1}])
+}
+
+func TestFloodFillIgnoresStartOutsideOfMap(t *testing.T) {
+	area := tilepaint.FloodFill(tilepaint.Point{X: -1, Y: 0}, 4, 3, func(tilepaint.Point) bool { return true })
+
+	assert.Equal(t, 0, len(area))
+}
+
+func TestAreaWithinRemovesPointsOutsideOfMap(t *testing.T) {
+	area := make(tilepaint.Area)
+	area.Add(tilepaint.Brush(tilepaint.Point{X: 0, Y: 0}, 3)...)
+
+	assert.Equal(t, []tilepaint.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}, area.Within(10, 10).Points())
+}
@@ -0,0 +1,66 @@
+package tilepaint
+
+import "github.com/inkyblackness/hacked/ss1/content/archive/level"
+
+// Template describes the properties that painting sets on tiles.
+// The values are taken from a source tile, and only the enabled groups are applied.
+type Template struct {
+	// Source is the tile that provides the values.
+	Source level.TileMapEntry
+
+	// Type sets the tile type.
+	Type bool
+	// Heights sets the floor, ceiling and slope heights, as well as the slope control.
+	Heights bool
+	// Textures sets the textures, their rotations, and the wall texture properties.
+	// For cyberspace, this covers the palette indices.
+	Textures bool
+	// Flags sets the music index, hazards, and the properties specific to real world or cyberspace.
+	Flags bool
+}
+
+// Apply sets the enabled properties of the template on the given tile.
+func (template Template) Apply(tile *level.TileMapEntry, cyberspace bool) {
+	src := template.Source
+	if template.Type {
+		tile.Type = src.Type
+	}
+	if template.Heights {
+		tile.Floor = tile.Floor.WithAbsoluteHeight(src.Floor.AbsoluteHeight())
+		tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(src.Ceiling.AbsoluteHeight())
+		tile.SlopeHeight = src.SlopeHeight
+		tile.Flags = tile.Flags.WithSlopeControl(src.Flags.SlopeControl())
+	}
+	if template.Textures {
+		tile.TextureInfo = src.TextureInfo
+		tile.Floor = tile.Floor.WithTextureRotations(src.Floor.TextureRotations())
+		tile.Ceiling = tile.Ceiling.WithTextureRotations(src.Ceiling.TextureRotations())
+		if !cyberspace {
+			srcFlags := src.Flags.ForRealWorld()
+			tile.Flags = tile.Flags.ForRealWorld().
+				WithWallTextureOffset(srcFlags.WallTextureOffset()).
+				WithWallTexturePattern(srcFlags.WallTexturePattern()).
+				WithUseAdjacentWallTexture(srcFlags.UseAdjacentWallTexture()).
+				AsTileFlag()
+		}
+	}
+	if template.Flags {
+		tile.Flags = tile.Flags.WithMusicIndex(src.Flags.MusicIndex())
+		tile.Floor = tile.Floor.WithHazard(src.Floor.HasHazard())
+		tile.Ceiling = tile.Ceiling.WithHazard(src.Ceiling.HasHazard())
+		if cyberspace {
+			srcFlags := src.Flags.ForCyberspace()
+			tile.Flags = tile.Flags.ForCyberspace().
+				WithFlightPull(srcFlags.FlightPull()).
+				WithGameOfLifeState(srcFlags.GameOfLifeState()).
+				AsTileFlag()
+		} else {
+			srcFlags := src.Flags.ForRealWorld()
+			tile.Flags = tile.Flags.ForRealWorld().
+				WithFloorShadow(srcFlags.FloorShadow()).
+				WithCeilingShadow(srcFlags.CeilingShadow()).
+				WithDeconstructed(srcFlags.Deconstructed()).
+				AsTileFlag()
+		}
+	}
+}
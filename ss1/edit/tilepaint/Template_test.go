@@ -0,0 +1,47 @@
+package tilepaint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/edit/tilepaint"
+)
+
+func sourceTile() level.TileMapEntry {
+	var tile level.TileMapEntry
+	tile.Type = level.TileTypeSlopeSouthToNorth
+	tile.Floor = tile.Floor.WithAbsoluteHeight(4).WithTextureRotations(2)
+	tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(20)
+	tile.SlopeHeight = 3
+	tile.TextureInfo = tile.TextureInfo.WithFloorTextureIndex(7)
+	tile.Flags = tile.Flags.WithMusicIndex(5).ForRealWorld().WithFloorShadow(9).AsTileFlag()
+	return tile
+}
+
+func TestTemplateAppliesOnlyEnabledGroups(t *testing.T) {
+	template := tilepaint.Template{Source: sourceTile(), Heights: true}
+	var tile level.TileMapEntry
+	tile.FirstObjectIndex = 12
+
+	template.Apply(&tile, false)
+
+	assert.Equal(t, level.TileTypeSolid, tile.Type, "type should not be changed")
+	assert.Equal(t, level.TileHeightUnit(4), tile.Floor.AbsoluteHeight())
+	assert.Equal(t, level.TileHeightUnit(20), tile.Ceiling.AbsoluteHeight())
+	assert.Equal(t, level.TileHeightUnit(3), tile.SlopeHeight)
+	assert.Equal(t, 0, tile.Floor.TextureRotations(), "rotations should not be changed")
+	assert.Equal(t, 0, tile.Flags.MusicIndex(), "flags should not be changed")
+	assert.Equal(t, int16(12), tile.FirstObjectIndex)
+}
+
+func TestTemplateWithAllGroupsCopiesTile(t *testing.T) {
+	source := sourceTile()
+	template := tilepaint.Template{Source: source, Type: true, Heights: true, Textures: true, Flags: true}
+	var tile level.TileMapEntry
+
+	template.Apply(&tile, false)
+
+	assert.Equal(t, source, tile)
+}
@@ -0,0 +1,2 @@
+// Package tilepaint provides the shapes and templates to paint tiles of a level map.
+package tilepaint
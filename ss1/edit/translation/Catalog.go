@@ -0,0 +1,20 @@
+package translation
+
+// Unit is one translatable string.
+type Unit struct {
+	// ID is the stable key of the string.
+	ID string
+	// Context is a human readable description of where the string is used.
+	Context string
+	// Source is the text in the source language.
+	Source string
+	// Target is the translated text. It is empty if not yet translated.
+	Target string
+}
+
+// Catalog is a collection of translatable strings between two languages.
+type Catalog struct {
+	SourceLanguage string
+	TargetLanguage string
+	Units          []Unit
+}
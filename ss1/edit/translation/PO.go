@@ -0,0 +1,183 @@
+package translation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const poSourceLanguageHeader = "X-Source-Language"
+
+// WritePO encodes the catalog in the gettext PO format.
+// The ID of a unit is stored as message context, the context as extracted comment.
+func WritePO(writer io.Writer, catalog Catalog) error {
+	buffered := bufio.NewWriter(writer)
+	header := "Content-Type: text/plain; charset=UTF-8\n" +
+		"Content-Transfer-Encoding: 8bit\n" +
+		"Language: " + catalog.TargetLanguage + "\n" +
+		poSourceLanguageHeader + ": " + catalog.SourceLanguage + "\n"
+	writePOString(buffered, "msgid", "")
+	writePOString(buffered, "msgstr", header)
+	for _, unit := range catalog.Units {
+		_, _ = buffered.WriteString("\n")
+		if len(unit.Context) > 0 {
+			for _, line := range strings.Split(unit.Context, "\n") {
+				_, _ = buffered.WriteString("#. " + line + "\n")
+			}
+		}
+		writePOString(buffered, "msgctxt", unit.ID)
+		writePOString(buffered, "msgid", unit.Source)
+		writePOString(buffered, "msgstr", unit.Target)
+	}
+	return buffered.Flush()
+}
+
+func writePOString(writer *bufio.Writer, keyword string, value string) {
+	_, _ = writer.WriteString(keyword + " ")
+	lines := strings.SplitAfter(value, "\n")
+	if len(lines) > 1 {
+		_, _ = writer.WriteString("\"\"\n")
+	}
+	for _, line := range lines {
+		if (len(line) == 0) && (len(lines) > 1) {
+			continue
+		}
+		_, _ = writer.WriteString(poQuoted(line) + "\n")
+	}
+}
+
+func poQuoted(value string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\t", "\\t", "\r", "\\r")
+	return "\"" + replacer.Replace(value) + "\""
+}
+
+// ReadPO decodes a catalog from the gettext PO format.
+// Entries marked as fuzzy are read without their translation.
+func ReadPO(reader io.Reader) (Catalog, error) {
+	var catalog Catalog
+	var current poEntry
+	var currentField *string
+	lineNumber := 0
+
+	finishEntry := func() {
+		if current.started {
+			if current.isHeader() {
+				catalog.applyPOHeader(current.target)
+			} else {
+				unit := Unit{ID: current.context, Context: current.comment, Source: current.source, Target: current.target}
+				if current.fuzzy {
+					unit.Target = ""
+				}
+				catalog.Units = append(catalog.Units, unit)
+			}
+		}
+		current = poEntry{}
+		currentField = nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case len(line) == 0:
+			continue
+		case strings.HasPrefix(line, "#"):
+			if current.hasMessage {
+				finishEntry()
+			}
+			if strings.HasPrefix(line, "#.") {
+				if len(current.comment) > 0 {
+					current.comment += "\n"
+				}
+				current.comment += strings.TrimSpace(line[2:])
+			} else if strings.HasPrefix(line, "#,") && strings.Contains(line, "fuzzy") {
+				current.fuzzy = true
+			}
+			current.started = true
+			continue
+		case strings.HasPrefix(line, "\""):
+			if currentField == nil {
+				return catalog, fmt.Errorf("line %d: string without keyword", lineNumber)
+			}
+			value, err := strconv.Unquote(line)
+			if err != nil {
+				return catalog, fmt.Errorf("line %d: %v", lineNumber, err)
+			}
+			*currentField += value
+			continue
+		}
+
+		keyword := line
+		rest := ""
+		if separator := strings.IndexAny(line, " \t"); separator >= 0 {
+			keyword = line[:separator]
+			rest = strings.TrimSpace(line[separator:])
+		}
+		value, err := strconv.Unquote(rest)
+		if err != nil {
+			return catalog, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		switch keyword {
+		case "msgctxt":
+			if current.hasMessage {
+				finishEntry()
+			}
+			current.context = value
+			currentField = &current.context
+		case "msgid":
+			if current.hasMessage {
+				finishEntry()
+			}
+			current.source = value
+			current.hasMessage = true
+			currentField = &current.source
+		case "msgstr", "msgstr[0]":
+			current.target = value
+			currentField = &current.target
+		default:
+			// Other keywords, such as for plural forms, are not used. Their strings are ignored.
+			currentField = new(string)
+		}
+		current.started = true
+	}
+	if err := scanner.Err(); err != nil {
+		return catalog, err
+	}
+	finishEntry()
+	return catalog, nil
+}
+
+type poEntry struct {
+	started    bool
+	hasMessage bool
+	fuzzy      bool
+	comment    string
+	context    string
+	source     string
+	target     string
+}
+
+func (entry poEntry) isHeader() bool {
+	return entry.hasMessage && (len(entry.context) == 0) && (len(entry.source) == 0)
+}
+
+func (catalog *Catalog) applyPOHeader(header string) {
+	for _, line := range strings.Split(header, "\n") {
+		separator := strings.Index(line, ":")
+		if separator < 0 {
+			continue
+		}
+		name := strings.TrimSpace(line[:separator])
+		value := strings.TrimSpace(line[separator+1:])
+		switch name {
+		case "Language":
+			catalog.TargetLanguage = value
+		case poSourceLanguageHeader:
+			catalog.SourceLanguage = value
+		}
+	}
+}
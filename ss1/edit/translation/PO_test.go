@@ -0,0 +1,71 @@
+package translation_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/translation"
+)
+
+func aCatalog() translation.Catalog {
+	return translation.Catalog{
+		SourceLanguage: "Default",
+		TargetLanguage: "German",
+		Units: []translation.Unit{
+			{ID: "0867:3", Context: "Trap Messages #3", Source: "Hello \"World\"", Target: "Hallo \"Welt\""},
+			{ID: "0989:0:verbose", Context: "Mails #0 Verbose", Source: "First\nSecond\n", Target: ""},
+			{ID: "0024:1", Source: "back\\slash", Target: "Rück\\strich"},
+		},
+	}
+}
+
+func TestPORoundTrip(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	catalog := aCatalog()
+
+	err := translation.WritePO(buf, catalog)
+	require.Nil(t, err)
+	result, err := translation.ReadPO(bytes.NewReader(buf.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, catalog, result)
+}
+
+func TestPOWritesMultiLineStrings(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	err := translation.WritePO(buf, aCatalog())
+
+	require.Nil(t, err)
+	assert.Contains(t, buf.String(), "msgid \"\"\n\"First\\n\"\n\"Second\\n\"\n")
+	assert.Contains(t, buf.String(), "#. Trap Messages #3\nmsgctxt \"0867:3\"\n")
+}
+
+func TestReadPOIgnoresFuzzyTranslations(t *testing.T) {
+	input := `
+#, fuzzy
+msgctxt "0867:3"
+msgid "Hello"
+msgstr "Hallo"
+
+msgctxt "0867:4"
+msgid "World"
+msgstr "Welt"
+`
+	result, err := translation.ReadPO(strings.NewReader(input))
+
+	require.Nil(t, err)
+	require.Equal(t, 2, len(result.Units))
+	assert.Equal(t, "", result.Units[0].Target)
+	assert.Equal(t, "Welt", result.Units[1].Target)
+}
+
+func TestReadPOReturnsErrorForInvalidStrings(t *testing.T) {
+	_, err := translation.ReadPO(strings.NewReader("msgid \"unterminated\n"))
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,90 @@
+package translation
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type xliffDocument struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string           `xml:"original,attr"`
+	SourceLanguage string           `xml:"source-language,attr"`
+	TargetLanguage string           `xml:"target-language,attr,omitempty"`
+	DataType       string           `xml:"datatype,attr"`
+	Units          []xliffTransUnit `xml:"body>trans-unit"`
+}
+
+type xliffTransUnit struct {
+	ID     string       `xml:"id,attr"`
+	Space  string       `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Source string       `xml:"source"`
+	Target *xliffTarget `xml:"target"`
+	Note   string       `xml:"note,omitempty"`
+}
+
+type xliffTarget struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+// WriteXLIFF encodes the catalog as XLIFF 1.2 document.
+// Units without translation are written without target.
+func WriteXLIFF(writer io.Writer, catalog Catalog, original string) error {
+	file := xliffFile{
+		Original:       original,
+		SourceLanguage: catalog.SourceLanguage,
+		TargetLanguage: catalog.TargetLanguage,
+		DataType:       "plaintext",
+	}
+	for _, unit := range catalog.Units {
+		transUnit := xliffTransUnit{ID: unit.ID, Space: "preserve", Source: unit.Source, Note: unit.Context}
+		if len(unit.Target) > 0 {
+			transUnit.Target = &xliffTarget{Text: unit.Target}
+		}
+		file.Units = append(file.Units, transUnit)
+	}
+	doc := xliffDocument{Version: "1.2", Files: []xliffFile{file}}
+
+	_, err := io.WriteString(writer, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(doc)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(writer, "\n")
+	return err
+}
+
+// ReadXLIFF decodes a catalog from an XLIFF 1.2 document.
+// Units of all contained files are returned. Targets in state "needs-translation" are ignored.
+func ReadXLIFF(reader io.Reader) (Catalog, error) {
+	var catalog Catalog
+	var doc xliffDocument
+	err := xml.NewDecoder(reader).Decode(&doc)
+	if err != nil {
+		return catalog, err
+	}
+	for _, file := range doc.Files {
+		if len(catalog.SourceLanguage) == 0 {
+			catalog.SourceLanguage = file.SourceLanguage
+			catalog.TargetLanguage = file.TargetLanguage
+		}
+		for _, transUnit := range file.Units {
+			unit := Unit{ID: transUnit.ID, Context: transUnit.Note, Source: transUnit.Source}
+			if (transUnit.Target != nil) && (transUnit.Target.State != "needs-translation") {
+				unit.Target = transUnit.Target.Text
+			}
+			catalog.Units = append(catalog.Units, unit)
+		}
+	}
+	return catalog, nil
+}
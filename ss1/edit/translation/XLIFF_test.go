@@ -0,0 +1,42 @@
+package translation_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/translation"
+)
+
+func TestXLIFFRoundTrip(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	catalog := aCatalog()
+
+	err := translation.WriteXLIFF(buf, catalog, "test")
+	require.Nil(t, err)
+	result, err := translation.ReadXLIFF(bytes.NewReader(buf.Bytes()))
+
+	require.Nil(t, err)
+	assert.Equal(t, catalog, result)
+}
+
+func TestReadXLIFFIgnoresTargetsThatNeedTranslation(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="test" source-language="Default" target-language="French" datatype="plaintext">
+    <body>
+      <trans-unit id="a"><source>One</source><target state="needs-translation">One</target></trans-unit>
+      <trans-unit id="b"><source>Two</source><target state="translated">Deux</target></trans-unit>
+    </body>
+  </file>
+</xliff>`
+
+	result, err := translation.ReadXLIFF(strings.NewReader(input))
+
+	require.Nil(t, err)
+	assert.Equal(t, "French", result.TargetLanguage)
+	assert.Equal(t, []translation.Unit{{ID: "a", Source: "One"}, {ID: "b", Source: "Two", Target: "Deux"}}, result.Units)
+}
@@ -0,0 +1,2 @@
+// Package translation provides file formats to exchange translatable strings with external tools.
+package translation
@@ -32,9 +32,15 @@ func (service AugmentedTextService) Text(key resource.Key) string {
 	return service.wrapped.Text(key)
 }
 
+// Unencodable returns the characters of the given value that can not be stored in the language of the key.
+func (service AugmentedTextService) Unencodable(key resource.Key, value string) []rune {
+	return service.wrapped.Unencodable(key, value)
+}
+
 // RequestSetText queues the change to update the text.
 func (service AugmentedTextService) RequestSetText(key resource.Key, value string, restoreFunc func()) {
 	service.requestCommand(
+		"Set text "+keyTitle(key),
 		func(setter edit.AugmentedTextBlockSetter) {
 			service.wrapped.SetText(setter, key, value)
 		},
@@ -51,6 +57,7 @@ func (service AugmentedTextService) Sound(key resource.Key) audio.L8 {
 // RequestSetSound queues the change to update the sound.
 func (service AugmentedTextService) RequestSetSound(key resource.Key, sound audio.L8, restoreFunc func()) {
 	service.requestCommand(
+		"Set sound of text "+keyTitle(key),
 		func(setter edit.AugmentedTextBlockSetter) {
 			service.wrapped.SetSound(setter, key, sound)
 		},
@@ -61,6 +68,7 @@ func (service AugmentedTextService) RequestSetSound(key resource.Key, sound audi
 // RequestClear queues the change to set both the text and the sound empty.
 func (service AugmentedTextService) RequestClear(key resource.Key, restoreFunc func()) {
 	service.requestCommand(
+		"Clear text "+keyTitle(key),
 		func(setter edit.AugmentedTextBlockSetter) {
 			service.wrapped.Clear(setter, key)
 		},
@@ -71,6 +79,7 @@ func (service AugmentedTextService) RequestClear(key resource.Key, restoreFunc f
 // RequestRemove queues the change to remove both the text and the sound from the storage.
 func (service AugmentedTextService) RequestRemove(key resource.Key, restoreFunc func()) {
 	service.requestCommand(
+		"Remove text "+keyTitle(key),
 		func(setter edit.AugmentedTextBlockSetter) {
 			service.wrapped.Remove(setter, key)
 		},
@@ -78,11 +87,12 @@ func (service AugmentedTextService) RequestRemove(key resource.Key, restoreFunc
 		restoreFunc)
 }
 
-func (service AugmentedTextService) requestCommand(
+func (service AugmentedTextService) requestCommand(title string,
 	forward func(modder edit.AugmentedTextBlockSetter),
 	reverse func(modder edit.AugmentedTextBlockSetter),
 	restore func()) {
 	c := command{
+		title:   title,
 		forward: func(modder world.Modder) { forward(modder) },
 		reverse: func(modder world.Modder) { reverse(modder) },
 		restore: restore,
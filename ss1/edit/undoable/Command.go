@@ -1,10 +1,14 @@
 package undoable
 
 import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
 )
 
 type command struct {
+	title   string
 	forward func(world.Modder)
 	reverse func(world.Modder)
 	restore func()
@@ -23,3 +27,12 @@ func (c command) perform(modder world.Modder, callback func(setter world.Modder)
 	c.restore()
 	return nil
 }
+
+func (c command) Description() string {
+	return c.title
+}
+
+// keyTitle returns the key in a form for descriptions.
+func keyTitle(key resource.Key) string {
+	return fmt.Sprintf("%v[%d] (%v)", key.ID, key.Index, key.Lang)
+}
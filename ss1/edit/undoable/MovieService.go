@@ -1,6 +1,7 @@
 package undoable
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/inkyblackness/hacked/ss1/content/audio"
@@ -39,6 +40,7 @@ func (service MovieService) Video(key resource.Key) []movie.Scene {
 // RequestMoveSceneEarlier queues to move the identified scene earlier.
 func (service MovieService) RequestMoveSceneEarlier(key resource.Key, scene int, restoreFunc func()) {
 	service.requestCommand(
+		fmt.Sprintf("Move scene %d of movie %s earlier", scene, keyTitle(key)),
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.MoveSceneEarlier(setter, key, scene)
 		},
@@ -49,6 +51,7 @@ func (service MovieService) RequestMoveSceneEarlier(key resource.Key, scene int,
 // RequestMoveSceneLater queues to move the identified scene later.
 func (service MovieService) RequestMoveSceneLater(key resource.Key, scene int, restoreFunc func()) {
 	service.requestCommand(
+		fmt.Sprintf("Move scene %d of movie %s later", scene, keyTitle(key)),
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.MoveSceneLater(setter, key, scene)
 		},
@@ -59,6 +62,7 @@ func (service MovieService) RequestMoveSceneLater(key resource.Key, scene int, r
 // RequestAddScene queues to add the given scene at the end of the movie.
 func (service MovieService) RequestAddScene(key resource.Key, scene movie.HighResScene, restoreFunc func()) {
 	service.requestCommand(
+		"Add scene to movie "+keyTitle(key),
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.AddScene(setter, key, scene)
 		},
@@ -66,9 +70,26 @@ func (service MovieService) RequestAddScene(key resource.Key, scene movie.HighRe
 		restoreFunc)
 }
 
+// RequestAddSceneWithMedia queues to add the given scene at the end of the movie, together with
+// optional audio and subtitles. The sound is converted to the sample rate of existing audio right away,
+// the returned clipping information reports any samples that were limited during the conversion.
+func (service MovieService) RequestAddSceneWithMedia(key resource.Key, scene movie.HighResScene,
+	sound audio.L8, subtitles map[resource.Language]movie.SubtitleList, restoreFunc func()) audio.Clipping {
+	sound, clip := service.wrapped.SoundForScene(key, sound)
+	service.requestCommand(
+		"Add scene to movie "+keyTitle(key),
+		func(setter media.MovieBlockSetter) {
+			service.wrapped.AddSceneWithMedia(setter, key, scene, sound, subtitles)
+		},
+		service.wrapped.RestoreFunc(key),
+		restoreFunc)
+	return clip
+}
+
 // RequestRemoveScene queues to remove the identified scene.
 func (service MovieService) RequestRemoveScene(key resource.Key, scene int, restoreFunc func()) {
 	service.requestCommand(
+		fmt.Sprintf("Remove scene %d of movie %s", scene, keyTitle(key)),
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.RemoveScene(setter, key, scene)
 		},
@@ -80,6 +101,7 @@ func (service MovieService) RequestRemoveScene(key resource.Key, scene int, rest
 func (service MovieService) RequestSetSceneFramesDisplayTime(key resource.Key,
 	scene int, displayTime time.Duration, restoreFunc func()) {
 	service.requestCommand(
+		fmt.Sprintf("Set frame time of scene %d of movie %s", scene, keyTitle(key)),
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.SetSceneFramesDisplayTime(setter, key, scene, displayTime)
 		},
@@ -95,6 +117,7 @@ func (service MovieService) Audio(key resource.Key) audio.L8 {
 // RequestSetAudio queues the change to update the audio track.
 func (service MovieService) RequestSetAudio(key resource.Key, soundData audio.L8, restoreFunc func()) {
 	service.requestCommand(
+		"Set audio of movie "+keyTitle(key),
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.SetAudio(setter, key, soundData)
 		},
@@ -111,6 +134,7 @@ func (service MovieService) Subtitles(key resource.Key, language resource.Langua
 func (service MovieService) RequestSetSubtitles(key resource.Key,
 	language resource.Language, subtitles movie.SubtitleList, restoreFunc func()) {
 	service.requestCommand(
+		fmt.Sprintf("Set %v subtitles of movie %s", language, keyTitle(key)),
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.SetSubtitles(setter, key, language, subtitles)
 		},
@@ -118,11 +142,24 @@ func (service MovieService) RequestSetSubtitles(key resource.Key,
 		restoreFunc)
 }
 
-func (service MovieService) requestCommand(
+// RequestSetSubtitlesPerLanguage queues the change to update subtitles of several languages.
+func (service MovieService) RequestSetSubtitlesPerLanguage(key resource.Key,
+	subtitles map[resource.Language]movie.SubtitleList, restoreFunc func()) {
+	service.requestCommand(
+		"Set subtitles of movie "+keyTitle(key),
+		func(setter media.MovieBlockSetter) {
+			service.wrapped.SetSubtitlesPerLanguage(setter, key, subtitles)
+		},
+		service.wrapped.RestoreFunc(key),
+		restoreFunc)
+}
+
+func (service MovieService) requestCommand(title string,
 	forward func(modder media.MovieBlockSetter),
 	reverse func(modder media.MovieBlockSetter),
 	restore func()) {
 	c := command{
+		title:   title,
 		forward: func(modder world.Modder) { forward(modder) },
 		reverse: func(modder world.Modder) { reverse(modder) },
 		restore: restore,
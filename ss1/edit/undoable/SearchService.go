@@ -0,0 +1,47 @@
+package undoable
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// SearchService provides search and replace functionality with undo capability.
+type SearchService struct {
+	wrapped   edit.SearchService
+	commander cmd.Commander
+}
+
+// NewSearchService returns a new instance of a service.
+func NewSearchService(wrapped edit.SearchService, commander cmd.Commander) SearchService {
+	return SearchService{
+		wrapped:   wrapped,
+		commander: commander,
+	}
+}
+
+// Find returns all strings that match the query.
+func (service SearchService) Find(query edit.SearchQuery) []edit.SearchMatch {
+	return service.wrapped.Find(query)
+}
+
+// Unchanged returns those of the given matches of which the string was not modified since they were found.
+func (service SearchService) Unchanged(matches []edit.SearchMatch) []edit.SearchMatch {
+	return service.wrapped.Unchanged(matches)
+}
+
+// RequestReplace queues the change to set the replaced strings of all given matches.
+// All matches are replaced with one command, so they are undone together.
+func (service SearchService) RequestReplace(matches []edit.SearchMatch, restoreFunc func()) {
+	reverse := service.wrapped.RestoreFunc(matches)
+	c := command{
+		title:   fmt.Sprintf("Replace %d match(es)", len(matches)),
+		forward: func(modder world.Modder) { service.wrapped.Replace(modder, matches) },
+		reverse: func(modder world.Modder) { reverse(media.TextBlockSetter(modder)) },
+		restore: restoreFunc,
+	}
+	service.commander.Queue(c)
+}
@@ -31,6 +31,7 @@ func (service SoundEffectService) Modified(key resource.Key) bool {
 // RequestRemove queues to erase the sound from the resources.
 func (service SoundEffectService) RequestRemove(key resource.Key, restoreFunc func()) {
 	service.requestCommand(
+		"Remove sound effect "+keyTitle(key),
 		func(setter media.SoundEffectBlockSetter) {
 			service.wrapped.Remove(setter, key)
 		},
@@ -41,6 +42,7 @@ func (service SoundEffectService) RequestRemove(key resource.Key, restoreFunc fu
 // RequestClear queues to reset the identified audio resource to a silent one-sample audio.
 func (service SoundEffectService) RequestClear(key resource.Key, restoreFunc func()) {
 	service.requestCommand(
+		"Clear sound effect "+keyTitle(key),
 		func(setter media.SoundEffectBlockSetter) {
 			service.wrapped.Clear(setter, key)
 		},
@@ -56,6 +58,7 @@ func (service SoundEffectService) Audio(key resource.Key) audio.L8 {
 // RequestSetAudio queues the change to update the audio.
 func (service SoundEffectService) RequestSetAudio(key resource.Key, data audio.L8, restoreFunc func()) {
 	service.requestCommand(
+		"Set sound effect "+keyTitle(key),
 		func(setter media.SoundEffectBlockSetter) {
 			service.wrapped.SetAudio(setter, key, data)
 		},
@@ -67,11 +70,12 @@ func (service SoundEffectService) RequestClearAudio() {
 
 }
 
-func (service SoundEffectService) requestCommand(
+func (service SoundEffectService) requestCommand(title string,
 	forward func(modder media.SoundEffectBlockSetter),
 	reverse func(modder media.SoundEffectBlockSetter),
 	restore func()) {
 	c := command{
+		title:   title,
 		forward: func(modder world.Modder) { forward(modder) },
 		reverse: func(modder world.Modder) { reverse(modder) },
 		restore: restore,
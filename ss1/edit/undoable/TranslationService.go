@@ -0,0 +1,49 @@
+package undoable
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// TranslationService provides bulk translation functionality with undo capability.
+type TranslationService struct {
+	wrapped   edit.TranslationService
+	commander cmd.Commander
+}
+
+// NewTranslationService returns a new instance of a service.
+func NewTranslationService(wrapped edit.TranslationService, commander cmd.Commander) TranslationService {
+	return TranslationService{
+		wrapped:   wrapped,
+		commander: commander,
+	}
+}
+
+// Entries returns all translatable strings, with their texts in the given languages.
+func (service TranslationService) Entries(source, target resource.Language) []edit.TranslationEntry {
+	return service.wrapped.Entries(source, target)
+}
+
+// Review compares the given translated entries with the current texts.
+func (service TranslationService) Review(source, target resource.Language,
+	translated []edit.TranslationEntry) ([]edit.TranslationEntry, edit.TranslationReport) {
+	return service.wrapped.Review(source, target, translated)
+}
+
+// RequestApply queues the change to set all the given entries in the target language.
+// All entries are applied with one command, so they are undone together.
+func (service TranslationService) RequestApply(target resource.Language, entries []edit.TranslationEntry, restoreFunc func()) {
+	reverse := service.wrapped.RestoreFunc(target, entries)
+	c := command{
+		title:   fmt.Sprintf("Apply %d translation(s) to %v", len(entries), target),
+		forward: func(modder world.Modder) { service.wrapped.Apply(modder, target, entries) },
+		reverse: func(modder world.Modder) { reverse(media.TextBlockSetter(modder)) },
+		restore: restoreFunc,
+	}
+	service.commander.Queue(c)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+// Describer is a command that describes itself for the history.
+type Describer interface {
+	Description() string
+}
+
+// Grouper is a command that belongs to a series of small edits.
+type Grouper interface {
+	GroupKey() string
+}
+
+// Named is a command with a description and an optional group.
+type Named struct {
+	Command
+	// Title describes the change in a human-readable form.
+	Title string
+	// Group identifies a series of small edits, such as those made by dragging a slider.
+	// Consecutively performed commands of the same group are merged into one step.
+	Group string
+}
+
+// Description returns the title.
+func (named Named) Description() string {
+	return named.Title
+}
+
+// GroupKey returns the group.
+func (named Named) GroupKey() string {
+	return named.Group
+}
+
+// DefaultDescription is used for commands that do not describe themselves.
+const DefaultDescription = "Change"
+
+// DescriptionOf returns the description of given command.
+// Lists are described by their first entry that has a description.
+func DescriptionOf(command Command) string {
+	switch typed := command.(type) {
+	case Describer:
+		return typed.Description()
+	case List:
+		for _, entry := range typed {
+			if description := DescriptionOf(entry); description != DefaultDescription {
+				return description
+			}
+		}
+	}
+	return DefaultDescription
+}
+
+// GroupOf returns the group of given command. Commands without group return an empty string.
+func GroupOf(command Command) string {
+	if grouper, isGrouper := command.(Grouper); isGrouper {
+		return grouper.GroupKey()
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+)
+
+func TestDescriptionOf(t *testing.T) {
+	named := cmd.Named{Command: &TestCommand{}, Title: "Set something"}
+	tt := []struct {
+		name     string
+		command  cmd.Command
+		expected string
+	}{
+		{name: "unnamed", command: &TestCommand{}, expected: cmd.DefaultDescription},
+		{name: "named", command: named, expected: "Set something"},
+		{name: "list", command: cmd.List{&TestCommand{}, named}, expected: "Set something"},
+		{name: "empty list", command: cmd.List{}, expected: cmd.DefaultDescription},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			assert.Equal(t, td.expected, cmd.DescriptionOf(td.command))
+		})
+	}
+}
+
+func TestGroupOf(t *testing.T) {
+	assert.Equal(t, "", cmd.GroupOf(&TestCommand{}))
+	assert.Equal(t, "key", cmd.GroupOf(cmd.Named{Command: &TestCommand{}, Group: "key"}))
+}
@@ -3,8 +3,16 @@ package cmd
 import "github.com/inkyblackness/hacked/ss1/world"
 
 type stackEntry struct {
-	link *stackEntry
-	cmd  Command
+	link        *stackEntry
+	cmd         Command
+	description string
+	group       string
+}
+
+// Entry describes one step of the history of a stack.
+type Entry struct {
+	Command     Command
+	Description string
 }
 
 // Stack describes a list of commands. The stack allows to sequentially
@@ -16,11 +24,32 @@ type Stack struct {
 	lockedBy string
 	undoList *stackEntry
 	redoList *stackEntry
+
+	lastPerformed *stackEntry
+}
+
+// NewStackFrom returns a stack with the given entries, oldest first.
+// The first done entries can be undone, the remaining ones redone.
+func NewStackFrom(entries []Entry, done int) *Stack {
+	stack := new(Stack)
+	for index := len(entries) - 1; index >= done; index-- {
+		stack.redoList = stack.newEntry(stack.redoList, entries[index])
+	}
+	for index := 0; (index < done) && (index < len(entries)); index++ {
+		stack.undoList = stack.newEntry(stack.undoList, entries[index])
+	}
+	return stack
+}
+
+func (stack *Stack) newEntry(link *stackEntry, entry Entry) *stackEntry {
+	return &stackEntry{link: link, cmd: entry.Command, description: entry.Description}
 }
 
 // Perform executes the given command and puts it on the stack
 // if the command was successful.
 // This function also clears the list of commands to be redone.
+// If the command belongs to the same group as the previously performed one,
+// both are merged into one step.
 func (stack *Stack) Perform(cmd Command, modder world.Modder) error {
 	stack.lock("Perform")
 	defer stack.unlock()
@@ -29,8 +58,16 @@ func (stack *Stack) Perform(cmd Command, modder world.Modder) error {
 	if err != nil {
 		return err
 	}
-	stack.undoList = &stackEntry{link: stack.undoList, cmd: cmd}
+	group := GroupOf(cmd)
+	if (len(group) > 0) && (stack.undoList != nil) && (stack.undoList == stack.lastPerformed) &&
+		(stack.undoList.group == group) {
+		stack.undoList.cmd = List{stack.undoList.cmd, cmd}
+		stack.undoList.description = DescriptionOf(cmd)
+	} else {
+		stack.undoList = &stackEntry{link: stack.undoList, cmd: cmd, description: DescriptionOf(cmd), group: group}
+	}
 	stack.redoList = nil
+	stack.lastPerformed = stack.undoList
 	return nil
 }
 
@@ -58,6 +95,7 @@ func (stack *Stack) Undo(modder world.Modder) error {
 	stack.undoList = entry.link
 	entry.link = stack.redoList
 	stack.redoList = entry
+	stack.lastPerformed = nil
 	return nil
 }
 
@@ -85,9 +123,24 @@ func (stack *Stack) Redo(modder world.Modder) error {
 	stack.redoList = entry.link
 	entry.link = stack.undoList
 	stack.undoList = entry
+	stack.lastPerformed = nil
 	return nil
 }
 
+// History returns all entries of the stack, oldest first, and the number of entries that can be undone.
+// The remaining entries can be redone.
+func (stack *Stack) History() ([]Entry, int) {
+	var entries []Entry
+	for entry := stack.undoList; entry != nil; entry = entry.link {
+		entries = append([]Entry{{Command: entry.cmd, Description: entry.description}}, entries...)
+	}
+	done := len(entries)
+	for entry := stack.redoList; entry != nil; entry = entry.link {
+		entries = append(entries, Entry{Command: entry.cmd, Description: entry.description})
+	}
+	return entries, done
+}
+
 func (stack *Stack) lock(by string) {
 	if stack.lockedBy != "" {
 		panic("Stack already in use by <" + stack.lockedBy + ">")
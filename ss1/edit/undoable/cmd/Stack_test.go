@@ -176,6 +176,52 @@ func (suite *StackSuite) TestPerformDropsPendingRedoStack() {
 	suite.thenCommandShouldHaveBeenExecutedTimes("cmd1", 1)
 }
 
+func (suite *StackSuite) TestHistoryListsDescriptions() {
+	suite.whenPerforming(suite.aNamedCommand("cmd1", "first", ""))
+	suite.whenPerforming(suite.aNamedCommand("cmd2", "second", ""))
+	suite.givenUndoWasCalledTimes(1)
+
+	suite.thenHistoryShouldBe([]string{"first", "second"}, 1)
+}
+
+func (suite *StackSuite) TestPerformMergesConsecutiveCommandsOfSameGroup() {
+	suite.whenPerforming(suite.aNamedCommand("cmd1", "height 1", "height"))
+	suite.whenPerforming(suite.aNamedCommand("cmd2", "height 2", "height"))
+	suite.thenHistoryShouldBe([]string{"height 2"}, 1)
+
+	suite.whenUndoing()
+	suite.thenCommandShouldHaveBeenReverted("cmd1")
+	suite.thenCommandShouldHaveBeenReverted("cmd2")
+	suite.thenStackShouldNotSupportUndo()
+}
+
+func (suite *StackSuite) TestPerformKeepsCommandsOfDifferentGroupsSeparate() {
+	suite.whenPerforming(suite.aNamedCommand("cmd1", "height", "height"))
+	suite.whenPerforming(suite.aNamedCommand("cmd2", "texture", "texture"))
+	suite.thenHistoryShouldBe([]string{"height", "texture"}, 2)
+}
+
+func (suite *StackSuite) TestPerformDoesNotMergeAfterUndoRedo() {
+	suite.whenPerforming(suite.aNamedCommand("cmd1", "height 1", "height"))
+	suite.givenUndoWasCalledTimes(1)
+	suite.givenRedoWasCalledTimes(1)
+	suite.whenPerforming(suite.aNamedCommand("cmd2", "height 2", "height"))
+	suite.thenHistoryShouldBe([]string{"height 1", "height 2"}, 2)
+}
+
+func (suite *StackSuite) TestNewStackFromRestoresHistory() {
+	suite.stack = *cmd.NewStackFrom([]cmd.Entry{
+		{Command: suite.aCommand("cmd1"), Description: "first"},
+		{Command: suite.aCommand("cmd2"), Description: "second"},
+	}, 1)
+	suite.thenHistoryShouldBe([]string{"first", "second"}, 1)
+
+	suite.whenRedoing()
+	suite.thenCommandShouldHaveBeenExecuted("cmd2")
+	suite.givenUndoWasCalledTimes(2)
+	suite.thenCommandShouldHaveBeenReverted("cmd1")
+}
+
 func (suite *StackSuite) TestPerformPanicsIfStackIsInUse() {
 	callPerform := func(name string) func() {
 		var times int
@@ -316,6 +362,20 @@ func (suite *StackSuite) thenPerformShouldReturnError(cmd cmd.Command, expected
 	assert.Equal(suite.T(), expected, result)
 }
 
+func (suite *StackSuite) thenHistoryShouldBe(expected []string, expectedDone int) {
+	entries, done := suite.stack.History()
+	descriptions := make([]string, len(entries))
+	for index, entry := range entries {
+		descriptions[index] = entry.Description
+	}
+	assert.Equal(suite.T(), expected, descriptions, "descriptions mismatch")
+	assert.Equal(suite.T(), expectedDone, done, "done count mismatch")
+}
+
+func (suite *StackSuite) aNamedCommand(name string, title string, group string) cmd.Command {
+	return cmd.Named{Command: suite.aCommand(name), Title: title, Group: group}
+}
+
 func (suite *StackSuite) aCommand(name string) cmd.Command {
 	cmd := &TestCommand{name: name}
 	suite.commands[name] = cmd
@@ -0,0 +1,180 @@
+package history
+
+import (
+	"bytes"
+	"hash/crc32"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/serial/rle"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// BlockPatch describes the change of one block of a resource.
+// If Length is not negative, the block has this length both before and after the change,
+// and the data are patches as produced by rle.Compress(). Otherwise, the data are the complete blocks.
+type BlockPatch struct {
+	Index   int
+	Length  int
+	Forward []byte
+	Reverse []byte
+}
+
+// ResourceChange describes the change of one resource of the mod.
+type ResourceChange struct {
+	Lang resource.Language
+	ID   resource.ID
+
+	BeforeExists bool
+	AfterExists  bool
+	// Patched is set if the resource exists before and after with the same number of blocks.
+	// In this case, only the changed blocks are listed in Patches.
+	Patched bool
+	Patches []BlockPatch
+	// Before and After contain all blocks if the resource is not patched.
+	Before [][]byte
+	After  [][]byte
+
+	// BeforeSum and AfterSum are checksums of the complete resource, to verify the state of the mod.
+	BeforeSum uint32
+	AfterSum  uint32
+}
+
+func newResourceChange(lang resource.Language, id resource.ID, before, after [][]byte) ResourceChange {
+	change := ResourceChange{
+		Lang:         lang,
+		ID:           id,
+		BeforeExists: before != nil,
+		AfterExists:  after != nil,
+		BeforeSum:    checksum(before),
+		AfterSum:     checksum(after),
+	}
+	change.Patched = change.BeforeExists && change.AfterExists && (len(before) == len(after))
+	if change.Patched {
+		for index := range before {
+			if !bytes.Equal(before[index], after[index]) {
+				change.Patches = append(change.Patches, newBlockPatch(index, before[index], after[index]))
+			}
+		}
+	} else {
+		change.Before = before
+		change.After = after
+	}
+	return change
+}
+
+func newBlockPatch(index int, before, after []byte) BlockPatch {
+	if len(before) == len(after) {
+		forward := bytes.NewBuffer(nil)
+		reverse := bytes.NewBuffer(nil)
+		forwardErr := rle.Compress(forward, after, before)
+		reverseErr := rle.Compress(reverse, before, after)
+		if (forwardErr == nil) && (reverseErr == nil) {
+			return BlockPatch{Index: index, Length: len(before), Forward: forward.Bytes(), Reverse: reverse.Bytes()}
+		}
+	}
+	return BlockPatch{Index: index, Length: -1, Forward: after, Reverse: before}
+}
+
+func (change ResourceChange) apply(modder world.Modder, forward bool) {
+	if change.Patched {
+		for _, patch := range change.Patches {
+			data := patch.Reverse
+			if forward {
+				data = patch.Forward
+			}
+			if patch.Length < 0 {
+				modder.SetResourceBlock(change.Lang, change.ID, patch.Index, data)
+			} else {
+				modder.PatchResourceBlock(change.Lang, change.ID, patch.Index, patch.Length, data)
+			}
+		}
+		return
+	}
+	exists, blocks := change.BeforeExists, change.Before
+	if forward {
+		exists, blocks = change.AfterExists, change.After
+	}
+	if exists {
+		modder.SetResourceBlocks(change.Lang, change.ID, blocks)
+	} else {
+		modder.DelResource(change.Lang, change.ID)
+	}
+}
+
+// TextureChange describes the change of the properties of one texture.
+type TextureChange struct {
+	Index  int
+	Before texture.Properties
+	After  texture.Properties
+}
+
+// ObjectChange describes the change of the properties of one object type.
+type ObjectChange struct {
+	Triple object.Triple
+	Before object.Properties
+	After  object.Properties
+}
+
+// Change is the recorded effect of a command on the mod. It can be used as a command itself.
+type Change struct {
+	Resources []ResourceChange
+	Textures  []TextureChange
+	Objects   []ObjectChange
+}
+
+// Do applies the state after the change.
+func (change Change) Do(modder world.Modder) error {
+	change.apply(modder, true)
+	return nil
+}
+
+// Undo applies the state before the change.
+func (change Change) Undo(modder world.Modder) error {
+	change.apply(modder, false)
+	return nil
+}
+
+func (change Change) apply(modder world.Modder, forward bool) {
+	for _, res := range change.Resources {
+		res.apply(modder, forward)
+	}
+	for _, tex := range change.Textures {
+		if forward {
+			modder.SetTextureProperties(tex.Index, tex.After)
+		} else {
+			modder.SetTextureProperties(tex.Index, tex.Before)
+		}
+	}
+	for _, obj := range change.Objects {
+		if forward {
+			modder.SetObjectProperties(obj.Triple, obj.After)
+		} else {
+			modder.SetObjectProperties(obj.Triple, obj.Before)
+		}
+	}
+}
+
+// Empty returns true if the change does not affect anything.
+func (change Change) Empty() bool {
+	return (len(change.Resources) == 0) && (len(change.Textures) == 0) && (len(change.Objects) == 0)
+}
+
+func checksum(blocks [][]byte) uint32 {
+	if blocks == nil {
+		return 0
+	}
+	hash := crc32.NewIEEE()
+	for _, block := range blocks {
+		length := len(block)
+		_, _ = hash.Write([]byte{byte(length >> 24), byte(length >> 16), byte(length >> 8), byte(length)})
+		_, _ = hash.Write(block)
+	}
+	// Existing resources never have a zero checksum, to distinguish them from missing ones.
+	return hash.Sum32() | 1
+}
+
+func equalObjectProperties(a, b object.Properties) bool {
+	return (a.Common == b.Common) && bytes.Equal(a.Generic, b.Generic) && bytes.Equal(a.Specific, b.Specific)
+}
@@ -0,0 +1,204 @@
+package history
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+)
+
+// CurrentVersion is the version of the file format.
+const CurrentVersion = 1
+
+// Step is one entry of the history, with the changes it made to the mod.
+type Step struct {
+	Description string
+	Changes     []Change
+}
+
+// File is the stored history of a mod.
+type File struct {
+	Version int
+	// Steps are listed oldest first.
+	Steps []Step
+	// Done is the number of steps that are applied to the mod. The remaining steps were undone.
+	Done int
+}
+
+// Collect returns the recorded changes of the given command.
+// It returns false if the command contains parts of which the changes are not known.
+func Collect(command cmd.Command) ([]Change, bool) {
+	switch typed := command.(type) {
+	case *Recorded:
+		change, completed := typed.Change()
+		if !completed {
+			return nil, false
+		}
+		return []Change{change}, true
+	case Change:
+		return []Change{typed}, true
+	case cmd.Named:
+		return Collect(typed.Command)
+	case cmd.List:
+		var changes []Change
+		for _, entry := range typed {
+			entryChanges, known := Collect(entry)
+			if !known {
+				return nil, false
+			}
+			changes = append(changes, entryChanges...)
+		}
+		return changes, true
+	default:
+		return nil, false
+	}
+}
+
+// FromStack returns the history of the given stack entries.
+// Starting from the current state, the history extends in both directions up to the first entry
+// of which the changes are not known, as neither that entry nor any beyond it could be replayed.
+// Entries that do not change the mod are skipped.
+func FromStack(entries []cmd.Entry, done int) File {
+	file := File{Version: CurrentVersion}
+	first := done
+	for (first > 0) && recordable(entries[first-1]) {
+		first--
+	}
+	last := done
+	for (last < len(entries)) && recordable(entries[last]) {
+		last++
+	}
+	for index := first; index < last; index++ {
+		changes, _ := Collect(entries[index].Command)
+		var nonEmpty []Change
+		for _, change := range changes {
+			if !change.Empty() {
+				nonEmpty = append(nonEmpty, change)
+			}
+		}
+		if len(nonEmpty) == 0 {
+			continue
+		}
+		file.Steps = append(file.Steps, Step{Description: entries[index].Description, Changes: nonEmpty})
+		if index < done {
+			file.Done++
+		}
+	}
+	return file
+}
+
+func recordable(entry cmd.Entry) bool {
+	_, known := Collect(entry.Command)
+	return known
+}
+
+// StackEntries returns the steps of the history as entries for a command stack.
+func (file File) StackEntries() []cmd.Entry {
+	entries := make([]cmd.Entry, len(file.Steps))
+	for index, step := range file.Steps {
+		list := make(cmd.List, len(step.Changes))
+		for changeIndex, change := range step.Changes {
+			list[changeIndex] = change
+		}
+		entries[index] = cmd.Entry{Command: list, Description: step.Description}
+	}
+	return entries
+}
+
+// Write stores the history, compressed, in the given writer.
+func Write(writer io.Writer, file File) error {
+	compressor := gzip.NewWriter(writer)
+	err := gob.NewEncoder(compressor).Encode(&file)
+	closeErr := compressor.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// Read loads a history as stored by Write.
+func Read(reader io.Reader) (File, error) {
+	var file File
+	decompressor, err := gzip.NewReader(reader)
+	if err != nil {
+		return file, err
+	}
+	defer func() { _ = decompressor.Close() }()
+	err = gob.NewDecoder(decompressor).Decode(&file)
+	if err != nil {
+		return File{}, err
+	}
+	if file.Version != CurrentVersion {
+		return File{}, errors.New("unsupported version")
+	}
+	if (file.Done < 0) || (file.Done > len(file.Steps)) {
+		return File{}, errors.New("invalid number of done steps")
+	}
+	return file, nil
+}
+
+// Consistent returns true if the given state matches the history.
+// Every resource and property the history refers to must be in the state of the most recent
+// done step that touched it, or in the state before the first step if no done step touched it.
+func (file File) Consistent(state State) bool {
+	resourceSums := make(map[resourceKey]uint32)
+	textures := make(map[int]texture.Properties)
+	objects := make(map[object.Triple]object.Properties)
+	expect := func(step Step, forward bool) {
+		for changeIndex := range step.Changes {
+			change := step.Changes[changeIndex]
+			if !forward {
+				change = step.Changes[len(step.Changes)-1-changeIndex]
+			}
+			for _, res := range change.Resources {
+				key := resourceKey{lang: res.Lang, id: res.ID}
+				resourceSums[key] = res.BeforeSum
+				if forward {
+					resourceSums[key] = res.AfterSum
+				}
+			}
+			for _, tex := range change.Textures {
+				textures[tex.Index] = tex.Before
+				if forward {
+					textures[tex.Index] = tex.After
+				}
+			}
+			for _, obj := range change.Objects {
+				objects[obj.Triple] = obj.Before
+				if forward {
+					objects[obj.Triple] = obj.After
+				}
+			}
+		}
+	}
+	for index := len(file.Steps) - 1; index >= file.Done; index-- {
+		expect(file.Steps[index], false)
+	}
+	for index := 0; index < file.Done; index++ {
+		expect(file.Steps[index], true)
+	}
+
+	for key, sum := range resourceSums {
+		if checksum(state.ModifiedBlocks(key.lang, key.id)) != sum {
+			return false
+		}
+	}
+	textureList := state.TextureProperties()
+	for index, expected := range textures {
+		if (index >= len(textureList)) || (textureList[index] != expected) {
+			return false
+		}
+	}
+	objectTable := state.ObjectProperties()
+	for triple, expected := range objects {
+		prop, err := objectTable.ForObject(triple)
+		if (err != nil) || !equalObjectProperties(*prop, expected) {
+			return false
+		}
+	}
+	return true
+}
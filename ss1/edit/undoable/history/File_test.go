@@ -0,0 +1,213 @@
+package history_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/history"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+type modderFunc func(modder world.Modder)
+
+func (f modderFunc) Do(modder world.Modder) error {
+	f(modder)
+	return nil
+}
+
+func (f modderFunc) Undo(modder world.Modder) error {
+	return nil
+}
+
+type reversibleCommand struct {
+	do   func(modder world.Modder)
+	undo func(modder world.Modder)
+}
+
+func (command reversibleCommand) Do(modder world.Modder) error {
+	command.do(modder)
+	return nil
+}
+
+func (command reversibleCommand) Undo(modder world.Modder) error {
+	command.undo(modder)
+	return nil
+}
+
+func newTestMod() *world.Mod {
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	mod.Reset(nil, object.StandardPropertiesTable(), make(texture.PropertiesList, 4))
+	return mod
+}
+
+func perform(t *testing.T, mod *world.Mod, stack *cmd.Stack, command cmd.Command) {
+	t.Helper()
+	recorded := history.Record(command, mod)
+	var err error
+	mod.Modify(func(modder world.Modder) { err = stack.Perform(recorded, modder) })
+	require.Nil(t, err, "no error expected performing")
+	recorded.Complete()
+}
+
+func undo(t *testing.T, mod *world.Mod, stack *cmd.Stack) {
+	t.Helper()
+	var err error
+	mod.Modify(func(modder world.Modder) { err = stack.Undo(modder) })
+	require.Nil(t, err, "no error expected undoing")
+}
+
+func redo(t *testing.T, mod *world.Mod, stack *cmd.Stack) {
+	t.Helper()
+	var err error
+	mod.Modify(func(modder world.Modder) { err = stack.Redo(modder) })
+	require.Nil(t, err, "no error expected redoing")
+}
+
+func roundTrip(t *testing.T, file history.File) history.File {
+	t.Helper()
+	buffer := bytes.NewBuffer(nil)
+	require.Nil(t, history.Write(buffer, file), "no error expected writing")
+	result, err := history.Read(buffer)
+	require.Nil(t, err, "no error expected reading")
+	return result
+}
+
+func TestRecordedChangesCanBeUndoneAfterRestore(t *testing.T) {
+	mod := newTestMod()
+	stack := new(cmd.Stack)
+	triple := object.TripleFrom(0, 0, 0)
+	perform(t, mod, stack, cmd.Named{Title: "first", Command: modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x01, 0x02, 0x03}, {0x04}})
+	})})
+	perform(t, mod, stack, cmd.Named{Title: "second", Command: modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x1000, 0, []byte{0x01, 0xFF, 0x03})
+		modder.SetTextureProperties(2, texture.Properties{Climbable: 1})
+		prop, _ := mod.ObjectProperties().ForObject(triple)
+		changed := prop.Clone()
+		changed.Common.Mass = 1234
+		modder.SetObjectProperties(triple, changed)
+	})})
+
+	entries, done := stack.History()
+	file := roundTrip(t, history.FromStack(entries, done))
+	require.Equal(t, 2, len(file.Steps))
+	assert.Equal(t, "second", file.Steps[1].Description)
+	assert.Equal(t, 2, file.Done)
+	assert.True(t, file.Consistent(mod), "history should be consistent")
+
+	restored := cmd.NewStackFrom(file.StackEntries(), file.Done)
+	undo(t, mod, restored)
+	assert.Equal(t, [][]byte{{0x01, 0x02, 0x03}, {0x04}}, mod.ModifiedBlocks(resource.LangAny, 0x1000))
+	assert.Equal(t, texture.Properties{}, mod.TextureProperties()[2])
+	prop, _ := mod.ObjectProperties().ForObject(triple)
+	assert.Equal(t, int32(0), prop.Common.Mass)
+
+	undo(t, mod, restored)
+	assert.Nil(t, mod.ModifiedBlocks(resource.LangAny, 0x1000), "resource should be removed")
+
+	redo(t, mod, restored)
+	redo(t, mod, restored)
+	assert.Equal(t, [][]byte{{0x01, 0xFF, 0x03}, {0x04}}, mod.ModifiedBlocks(resource.LangAny, 0x1000))
+	assert.Equal(t, texture.Properties{Climbable: 1}, mod.TextureProperties()[2])
+}
+
+func TestHistoryConsidersUndoneSteps(t *testing.T) {
+	mod := newTestMod()
+	stack := new(cmd.Stack)
+	perform(t, mod, stack, reversibleCommand{
+		do:   func(modder world.Modder) { modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x01}}) },
+		undo: func(modder world.Modder) { modder.DelResource(resource.LangAny, 0x1000) },
+	})
+	undo(t, mod, stack)
+
+	entries, done := stack.History()
+	file := roundTrip(t, history.FromStack(entries, done))
+	assert.Equal(t, 0, file.Done)
+	assert.True(t, file.Consistent(mod), "history should be consistent")
+
+	restored := cmd.NewStackFrom(file.StackEntries(), file.Done)
+	redo(t, mod, restored)
+	assert.Equal(t, [][]byte{{0x01}}, mod.ModifiedBlocks(resource.LangAny, 0x1000))
+}
+
+func TestHistoryIsInconsistentWithDifferentState(t *testing.T) {
+	mod := newTestMod()
+	stack := new(cmd.Stack)
+	perform(t, mod, stack, modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x01}})
+	}))
+	entries, done := stack.History()
+	file := history.FromStack(entries, done)
+
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x02}})
+	})
+	assert.False(t, file.Consistent(mod), "history should not be consistent")
+}
+
+func TestHistorySkipsEmptyCommands(t *testing.T) {
+	mod := newTestMod()
+	stack := new(cmd.Stack)
+	perform(t, mod, stack, modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x01}})
+	}))
+	perform(t, mod, stack, modderFunc(func(modder world.Modder) {}))
+	perform(t, mod, stack, modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x01}})
+	}))
+	perform(t, mod, stack, modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x02}})
+	}))
+
+	entries, done := stack.History()
+	file := history.FromStack(entries, done)
+	assert.Equal(t, 2, len(file.Steps))
+	assert.Equal(t, 2, file.Done)
+}
+
+func TestHistoryStopsAtUnknownCommands(t *testing.T) {
+	mod := newTestMod()
+	stack := new(cmd.Stack)
+	performUnknown := func(command cmd.Command) {
+		mod.Modify(func(modder world.Modder) {
+			err := stack.Perform(command, modder)
+			require.Nil(t, err, "no error expected performing")
+		})
+	}
+	perform(t, mod, stack, cmd.Named{Title: "lost", Command: modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x01}})
+	})})
+	performUnknown(modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x02}})
+	}))
+	perform(t, mod, stack, cmd.Named{Title: "kept", Command: modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1000, [][]byte{{0x03}})
+	})})
+	performUnknown(modderFunc(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x1001, [][]byte{{0x04}})
+	}))
+	undo(t, mod, stack)
+
+	entries, done := stack.History()
+	file := roundTrip(t, history.FromStack(entries, done))
+	require.Equal(t, 1, len(file.Steps))
+	assert.Equal(t, "kept", file.Steps[0].Description)
+	assert.Equal(t, 1, file.Done)
+	assert.True(t, file.Consistent(mod), "history should be consistent")
+
+	restored := cmd.NewStackFrom(file.StackEntries(), file.Done)
+	undo(t, mod, restored)
+	assert.Equal(t, [][]byte{{0x02}}, mod.ModifiedBlocks(resource.LangAny, 0x1000))
+}
+
+func TestReadFailsForInvalidData(t *testing.T) {
+	_, err := history.Read(bytes.NewReader([]byte{0x01, 0x02}))
+	assert.NotNil(t, err, "error expected")
+}
@@ -0,0 +1,174 @@
+package history
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// State provides the current data of a mod.
+type State interface {
+	// ModifiedBlocks returns all blocks of the resource, or nil if the mod does not contain it.
+	ModifiedBlocks(lang resource.Language, id resource.ID) [][]byte
+	TextureProperties() texture.PropertiesList
+	ObjectProperties() object.PropertiesTable
+}
+
+type resourceKey struct {
+	lang resource.Language
+	id   resource.ID
+}
+
+// Recorded is a command of which the change to the mod is recorded when it is performed the first time.
+type Recorded struct {
+	cmd.Command
+
+	state    State
+	recorder *recordingModder
+	change   *Change
+}
+
+// Record returns a command that records the change of the given one, based on the given state.
+// Complete must be called after the first Do has been applied to the state.
+func Record(command cmd.Command, state State) *Recorded {
+	return &Recorded{Command: command, state: state}
+}
+
+// Description returns the description of the wrapped command.
+func (rec *Recorded) Description() string {
+	return cmd.DescriptionOf(rec.Command)
+}
+
+// GroupKey returns the group of the wrapped command.
+func (rec *Recorded) GroupKey() string {
+	return cmd.GroupOf(rec.Command)
+}
+
+// Do performs the wrapped command. The first call records the affected data.
+func (rec *Recorded) Do(modder world.Modder) error {
+	if (rec.change != nil) || (rec.recorder != nil) {
+		return rec.Command.Do(modder)
+	}
+	rec.recorder = newRecordingModder(modder, rec.state)
+	return rec.Command.Do(rec.recorder)
+}
+
+// Complete records the data after the first Do.
+func (rec *Recorded) Complete() {
+	if (rec.recorder == nil) || (rec.change != nil) {
+		return
+	}
+	change := rec.recorder.change(rec.state)
+	rec.change = &change
+	rec.recorder = nil
+}
+
+// Change returns the recorded change. It returns false if the change has not been completed.
+func (rec *Recorded) Change() (Change, bool) {
+	if rec.change == nil {
+		return Change{}, false
+	}
+	return *rec.change, true
+}
+
+type recordingModder struct {
+	world.Modder
+
+	resourceKeys   []resourceKey
+	resourceBefore map[resourceKey][][]byte
+	textureIndices []int
+	textureBefore  map[int]texture.Properties
+	objectTriples  []object.Triple
+	objectBefore   map[object.Triple]object.Properties
+
+	state State
+}
+
+func newRecordingModder(modder world.Modder, state State) *recordingModder {
+	return &recordingModder{
+		Modder:         modder,
+		resourceBefore: make(map[resourceKey][][]byte),
+		textureBefore:  make(map[int]texture.Properties),
+		objectBefore:   make(map[object.Triple]object.Properties),
+		state:          state,
+	}
+}
+
+func (modder *recordingModder) touchResource(lang resource.Language, id resource.ID) {
+	key := resourceKey{lang: lang, id: id}
+	if _, known := modder.resourceBefore[key]; known {
+		return
+	}
+	modder.resourceKeys = append(modder.resourceKeys, key)
+	modder.resourceBefore[key] = modder.state.ModifiedBlocks(lang, id)
+}
+
+func (modder *recordingModder) SetResourceBlock(lang resource.Language, id resource.ID, index int, data []byte) {
+	modder.touchResource(lang, id)
+	modder.Modder.SetResourceBlock(lang, id, index, data)
+}
+
+func (modder *recordingModder) PatchResourceBlock(lang resource.Language, id resource.ID, index int, expectedLength int, patch []byte) {
+	modder.touchResource(lang, id)
+	modder.Modder.PatchResourceBlock(lang, id, index, expectedLength, patch)
+}
+
+func (modder *recordingModder) SetResourceBlocks(lang resource.Language, id resource.ID, data [][]byte) {
+	modder.touchResource(lang, id)
+	modder.Modder.SetResourceBlocks(lang, id, data)
+}
+
+func (modder *recordingModder) DelResource(lang resource.Language, id resource.ID) {
+	modder.touchResource(lang, id)
+	modder.Modder.DelResource(lang, id)
+}
+
+func (modder *recordingModder) SetTextureProperties(textureIndex int, properties texture.Properties) {
+	if _, known := modder.textureBefore[textureIndex]; !known {
+		list := modder.state.TextureProperties()
+		if (textureIndex >= 0) && (textureIndex < len(list)) {
+			modder.textureIndices = append(modder.textureIndices, textureIndex)
+			modder.textureBefore[textureIndex] = list[textureIndex]
+		}
+	}
+	modder.Modder.SetTextureProperties(textureIndex, properties)
+}
+
+func (modder *recordingModder) SetObjectProperties(triple object.Triple, properties object.Properties) {
+	if _, known := modder.objectBefore[triple]; !known {
+		prop, err := modder.state.ObjectProperties().ForObject(triple)
+		if err == nil {
+			modder.objectTriples = append(modder.objectTriples, triple)
+			modder.objectBefore[triple] = prop.Clone()
+		}
+	}
+	modder.Modder.SetObjectProperties(triple, properties)
+}
+
+func (modder *recordingModder) change(state State) Change {
+	var change Change
+	for _, key := range modder.resourceKeys {
+		res := newResourceChange(key.lang, key.id, modder.resourceBefore[key], state.ModifiedBlocks(key.lang, key.id))
+		if res.BeforeSum != res.AfterSum {
+			change.Resources = append(change.Resources, res)
+		}
+	}
+	textures := state.TextureProperties()
+	for _, index := range modder.textureIndices {
+		if (index < len(textures)) && (textures[index] != modder.textureBefore[index]) {
+			change.Textures = append(change.Textures,
+				TextureChange{Index: index, Before: modder.textureBefore[index], After: textures[index]})
+		}
+	}
+	objects := state.ObjectProperties()
+	for _, triple := range modder.objectTriples {
+		prop, err := objects.ForObject(triple)
+		if (err == nil) && !equalObjectProperties(*prop, modder.objectBefore[triple]) {
+			change.Objects = append(change.Objects,
+				ObjectChange{Triple: triple, Before: modder.objectBefore[triple], After: prop.Clone()})
+		}
+	}
+	return change
+}
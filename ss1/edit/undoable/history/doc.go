@@ -0,0 +1,2 @@
+// Package history keeps the undo history of a mod beyond a session.
+package history
@@ -12,11 +12,11 @@ type Filename interface {
 }
 
 // I18nFile is for internationalized resource files - i.e., those that store resources per file.
-type I18nFile [LanguageCount]string
+type I18nFile map[Language]string
 
-// For returns the string per language index.
+// For returns the string for given language, or an empty string if the language has no file.
 func (spec I18nFile) For(lang Language) string {
-	return spec[int(lang)]
+	return spec[lang]
 }
 
 // Matches returns true if the given filename matches one of the localized filenames.
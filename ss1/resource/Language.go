@@ -1,6 +1,9 @@
 package resource
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Language defines the human language of a resource.
 type Language byte
@@ -15,28 +18,114 @@ const (
 	// LangGerman identifies the German language.
 	LangGerman Language = 2
 
-	// LanguageCount specifies how many languages are supported.
-	LanguageCount = 3
+	// BuiltinLanguageCount specifies how many languages the original game supports.
+	BuiltinLanguageCount = 3
+	// MaxLanguageCount specifies how many languages can be registered at most.
+	MaxLanguageCount = int(LangAny)
 )
 
+// LanguageInfo describes a human language.
+type LanguageInfo struct {
+	// Name is the displayed name of the language, such as "Spanish".
+	Name string
+	// Code is an abbreviation of three upper case letters, such as "SPA".
+	// It identifies the language within data files.
+	Code string
+}
+
+var builtinLanguages = []LanguageInfo{
+	{Name: "Default", Code: "STD"},
+	{Name: "French", Code: "FRN"},
+	{Name: "German", Code: "GER"},
+}
+
+var registeredLanguages = append([]LanguageInfo{}, builtinLanguages...)
+
+// RegisterLanguage adds a further language to the registry and returns its identifier.
+// Names and codes must be unique. Languages must be registered before any resources are loaded.
+// The registry is not safe for concurrent modification.
+func RegisterLanguage(info LanguageInfo) (Language, error) {
+	if len(registeredLanguages) >= MaxLanguageCount {
+		return LangAny, fmt.Errorf("too many languages")
+	}
+	if (len(info.Name) == 0) || strings.EqualFold(info.Name, LangAny.String()) {
+		return LangAny, fmt.Errorf("invalid language name <%s>", info.Name)
+	}
+	if !isLanguageCode(info.Code) {
+		return LangAny, fmt.Errorf("invalid language code <%s>, must be three upper case letters", info.Code)
+	}
+	if _, exists := LanguageNamed(info.Name); exists {
+		return LangAny, fmt.Errorf("language <%s> already registered", info.Name)
+	}
+	if _, exists := LanguageWithCode(info.Code); exists {
+		return LangAny, fmt.Errorf("language code <%s> already registered", info.Code)
+	}
+	registeredLanguages = append(registeredLanguages, info)
+	return Language(len(registeredLanguages) - 1), nil
+}
+
+// ResetLanguages removes all registered languages, keeping only the built-in ones.
+func ResetLanguages() {
+	registeredLanguages = append([]LanguageInfo{}, builtinLanguages...)
+}
+
+func isLanguageCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'A') || (r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// LanguageNamed returns the registered language with given name. The name is compared case-insensitive.
+func LanguageNamed(name string) (Language, bool) {
+	for index, info := range registeredLanguages {
+		if strings.EqualFold(info.Name, name) {
+			return Language(index), true
+		}
+	}
+	return LangAny, false
+}
+
+// LanguageWithCode returns the registered language with given code.
+func LanguageWithCode(code string) (Language, bool) {
+	for index, info := range registeredLanguages {
+		if info.Code == code {
+			return Language(index), true
+		}
+	}
+	return LangAny, false
+}
+
+// Info returns the description of the language. Returns false if the language is not registered.
+func (lang Language) Info() (LanguageInfo, bool) {
+	if int(lang) >= len(registeredLanguages) {
+		return LanguageInfo{}, false
+	}
+	return registeredLanguages[lang], true
+}
+
 func (lang Language) String() string {
-	switch lang {
-	case LangAny:
+	if lang == LangAny {
 		return "Any"
-	case LangDefault:
-		return "Default"
-	case LangFrench:
-		return "French"
-	case LangGerman:
-		return "German"
-	default:
-		return fmt.Sprintf("Unknown%02X", int(lang))
 	}
+	if info, known := lang.Info(); known {
+		return info.Name
+	}
+	return fmt.Sprintf("Unknown%02X", int(lang))
 }
 
-// Languages returns a slice of all human languages. Does not include "Any" selector.
+// Languages returns a slice of all registered human languages. Does not include "Any" selector.
 func Languages() []Language {
-	return []Language{LangDefault, LangFrench, LangGerman}
+	result := make([]Language, len(registeredLanguages))
+	for index := range registeredLanguages {
+		result[index] = Language(index)
+	}
+	return result
 }
 
 // Includes returns true if the language includes the provided one.
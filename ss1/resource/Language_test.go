@@ -56,3 +56,43 @@ func TestLanguages(t *testing.T) {
 	result := resource.Languages()
 	assert.Equal(t, 3, len(result))
 }
+
+func TestRegisterLanguage(t *testing.T) {
+	defer resource.ResetLanguages()
+	lang, err := resource.RegisterLanguage(resource.LanguageInfo{Name: "Spanish", Code: "SPA"})
+	assert.Nil(t, err, "no error expected")
+	assert.Equal(t, resource.Language(3), lang)
+	assert.Equal(t, "Spanish", lang.String())
+	assert.Equal(t, 4, len(resource.Languages()))
+
+	named, known := resource.LanguageNamed("spanish")
+	assert.True(t, known, "language should be found by name")
+	assert.Equal(t, lang, named)
+	coded, known := resource.LanguageWithCode("SPA")
+	assert.True(t, known, "language should be found by code")
+	assert.Equal(t, lang, coded)
+}
+
+func TestRegisterLanguageRejectsInvalidInfo(t *testing.T) {
+	defer resource.ResetLanguages()
+	tt := []resource.LanguageInfo{
+		{Name: "", Code: "SPA"},
+		{Name: "Any", Code: "SPA"},
+		{Name: "german", Code: "SPA"},
+		{Name: "Spanish", Code: "GER"},
+		{Name: "Spanish", Code: "spa"},
+		{Name: "Spanish", Code: "SPAN"},
+	}
+	for _, tc := range tt {
+		_, err := resource.RegisterLanguage(tc)
+		assert.NotNil(t, err, fmt.Sprintf("error expected for %v", tc))
+	}
+	assert.Equal(t, resource.BuiltinLanguageCount, len(resource.Languages()))
+}
+
+func TestResetLanguages(t *testing.T) {
+	_, _ = resource.RegisterLanguage(resource.LanguageInfo{Name: "Polish", Code: "POL"})
+	resource.ResetLanguages()
+	assert.Equal(t, resource.BuiltinLanguageCount, len(resource.Languages()))
+	assert.Equal(t, "Unknown03", resource.Language(3).String())
+}
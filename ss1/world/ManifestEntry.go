@@ -10,6 +10,8 @@ import (
 type ManifestEntry struct {
 	ID        string
 	Resources resource.LocalizedResourcesList
+	// Sources lists the paths the resources were loaded from.
+	Sources []string
 
 	ObjectProperties  object.PropertiesTable
 	TextureProperties texture.PropertiesList
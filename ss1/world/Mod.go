@@ -27,7 +27,8 @@ type Mod struct {
 
 	modPath        string
 	lastChangeTime time.Time
-	changedFiles   map[string]struct{}
+	changeCount    uint64
+	changedFiles   map[string]uint64
 
 	data ModData
 }
@@ -37,7 +38,7 @@ func NewMod(resourcesChanged resource.ModificationCallback, resetCallback ModRes
 	mod := &Mod{
 		resourcesChanged: resourcesChanged,
 		resetCallback:    resetCallback,
-		changedFiles:     make(map[string]struct{}),
+		changedFiles:     make(map[string]uint64),
 	}
 	mod.worldManifest = NewManifest(mod.worldChanged)
 	mod.data.FileChangeCallback = mod.markFileChanged
@@ -75,6 +76,22 @@ func (mod Mod) ModifiedFilenames() []string {
 	return result
 }
 
+// ModifiedFilenamesSince returns the list of filenames suspected of change after the given change count.
+func (mod Mod) ModifiedFilenamesSince(count uint64) []string {
+	var result []string
+	for filename, changedAt := range mod.changedFiles {
+		if changedAt > count {
+			result = append(result, filename)
+		}
+	}
+	return result
+}
+
+// ChangeCount returns the number of changes so far. It is never reset.
+func (mod Mod) ChangeCount() uint64 {
+	return mod.changeCount
+}
+
 // LastChangeTime returns the timestamp of the last change. Zero if not modified.
 func (mod *Mod) LastChangeTime() time.Time {
 	return mod.lastChangeTime
@@ -85,9 +102,17 @@ func (mod *Mod) ResetLastChangeTime() {
 	mod.lastChangeTime = time.Time{}
 }
 
+// MarkFilesChanged adds the given filenames to the list of modified filenames.
+// This is used for changes that were restored from elsewhere than the mod path, and still need to be saved.
+func (mod *Mod) MarkFilesChanged(filenames []string) {
+	for _, filename := range filenames {
+		mod.markFileChanged(filename)
+	}
+}
+
 // MarkSave clears the list of modified filenames.
 func (mod *Mod) MarkSave() {
-	mod.changedFiles = make(map[string]struct{})
+	mod.changedFiles = make(map[string]uint64)
 	mod.lastChangeTime = time.Time{}
 }
 
@@ -284,14 +309,15 @@ func (mod *Mod) Reset(newResources []*LocalizedResources, objectProperties objec
 	mod.data.LocalizedResources = newResources
 	mod.data.ObjectProperties = objectProperties
 	mod.data.TextureProperties = textureProperties
-	mod.changedFiles = make(map[string]struct{})
+	mod.changedFiles = make(map[string]uint64)
 	mod.lastChangeTime = time.Time{}
 	mod.resetCallback()
 	mod.resourcesChanged(modifiedIDs.ToList(), nil)
 }
 
 func (mod *Mod) markFileChanged(filename string) {
-	mod.changedFiles[filename] = struct{}{}
+	mod.changeCount++
+	mod.changedFiles[filename] = mod.changeCount
 	mod.lastChangeTime = time.Now()
 }
 
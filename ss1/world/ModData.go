@@ -109,7 +109,9 @@ func (data *ModData) newResource(lang resource.Language, id resource.ID) (*Local
 		compound = info.Compound
 		contentType = info.ContentType
 		compressed = info.Compressed
-		filename = info.ResFile.For(lang)
+		if localized := info.ResFile.For(lang); len(localized) > 0 {
+			filename = localized
+		}
 	}
 
 	loc := data.ensureStore(lang, filename)
@@ -146,6 +146,22 @@ func (suite *ModSuite) TestModifiedBlocksCanBeRetrievedBulk() {
 	assert.Equal(suite.T(), [][]byte{{0xBB}, {0xCC}}, suite.mod.ModifiedBlocks(resource.LangAny, 0x0800))
 }
 
+func (suite *ModSuite) TestMarkFilesChangedListsFilenames() {
+	suite.mod.MarkFilesChanged([]string{"citmat.res", "objprop.dat"})
+	filenames := suite.mod.ModifiedFilenames()
+	sort.Strings(filenames)
+	assert.Equal(suite.T(), []string{"citmat.res", "objprop.dat"}, filenames)
+	assert.False(suite.T(), suite.mod.LastChangeTime().IsZero(), "change time should be set")
+}
+
+func (suite *ModSuite) TestModifiedFilenamesSinceListsLaterChanges() {
+	suite.mod.MarkFilesChanged([]string{"citmat.res"})
+	count := suite.mod.ChangeCount()
+	suite.mod.MarkFilesChanged([]string{"objprop.dat"})
+	assert.Equal(suite.T(), []string{"objprop.dat"}, suite.mod.ModifiedFilenamesSince(count))
+	assert.Equal(suite.T(), 0, len(suite.mod.ModifiedFilenamesSince(suite.mod.ChangeCount())))
+}
+
 func (suite *ModSuite) givenWorldHas(res ...resource.LocalizedResources) {
 	suite.whenWorldIsExtendedWith(res...)
 	suite.lastModifiedIDs = nil
@@ -0,0 +1,32 @@
+package world
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/serial"
+)
+
+// DecodeObjectProperties reads the content of an object properties file.
+// The file is expected to have the layout of the standard descriptors.
+func DecodeObjectProperties(data []byte) (object.PropertiesTable, error) {
+	decoder := serial.NewDecoder(bytes.NewReader(data))
+	properties := object.StandardPropertiesTable()
+	properties.Code(decoder)
+	return properties, decoder.FirstError()
+}
+
+// DecodeTextureProperties reads the content of a texture properties file.
+// The number of entries is determined by the size of the data.
+func DecodeTextureProperties(data []byte) (texture.PropertiesList, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("texture properties too short")
+	}
+	decoder := serial.NewDecoder(bytes.NewReader(data))
+	entryCount := (len(data) - 4) / texture.PropertiesSize
+	properties := make(texture.PropertiesList, entryCount)
+	properties.Code(decoder)
+	return properties, decoder.FirstError()
+}
@@ -0,0 +1,36 @@
+package world_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/serial"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+func TestDecodeTexturePropertiesDeterminesCountFromSize(t *testing.T) {
+	list := make(texture.PropertiesList, 3)
+	list[2].AnimationGroup = 5
+	buf := bytes.NewBuffer(nil)
+	encoder := serial.NewEncoder(buf)
+	list.Code(encoder)
+	require.Nil(t, encoder.FirstError())
+
+	decoded, err := world.DecodeTextureProperties(buf.Bytes())
+	require.Nil(t, err)
+	assert.Equal(t, list, decoded)
+}
+
+func TestDecodeTexturePropertiesFailsForShortData(t *testing.T) {
+	_, err := world.DecodeTextureProperties([]byte{0x01})
+	assert.Error(t, err)
+}
+
+func TestDecodeObjectPropertiesFailsForShortData(t *testing.T) {
+	_, err := world.DecodeObjectProperties([]byte{0x01, 0x02})
+	assert.Error(t, err)
+}
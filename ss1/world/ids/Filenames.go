@@ -7,22 +7,46 @@ import (
 )
 
 // CybStrng contains all strings.
-var CybStrng = resource.I18nFile([resource.LanguageCount]string{"cybstrng.res", "frnstrng.res", "gerstrng.res"})
+var CybStrng = resource.I18nFile{
+	resource.LangDefault: "cybstrng.res",
+	resource.LangFrench:  "frnstrng.res",
+	resource.LangGerman:  "gerstrng.res",
+}
 
 // MfdArt contains all MFD graphics.
-var MfdArt = resource.I18nFile([resource.LanguageCount]string{"mfdart.res", "mfdfrn.res", "mfdger.res"})
+var MfdArt = resource.I18nFile{
+	resource.LangDefault: "mfdart.res",
+	resource.LangFrench:  "mfdfrn.res",
+	resource.LangGerman:  "mfdger.res",
+}
 
 // CitALog contains all log audio.
-var CitALog = resource.I18nFile([resource.LanguageCount]string{"citalog.res", "frnalog.res", "geralog.res"})
+var CitALog = resource.I18nFile{
+	resource.LangDefault: "citalog.res",
+	resource.LangFrench:  "frnalog.res",
+	resource.LangGerman:  "geralog.res",
+}
 
 // CitBark contains all bark audio.
-var CitBark = resource.I18nFile([resource.LanguageCount]string{"citbark.res", "frnbark.res", "gerbark.res"})
+var CitBark = resource.I18nFile{
+	resource.LangDefault: "citbark.res",
+	resource.LangFrench:  "frnbark.res",
+	resource.LangGerman:  "gerbark.res",
+}
 
 // LowIntr contains the low-res intro video.
-var LowIntr = resource.I18nFile([resource.LanguageCount]string{"lowintr.res", "lofrintr.res", "logeintr.res"})
+var LowIntr = resource.I18nFile{
+	resource.LangDefault: "lowintr.res",
+	resource.LangFrench:  "lofrintr.res",
+	resource.LangGerman:  "logeintr.res",
+}
 
 // SvgaIntr contains the high-res intro video.
-var SvgaIntr = resource.I18nFile([resource.LanguageCount]string{"svgaintr.res", "svfrintr.res", "svgeintr.res"})
+var SvgaIntr = resource.I18nFile{
+	resource.LangDefault: "svgaintr.res",
+	resource.LangFrench:  "svfrintr.res",
+	resource.LangGerman:  "svgeintr.res",
+}
 
 // Archive contains the game world.
 var Archive = resource.AnyLanguage("archive.dat")
@@ -36,6 +36,14 @@ const (
 	VideoMailAnimationsStart resource.ID = 0x0A4C
 )
 
+// Fonts
+const (
+	// FontsStart is the first identifier of the range in which the game stores its fonts.
+	FontsStart resource.ID = 0x0258
+	// FontsEnd is the identifier after the range of fonts (exclusive).
+	FontsEnd resource.ID = 0x0268
+)
+
 // Movies
 const (
 	MovieIntro resource.ID = 0x0BD6
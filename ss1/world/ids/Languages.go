@@ -0,0 +1,110 @@
+package ids
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// LanguageFiles lists the names of the localized resource files of one language.
+type LanguageFiles struct {
+	CybStrng string `json:"cybstrng"`
+	MfdArt   string `json:"mfdart"`
+	CitALog  string `json:"citalog"`
+	CitBark  string `json:"citbark"`
+	LowIntr  string `json:"lowintr"`
+	SvgaIntr string `json:"svgaintr"`
+}
+
+func (files LanguageFiles) assignments() []struct {
+	spec     resource.I18nFile
+	filename string
+} {
+	return []struct {
+		spec     resource.I18nFile
+		filename string
+	}{
+		{spec: CybStrng, filename: files.CybStrng},
+		{spec: MfdArt, filename: files.MfdArt},
+		{spec: CitALog, filename: files.CitALog},
+		{spec: CitBark, filename: files.CitBark},
+		{spec: LowIntr, filename: files.LowIntr},
+		{spec: SvgaIntr, filename: files.SvgaIntr},
+	}
+}
+
+var registeredDefinitions []LanguageDefinition
+
+// LanguageDefinition describes an additional language with its resource files.
+type LanguageDefinition struct {
+	Name  string        `json:"name"`
+	Code  string        `json:"code"`
+	Files LanguageFiles `json:"files"`
+}
+
+// RegisterLanguage registers the given language and adds its files to the localized filenames.
+// All filenames must be given and must not be in use by another language.
+func RegisterLanguage(def LanguageDefinition) (resource.Language, error) {
+	assignments := def.Files.assignments()
+	used := make(map[string]bool)
+	for _, assignment := range assignments {
+		filename := strings.ToLower(assignment.filename)
+		if len(filename) == 0 {
+			return resource.LangAny, fmt.Errorf("language <%s> is missing a filename", def.Name)
+		}
+		if used[filename] || (LocalizeFilename(filename) != resource.LangAny) {
+			return resource.LangAny, fmt.Errorf("filename <%s> of language <%s> is already in use", filename, def.Name)
+		}
+		used[filename] = true
+	}
+	lang, err := resource.RegisterLanguage(resource.LanguageInfo{Name: def.Name, Code: def.Code})
+	if err != nil {
+		return lang, err
+	}
+	for _, assignment := range assignments {
+		assignment.spec[lang] = strings.ToLower(assignment.filename)
+	}
+	registeredDefinitions = append(registeredDefinitions, def)
+	return lang, nil
+}
+
+// RegisteredLanguages returns the definitions of all additionally registered languages, in order of registration.
+func RegisteredLanguages() []LanguageDefinition {
+	return append([]LanguageDefinition{}, registeredDefinitions...)
+}
+
+// LoadLanguages registers all the languages that are described in given JSON source.
+// The source contains an array of language definitions.
+func LoadLanguages(source io.Reader) error {
+	var defs []LanguageDefinition
+	decoder := json.NewDecoder(source)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&defs)
+	if err != nil {
+		return err
+	}
+	for _, def := range defs {
+		_, err = RegisterLanguage(def)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetLanguages removes all additionally registered languages and their files.
+func ResetLanguages() {
+	for _, file := range LocalizedFiles() {
+		spec := file.(resource.I18nFile)
+		for lang := range spec {
+			if int(lang) >= resource.BuiltinLanguageCount {
+				delete(spec, lang)
+			}
+		}
+	}
+	resource.ResetLanguages()
+	registeredDefinitions = nil
+}
@@ -0,0 +1,62 @@
+package ids_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+func TestLoadLanguagesRegistersFiles(t *testing.T) {
+	defer ids.ResetLanguages()
+	source := `[{"name": "Spanish", "code": "SPA", "files": {
+		"cybstrng": "SPASTRNG.RES", "mfdart": "mfdspa.res", "citalog": "spaalog.res",
+		"citbark": "spabark.res", "lowintr": "lospintr.res", "svgaintr": "svspintr.res"}}]`
+	err := ids.LoadLanguages(strings.NewReader(source))
+	require.Nil(t, err, "no error expected")
+
+	lang, known := resource.LanguageNamed("Spanish")
+	require.True(t, known, "language should be registered")
+	assert.Equal(t, "spastrng.res", ids.CybStrng.For(lang))
+	assert.Equal(t, lang, ids.LocalizeFilename("mfdspa.res"))
+	assert.Equal(t, lang, ids.LocalizeFilename("svspintr.res"))
+}
+
+func TestRegisterLanguageRejectsFilenamesInUse(t *testing.T) {
+	defer ids.ResetLanguages()
+	files := ids.LanguageFiles{
+		CybStrng: "gerstrng.res", MfdArt: "mfdspa.res", CitALog: "spaalog.res",
+		CitBark: "spabark.res", LowIntr: "lospintr.res", SvgaIntr: "svspintr.res",
+	}
+	_, err := ids.RegisterLanguage(ids.LanguageDefinition{Name: "Spanish", Code: "SPA", Files: files})
+	assert.NotNil(t, err, "error expected")
+	assert.Equal(t, resource.BuiltinLanguageCount, len(resource.Languages()))
+}
+
+func TestResetLanguagesRemovesFiles(t *testing.T) {
+	files := ids.LanguageFiles{
+		CybStrng: "itastrng.res", MfdArt: "mfdita.res", CitALog: "itaalog.res",
+		CitBark: "itabark.res", LowIntr: "loitintr.res", SvgaIntr: "svitintr.res",
+	}
+	_, err := ids.RegisterLanguage(ids.LanguageDefinition{Name: "Italian", Code: "ITA", Files: files})
+	require.Nil(t, err, "no error expected")
+	ids.ResetLanguages()
+	assert.Equal(t, resource.LangAny, ids.LocalizeFilename("itastrng.res"))
+}
+
+func TestRegisteredLanguagesListsDefinitions(t *testing.T) {
+	defer ids.ResetLanguages()
+	def := ids.LanguageDefinition{Name: "Italian", Code: "ITA", Files: ids.LanguageFiles{
+		CybStrng: "itastrng.res", MfdArt: "mfdita.res", CitALog: "itaalog.res",
+		CitBark: "itabark.res", LowIntr: "loitintr.res", SvgaIntr: "svitintr.res",
+	}}
+	_, err := ids.RegisterLanguage(def)
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, []ids.LanguageDefinition{def}, ids.RegisteredLanguages())
+	ids.ResetLanguages()
+	assert.Equal(t, 0, len(ids.RegisteredLanguages()))
+}
@@ -0,0 +1,87 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+)
+
+var modifierNames = []struct {
+	modifier Modifier
+	name     string
+}{
+	{modifier: ModControl, name: "Ctrl"},
+	{modifier: ModAlt, name: "Alt"},
+	{modifier: ModShift, name: "Shift"},
+	{modifier: ModSuper, name: "Super"},
+}
+
+// Binding is a combination of a key and modifier that triggers an action.
+type Binding struct {
+	Key      Key
+	Modifier Modifier
+}
+
+// String returns the textual form of the binding, such as "Ctrl+Shift+Z".
+func (binding Binding) String() string {
+	var parts []string
+	for _, entry := range modifierNames {
+		if binding.Modifier.Has(entry.modifier) {
+			parts = append(parts, entry.name)
+		}
+	}
+	return strings.Join(append(parts, binding.Key.String()), "+")
+}
+
+// Typing returns true if the binding produces text when typed, such as a character with only shift.
+// Such bindings must not be handled while text is entered.
+func (binding Binding) Typing() bool {
+	_, isChar := binding.Key.Character()
+	return isChar && !binding.Modifier.Has(ModControl) && !binding.Modifier.Has(ModAlt) && !binding.Modifier.Has(ModSuper)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (binding Binding) MarshalText() ([]byte, error) {
+	return []byte(binding.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (binding *Binding) UnmarshalText(text []byte) error {
+	parsed, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*binding = parsed
+	return nil
+}
+
+// ParseBinding returns the binding of the given text, as returned by String.
+// The "+" character can be used as key if it is the last part.
+func ParseBinding(text string) (Binding, error) {
+	var binding Binding
+	keyName := text
+	if index := strings.LastIndex(text[:len(strings.TrimSuffix(text, "+"))], "+"); index >= 0 {
+		keyName = text[index+1:]
+		for _, modName := range strings.Split(text[:index], "+") {
+			mod, known := modifierByName(modName)
+			if !known {
+				return Binding{}, fmt.Errorf("unknown modifier <%s> in <%s>", modName, text)
+			}
+			binding.Modifier = binding.Modifier.With(mod)
+		}
+	}
+	key, known := KeyByName(keyName)
+	if !known {
+		return Binding{}, fmt.Errorf("unknown key <%s> in <%s>", keyName, text)
+	}
+	binding.Key = key
+	return binding, nil
+}
+
+func modifierByName(name string) (Modifier, bool) {
+	for _, entry := range modifierNames {
+		if strings.EqualFold(entry.name, name) {
+			return entry.modifier, true
+		}
+	}
+	return ModNone, false
+}
@@ -0,0 +1,46 @@
+package input_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ui/input"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindingStringListsModifiersAndKey(t *testing.T) {
+	binding := input.Binding{Key: input.CharacterKey('z'), Modifier: input.ModShift.With(input.ModControl)}
+
+	assert.Equal(t, "Ctrl+Shift+Z", binding.String())
+	assert.Equal(t, "F2", input.Binding{Key: input.KeyF2}.String())
+}
+
+func TestParseBindingIsInverseOfString(t *testing.T) {
+	bindings := []input.Binding{
+		{Key: input.CharacterKey('v')},
+		{Key: input.KeyF12, Modifier: input.ModAlt},
+		{Key: input.KeyPageUp, Modifier: input.ModControl.With(input.ModSuper)},
+		{Key: input.CharacterKey('+'), Modifier: input.ModControl},
+	}
+
+	for _, binding := range bindings {
+		parsed, err := input.ParseBinding(binding.String())
+		require.Nil(t, err, "no error expected for %v", binding)
+		assert.Equal(t, binding, parsed)
+	}
+}
+
+func TestParseBindingFailsForUnknownParts(t *testing.T) {
+	_, err := input.ParseBinding("Hyper+Z")
+	assert.NotNil(t, err, "error expected for unknown modifier")
+	_, err = input.ParseBinding("Ctrl+Something")
+	assert.NotNil(t, err, "error expected for unknown key")
+}
+
+func TestBindingTypingIsTrueForUnmodifiedCharacters(t *testing.T) {
+	assert.True(t, input.Binding{Key: input.CharacterKey('f')}.Typing())
+	assert.True(t, input.Binding{Key: input.CharacterKey('f'), Modifier: input.ModShift}.Typing())
+	assert.False(t, input.Binding{Key: input.CharacterKey('f'), Modifier: input.ModControl}.Typing())
+	assert.False(t, input.Binding{Key: input.KeyF3}.Typing())
+}
@@ -1,5 +1,11 @@
 package input
 
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
 // Key describes a named key on the keyboard. These are keys which are
 // unspecific to layout or language, or are universal.
 // Or, described in another way: keys that don't end up as printable characters.
@@ -86,3 +92,72 @@ func (key Key) AsModifier() Modifier {
 
 	return mod
 }
+
+// keyCharacterBase is the offset for keys that are identified by their character.
+const keyCharacterBase = Key(0x10000)
+
+// CharacterKey returns the key that types the given character, irrespective of its case.
+func CharacterKey(char rune) Key {
+	return keyCharacterBase + Key(unicode.ToLower(char))
+}
+
+// Character returns the character of a key created by CharacterKey.
+func (key Key) Character() (rune, bool) {
+	if key < keyCharacterBase {
+		return 0, false
+	}
+	return rune(key - keyCharacterBase), true
+}
+
+var keyNames = map[Key]string{
+	KeyEnter:     "Enter",
+	KeyEscape:    "Escape",
+	KeyBackspace: "Backspace",
+	KeyTab:       "Tab",
+
+	KeyDown:  "Down",
+	KeyLeft:  "Left",
+	KeyRight: "Right",
+	KeyUp:    "Up",
+
+	KeyDelete:   "Delete",
+	KeyEnd:      "End",
+	KeyHome:     "Home",
+	KeyInsert:   "Insert",
+	KeyPageDown: "PageDown",
+	KeyPageUp:   "PageUp",
+
+	KeyPause:       "Pause",
+	KeyPrintScreen: "PrintScreen",
+}
+
+func init() {
+	for index := 0; index < 12; index++ {
+		keyNames[KeyF1+Key(index)] = fmt.Sprintf("F%d", index+1)
+	}
+}
+
+// String returns the name of the key. Character keys are named by their upper case character.
+func (key Key) String() string {
+	if char, isChar := key.Character(); isChar {
+		return string(unicode.ToUpper(char))
+	}
+	if name, known := keyNames[key]; known {
+		return name
+	}
+	return fmt.Sprintf("Key%d", int(key))
+}
+
+// KeyByName returns the key for the given name, as returned by String.
+// Names of a single character are resolved as character keys.
+func KeyByName(name string) (Key, bool) {
+	if runes := []rune(name); len(runes) == 1 {
+		return CharacterKey(runes[0]), true
+	}
+	for key, keyName := range keyNames {
+		if strings.EqualFold(keyName, name) {
+			return key, true
+		}
+	}
+	return 0, false
+}
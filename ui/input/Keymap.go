@@ -0,0 +1,84 @@
+package input
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// Keymap assigns bindings to named actions.
+type Keymap struct {
+	Bindings map[string][]Binding `json:"bindings"`
+}
+
+// NewKeymap returns an empty keymap.
+func NewKeymap() Keymap {
+	return Keymap{Bindings: make(map[string][]Binding)}
+}
+
+// Set replaces the bindings of given action. An action without bindings is kept
+// as explicitly unbound.
+func (keymap *Keymap) Set(action string, bindings ...Binding) {
+	if keymap.Bindings == nil {
+		keymap.Bindings = make(map[string][]Binding)
+	}
+	keymap.Bindings[action] = append([]Binding{}, bindings...)
+}
+
+// Actions returns the sorted names of all actions that are triggered by the given binding.
+func (keymap Keymap) Actions(binding Binding) []string {
+	var actions []string
+	for action, bindings := range keymap.Bindings {
+		for _, existing := range bindings {
+			if existing == binding {
+				actions = append(actions, action)
+				break
+			}
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// Conflicts returns all bindings that trigger more than one action, with the names of these actions.
+func (keymap Keymap) Conflicts() map[Binding][]string {
+	conflicts := make(map[Binding][]string)
+	for _, bindings := range keymap.Bindings {
+		for _, binding := range bindings {
+			if _, checked := conflicts[binding]; checked {
+				continue
+			}
+			if actions := keymap.Actions(binding); len(actions) > 1 {
+				conflicts[binding] = actions
+			}
+		}
+	}
+	return conflicts
+}
+
+// WithDefaults returns a keymap that has the bindings of the given defaults for all
+// actions that are not part of this keymap.
+func (keymap Keymap) WithDefaults(defaults Keymap) Keymap {
+	result := NewKeymap()
+	for action, bindings := range defaults.Bindings {
+		result.Set(action, bindings...)
+	}
+	for action, bindings := range keymap.Bindings {
+		result.Set(action, bindings...)
+	}
+	return result
+}
+
+// ReadKeymap decodes a keymap from given reader.
+func ReadKeymap(reader io.Reader) (Keymap, error) {
+	keymap := NewKeymap()
+	err := json.NewDecoder(reader).Decode(&keymap)
+	return keymap, err
+}
+
+// WriteKeymap encodes the given keymap to given writer.
+func WriteKeymap(writer io.Writer, keymap Keymap) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(keymap)
+}
@@ -0,0 +1,50 @@
+package input_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ui/input"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeymapConflictsListsActionsSharingBindings(t *testing.T) {
+	keymap := input.NewKeymap()
+	shared := input.Binding{Key: input.KeyF2}
+	keymap.Set("b", shared, input.Binding{Key: input.KeyF3})
+	keymap.Set("a", shared)
+	keymap.Set("c", input.Binding{Key: input.KeyF4})
+
+	conflicts := keymap.Conflicts()
+
+	assert.Equal(t, map[input.Binding][]string{shared: {"a", "b"}}, conflicts)
+}
+
+func TestKeymapWithDefaultsKeepsExplicitlyUnboundActions(t *testing.T) {
+	defaults := input.NewKeymap()
+	defaults.Set("a", input.Binding{Key: input.KeyF1})
+	defaults.Set("b", input.Binding{Key: input.KeyF2})
+	keymap := input.NewKeymap()
+	keymap.Set("a")
+
+	result := keymap.WithDefaults(defaults)
+
+	assert.Equal(t, 0, len(result.Bindings["a"]))
+	assert.Equal(t, []input.Binding{{Key: input.KeyF2}}, result.Bindings["b"])
+}
+
+func TestKeymapCanBeWrittenAndRead(t *testing.T) {
+	keymap := input.NewKeymap()
+	keymap.Set("undo", input.Binding{Key: input.CharacterKey('z'), Modifier: input.ModControl})
+	keymap.Set("nothing")
+	buffer := bytes.NewBuffer(nil)
+
+	require.Nil(t, input.WriteKeymap(buffer, keymap), "no error expected writing")
+	assert.Contains(t, buffer.String(), `"Ctrl+Z"`)
+	result, err := input.ReadKeymap(buffer)
+	require.Nil(t, err, "no error expected reading")
+
+	assert.Equal(t, keymap, result)
+}
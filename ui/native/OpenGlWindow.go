@@ -189,9 +189,9 @@ func (window *OpenGLWindow) onKey(rawWindow *glfw.Window, glfwKey glfw.Key, scan
 			window.keyBuffer.KeyUp(key, modifier)
 		}
 	} else if action != glfw.Release {
-		keyName := glfw.GetKeyName(glfwKey, scancode)
-		if key, knownKey = input.ResolveShortcut(keyName, modifier); knownKey {
-			window.CallKey(key, modifier)
+		keyName := []rune(glfw.GetKeyName(glfwKey, scancode))
+		if len(keyName) == 1 {
+			window.CallKey(input.CharacterKey(keyName[0]), modifier)
 		}
 	}
 }